@@ -1,3 +1,3 @@
 #!/usr/bin/env bash
 
-neuron-swagger-go.sh
\ No newline at end of file
+neuron-swagger-go.sh --principal=github.com/NeuronUser/user/models.Principal
@@ -0,0 +1,87 @@
+package blobstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore keeps blobs as files under a directory. The content type isn't
+// kept; whatever serves the directory derives it from the file extension.
+type LocalStore struct {
+	dir     string
+	baseUrl string
+}
+
+func NewLocalStore(dir string, baseUrl string) (*LocalStore, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocalStore{dir: dir, baseUrl: baseUrl}, nil
+}
+
+// path maps key into the directory, refusing keys that would escape it.
+func (s *LocalStore) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if key == "" || clean != "/"+key {
+		return "", fmt.Errorf("blobstore: invalid key %q", key)
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
+
+// Put writes data to a temporary file first, so that readers never see a
+// partly written blob.
+func (s *LocalStore) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(p), 0755)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(p), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Chmod(0644)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), p)
+}
+
+// DeletePrefix only supports prefixes ending in "/", i.e. directories.
+func (s *LocalStore) DeletePrefix(ctx context.Context, prefix string) error {
+	if !strings.HasSuffix(prefix, "/") {
+		return fmt.Errorf("blobstore: prefix %q is not a directory", prefix)
+	}
+
+	p, err := s.path(strings.TrimSuffix(prefix, "/"))
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(p)
+}
+
+func (s *LocalStore) URL(key string) string {
+	return s.baseUrl + "/" + key
+}
@@ -0,0 +1,75 @@
+package blobstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStoreRejectsEscapingKeys(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "blobs")
+	s, err := NewLocalStore(dir, "https://cdn.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{
+		"",
+		"..",
+		"../outside.png",
+		"avatars/../../outside.png",
+		"avatars/..",
+		"/etc/passwd",
+		"avatars//256.png",
+		"avatars/./256.png",
+		"avatars/",
+	}
+	for _, key := range keys {
+		if err := s.Put(context.Background(), key, "image/png", []byte("x")); err == nil {
+			t.Errorf("Put(%q) succeeded", key)
+		}
+	}
+
+	if err := s.DeletePrefix(context.Background(), "../"); err == nil {
+		t.Errorf("DeletePrefix(../) succeeded")
+	}
+	if err := s.DeletePrefix(context.Background(), "avatars/../../"); err == nil {
+		t.Errorf("DeletePrefix(avatars/../../) succeeded")
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "outside.png")); !os.IsNotExist(err) {
+		t.Errorf("a blob was written outside the store")
+	}
+}
+
+func TestLocalStorePutAndDeletePrefix(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocalStore(dir, "https://cdn.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if err := s.Put(ctx, "avatars/u1/v1/256.png", "image/png", []byte("png")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "avatars", "u1", "v1", "256.png"))
+	if err != nil || string(data) != "png" {
+		t.Fatalf("stored blob = %q, %v", data, err)
+	}
+	if url := s.URL("avatars/u1/v1/256.png"); url != "https://cdn.example.com/avatars/u1/v1/256.png" {
+		t.Errorf("URL = %s", url)
+	}
+
+	if err := s.DeletePrefix(ctx, "avatars/u1/v1"); err == nil {
+		t.Errorf("DeletePrefix without a trailing / succeeded")
+	}
+	if err := s.DeletePrefix(ctx, "avatars/u1/v1/"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "avatars", "u1", "v1")); !os.IsNotExist(err) {
+		t.Errorf("prefix still present after DeletePrefix")
+	}
+}
@@ -0,0 +1,37 @@
+package blobstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Store keeps files that are served to clients as is, e.g. avatar images.
+// Keys are slash separated paths such as "avatars/<userId>/<version>/256.jpg".
+// Implementations backed by object storage let several instances share the
+// files.
+type Store interface {
+	Put(ctx context.Context, key string, contentType string, data []byte) error
+	// DeletePrefix deletes every blob whose key starts with prefix.
+	DeletePrefix(ctx context.Context, prefix string) error
+	// URL is where clients fetch the blob stored under key.
+	URL(key string) string
+}
+
+// NewStoreFromEnv reads BLOB_STORE_DIR, the directory of a LocalStore, and
+// BLOB_STORE_URL, the URL the directory is published under, e.g. by the web
+// server in front of the API. It returns nil when BLOB_STORE_DIR is not set.
+func NewStoreFromEnv() (Store, error) {
+	dir := os.Getenv("BLOB_STORE_DIR")
+	if dir == "" {
+		return nil, nil
+	}
+
+	baseUrl := strings.TrimSuffix(os.Getenv("BLOB_STORE_URL"), "/")
+	if baseUrl == "" {
+		return nil, fmt.Errorf("BLOB_STORE_URL env nil")
+	}
+
+	return NewLocalStore(dir, baseUrl)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/NeuronUser/user/services"
+	"log"
+)
+
+// user-hash-secrets is run once, with the same env as user-private-api,
+// after upgrading to a version that stores secrets hashed.
+func main() {
+	batchSize := flag.Int64("batch", 1000, "rows read per query")
+	flag.Parse()
+
+	s, err := services.NewUserService()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	hashed, err := s.HashLegacySecrets(context.Background(), *batchSize)
+	if err != nil {
+		log.Fatalf("hashed %d refresh tokens before: %v", hashed, err)
+	}
+
+	log.Printf("hashed %d refresh tokens", hashed)
+}
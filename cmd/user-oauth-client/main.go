@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/services"
+	"log"
+	"strings"
+)
+
+// user-oauth-client registers an application that signs users in with us
+// over OAuth2 / OpenID Connect. It is run with the same env as
+// user-private-api and prints the client's credentials, which are not
+// stored in plaintext and can't be shown again.
+func main() {
+	name := flag.String("name", "", "client name shown to users")
+	redirectUris := flag.String("redirect-uris", "", "comma separated redirect URIs")
+	grantTypes := flag.String("grant-types", "authorization_code,refresh_token", "comma separated grant types")
+	scope := flag.String("scope", "openid profile", "space separated scopes the client may ask for")
+	public := flag.Bool("public", false, "a native or single page app, which can't keep a secret")
+	flag.Parse()
+
+	s, err := services.NewUserService()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	client, err := s.RegisterOauthClient(context.Background(), &models.OauthClient{
+		ClientName:   *name,
+		RedirectUris: splitList(*redirectUris),
+		GrantTypes:   splitList(*grantTypes),
+		Scope:        *scope,
+	}, *public)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("client_id=%s\n", client.ClientId)
+	if client.ClientSecret != "" {
+		fmt.Printf("client_secret=%s\n", client.ClientSecret)
+	}
+}
+
+func splitList(s string) (list []string) {
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"encoding/json"
 	api "github.com/NeuronUser/user/api/gen/models"
 	"github.com/NeuronUser/user/models"
+	"github.com/go-openapi/strfmt"
 )
 
 func fromUserInfo(p *models.UserInfo) (r *api.UserInfo) {
@@ -14,6 +16,109 @@ func fromUserInfo(p *models.UserInfo) (r *api.UserInfo) {
 	r.UserID = p.UserID
 	r.Name = p.Name
 	r.Icon = p.Icon
+	r.Bio = p.Bio
+	r.Gender = p.Gender
+	r.Birthday = p.Birthday
+	r.Locale = p.Locale
+	r.Timezone = p.Timezone
+	r.Attributes = p.Attributes
+
+	return r
+}
+
+func fromUserInfoList(p []*models.UserInfo) (r []*api.UserInfo) {
+	r = make([]*api.UserInfo, len(p))
+	for i, v := range p {
+		r[i] = fromUserInfo(v)
+	}
+
+	return r
+}
+
+func toProfile(p *api.Profile) (r *models.Profile) {
+	r = &models.Profile{}
+	r.Bio = p.Bio
+	r.Gender = p.Gender
+	r.Birthday = p.Birthday
+	r.Locale = p.Locale
+	r.Timezone = p.Timezone
+
+	return r
+}
+
+func fromToken(p *models.Token) (r *api.Token) {
+	if p == nil {
+		return nil
+	}
+
+	r = &api.Token{}
+	r.AccessToken = p.AccessToken
+	r.RefreshToken = p.RefreshToken
+	r.MfaToken = p.MfaToken
+
+	return r
+}
+
+func fromSession(p *models.Session) (r *api.Session) {
+	if p == nil {
+		return nil
+	}
+
+	r = &api.Session{}
+	r.SessionID = p.SessionId
+	r.ClientID = p.ClientId
+	r.UserAgent = p.UserAgent
+	r.ClientIP = p.ClientIp
+	r.LoginTime = strfmt.DateTime(p.LoginTime)
+	r.LastUsedTime = strfmt.DateTime(p.LastUsedTime)
+	r.Current = p.Current
+
+	return r
+}
+
+func fromSessionList(p []*models.Session) (r []*api.Session) {
+	r = make([]*api.Session, len(p))
+	for i, v := range p {
+		r[i] = fromSession(v)
+	}
+
+	return r
+}
+
+func fromTotpEnrollment(p *models.TotpEnrollment) (r *api.TotpEnrollment) {
+	if p == nil {
+		return nil
+	}
+
+	r = &api.TotpEnrollment{}
+	r.Secret = p.Secret
+	r.URI = p.Uri
+
+	return r
+}
+
+func fromWebAuthnChallenge(p *models.WebAuthnChallenge) (r *api.WebAuthnChallenge) {
+	if p == nil {
+		return nil
+	}
+
+	r = &api.WebAuthnChallenge{}
+	r.SessionID = p.SessionId
+	r.Options = json.RawMessage(p.Options)
+
+	return r
+}
+
+func toAuthorizeRequest(p *api.AuthorizeRequest) (r *models.AuthorizeRequest) {
+	r = &models.AuthorizeRequest{}
+	r.ClientId = *p.ClientID
+	r.RedirectUri = *p.RedirectURI
+	r.ResponseType = *p.ResponseType
+	r.Scope = p.Scope
+	r.State = p.State
+	r.Nonce = p.Nonce
+	r.CodeChallenge = p.CodeChallenge
+	r.CodeChallengeMethod = p.CodeChallengeMethod
 
 	return r
 }
@@ -1,24 +1,33 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
 	"github.com/NeuronFramework/errors"
 	"github.com/NeuronFramework/log"
 	"github.com/NeuronFramework/restful"
+	api "github.com/NeuronUser/user/api/gen/models"
 	"github.com/NeuronUser/user/api/gen/restapi/operations"
+	"github.com/NeuronUser/user/models"
 	"github.com/NeuronUser/user/services"
-	"github.com/dgrijalva/jwt-go"
+	openapierrors "github.com/go-openapi/errors"
 	"github.com/go-openapi/runtime/middleware"
 	"go.uber.org/zap"
+	"net"
+	"net/http"
+	"os"
 )
 
 type UserHandler struct {
-	logger  *zap.Logger
-	service *services.UserService
+	logger         *zap.Logger
+	service        *services.UserService
+	clientIpHeader string
 }
 
 func NewUserHandler() (h *UserHandler, err error) {
 	h = &UserHandler{}
 	h.logger = log.TypedLogger(h)
+	h.clientIpHeader = os.Getenv("CLIENT_IP_HEADER")
 	h.service, err = services.NewUserService()
 	if err != nil {
 		return nil, err
@@ -27,27 +36,301 @@ func NewUserHandler() (h *UserHandler, err error) {
 	return h, nil
 }
 
-func (h *UserHandler) BearerAuth(token string) (userId interface{}, err error) {
-	claims := jwt.StandardClaims{}
-	_, err = jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
-		return []byte("0123456789"), nil
-	})
+// StartJanitor starts the expired-row cleanup in the background.
+func (h *UserHandler) StartJanitor() error {
+	config, err := services.NewJanitorConfigFromEnv()
+	if err != nil {
+		return err
+	}
+
+	go h.service.RunJanitor(context.Background(), config)
+
+	return nil
+}
+
+// StartOauthIconMirror starts copying provider avatars in the background.
+func (h *UserHandler) StartOauthIconMirror() error {
+	config, err := services.NewOauthIconMirrorConfigFromEnv()
+	if err != nil {
+		return err
+	}
+
+	go h.service.RunOauthIconMirror(context.Background(), config)
+
+	return nil
+}
+
+// BearerAuth authenticates users with their access token, refusing tokens
+// that were revoked or whose session has signed out.
+func (h *UserHandler) BearerAuth(token string) (*models.Principal, error) {
+	return h.service.AuthenticateAccessToken(context.Background(), token)
+}
+
+// BasicAuth authenticates our own services, which call the private API with
+// the credentials of an OAuth client limited to client_credentials.
+func (h *UserHandler) BasicAuth(clientId string, clientSecret string) (*models.Principal, error) {
+	principal, err := h.service.AuthenticateService(context.Background(), clientId, clientSecret)
 	if err != nil {
 		return nil, err
 	}
+	if principal == nil {
+		// a typed nil would pass as authenticated
+		return nil, openapierrors.Unauthenticated("basic")
+	}
+
+	return principal, nil
+}
+
+func (h *UserHandler) SendLoginSmsCode(p operations.SendLoginSmsCodeParams) middleware.Responder {
+	err := h.service.SendLoginSmsCode(restful.NewContext(p.HTTPRequest), p.PhoneNumber, h.ClientIp(p.HTTPRequest))
+	if err != nil {
+		return errors.Wrap(err)
+	}
 
-	if claims.Subject == "" {
-		return nil, errors.Unknown("验证失败： claims.Subject nil")
+	return operations.NewSendLoginSmsCodeOK()
+}
+
+func (h *UserHandler) SmsLogin(p operations.SmsLoginParams) middleware.Responder {
+	token, err := h.service.SmsLogin(restful.NewContext(p.HTTPRequest), p.PhoneNumber, p.SmsCode, p.HTTPRequest.UserAgent(), h.ClientIp(p.HTTPRequest))
+	if err != nil {
+		return errors.Wrap(err)
 	}
 
-	return claims.Subject, nil
+	return operations.NewSmsLoginOK().WithPayload(fromToken(token))
 }
 
-func (h *UserHandler) GetUserInfo(p operations.GetUserInfoParams, userId interface{}) middleware.Responder {
-	userInfo, err := h.service.GetUserInfo(restful.NewContext(p.HTTPRequest), userId.(string))
+func (h *UserHandler) RefreshToken(p operations.RefreshTokenParams) middleware.Responder {
+	token, err := h.service.RefreshToken(restful.NewContext(p.HTTPRequest), p.RefreshToken, p.HTTPRequest.UserAgent(), h.ClientIp(p.HTTPRequest))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewRefreshTokenOK().WithPayload(fromToken(token))
+}
+
+func (h *UserHandler) Logout(p operations.LogoutParams) middleware.Responder {
+	err := h.service.Logout(restful.NewContext(p.HTTPRequest), p.RefreshToken)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewLogoutOK()
+}
+
+func (h *UserHandler) ListMySessions(p operations.ListMySessionsParams, principal *models.Principal) middleware.Responder {
+	sessions, err := h.service.ListMySessions(restful.NewContext(p.HTTPRequest), principal.UserId, principal.SessionId)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewListMySessionsOK().WithPayload(fromSessionList(sessions))
+}
+
+func (h *UserHandler) RevokeSession(p operations.RevokeSessionParams, principal *models.Principal) middleware.Responder {
+	err := h.service.RevokeSession(restful.NewContext(p.HTTPRequest), principal.UserId, p.SessionID, p.HTTPRequest.UserAgent())
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewRevokeSessionOK()
+}
+
+func (h *UserHandler) PasswordLogin(p operations.PasswordLoginParams) middleware.Responder {
+	token, err := h.service.PasswordLogin(restful.NewContext(p.HTTPRequest), *p.Body.UserName, *p.Body.Password, p.HTTPRequest.UserAgent(), h.ClientIp(p.HTTPRequest))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewPasswordLoginOK().WithPayload(fromToken(token))
+}
+
+func (h *UserHandler) SetPassword(p operations.SetPasswordParams, principal *models.Principal) middleware.Responder {
+	err := h.service.SetPassword(restful.NewContext(p.HTTPRequest), principal.UserId, *p.Body.Password, p.HTTPRequest.UserAgent())
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewSetPasswordOK()
+}
+
+func (h *UserHandler) ChangePassword(p operations.ChangePasswordParams, principal *models.Principal) middleware.Responder {
+	err := h.service.ChangePassword(restful.NewContext(p.HTTPRequest), principal.UserId, *p.Body.OldPassword, *p.Body.NewPassword, p.HTTPRequest.UserAgent())
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewChangePasswordOK()
+}
+
+func (h *UserHandler) EnrollTotp(p operations.EnrollTotpParams, principal *models.Principal) middleware.Responder {
+	enrollment, err := h.service.EnrollTotp(restful.NewContext(p.HTTPRequest), principal.UserId)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewEnrollTotpOK().WithPayload(fromTotpEnrollment(enrollment))
+}
+
+func (h *UserHandler) ConfirmTotp(p operations.ConfirmTotpParams, principal *models.Principal) middleware.Responder {
+	recoveryCodes, err := h.service.ConfirmTotp(restful.NewContext(p.HTTPRequest), principal.UserId, *p.Body.Code, p.HTTPRequest.UserAgent())
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewConfirmTotpOK().WithPayload(&api.RecoveryCodes{Codes: recoveryCodes})
+}
+
+func (h *UserHandler) DisableTotp(p operations.DisableTotpParams, principal *models.Principal) middleware.Responder {
+	err := h.service.DisableTotp(restful.NewContext(p.HTTPRequest), principal.UserId, *p.Body.Code, p.HTTPRequest.UserAgent())
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewDisableTotpOK()
+}
+
+func (h *UserHandler) VerifyMfa(p operations.VerifyMfaParams) middleware.Responder {
+	token, err := h.service.VerifyMfa(restful.NewContext(p.HTTPRequest), *p.Body.MfaToken, *p.Body.Code, p.HTTPRequest.UserAgent(), h.ClientIp(p.HTTPRequest))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewVerifyMfaOK().WithPayload(fromToken(token))
+}
+
+func (h *UserHandler) BeginWebAuthnRegistration(p operations.BeginWebAuthnRegistrationParams, principal *models.Principal) middleware.Responder {
+	challenge, err := h.service.BeginWebAuthnRegistration(restful.NewContext(p.HTTPRequest), principal.UserId)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewBeginWebAuthnRegistrationOK().WithPayload(fromWebAuthnChallenge(challenge))
+}
+
+func (h *UserHandler) FinishWebAuthnRegistration(p operations.FinishWebAuthnRegistrationParams, principal *models.Principal) middleware.Responder {
+	credential, err := json.Marshal(p.Body.Credential)
+	if err != nil {
+		return errors.Wrap(errors.BadRequest("InvalidWebAuthnCredential", "通行密钥验证失败"))
+	}
+
+	err = h.service.FinishWebAuthnRegistration(restful.NewContext(p.HTTPRequest), principal.UserId, *p.Body.SessionID, credential, p.HTTPRequest.UserAgent())
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewFinishWebAuthnRegistrationOK()
+}
+
+func (h *UserHandler) BeginWebAuthnLogin(p operations.BeginWebAuthnLoginParams) middleware.Responder {
+	challenge, err := h.service.BeginWebAuthnLogin(restful.NewContext(p.HTTPRequest))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewBeginWebAuthnLoginOK().WithPayload(fromWebAuthnChallenge(challenge))
+}
+
+func (h *UserHandler) FinishWebAuthnLogin(p operations.FinishWebAuthnLoginParams) middleware.Responder {
+	credential, err := json.Marshal(p.Body.Credential)
+	if err != nil {
+		return errors.Wrap(errors.BadRequest("InvalidWebAuthnCredential", "通行密钥验证失败"))
+	}
+
+	token, err := h.service.FinishWebAuthnLogin(restful.NewContext(p.HTTPRequest), *p.Body.SessionID, credential, p.HTTPRequest.UserAgent(), h.ClientIp(p.HTTPRequest))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewFinishWebAuthnLoginOK().WithPayload(fromToken(token))
+}
+
+func (h *UserHandler) Authorize(p operations.AuthorizeParams, principal *models.Principal) middleware.Responder {
+	redirectUri, err := h.service.Authorize(restful.NewContext(p.HTTPRequest), principal.UserId, toAuthorizeRequest(p.Body), p.HTTPRequest.UserAgent())
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewAuthorizeOK().WithPayload(&api.OauthRedirect{RedirectURI: redirectUri})
+}
+
+func (h *UserHandler) GetUserInfo(p operations.GetUserInfoParams, principal *models.Principal) middleware.Responder {
+	userInfo, err := h.service.GetUserInfo(restful.NewContext(p.HTTPRequest), principal, principal.UserId, p.Fields)
 	if err != nil {
 		return errors.Wrap(err)
 	}
 
 	return operations.NewGetUserInfoOK().WithPayload(fromUserInfo(userInfo))
 }
+
+func (h *UserHandler) UpdateProfile(p operations.UpdateProfileParams, principal *models.Principal) middleware.Responder {
+	userInfo, err := h.service.UpdateProfile(restful.NewContext(p.HTTPRequest), principal.UserId, toProfile(p.Body))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewUpdateProfileOK().WithPayload(fromUserInfo(userInfo))
+}
+
+func (h *UserHandler) SetUserAttributes(p operations.SetUserAttributesParams, principal *models.Principal) middleware.Responder {
+	attributes, err := h.service.SetUserAttributes(restful.NewContext(p.HTTPRequest), principal, p.UserID, p.Namespace, p.Body)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewSetUserAttributesOK().WithPayload(attributes)
+}
+
+func (h *UserHandler) UpdateUserIcon(p operations.UpdateUserIconParams, principal *models.Principal) middleware.Responder {
+	defer p.File.Close()
+
+	userInfo, err := h.service.UpdateUserIcon(restful.NewContext(p.HTTPRequest), principal.UserId, p.File)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewUpdateUserIconOK().WithPayload(fromUserInfo(userInfo))
+}
+
+func (h *UserHandler) UpdateUserName(p operations.UpdateUserNameParams, principal *models.Principal) middleware.Responder {
+	err := h.service.UpdateUserName(restful.NewContext(p.HTTPRequest), principal.UserId, p.UserName)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewUpdateUserNameOK()
+}
+
+// ClientIp is the request's peer address, or the CLIENT_IP_HEADER header
+// (e.g. X-Real-IP) when running behind a proxy that sets it. Anything that
+// does not parse as an IP is dropped.
+func (h *UserHandler) ClientIp(r *http.Request) string {
+	addr := ""
+	if h.clientIpHeader != "" {
+		addr = r.Header.Get(h.clientIpHeader)
+	} else {
+		addr, _, _ = net.SplitHostPort(r.RemoteAddr)
+	}
+
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return ""
+	}
+
+	return ip.String()
+}
+
+// UserId is the id of the user r is authenticated as, or "" for anonymous
+// requests and invalid tokens.
+func (h *UserHandler) UserId(r *http.Request) string {
+	token := r.Header.Get("Authorization")
+	if token == "" {
+		return ""
+	}
+
+	principal, err := h.service.ParseAccessToken(token)
+	if err != nil {
+		return ""
+	}
+
+	return principal.UserId
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronFramework/restful"
+	"github.com/NeuronUser/user/api/gen/restapi/operations"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/services"
+	openapierrors "github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime/middleware"
+	"net/http"
+)
+
+// AuthorizeOperation checks that principal holds the scopes the operation r
+// was routed to declares in x-scopes, and that an impersonation token isn't
+// used for an operation marked x-no-impersonation. BearerAuth has checked
+// the token's impersonation against the recorded token.
+func (h *UserHandler) AuthorizeOperation(r *http.Request, principal interface{}) error {
+	p, ok := principal.(*models.Principal)
+	if !ok || p == nil {
+		return openapierrors.New(http.StatusForbidden, "unknown principal")
+	}
+
+	route := middleware.MatchedRouteFrom(r)
+	if route == nil || route.Operation == nil {
+		return openapierrors.New(http.StatusForbidden, "unknown operation")
+	}
+
+	scopes, _ := route.Operation.Extensions.GetStringSlice("x-scopes")
+	if !services.PrincipalHasScopes(p, scopes) {
+		return openapierrors.New(http.StatusForbidden, "%s requires scopes %v", route.Operation.ID, scopes)
+	}
+
+	if noImpersonation, _ := route.Operation.Extensions.GetBool("x-no-impersonation"); noImpersonation && p.ActorId != "" {
+		return openapierrors.New(http.StatusForbidden, "%s is not allowed while impersonating", route.Operation.ID)
+	}
+
+	return nil
+}
+
+func (h *UserHandler) BatchGetUserInfo(p operations.BatchGetUserInfoParams, principal *models.Principal) middleware.Responder {
+	list, err := h.service.BatchGetUserInfo(restful.NewContext(p.HTTPRequest), principal, p.UserIds, p.Fields)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewBatchGetUserInfoOK().WithPayload(fromUserInfoList(list))
+}
+
+func (h *UserHandler) Impersonate(p operations.ImpersonateParams, principal *models.Principal) middleware.Responder {
+	token, err := h.service.Impersonate(restful.NewContext(p.HTTPRequest), principal, p.UserID, *p.Body.Reason, p.HTTPRequest.UserAgent())
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewImpersonateOK().WithPayload(fromToken(token))
+}
+
+func (h *UserHandler) RevokeUserSessions(p operations.RevokeUserSessionsParams, principal *models.Principal) middleware.Responder {
+	err := h.service.RevokeUserSessions(restful.NewContext(p.HTTPRequest), p.UserID, principal)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return operations.NewRevokeUserSessionsOK()
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/NeuronFramework/restful"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/services"
+	"go.uber.org/zap"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// The OAuth2 endpoints below take forms and return the JSON of the OAuth2
+// and OpenID Connect specs, which swagger can't describe, so they are plain
+// http handlers mounted next to the API.
+
+type oauthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IdToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+type oauthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+func writeOauthJson(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func (h *UserHandler) writeOauthError(w http.ResponseWriter, err error) {
+	oauthErr, ok := err.(*services.OauthError)
+	if !ok {
+		h.logger.Error("oauth", zap.Error(err))
+		oauthErr = &services.OauthError{Code: "server_error"}
+	}
+
+	status := http.StatusBadRequest
+	switch oauthErr.Code {
+	case "invalid_client", "invalid_token":
+		status = http.StatusUnauthorized
+	case "insufficient_scope":
+		status = http.StatusForbidden
+	case "server_error":
+		status = http.StatusInternalServerError
+	}
+
+	writeOauthJson(w, status, &oauthErrorResponse{Error: oauthErr.Code, ErrorDescription: oauthErr.Description})
+}
+
+func (h *UserHandler) OauthDiscovery(w http.ResponseWriter, r *http.Request) {
+	if !h.service.OauthProviderEnabled() {
+		http.NotFound(w, r)
+		return
+	}
+
+	writeOauthJson(w, http.StatusOK, h.service.OauthDiscovery())
+}
+
+func (h *UserHandler) OauthJwks(w http.ResponseWriter, r *http.Request) {
+	if !h.service.OauthProviderEnabled() {
+		http.NotFound(w, r)
+		return
+	}
+
+	writeOauthJson(w, http.StatusOK, h.service.OauthJwks())
+}
+
+// oauthClientCredentials returns the client credentials of r, a parsed
+// form post, from either HTTP Basic auth, whose credentials are form encoded
+// (RFC 6749 2.3.1), or the form.
+func oauthClientCredentials(r *http.Request) (clientId string, clientSecret string, err error) {
+	clientId = r.PostForm.Get("client_id")
+	clientSecret = r.PostForm.Get("client_secret")
+
+	if user, password, ok := r.BasicAuth(); ok {
+		basicId, err1 := url.QueryUnescape(user)
+		basicSecret, err2 := url.QueryUnescape(password)
+		if err1 != nil || err2 != nil || clientSecret != "" || clientId != "" && clientId != basicId {
+			return "", "", &services.OauthError{Code: "invalid_request", Description: "use one client authentication method"}
+		}
+		clientId = basicId
+		clientSecret = basicSecret
+	}
+
+	return clientId, clientSecret, nil
+}
+
+// writeOauthClientError is writeOauthError for endpoints clients
+// authenticate to, which challenge them to use HTTP Basic.
+func (h *UserHandler) writeOauthClientError(w http.ResponseWriter, err error) {
+	if e, ok := err.(*services.OauthError); ok && e.Code == "invalid_client" {
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth2"`)
+	}
+	h.writeOauthError(w, err)
+}
+
+// parseOauthForm checks that r is a form post and parses it, writing the
+// response if it is not.
+func (h *UserHandler) parseOauthForm(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return false
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		h.writeOauthError(w, &services.OauthError{Code: "invalid_request", Description: "invalid form"})
+		return false
+	}
+
+	return true
+}
+
+// OauthToken is the token endpoint. Clients authenticate with HTTP Basic or
+// in the form.
+func (h *UserHandler) OauthToken(w http.ResponseWriter, r *http.Request) {
+	if !h.service.OauthProviderEnabled() {
+		http.NotFound(w, r)
+		return
+	}
+
+	if !h.parseOauthForm(w, r) {
+		return
+	}
+
+	clientId, clientSecret, err := oauthClientCredentials(r)
+	if err != nil {
+		h.writeOauthError(w, err)
+		return
+	}
+
+	req := &models.OauthTokenRequest{
+		GrantType:    r.PostForm.Get("grant_type"),
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+		Code:         r.PostForm.Get("code"),
+		RedirectUri:  r.PostForm.Get("redirect_uri"),
+		CodeVerifier: r.PostForm.Get("code_verifier"),
+		RefreshToken: r.PostForm.Get("refresh_token"),
+		Scope:        r.PostForm.Get("scope"),
+	}
+
+	token, err := h.service.OauthToken(restful.NewContext(r), req, r.UserAgent(), h.ClientIp(r))
+	if err != nil {
+		h.writeOauthClientError(w, err)
+		return
+	}
+
+	writeOauthJson(w, http.StatusOK, &oauthTokenResponse{
+		AccessToken:  token.AccessToken,
+		TokenType:    token.TokenType,
+		ExpiresIn:    token.ExpiresIn,
+		RefreshToken: token.RefreshToken,
+		IdToken:      token.IdToken,
+		Scope:        token.Scope,
+	})
+}
+
+// OauthUserInfo is the OpenID Connect UserInfo endpoint. Unlike the API it
+// takes the access token as "Bearer <token>" (RFC 6750).
+func (h *UserHandler) OauthUserInfo(w http.ResponseWriter, r *http.Request) {
+	if !h.service.OauthProviderEnabled() {
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	authorization := r.Header.Get("Authorization")
+	if len(authorization) < 7 || !strings.EqualFold(authorization[:7], "Bearer ") {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := h.service.OauthUserInfo(restful.NewContext(r), strings.TrimSpace(authorization[7:]))
+	if err != nil {
+		if e, ok := err.(*services.OauthError); ok {
+			w.Header().Set("WWW-Authenticate", `Bearer error="`+e.Code+`"`)
+		}
+		h.writeOauthError(w, err)
+		return
+	}
+
+	writeOauthJson(w, http.StatusOK, claims)
+}
+
+type tokenIntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Subject   string `json:"sub,omitempty"`
+	ClientId  string `json:"client_id,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+}
+
+// IntrospectToken is the token introspection endpoint (RFC 7662) for our
+// services that can't validate tokens themselves. They authenticate like
+// clients of the token endpoint, with the credentials of a client allowed
+// the client_credentials grant. token_type_hint is ignored, as access tokens
+// are JWTs and refresh tokens never are.
+func (h *UserHandler) IntrospectToken(w http.ResponseWriter, r *http.Request) {
+	if !h.parseOauthForm(w, r) {
+		return
+	}
+
+	clientId, clientSecret, err := oauthClientCredentials(r)
+	if err != nil {
+		h.writeOauthError(w, err)
+		return
+	}
+
+	introspection, err := h.service.IntrospectToken(restful.NewContext(r), clientId, clientSecret, r.PostForm.Get("token"))
+	if err != nil {
+		h.writeOauthClientError(w, err)
+		return
+	}
+
+	writeOauthJson(w, http.StatusOK, &tokenIntrospectionResponse{
+		Active:    introspection.Active,
+		Subject:   introspection.Subject,
+		ClientId:  introspection.ClientId,
+		Scope:     introspection.Scope,
+		TokenType: introspection.TokenType,
+		Exp:       introspection.ExpiresAt,
+		Iat:       introspection.IssuedAt,
+	})
+}
+
+// RevokeToken is the token revocation endpoint (RFC 7009), authenticated
+// like IntrospectToken. It answers 200 for unknown tokens too.
+func (h *UserHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	if !h.parseOauthForm(w, r) {
+		return
+	}
+
+	clientId, clientSecret, err := oauthClientCredentials(r)
+	if err != nil {
+		h.writeOauthError(w, err)
+		return
+	}
+
+	err = h.service.RevokeToken(restful.NewContext(r), clientId, clientSecret, r.PostForm.Get("token"))
+	if err != nil {
+		h.writeOauthClientError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+}
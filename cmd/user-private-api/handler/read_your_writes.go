@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// readPrimaryCookie, or the header of the same name for clients without a
+// cookie jar, carries the unix time until which the client's reads must go
+// to the primary.
+const readPrimaryCookie = "Read-Primary-Until"
+
+// ReadYourWrites lets a user read their own writes whichever instance serves
+// them: a successful request other than GET hands the client a hint, which
+// keeps the user's reads on the primary for DB_READ_YOUR_WRITES_WINDOW on
+// every instance it is sent back to.
+func (h *UserHandler) ReadYourWrites(next http.Handler) http.Handler {
+	window := h.service.ReadYourWritesWindow()
+	if window <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if until, ok := readPrimaryHint(r); ok && until.After(time.Now()) {
+			if userId := h.UserId(r); userId != "" {
+				h.service.ReadPrimaryUntil(userId, until)
+			}
+		}
+
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(&readPrimaryWriter{ResponseWriter: w, window: window}, r)
+	})
+}
+
+func readPrimaryHint(r *http.Request) (until time.Time, ok bool) {
+	v := r.Header.Get(readPrimaryCookie)
+	if cookie, err := r.Cookie(readPrimaryCookie); err == nil {
+		v = cookie.Value
+	}
+
+	unix, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(unix, 0), true
+}
+
+// readPrimaryWriter adds the hint to successful responses.
+type readPrimaryWriter struct {
+	http.ResponseWriter
+	window      time.Duration
+	wroteHeader bool
+}
+
+func (w *readPrimaryWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if code < http.StatusBadRequest {
+			until := strconv.FormatInt(time.Now().Add(w.window).Unix()+1, 10)
+			w.Header().Set(readPrimaryCookie, until)
+			http.SetCookie(w.ResponseWriter, &http.Cookie{
+				Name:     readPrimaryCookie,
+				Value:    until,
+				Path:     "/",
+				MaxAge:   int(w.window/time.Second) + 1,
+				HttpOnly: true,
+			})
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *readPrimaryWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
@@ -5,7 +5,9 @@ import (
 	"github.com/NeuronUser/user/api/gen/restapi"
 	"github.com/NeuronUser/user/api/gen/restapi/operations"
 	"github.com/NeuronUser/user/cmd/user-private-api/handler"
+	"github.com/NeuronUser/user/ratelimit"
 	"github.com/go-openapi/loads"
+	"github.com/go-openapi/runtime"
 	"net/http"
 )
 
@@ -16,6 +18,16 @@ func main() {
 			return nil, err
 		}
 
+		err = h.StartJanitor()
+		if err != nil {
+			return nil, err
+		}
+
+		err = h.StartOauthIconMirror()
+		if err != nil {
+			return nil, err
+		}
+
 		swaggerSpec, err := loads.Analyzed(restapi.SwaggerJSON, "")
 		if err != nil {
 			return nil, err
@@ -23,8 +35,77 @@ func main() {
 
 		api := operations.NewUserAPI(swaggerSpec)
 		api.BearerAuth = h.BearerAuth
+		api.BasicAuth = h.BasicAuth
+		api.APIAuthorizer = runtime.AuthorizerFunc(h.AuthorizeOperation)
+		api.SendLoginSmsCodeHandler = operations.SendLoginSmsCodeHandlerFunc(h.SendLoginSmsCode)
+		api.SmsLoginHandler = operations.SmsLoginHandlerFunc(h.SmsLogin)
+		api.RefreshTokenHandler = operations.RefreshTokenHandlerFunc(h.RefreshToken)
+		api.LogoutHandler = operations.LogoutHandlerFunc(h.Logout)
+		api.ListMySessionsHandler = operations.ListMySessionsHandlerFunc(h.ListMySessions)
+		api.RevokeSessionHandler = operations.RevokeSessionHandlerFunc(h.RevokeSession)
+		api.PasswordLoginHandler = operations.PasswordLoginHandlerFunc(h.PasswordLogin)
+		api.SetPasswordHandler = operations.SetPasswordHandlerFunc(h.SetPassword)
+		api.ChangePasswordHandler = operations.ChangePasswordHandlerFunc(h.ChangePassword)
+		api.EnrollTotpHandler = operations.EnrollTotpHandlerFunc(h.EnrollTotp)
+		api.ConfirmTotpHandler = operations.ConfirmTotpHandlerFunc(h.ConfirmTotp)
+		api.DisableTotpHandler = operations.DisableTotpHandlerFunc(h.DisableTotp)
+		api.VerifyMfaHandler = operations.VerifyMfaHandlerFunc(h.VerifyMfa)
+		api.BeginWebAuthnRegistrationHandler = operations.BeginWebAuthnRegistrationHandlerFunc(h.BeginWebAuthnRegistration)
+		api.FinishWebAuthnRegistrationHandler = operations.FinishWebAuthnRegistrationHandlerFunc(h.FinishWebAuthnRegistration)
+		api.BeginWebAuthnLoginHandler = operations.BeginWebAuthnLoginHandlerFunc(h.BeginWebAuthnLogin)
+		api.FinishWebAuthnLoginHandler = operations.FinishWebAuthnLoginHandlerFunc(h.FinishWebAuthnLogin)
+		api.AuthorizeHandler = operations.AuthorizeHandlerFunc(h.Authorize)
 		api.GetUserInfoHandler = operations.GetUserInfoHandlerFunc(h.GetUserInfo)
+		api.UpdateUserNameHandler = operations.UpdateUserNameHandlerFunc(h.UpdateUserName)
+		api.UpdateUserIconHandler = operations.UpdateUserIconHandlerFunc(h.UpdateUserIcon)
+		api.UpdateProfileHandler = operations.UpdateProfileHandlerFunc(h.UpdateProfile)
+		api.SetUserAttributesHandler = operations.SetUserAttributesHandlerFunc(h.SetUserAttributes)
+		api.BatchGetUserInfoHandler = operations.BatchGetUserInfoHandlerFunc(h.BatchGetUserInfo)
+		api.ImpersonateHandler = operations.ImpersonateHandlerFunc(h.Impersonate)
+		api.RevokeUserSessionsHandler = operations.RevokeUserSessionsHandlerFunc(h.RevokeUserSessions)
+
+		limiterConfig, err := ratelimit.NewConfigFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		limiter := ratelimit.NewLimiter(limiterConfig, ratelimit.NewMemoryStore())
+
+		// the OAuth2 endpoints, introspection and revocation are plain handlers
+		// outside of swagger, mounted under the API base path so the issuer is
+		// the base path URL
+		mux := http.NewServeMux()
+		oauthOperations := map[string]string{}
+		for path, v := range map[string]struct {
+			operationId string
+			handler     http.HandlerFunc
+		}{
+			"/.well-known/openid-configuration": {"OauthDiscovery", h.OauthDiscovery},
+			"/oauth2/jwks":                      {"OauthJwks", h.OauthJwks},
+			"/oauth2/token":                     {"OauthToken", h.OauthToken},
+			"/oauth2/userinfo":                  {"OauthUserInfo", h.OauthUserInfo},
+			"/introspect":                       {"IntrospectToken", h.IntrospectToken},
+			"/revoke":                           {"RevokeToken", h.RevokeToken},
+		} {
+			mux.Handle(swaggerSpec.BasePath()+path, v.handler)
+			oauthOperations[swaggerSpec.BasePath()+path] = v.operationId
+		}
+		mux.Handle("/", api.Serve(nil))
+
+		operationId := func(r *http.Request) string {
+			if v, ok := oauthOperations[r.URL.Path]; ok {
+				return v
+			}
+
+			route, ok := api.Context().LookupRoute(r)
+			if !ok || route.Operation == nil {
+				return ""
+			}
+			return route.Operation.ID
+		}
 
-		return api.Serve(nil), nil
+		return limiter.Handler(h.ReadYourWrites(mux), operationId, map[string]ratelimit.KeyFunc{
+			"ip":   h.ClientIp,
+			"user": h.UserId,
+		}), nil
 	})
 }
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/NeuronUser/user/services"
+	"log"
+)
+
+// user-role grants or revokes a user's role, e.g. admin. It is run with the
+// same env as user-private-api. The user's access tokens get the new scopes
+// at their next refresh.
+func main() {
+	userId := flag.String("user", "", "user id")
+	grant := flag.String("grant", "", "role to grant")
+	revoke := flag.String("revoke", "", "role to revoke")
+	flag.Parse()
+
+	if *userId == "" || (*grant == "") == (*revoke == "") {
+		log.Fatal("need -user and one of -grant or -revoke")
+	}
+
+	s, err := services.NewUserService()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *grant != "" {
+		err = s.GrantRole(context.Background(), *userId, *grant)
+	} else {
+		err = s.RevokeRole(context.Background(), *userId, *revoke)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
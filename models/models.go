@@ -1,7 +1,145 @@
 package models
 
+import (
+	"time"
+)
+
+// UserInfo holds the fields of a user that were asked for; the others are
+// left empty.
 type UserInfo struct {
-	UserID string
-	Name   string
-	Icon   string
+	UserID   string
+	Name     string
+	Icon     string
+	Bio      string
+	Gender   string
+	Birthday string
+	Locale   string
+	Timezone string
+	// Attributes holds app specific values by namespace and key.
+	Attributes map[string]map[string]string
+}
+
+// Profile holds the standard fields users fill in themselves. Empty fields
+// are unset.
+type Profile struct {
+	Bio      string
+	Gender   string
+	Birthday string
+	Locale   string
+	Timezone string
+}
+
+// Token holds either a token pair, or only MfaToken when the user must
+// pass a second factor first.
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	MfaToken     string
+}
+
+type TotpEnrollment struct {
+	Secret string
+	Uri    string
+}
+
+// WebAuthnChallenge starts a WebAuthn ceremony. Options is the JSON to pass
+// to navigator.credentials.create() or get(); SessionId goes back with the
+// authenticator's response.
+type WebAuthnChallenge struct {
+	SessionId string
+	Options   []byte
+}
+
+// Session is one login of a user, kept alive by refreshing its tokens.
+type Session struct {
+	SessionId    string
+	ClientId     string
+	UserAgent    string
+	ClientIp     string
+	LoginTime    time.Time
+	LastUsedTime time.Time
+	// Current is set on the session that asked for the list
+	Current bool
+}
+
+// AuthorizeRequest is the query of an OAuth2 authorization request, which
+// the authorize page passes on once the signed in user has consented.
+type AuthorizeRequest struct {
+	ClientId            string
+	RedirectUri         string
+	ResponseType        string
+	Scope               string
+	State               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// OauthTokenRequest is the form posted to the OAuth2 token endpoint.
+// ClientId and ClientSecret come from either the form or HTTP Basic auth.
+type OauthTokenRequest struct {
+	GrantType    string
+	ClientId     string
+	ClientSecret string
+	Code         string
+	RedirectUri  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string
+}
+
+type OauthToken struct {
+	AccessToken  string
+	TokenType    string
+	ExpiresIn    int64
+	RefreshToken string
+	IdToken      string
+	Scope        string
+}
+
+// OauthClient is an application registered to sign users in with us.
+// ClientSecret is empty for public clients, and only known when registered.
+type OauthClient struct {
+	ClientId     string
+	ClientSecret string
+	ClientName   string
+	RedirectUris []string
+	GrantTypes   []string
+	Scope        string
+}
+
+// TokenIntrospection describes a token to the service that asked about it
+// (RFC 7662 2.2). Only Active is meaningful for inactive tokens.
+type TokenIntrospection struct {
+	Active    bool
+	Subject   string
+	ClientId  string
+	Scope     string
+	TokenType string
+	ExpiresAt int64
+	IssuedAt  int64
 }
+
+// Principal is who an API request is authenticated as: a user, with their
+// current roles and the scopes of their access token that those roles still
+// grant, or, when ClientId is set, one of our own services with the scopes
+// its OAuth client was granted.
+type Principal struct {
+	UserId     string
+	ClientId   string
+	Roles      []string
+	Scopes     []string
+	AuthMethod string
+	// SessionId and TokenId identify the session and access token a user
+	// called with. Tokens issued before they were recorded have neither.
+	SessionId string
+	TokenId   string
+	IssuedAt  time.Time
+	// ActorId is the support staff member impersonating UserId, if any.
+	ActorId string
+}
+
+const (
+	AuthMethodBearer = "bearer"
+	AuthMethodBasic  = "basic"
+)
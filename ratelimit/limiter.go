@@ -0,0 +1,188 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/NeuronFramework/log"
+	"go.uber.org/zap"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests per Per, with bursts of up to Requests. A Limit with
+// Requests <= 0 is unlimited.
+type Limit struct {
+	Requests int64
+	Per      time.Duration
+}
+
+func ParseLimit(s string) (l Limit, err error) {
+	if s == "off" {
+		return Limit{}, nil
+	}
+
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return l, fmt.Errorf("invalid rate limit %q, want e.g. 10/1m", s)
+	}
+
+	l.Requests, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return l, fmt.Errorf("invalid rate limit %q: %v", s, err)
+	}
+
+	l.Per, err = time.ParseDuration(parts[1])
+	if err != nil || l.Per <= 0 {
+		return l, fmt.Errorf("invalid rate limit %q, want e.g. 10/1m", s)
+	}
+
+	return l, nil
+}
+
+// Store keeps the token buckets. Take removes one token from the bucket of
+// each of keys if every one of them has a token, or reports how long until
+// they all do; a request denied by one bucket spends none of the others.
+// Implementations backed by a shared cache let several instances enforce
+// one limit together.
+type Store interface {
+	Take(ctx context.Context, keys []string, limit Limit) (allowed bool, retryAfter time.Duration, err error)
+}
+
+type Config struct {
+	Default    Limit
+	Operations map[string]Limit
+}
+
+// NewConfigFromEnv reads RATE_LIMIT_DEFAULT and RATE_LIMITS, a comma
+// separated list of operationId=limit, e.g.
+// RATE_LIMITS=SmsLogin=10/1m,SendLoginSmsCode=5/1m. A limit of "off"
+// disables limiting.
+func NewConfigFromEnv() (c *Config, err error) {
+	c = &Config{
+		Default: Limit{Requests: 120, Per: time.Minute},
+		Operations: map[string]Limit{
+			"SendLoginSmsCode":    {Requests: 5, Per: time.Minute},
+			"SmsLogin":            {Requests: 10, Per: time.Minute},
+			"PasswordLogin":       {Requests: 10, Per: time.Minute},
+			"VerifyMfa":           {Requests: 10, Per: time.Minute},
+			"FinishWebAuthnLogin": {Requests: 10, Per: time.Minute},
+			"UpdateUserIcon":      {Requests: 10, Per: time.Minute},
+		},
+	}
+
+	if v := os.Getenv("RATE_LIMIT_DEFAULT"); v != "" {
+		c.Default, err = ParseLimit(v)
+		if err != nil {
+			return nil, fmt.Errorf("RATE_LIMIT_DEFAULT env invalid: %v", err)
+		}
+	}
+
+	if v := os.Getenv("RATE_LIMITS"); v != "" {
+		for _, item := range strings.Split(v, ",") {
+			kv := strings.SplitN(strings.TrimSpace(item), "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("RATE_LIMITS env invalid: %q", item)
+			}
+
+			c.Operations[kv[0]], err = ParseLimit(kv[1])
+			if err != nil {
+				return nil, fmt.Errorf("RATE_LIMITS env invalid: %v", err)
+			}
+		}
+	}
+
+	return c, nil
+}
+
+func (c *Config) limit(operationId string) Limit {
+	if l, ok := c.Operations[operationId]; ok {
+		return l
+	}
+
+	return c.Default
+}
+
+// KeyFunc returns the identity a request is limited by, or "" if it has
+// none (e.g. an anonymous request has no user id).
+type KeyFunc func(r *http.Request) string
+
+type Limiter struct {
+	logger *zap.Logger
+	config *Config
+	store  Store
+}
+
+func NewLimiter(config *Config, store Store) *Limiter {
+	l := &Limiter{}
+	l.logger = log.TypedLogger(l)
+	l.config = config
+	l.store = store
+	return l
+}
+
+// Handler limits each operation, as named by operationId, separately for
+// every key, e.g. once per client IP and once per user. A request is let
+// through only if all its keys are within the limit. Requests for which
+// operationId returns "" are not limited; they are left for next to reject.
+func (l *Limiter) Handler(next http.Handler, operationId KeyFunc, keys map[string]KeyFunc) http.Handler {
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op := operationId(r)
+		if op == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		limit := l.config.limit(op)
+		if limit.Requests <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buckets := make([]string, 0, len(names))
+		for _, name := range names {
+			if k := keys[name](r); k != "" {
+				buckets = append(buckets, op+":"+name+":"+k)
+			}
+		}
+		if len(buckets) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		allowed, retryAfter, err := l.store.Take(r.Context(), buckets, limit)
+		if err != nil {
+			// fail open, an unavailable store must not take the API down
+			l.logger.Error("Take", zap.String("operationId", op), zap.Error(err))
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !allowed {
+			tooManyRequests(w, retryAfter)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func tooManyRequests(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(retryAfter.Seconds())), 10))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(map[string]string{
+		"code":    "TooManyRequests",
+		"message": "请求过于频繁，请稍后再试",
+	})
+}
@@ -0,0 +1,256 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    Limit
+		wantErr bool
+	}{
+		{"10/1m", Limit{Requests: 10, Per: time.Minute}, false},
+		{"1/500ms", Limit{Requests: 1, Per: time.Millisecond * 500}, false},
+		{"off", Limit{}, false},
+		{"0/1m", Limit{Per: time.Minute}, false},
+		{"", Limit{}, true},
+		{"10", Limit{}, true},
+		{"ten/1m", Limit{}, true},
+		{"10/minute", Limit{}, true},
+		{"10/0s", Limit{}, true},
+		{"10/-1m", Limit{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseLimit(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Fatalf("ParseLimit = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewConfigFromEnv(t *testing.T) {
+	t.Setenv("RATE_LIMIT_DEFAULT", "")
+	t.Setenv("RATE_LIMITS", "")
+	c, err := NewConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.limit("GetUserInfo"); got != (Limit{Requests: 120, Per: time.Minute}) {
+		t.Fatalf("default limit = %+v", got)
+	}
+	if got := c.limit("SmsLogin"); got != (Limit{Requests: 10, Per: time.Minute}) {
+		t.Fatalf("SmsLogin limit = %+v", got)
+	}
+
+	t.Setenv("RATE_LIMIT_DEFAULT", "off")
+	t.Setenv("RATE_LIMITS", "SmsLogin=3/1s, GetUserInfo=60/1h,PasswordLogin=off")
+	c, err = NewConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]Limit{
+		"SmsLogin":      {Requests: 3, Per: time.Second},
+		"GetUserInfo":   {Requests: 60, Per: time.Hour},
+		"PasswordLogin": {},
+		"ListSessions":  {},
+		// the built-in limits not named are kept
+		"SendLoginSmsCode": {Requests: 5, Per: time.Minute},
+	}
+	for op, l := range want {
+		if got := c.limit(op); got != l {
+			t.Fatalf("%s limit = %+v, want %+v", op, got, l)
+		}
+	}
+
+	for _, v := range []string{"SmsLogin", "SmsLogin=10", "SmsLogin=10/1m,=", "SmsLogin=x/1m"} {
+		t.Setenv("RATE_LIMIT_DEFAULT", "")
+		t.Setenv("RATE_LIMITS", v)
+		if _, err := NewConfigFromEnv(); err == nil {
+			t.Fatalf("RATE_LIMITS=%s accepted", v)
+		}
+	}
+
+	t.Setenv("RATE_LIMITS", "")
+	t.Setenv("RATE_LIMIT_DEFAULT", "120")
+	if _, err := NewConfigFromEnv(); err == nil {
+		t.Fatalf("RATE_LIMIT_DEFAULT=120 accepted")
+	}
+}
+
+// testStore records the keys it is asked for and fails if err is set.
+type testStore struct {
+	keys [][]string
+	err  error
+}
+
+func (s *testStore) Take(ctx context.Context, keys []string, limit Limit) (bool, time.Duration, error) {
+	s.keys = append(s.keys, keys)
+	return s.err == nil, time.Minute, s.err
+}
+
+// newTestHandler limits every request as operation op by its X-Ip and
+// X-User headers, and counts those that get through.
+func newTestHandler(config *Config, store Store, op string, served *int) http.Handler {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { *served++ })
+	return NewLimiter(config, store).Handler(next,
+		func(r *http.Request) string { return op },
+		map[string]KeyFunc{
+			"user": func(r *http.Request) string { return r.Header.Get("X-User") },
+			"ip":   func(r *http.Request) string { return r.Header.Get("X-Ip") },
+		})
+}
+
+func serveTest(h http.Handler, ip string, user string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("X-Ip", ip)
+	r.Header.Set("X-User", user)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestHandlerTooManyRequests(t *testing.T) {
+	config := &Config{Default: Limit{Requests: 1, Per: time.Minute}}
+	served := 0
+	h := newTestHandler(config, NewMemoryStore(), "SmsLogin", &served)
+
+	if w := serveTest(h, "10.0.0.1", ""); w.Code != http.StatusOK {
+		t.Fatalf("first request got %d", w.Code)
+	}
+
+	w := serveTest(h, "10.0.0.1", "")
+	if w.Code != http.StatusTooManyRequests || served != 1 {
+		t.Fatalf("second request got %d, %d served", w.Code, served)
+	}
+	if v := w.Header().Get("Retry-After"); v != "60" {
+		t.Fatalf("Retry-After = %s, want 60", v)
+	}
+	body := map[string]string{}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil || body["code"] != "TooManyRequests" {
+		t.Fatalf("body = %v, %v", body, err)
+	}
+}
+
+func TestTooManyRequestsRetryAfter(t *testing.T) {
+	tests := []struct {
+		retryAfter time.Duration
+		want       string
+	}{
+		{time.Second, "1"},
+		// rounded up, so a client waiting that long gets a token
+		{time.Millisecond * 1200, "2"},
+		{time.Millisecond, "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.retryAfter.String(), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tooManyRequests(w, tt.retryAfter)
+			if v := w.Header().Get("Retry-After"); v != tt.want {
+				t.Fatalf("Retry-After = %s, want %s", v, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlerDenialSpendsNoOtherBucket(t *testing.T) {
+	config := &Config{Default: Limit{Requests: 2, Per: time.Minute}}
+	served := 0
+	h := newTestHandler(config, NewMemoryStore(), "SmsLogin", &served)
+
+	serveTest(h, "10.0.0.1", "user1")
+	serveTest(h, "10.0.0.1", "user1")
+
+	// user1 is out of tokens; the request from another IP is denied without
+	// spending that IP's bucket
+	if w := serveTest(h, "10.0.0.2", "user1"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("third request of user1 got %d", w.Code)
+	}
+	for i := 0; i < 2; i++ {
+		if w := serveTest(h, "10.0.0.2", "user2"); w.Code != http.StatusOK {
+			t.Fatalf("request %d from 10.0.0.2 got %d", i, w.Code)
+		}
+	}
+
+	// and the reverse, the exhausted IP spends nothing of user3
+	if w := serveTest(h, "10.0.0.1", "user3"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request from 10.0.0.1 got %d", w.Code)
+	}
+	for i := 0; i < 2; i++ {
+		if w := serveTest(h, "10.0.0.3", "user3"); w.Code != http.StatusOK {
+			t.Fatalf("request %d of user3 got %d", i, w.Code)
+		}
+	}
+}
+
+func TestHandlerKeys(t *testing.T) {
+	config := &Config{Default: Limit{Requests: 1, Per: time.Minute}}
+	store := &testStore{}
+	served := 0
+	h := newTestHandler(config, store, "SmsLogin", &served)
+
+	serveTest(h, "10.0.0.1", "user1")
+	serveTest(h, "10.0.0.1", "")
+	serveTest(h, "", "")
+
+	// in the order of their names, without those a request has none of,
+	// and requests with no key at all aren't limited
+	want := [][]string{
+		{"SmsLogin:ip:10.0.0.1", "SmsLogin:user:user1"},
+		{"SmsLogin:ip:10.0.0.1"},
+	}
+	if !reflect.DeepEqual(store.keys, want) {
+		t.Fatalf("keys = %v, want %v", store.keys, want)
+	}
+	if served != 3 {
+		t.Fatalf("%d served, want 3", served)
+	}
+}
+
+func TestHandlerUnlimited(t *testing.T) {
+	config := &Config{Default: Limit{Requests: 1, Per: time.Minute}, Operations: map[string]Limit{"OauthJwks": {}}}
+	store := &testStore{}
+
+	for _, op := range []string{"", "OauthJwks"} {
+		served := 0
+		h := newTestHandler(config, store, op, &served)
+		for i := 0; i < 3; i++ {
+			serveTest(h, "10.0.0.1", "")
+		}
+		if served != 3 {
+			t.Fatalf("operation %q: %d served, want 3", op, served)
+		}
+	}
+	if len(store.keys) != 0 {
+		t.Fatalf("store asked for %v", store.keys)
+	}
+}
+
+func TestHandlerFailOpen(t *testing.T) {
+	config := &Config{Default: Limit{Requests: 1, Per: time.Minute}}
+	served := 0
+	h := newTestHandler(config, &testStore{err: errors.New("cache unavailable")}, "SmsLogin", &served)
+
+	for i := 0; i < 3; i++ {
+		if w := serveTest(h, "10.0.0.1", "user1"); w.Code != http.StatusOK {
+			t.Fatalf("request %d got %d", i, w.Code)
+		}
+	}
+	if served != 3 {
+		t.Fatalf("%d served, want 3", served)
+	}
+}
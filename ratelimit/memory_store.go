@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time
+}
+
+// MemoryStore keeps buckets in process, so each instance enforces its limits
+// on its own.
+type MemoryStore struct {
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	// now is time.Now, except in tests
+	now func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), lastSweep: time.Now(), now: time.Now}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, keys []string, limit Limit) (allowed bool, retryAfter time.Duration, err error) {
+	now := s.now()
+	capacity := float64(limit.Requests)
+	rate := capacity / limit.Per.Seconds()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.sweep(now)
+
+	allowed = true
+	list := make([]*bucket, len(keys))
+	for i, key := range keys {
+		b, ok := s.buckets[key]
+		if !ok {
+			b = &bucket{tokens: capacity, last: now}
+			s.buckets[key] = b
+		}
+
+		b.tokens += now.Sub(b.last).Seconds() * rate
+		if b.tokens > capacity {
+			b.tokens = capacity
+		}
+		b.last = now
+
+		if b.tokens < 1 {
+			allowed = false
+			if wait := time.Duration((1 - b.tokens) / rate * float64(time.Second)); wait > retryAfter {
+				retryAfter = wait
+			}
+		}
+		list[i] = b
+	}
+	if !allowed {
+		return false, retryAfter, nil
+	}
+
+	for _, b := range list {
+		b.tokens--
+		b.full = now.Add(time.Duration((capacity - b.tokens) / rate * float64(time.Second)))
+	}
+	return true, 0, nil
+}
+
+// sweep drops buckets that have refilled completely, which are the same as
+// no bucket at all.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+
+	for k, b := range s.buckets {
+		if now.After(b.full) {
+			delete(s.buckets, k)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newTestMemoryStore returns a MemoryStore on a clock the test moves.
+func newTestMemoryStore() (*MemoryStore, *time.Time) {
+	now := time.Unix(1700000000, 0)
+	s := NewMemoryStore()
+	s.lastSweep = now
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func TestMemoryStoreRefill(t *testing.T) {
+	s, now := newTestMemoryStore()
+	limit := Limit{Requests: 2, Per: time.Second * 10}
+	take := func() (bool, time.Duration) {
+		t.Helper()
+
+		allowed, retryAfter, err := s.Take(context.Background(), []string{"k"}, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the refill is computed in floating point
+		return allowed, retryAfter.Round(time.Millisecond)
+	}
+
+	// a burst of up to Requests
+	for i := 0; i < 2; i++ {
+		if allowed, _ := take(); !allowed {
+			t.Fatalf("take %d denied", i)
+		}
+	}
+	if allowed, retryAfter := take(); allowed || retryAfter != time.Second*5 {
+		t.Fatalf("take = %v, %v, want denied for 5s", allowed, retryAfter)
+	}
+
+	// one token every 5s
+	*now = now.Add(time.Second * 3)
+	if allowed, retryAfter := take(); allowed || retryAfter != time.Second*2 {
+		t.Fatalf("take = %v, %v, want denied for 2s", allowed, retryAfter)
+	}
+	*now = now.Add(time.Second * 2)
+	if allowed, _ := take(); !allowed {
+		t.Fatalf("take denied after the refill")
+	}
+	if allowed, _ := take(); allowed {
+		t.Fatalf("second take allowed after one token refilled")
+	}
+
+	// never more than Requests saved up
+	*now = now.Add(time.Hour)
+	for i := 0; i < 2; i++ {
+		if allowed, _ := take(); !allowed {
+			t.Fatalf("take %d denied", i)
+		}
+	}
+	if allowed, _ := take(); allowed {
+		t.Fatalf("take beyond the burst allowed")
+	}
+}
+
+func TestMemoryStoreTakeAll(t *testing.T) {
+	s, now := newTestMemoryStore()
+	limit := Limit{Requests: 1, Per: time.Second * 10}
+	ctx := context.Background()
+
+	if allowed, _, _ := s.Take(ctx, []string{"a"}, limit); !allowed {
+		t.Fatalf("take of a denied")
+	}
+	*now = now.Add(time.Second * 4)
+
+	// a is empty, so b isn't spent, and the wait is a's
+	allowed, retryAfter, _ := s.Take(ctx, []string{"b", "a"}, limit)
+	if allowed || retryAfter.Round(time.Millisecond) != time.Second*6 {
+		t.Fatalf("take = %v, %v, want denied for 6s", allowed, retryAfter)
+	}
+	if allowed, _, _ := s.Take(ctx, []string{"b"}, limit); !allowed {
+		t.Fatalf("b was spent by a denied take")
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	s, now := newTestMemoryStore()
+	limit := Limit{Requests: 2, Per: time.Second * 10}
+	ctx := context.Background()
+
+	s.Take(ctx, []string{"a"}, limit)
+	*now = now.Add(time.Minute)
+	s.Take(ctx, []string{"b"}, limit)
+
+	// a refilled completely and is dropped, b isn't
+	if _, ok := s.buckets["a"]; ok {
+		t.Fatalf("refilled bucket kept")
+	}
+	if _, ok := s.buckets["b"]; !ok {
+		t.Fatalf("bucket in use dropped")
+	}
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/NeuronFramework/errors"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// user.user_icon is varchar(256)
+const userIconMaxLength = 256
+
+const avatarJpegQuality = 85
+
+// avatarContentTypes are the uploads accepted, by sniffed content type.
+var avatarContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+type AvatarConfig struct {
+	// MaxSize is the largest upload accepted, in bytes.
+	MaxSize int64
+	// MaxPixels caps the width times height of uploads, so that a small
+	// file can't decode into a huge image.
+	MaxPixels int64
+	// Sizes are the edge lengths in pixels of the square variants made of
+	// each upload, largest first. user_icon points at the largest.
+	Sizes []int
+}
+
+// NewAvatarConfigFromEnv reads AVATAR_MAX_SIZE, AVATAR_MAX_PIXELS and
+// AVATAR_SIZES, a comma separated list of edge lengths.
+func NewAvatarConfigFromEnv() (c *AvatarConfig, err error) {
+	c = &AvatarConfig{
+		MaxSize:   5 << 20,
+		MaxPixels: 25000000,
+		Sizes:     []int{256, 128, 64},
+	}
+
+	limits := []struct {
+		key string
+		v   *int64
+	}{
+		{"AVATAR_MAX_SIZE", &c.MaxSize},
+		{"AVATAR_MAX_PIXELS", &c.MaxPixels},
+	}
+	for _, l := range limits {
+		if v := os.Getenv(l.key); v != "" {
+			*l.v, err = strconv.ParseInt(v, 10, 64)
+			if err != nil || *l.v <= 0 {
+				return nil, fmt.Errorf("%s env invalid: %s", l.key, v)
+			}
+		}
+	}
+
+	if v := os.Getenv("AVATAR_SIZES"); v != "" {
+		c.Sizes = nil
+		for _, item := range strings.Split(v, ",") {
+			size, err := strconv.Atoi(strings.TrimSpace(item))
+			if err != nil || size < 16 || size > 2048 {
+				return nil, fmt.Errorf("AVATAR_SIZES env invalid: %s", v)
+			}
+			c.Sizes = append(c.Sizes, size)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(c.Sizes)))
+	}
+
+	return c, nil
+}
+
+// avatarVariant is one size of an avatar, ready to be stored.
+type avatarVariant struct {
+	name        string
+	contentType string
+	data        []byte
+}
+
+// makeAvatarVariants checks an uploaded image and makes the variants of
+// c.Sizes from its centre square. Decoding and encoding again drops any
+// metadata the upload carried, e.g. the EXIF location of a photo.
+func (c *AvatarConfig) makeAvatarVariants(data []byte) (variants []*avatarVariant, err error) {
+	if !avatarContentTypes[http.DetectContentType(data)] {
+		return nil, errors.BadRequest("InvalidAvatar", "头像仅支持JPEG、PNG、GIF或WebP图片")
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.BadRequest("InvalidAvatar", "头像图片无法识别")
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > c.MaxPixels {
+		return nil, errors.BadRequest("InvalidAvatar", "头像图片尺寸过大")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.BadRequest("InvalidAvatar", "头像图片无法识别")
+	}
+
+	orientation := 1
+	if format == "jpeg" {
+		orientation = jpegOrientation(data)
+	}
+
+	// the centre square is the same whichever way the image is turned, so
+	// it is only oriented once it has been scaled down
+	b := img.Bounds()
+	edge := b.Dx()
+	if b.Dy() < edge {
+		edge = b.Dy()
+	}
+	x := b.Min.X + (b.Dx()-edge)/2
+	y := b.Min.Y + (b.Dy()-edge)/2
+	square := image.Rect(x, y, x+edge, y+edge)
+
+	// JPEG can't keep transparency
+	contentType, ext := "image/png", "png"
+	if format == "jpeg" {
+		contentType, ext = "image/jpeg", "jpg"
+	}
+
+	for _, size := range c.Sizes {
+		dst := image.NewRGBA(image.Rect(0, 0, size, size))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), img, square, draw.Src, nil)
+		dst = orientSquare(dst, orientation)
+
+		buf := bytes.Buffer{}
+		if format == "jpeg" {
+			err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: avatarJpegQuality})
+		} else {
+			err = png.Encode(&buf, dst)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		variants = append(variants, &avatarVariant{
+			name:        strconv.Itoa(size) + "." + ext,
+			contentType: contentType,
+			data:        buf.Bytes(),
+		})
+	}
+
+	return variants, nil
+}
+
+// orientSquare applies to src, a square image stored with the given EXIF
+// orientation, the transform that shows it the right way up.
+func orientSquare(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	n := src.Bounds().Dx()
+	dst := image.NewRGBA(src.Bounds())
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			sx, sy := x, y
+			switch orientation {
+			case 2: // flip horizontally
+				sx = n - 1 - x
+			case 3: // rotate 180°
+				sx, sy = n-1-x, n-1-y
+			case 4: // flip vertically
+				sy = n - 1 - y
+			case 5: // transpose
+				sx, sy = y, x
+			case 6: // rotate 90° clockwise
+				sx, sy = y, n-1-x
+			case 7: // transverse
+				sx, sy = n-1-y, n-1-x
+			case 8: // rotate 90° counter-clockwise
+				sx, sy = n-1-y, x
+			}
+			dst.SetRGBA(x, y, src.RGBAAt(sx, sy))
+		}
+	}
+
+	return dst
+}
+
+// jpegOrientation returns the EXIF orientation of a JPEG file, or 1, the
+// image as stored, if it has none.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xff || data[1] != 0xd8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		marker := data[i+1]
+		if data[i] != 0xff || marker == 0xda || marker == 0xd9 {
+			// metadata precedes the start of scan
+			return 1
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+
+		if marker == 0xe1 {
+			if orientation := exifOrientation(data[i+4 : i+2+length]); orientation != 0 {
+				return orientation
+			}
+		}
+
+		i += 2 + length
+	}
+
+	return 1
+}
+
+// exifOrientation reads the orientation tag of the first IFD of an APP1
+// segment, or returns 0 if it has none.
+func exifOrientation(b []byte) int {
+	if len(b) < 14 || string(b[:6]) != "Exif\x00\x00" {
+		return 0
+	}
+
+	tiff := b[6:]
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 0
+			}
+			return orientation
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"github.com/NeuronFramework/errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var (
+	errAvatarType     = errors.BadRequest("InvalidAvatar", "头像仅支持JPEG、PNG、GIF或WebP图片")
+	errAvatarTooLarge = errors.BadRequest("AvatarTooLarge", "头像图片过大")
+	errAvatarPixels   = errors.BadRequest("InvalidAvatar", "头像图片尺寸过大")
+)
+
+// testWebp is a 1x1 lossless WebP; the webp package has no encoder.
+var testWebp, _ = base64.StdEncoding.DecodeString("UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA==")
+
+// testAvatarSecret is metadata that must not survive into the variants.
+const testAvatarSecret = "GPS 31.2304N 121.4737E"
+
+// testBlobStore keeps blobs in memory.
+type testBlobStore struct {
+	mutex sync.Mutex
+	blobs map[string][]byte
+}
+
+func (s *testBlobStore) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.blobs[key] = data
+	return nil
+}
+
+func (s *testBlobStore) DeletePrefix(ctx context.Context, prefix string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for k := range s.blobs {
+		if strings.HasPrefix(k, prefix) {
+			delete(s.blobs, k)
+		}
+	}
+	return nil
+}
+
+func (s *testBlobStore) URL(key string) string {
+	return "https://cdn.example.com/" + key
+}
+
+// halvesImage is w x h, red on its left half and blue on its right.
+func halvesImage(w int, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= w/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func encodeTestImage(t *testing.T, format string, img image.Image) []byte {
+	t.Helper()
+
+	buf := bytes.Buffer{}
+	var err error
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95})
+	case "png":
+		err = png.Encode(&buf, img)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	default:
+		t.Fatalf("unknown format %s", format)
+	}
+	assertError(t, err, nil)
+	return buf.Bytes()
+}
+
+// withExif inserts an APP1 segment after the SOI marker of a JPEG file,
+// holding an orientation tag followed by extra.
+func withExif(data []byte, orientation uint16, extra string) []byte {
+	ifd := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	ifd = binary.BigEndian.AppendUint16(ifd, 1)
+	ifd = binary.BigEndian.AppendUint16(ifd, 0x0112)
+	ifd = binary.BigEndian.AppendUint16(ifd, 3)
+	ifd = binary.BigEndian.AppendUint32(ifd, 1)
+	ifd = binary.BigEndian.AppendUint16(ifd, orientation)
+	ifd = append(ifd, 0, 0, 0, 0, 0, 0)
+	payload := append(append([]byte("Exif\x00\x00"), ifd...), extra...)
+
+	segment := []byte{0xff, 0xe1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	return append(append(append([]byte{}, data[:2]...), segment...), data[2:]...)
+}
+
+func newTestAvatarConfig() *AvatarConfig {
+	return &AvatarConfig{MaxSize: 1 << 20, MaxPixels: 1000000, Sizes: []int{64, 32}}
+}
+
+func TestMakeAvatarVariantsFormats(t *testing.T) {
+	img := halvesImage(80, 60)
+	tests := []struct {
+		name            string
+		data            []byte
+		wantContentType string
+		wantExt         string
+	}{
+		{"jpeg", encodeTestImage(t, "jpeg", img), "image/jpeg", "jpg"},
+		{"png", encodeTestImage(t, "png", img), "image/png", "png"},
+		{"gif", encodeTestImage(t, "gif", img), "image/png", "png"},
+		{"webp", testWebp, "image/png", "png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestAvatarConfig()
+			variants, err := c.makeAvatarVariants(tt.data)
+			assertError(t, err, nil)
+			if len(variants) != len(c.Sizes) {
+				t.Fatalf("%d variants, want %d", len(variants), len(c.Sizes))
+			}
+
+			for i, v := range variants {
+				size := c.Sizes[i]
+				if want := strconv.Itoa(size) + "." + tt.wantExt; v.name != want || v.contentType != tt.wantContentType {
+					t.Fatalf("variant %s (%s), want %s (%s)", v.name, v.contentType, want, tt.wantContentType)
+				}
+
+				decoded, format, err := image.Decode(bytes.NewReader(v.data))
+				assertError(t, err, nil)
+				if "image/"+format != tt.wantContentType {
+					t.Fatalf("variant %s is %s", v.name, format)
+				}
+				if b := decoded.Bounds(); b.Dx() != size || b.Dy() != size {
+					t.Fatalf("variant %s is %dx%d", v.name, b.Dx(), b.Dy())
+				}
+			}
+		})
+	}
+}
+
+func TestMakeAvatarVariantsRejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxPixels int64
+		data      []byte
+		want      error
+	}{
+		{"text", 1000000, []byte("just some text"), errAvatarType},
+		{"svg", 1000000, []byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`), errAvatarType},
+		{"too many pixels", 80*60 - 1, encodeTestImage(t, "png", halvesImage(80, 60)), errAvatarPixels},
+		{"too many pixels for its size", 1000000, encodeTestImage(t, "png", image.NewGray(image.Rect(0, 0, 2000, 1000))), errAvatarPixels},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestAvatarConfig()
+			c.MaxPixels = tt.maxPixels
+
+			_, err := c.makeAvatarVariants(tt.data)
+			assertError(t, err, tt.want)
+		})
+	}
+}
+
+func TestMakeAvatarVariantsOrientation(t *testing.T) {
+	// the left half is red as stored; each orientation tells where it
+	// should end up
+	tests := []struct {
+		orientation uint16
+		// red is the side of the variant that should be red
+		red string
+	}{
+		{1, "left"},
+		{2, "right"},
+		{3, "right"},
+		{6, "top"},
+		{8, "bottom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(int(tt.orientation)), func(t *testing.T) {
+			data := withExif(encodeTestImage(t, "jpeg", halvesImage(64, 64)), tt.orientation, "")
+			if got := jpegOrientation(data); got != int(tt.orientation) {
+				t.Fatalf("jpegOrientation = %d, want %d", got, tt.orientation)
+			}
+
+			variants, err := newTestAvatarConfig().makeAvatarVariants(data)
+			assertError(t, err, nil)
+			img, _, err := image.Decode(bytes.NewReader(variants[0].data))
+			assertError(t, err, nil)
+
+			n := img.Bounds().Dx()
+			points := map[string]image.Point{
+				"left":   {n / 8, n / 2},
+				"right":  {n - 1 - n/8, n / 2},
+				"top":    {n / 2, n / 8},
+				"bottom": {n / 2, n - 1 - n/8},
+			}
+			opposite := map[string]string{"left": "right", "right": "left", "top": "bottom", "bottom": "top"}
+
+			isRed := func(side string) bool {
+				r, _, b, _ := img.At(points[side].X, points[side].Y).RGBA()
+				return r > b
+			}
+			if !isRed(tt.red) || isRed(opposite[tt.red]) {
+				t.Fatalf("want red on the %s only", tt.red)
+			}
+		})
+	}
+}
+
+func TestMakeAvatarVariantsStripsMetadata(t *testing.T) {
+	data := withExif(encodeTestImage(t, "jpeg", halvesImage(64, 64)), 1, testAvatarSecret)
+
+	variants, err := newTestAvatarConfig().makeAvatarVariants(data)
+	assertError(t, err, nil)
+	for _, v := range variants {
+		if bytes.Contains(v.data, []byte("Exif")) || bytes.Contains(v.data, []byte(testAvatarSecret)) {
+			t.Fatalf("variant %s kept the EXIF segment", v.name)
+		}
+	}
+}
+
+func TestUpdateUserIconTooLarge(t *testing.T) {
+	s, _, _ := newTestService(t)
+	s.SetBlobStore(&testBlobStore{blobs: map[string][]byte{}})
+
+	data := encodeTestImage(t, "png", halvesImage(80, 60))
+	s.avatarConfig.MaxSize = int64(len(data)) - 1
+
+	_, err := s.UpdateUserIcon(newTestContext(), "user1", bytes.NewReader(data))
+	assertError(t, err, errAvatarTooLarge)
+}
+
+func TestUpdateUserIcon(t *testing.T) {
+	s, storage, sender := newTestService(t)
+	store := &testBlobStore{blobs: map[string][]byte{}}
+	s.SetBlobStore(store)
+	s.avatarConfig = newTestAvatarConfig()
+
+	_, user := smsLogin(t, s, sender, "13800000000")
+
+	data := encodeTestImage(t, "png", halvesImage(80, 60))
+	first, err := s.UpdateUserIcon(newTestContext(), user.UserId, bytes.NewReader(data))
+	assertError(t, err, nil)
+	if len(store.blobs) != len(s.avatarConfig.Sizes) {
+		t.Fatalf("%d blobs stored, want %d", len(store.blobs), len(s.avatarConfig.Sizes))
+	}
+
+	second, err := s.UpdateUserIcon(newTestContext(), user.UserId, bytes.NewReader(data))
+	assertError(t, err, nil)
+	if second.Icon == first.Icon {
+		t.Fatalf("second upload kept the URL %s", first.Icon)
+	}
+
+	// the replaced avatar is deleted
+	for k := range store.blobs {
+		if !strings.HasPrefix(s.blobStore.URL(k), strings.TrimSuffix(second.Icon, "64.png")) {
+			t.Fatalf("blob %s of the old avatar left behind", k)
+		}
+	}
+
+	dbUser, err := storage.Users().GetByUserId(context.Background(), user.UserId)
+	assertError(t, err, nil)
+	if dbUser.UserIcon != second.Icon {
+		t.Fatalf("user_icon = %s, want %s", dbUser.UserIcon, second.Icon)
+	}
+}
@@ -14,6 +14,29 @@ func fromUserInfo(p *user_db.User) (r *models.UserInfo) {
 	r.UserID = p.UserId
 	r.Name = p.UserName
 	r.Icon = p.UserIcon
+	r.Bio = p.Bio
+	r.Gender = p.Gender
+	r.Birthday = p.Birthday
+	r.Locale = p.Locale
+	r.Timezone = p.Timezone
+
+	return r
+}
+
+// fromSession converts the live refresh token of a session, which was
+// issued at its latest refresh.
+func fromSession(p *user_db.RefreshToken) (r *models.Session) {
+	if p == nil {
+		return nil
+	}
+
+	r = &models.Session{}
+	r.SessionId = p.SessionId
+	r.ClientId = p.ClientId
+	r.UserAgent = p.UserAgent
+	r.ClientIp = p.ClientIp
+	r.LoginTime = p.LoginTime
+	r.LastUsedTime = p.CreateTime
 
 	return r
 }
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/NeuronUser/user/storages/user_db"
+	"go.uber.org/zap"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const janitorLockName = "neuron-user.janitor"
+
+type JanitorConfig struct {
+	Interval  time.Duration
+	BatchSize int64
+	// Retention is how long a row is kept after its last update, by table.
+	// Tables that are missing or have a zero retention are never swept.
+	Retention map[string]time.Duration
+}
+
+// NewJanitorConfigFromEnv reads JANITOR_INTERVAL, JANITOR_BATCH_SIZE and
+// JANITOR_RETENTION_<TABLE>, e.g. JANITOR_RETENTION_REFRESH_TOKEN=720h.
+// JANITOR_INTERVAL=0 disables the janitor.
+func NewJanitorConfigFromEnv() (c *JanitorConfig, err error) {
+	c = &JanitorConfig{
+		Interval:  time.Minute * 10,
+		BatchSize: 1000,
+		Retention: map[string]time.Duration{
+			// kept a while after expiry so send quotas can count them
+			user_db.LOGIN_SMS_CODE_TABLE_NAME:   time.Hour * 48,
+			user_db.OAUTH_STATE_TABLE_NAME:      time.Hour * 24,
+			user_db.WEBAUTHN_SESSION_TABLE_NAME: time.Hour,
+			// codes live minutes; kept a day so a replayed code still revokes
+			// the tokens it was exchanged for
+			user_db.OAUTH_AUTHORIZATION_CODE_TABLE_NAME: time.Hour * 24,
+			user_db.ACCESS_TOKEN_TABLE_NAME:             time.Hour * 24 * 7,
+			// refresh tokens are touched on every refresh, so this is the
+			// longest a session may stay idle
+			user_db.REFRESH_TOKEN_TABLE_NAME: time.Hour * 24 * 30,
+		},
+	}
+
+	if v := os.Getenv("JANITOR_INTERVAL"); v != "" {
+		c.Interval, err = time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("JANITOR_INTERVAL env invalid: %v", err)
+		}
+	}
+
+	if v := os.Getenv("JANITOR_BATCH_SIZE"); v != "" {
+		c.BatchSize, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || c.BatchSize <= 0 {
+			return nil, fmt.Errorf("JANITOR_BATCH_SIZE env invalid: %s", v)
+		}
+	}
+
+	for table := range c.Retention {
+		key := "JANITOR_RETENTION_" + strings.ToUpper(table)
+		if v := os.Getenv(key); v != "" {
+			c.Retention[table], err = time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s env invalid: %v", key, err)
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// RunJanitor deletes expired rows every config.Interval until ctx is done.
+// When several instances share a database only the one holding the janitor
+// lock sweeps in a given round.
+func (s *UserService) RunJanitor(ctx context.Context, config *JanitorConfig) {
+	if config.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+
+	for {
+		locked, err := s.storage.Expiry().WithLock(ctx, janitorLockName, func() error {
+			return s.sweep(ctx, config)
+		})
+		if err != nil {
+			s.logger.Error("janitor", zap.Error(err))
+		} else if !locked {
+			s.logger.Debug("janitor lock held by another instance")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sweep deletes in batches of config.BatchSize so that no single statement
+// holds locks on a large range of idx_update.
+func (s *UserService) sweep(ctx context.Context, config *JanitorConfig) (err error) {
+	for table, retention := range config.Retention {
+		if retention <= 0 {
+			continue
+		}
+
+		before := time.Now().Add(-retention)
+		total := int64(0)
+		for {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			n, err := s.storage.Expiry().DeleteExpired(ctx, table, before, config.BatchSize)
+			if err != nil {
+				return fmt.Errorf("%s: %v", table, err)
+			}
+
+			total += n
+			if n < config.BatchSize {
+				break
+			}
+		}
+
+		if total > 0 {
+			s.logger.Info("janitor", zap.String("table", table), zap.Int64("deleted", total))
+		}
+	}
+
+	return nil
+}
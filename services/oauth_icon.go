@@ -0,0 +1,365 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"go.uber.org/zap"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const oauthIconMirrorLockName = "neuron-user.oauth-icon-mirror"
+
+// oauthIconMaxRedirects is how many redirects an icon URL may follow.
+const oauthIconMaxRedirects = 3
+
+// errOauthIconAddress is returned for an icon URL that leads to an address
+// other than a public one.
+var errOauthIconAddress = errors.New("oauth icon address not public")
+
+// nonPublicNetworks are the ranges not covered by the net.IP predicates in
+// publicAddress.
+var nonPublicNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("192.0.0.0/24"),
+	mustParseCIDR("198.18.0.0/15"),
+	mustParseCIDR("240.0.0.0/4"),
+	mustParseCIDR("64:ff9b::/96"),
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// HttpClient fetches provider avatars. *http.Client implements it; tests
+// can point it at a stand-in server.
+type HttpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// newOauthIconHttpClient returns the client provider avatars are fetched
+// with. The URLs come from the providers, so it only connects to public
+// addresses, checked after DNS resolution, and follows few redirects.
+func newOauthIconHttpClient() *http.Client {
+	dialer := &net.Dialer{Timeout: time.Second * 5, Control: oauthIconDialControl}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would be dialed instead of the icon host
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:       time.Second * 10,
+		Transport:     transport,
+		CheckRedirect: checkOauthIconRedirect,
+	}
+}
+
+// oauthIconDialControl refuses connections to addresses that aren't public.
+func oauthIconDialControl(network string, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !publicAddress(ip) {
+		return errOauthIconAddress
+	}
+
+	return nil
+}
+
+// checkOauthIconRedirect limits redirects, and refuses those to another
+// scheme or to an address that isn't public. Host names are checked when
+// they are dialed.
+func checkOauthIconRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > oauthIconMaxRedirects {
+		return fmt.Errorf("stopped after %d redirects", oauthIconMaxRedirects)
+	}
+	if req.URL.Scheme != "https" && req.URL.Scheme != "http" {
+		return errOauthIconAddress
+	}
+	if ip := net.ParseIP(req.URL.Hostname()); ip != nil && !publicAddress(ip) {
+		return errOauthIconAddress
+	}
+
+	return nil
+}
+
+// publicAddress reports whether ip is routable on the internet, as opposed
+// to loopback, private, link-local or otherwise reserved.
+func publicAddress(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	for _, n := range nonPublicNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type OauthIconMirrorConfig struct {
+	Interval  time.Duration
+	BatchSize int64
+}
+
+// NewOauthIconMirrorConfigFromEnv reads OAUTH_ICON_MIRROR_INTERVAL and
+// OAUTH_ICON_MIRROR_BATCH_SIZE. OAUTH_ICON_MIRROR_INTERVAL=0 disables the
+// mirror.
+func NewOauthIconMirrorConfigFromEnv() (c *OauthIconMirrorConfig, err error) {
+	c = &OauthIconMirrorConfig{
+		Interval:  time.Minute * 10,
+		BatchSize: 100,
+	}
+
+	if v := os.Getenv("OAUTH_ICON_MIRROR_INTERVAL"); v != "" {
+		c.Interval, err = time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("OAUTH_ICON_MIRROR_INTERVAL env invalid: %v", err)
+		}
+	}
+
+	if v := os.Getenv("OAUTH_ICON_MIRROR_BATCH_SIZE"); v != "" {
+		c.BatchSize, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || c.BatchSize <= 0 {
+			return nil, fmt.Errorf("OAUTH_ICON_MIRROR_BATCH_SIZE env invalid: %s", v)
+		}
+	}
+
+	return c, nil
+}
+
+// RunOauthIconMirror copies provider avatars into the blob store every
+// config.Interval until ctx is done, so that user_icon doesn't point at
+// provider URLs that expire or track users. OAuth logins only need to keep
+// oauth_icon up to date; a changed icon is copied in the next round. Like
+// the janitor, only one instance sharing a database mirrors in a round.
+func (s *UserService) RunOauthIconMirror(ctx context.Context, config *OauthIconMirrorConfig) {
+	if config.Interval <= 0 || s.blobStore == nil {
+		return
+	}
+
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+
+	for {
+		locked, err := s.storage.Expiry().WithLock(ctx, oauthIconMirrorLockName, func() error {
+			return s.mirrorOauthIcons(ctx, config.BatchSize)
+		})
+		if err != nil {
+			s.logger.Error("oauthIconMirror", zap.Error(err))
+		} else if !locked {
+			s.logger.Debug("oauth icon mirror lock held by another instance")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// mirrorOauthIcons pages through the accounts and mirrors those whose
+// oauth_icon changed since it was last copied. An account that fails is
+// logged and tried again in the next round.
+func (s *UserService) mirrorOauthIcons(ctx context.Context, batchSize int64) (err error) {
+	afterId := uint64(0)
+	mirrored := 0
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		list, err := s.storage.OauthAccounts().List(ctx, afterId, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range list {
+			afterId = v.Id
+			if v.OauthIcon == v.MirrorSource {
+				continue
+			}
+
+			err = s.mirrorOauthIcon(ctx, v)
+			if err != nil {
+				s.logger.Warn("mirrorOauthIcon", zap.Uint64("id", v.Id), zap.Error(err))
+				continue
+			}
+			mirrored++
+		}
+
+		if int64(len(list)) < batchSize {
+			break
+		}
+	}
+
+	if mirrored > 0 {
+		s.logger.Info("oauthIconMirror", zap.Int("mirrored", mirrored))
+	}
+
+	return nil
+}
+
+// usesOauthIcon reports whether user still has the icon account gave them,
+// as opposed to one they chose.
+func usesOauthIcon(user *user_db.User, account *user_db.OauthAccount) bool {
+	return user.UserIcon == "" ||
+		user.UserIcon == account.OauthIcon ||
+		user.UserIcon == account.MirrorSource ||
+		account.MirrorIcon != "" && user.UserIcon == account.MirrorIcon
+}
+
+// mirrorOauthIcon copies the oauth_icon of account and makes the copy the
+// user's icon, unless the user chose another one. The previous copy is
+// deleted once it is no longer used.
+func (s *UserService) mirrorOauthIcon(ctx context.Context, account *user_db.OauthAccount) (err error) {
+	dbUser, err := s.storage.Users().GetByUserId(ctx, account.UserId)
+	if err != nil {
+		return err
+	}
+
+	var variants []*avatarVariant
+	if account.OauthIcon != "" && dbUser != nil && usesOauthIcon(dbUser, account) {
+		variants, err = s.fetchOauthIcon(ctx, account.OauthIcon)
+		if err != nil {
+			return err
+		}
+	}
+
+	prefix, icon := "", ""
+	if len(variants) > 0 {
+		prefix, icon, err = s.storeAvatar(ctx, account.UserId, variants)
+		if err != nil {
+			return err
+		}
+	}
+
+	used := false
+	oldMirror := ""
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		used = false
+
+		dbAccount, err := tx.OauthAccounts().GetByOpenIdForUpdate(ctx, account.OauthProvider, account.OauthOpenId)
+		if err != nil {
+			return err
+		}
+		if dbAccount == nil || dbAccount.OauthIcon != account.OauthIcon {
+			// changed meanwhile; the next round copies the new icon
+			return nil
+		}
+
+		if icon != "" {
+			dbUser, err := tx.Users().GetByUserIdForUpdate(ctx, dbAccount.UserId)
+			if err != nil {
+				return err
+			}
+			if dbUser != nil && usesOauthIcon(dbUser, dbAccount) {
+				dbUser.UserIcon = icon
+				err = tx.Users().Update(ctx, dbUser)
+				if err != nil {
+					return err
+				}
+
+				used = true
+				oldMirror = dbAccount.MirrorIcon
+				dbAccount.MirrorIcon = icon
+			}
+		}
+
+		// an icon that can't be copied is recorded too, so that it isn't
+		// fetched again until the provider reports another one
+		dbAccount.MirrorSource = dbAccount.OauthIcon
+		return tx.OauthAccounts().Update(ctx, dbAccount)
+	})
+	if err != nil || !used {
+		if prefix != "" {
+			s.deleteAvatar(ctx, prefix)
+		}
+		return err
+	}
+
+	s.recentWriters.Mark(account.UserId)
+
+	if oldPrefix := s.avatarPrefix(account.UserId, oldMirror); oldPrefix != "" {
+		s.deleteAvatar(ctx, oldPrefix)
+	}
+
+	return nil
+}
+
+// fetchOauthIcon downloads a provider avatar and makes its variants. It
+// returns no variants, and no error, when iconUrl will never give a usable
+// image; errors are left for transient failures worth retrying.
+func (s *UserService) fetchOauthIcon(ctx context.Context, iconUrl string) (variants []*avatarVariant, err error) {
+	u, err := url.Parse(iconUrl)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		s.logger.Info("oauth icon url invalid", zap.String("url", iconUrl))
+		return nil, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, iconUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req.WithContext(ctx))
+	if errors.Is(err, errOauthIconAddress) {
+		s.logger.Info("oauth icon address refused", zap.String("url", iconUrl), zap.Error(err))
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("GET %s: %s", iconUrl, resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		s.logger.Info("oauth icon unavailable", zap.String("url", iconUrl), zap.String("status", resp.Status))
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, s.avatarConfig.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.avatarConfig.MaxSize {
+		s.logger.Info("oauth icon too large", zap.String("url", iconUrl))
+		return nil, nil
+	}
+
+	variants, err = s.avatarConfig.makeAvatarVariants(data)
+	if err != nil {
+		s.logger.Info("oauth icon invalid", zap.String("url", iconUrl), zap.Error(err))
+		return nil, nil
+	}
+
+	return variants, nil
+}
@@ -0,0 +1,370 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testIconServer serves provider avatars. Each path answers with the
+// status and body set for it, and requests are counted per path.
+type testIconServer struct {
+	*httptest.Server
+
+	mutex     sync.Mutex
+	responses map[string]testIconResponse
+	requests  map[string]int
+}
+
+type testIconResponse struct {
+	status   int
+	body     []byte
+	location string
+}
+
+func newTestIconServer(t *testing.T) *testIconServer {
+	srv := &testIconServer{responses: map[string]testIconResponse{}, requests: map[string]int{}}
+	srv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv.mutex.Lock()
+		srv.requests[r.URL.Path]++
+		resp, ok := srv.responses[r.URL.Path]
+		srv.mutex.Unlock()
+
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if resp.location != "" {
+			w.Header().Set("Location", resp.location)
+		}
+		w.WriteHeader(resp.status)
+		w.Write(resp.body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func (srv *testIconServer) respond(path string, status int, body []byte) {
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+
+	srv.responses[path] = testIconResponse{status: status, body: body}
+}
+
+func (srv *testIconServer) redirect(path string, location string) {
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+
+	srv.responses[path] = testIconResponse{status: http.StatusFound, location: location}
+}
+
+func (srv *testIconServer) requestCount(path string) int {
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+
+	return srv.requests[path]
+}
+
+type oauthIconTest struct {
+	s       *UserService
+	storage *storages.MemoryStorage
+	store   *testBlobStore
+	srv     *testIconServer
+}
+
+// newOauthIconTest returns a service mirroring into a testBlobStore from a
+// testIconServer, with user1 signed up through an OAuth account whose icon
+// is at iconPath and who has userIcon.
+func newOauthIconTest(t *testing.T, iconPath string, userIcon string) *oauthIconTest {
+	s, storage, _ := newTestService(t)
+	test := &oauthIconTest{s: s, storage: storage, store: &testBlobStore{blobs: map[string][]byte{}}, srv: newTestIconServer(t)}
+	s.SetBlobStore(test.store)
+	s.SetHttpClient(test.srv.Client())
+	s.avatarConfig = newTestAvatarConfig()
+
+	ctx := context.Background()
+	assertError(t, storage.Users().Insert(ctx, &user_db.User{UserId: "user1", UserName: "小明", UserIcon: userIcon}), nil)
+	assertError(t, storage.OauthAccounts().Insert(ctx, &user_db.OauthAccount{
+		UserId:        "user1",
+		OauthProvider: "wechat",
+		OauthOpenId:   "openid1",
+		OauthName:     "小明",
+		OauthIcon:     test.srv.URL + iconPath,
+	}), nil)
+
+	return test
+}
+
+func (test *oauthIconTest) mirror(t *testing.T) {
+	t.Helper()
+	assertError(t, test.s.mirrorOauthIcons(context.Background(), 10), nil)
+}
+
+func (test *oauthIconTest) account(t *testing.T) *user_db.OauthAccount {
+	t.Helper()
+
+	account, err := test.storage.OauthAccounts().GetByOpenId(context.Background(), "wechat", "openid1")
+	assertError(t, err, nil)
+	return account
+}
+
+func (test *oauthIconTest) userIcon(t *testing.T) string {
+	t.Helper()
+
+	dbUser, err := test.storage.Users().GetByUserId(context.Background(), "user1")
+	assertError(t, err, nil)
+	return dbUser.UserIcon
+}
+
+func (test *oauthIconTest) setOauthIcon(t *testing.T, path string) {
+	t.Helper()
+
+	account := test.account(t)
+	account.OauthIcon = test.srv.URL + path
+	assertError(t, test.storage.OauthAccounts().Update(context.Background(), account), nil)
+}
+
+func TestMirrorOauthIconChanged(t *testing.T) {
+	test := newOauthIconTest(t, "/a.png", "")
+	png := encodeTestImage(t, "png", halvesImage(80, 60))
+	test.srv.respond("/a.png", http.StatusOK, png)
+	test.srv.respond("/b.png", http.StatusOK, png)
+
+	test.mirror(t)
+	first := test.userIcon(t)
+	if !strings.HasPrefix(first, test.s.blobStore.URL(avatarKeyPrefix+"user1/")) {
+		t.Fatalf("user_icon = %s, want a mirrored copy", first)
+	}
+	if account := test.account(t); account.MirrorSource != test.srv.URL+"/a.png" || account.MirrorIcon != first {
+		t.Fatalf("account = %+v", account)
+	}
+
+	// nothing changed, nothing is fetched
+	test.mirror(t)
+	if n := test.srv.requestCount("/a.png"); n != 1 {
+		t.Fatalf("icon fetched %d times, want 1", n)
+	}
+
+	test.setOauthIcon(t, "/b.png")
+	test.mirror(t)
+	second := test.userIcon(t)
+	if second == first || test.account(t).MirrorIcon != second {
+		t.Fatalf("user_icon = %s after the provider icon changed, was %s", second, first)
+	}
+
+	// the first copy is deleted
+	if len(test.store.blobs) != len(test.s.avatarConfig.Sizes) {
+		t.Fatalf("%d blobs stored, want the %d of the new copy", len(test.store.blobs), len(test.s.avatarConfig.Sizes))
+	}
+}
+
+func TestMirrorOauthIconUnusable(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   []byte
+	}{
+		{"not found", http.StatusNotFound, nil},
+		{"gone", http.StatusGone, nil},
+		{"not an image", http.StatusOK, []byte("<html>login required</html>")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := newOauthIconTest(t, "/icon", "")
+			test.srv.respond("/icon", tt.status, tt.body)
+
+			test.mirror(t)
+			if account := test.account(t); account.MirrorSource != account.OauthIcon || account.MirrorIcon != "" {
+				t.Fatalf("account = %+v, want the icon recorded as copied", account)
+			}
+			if icon := test.userIcon(t); icon != "" {
+				t.Fatalf("user_icon = %s", icon)
+			}
+
+			// not fetched again until the provider reports another icon
+			test.mirror(t)
+			if n := test.srv.requestCount("/icon"); n != 1 {
+				t.Fatalf("icon fetched %d times, want 1", n)
+			}
+		})
+	}
+}
+
+func TestMirrorOauthIconRetried(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{"server error", http.StatusInternalServerError},
+		{"unavailable", http.StatusServiceUnavailable},
+		{"rate limited", http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := newOauthIconTest(t, "/icon.png", "")
+			test.srv.respond("/icon.png", tt.status, nil)
+
+			test.mirror(t)
+			if account := test.account(t); account.MirrorSource != "" {
+				t.Fatalf("account = %+v, want the icon left to retry", account)
+			}
+
+			test.srv.respond("/icon.png", http.StatusOK, encodeTestImage(t, "png", halvesImage(80, 60)))
+			test.mirror(t)
+			if n := test.srv.requestCount("/icon.png"); n != 2 {
+				t.Fatalf("icon fetched %d times, want 2", n)
+			}
+			if icon := test.userIcon(t); test.account(t).MirrorIcon != icon || icon == "" {
+				t.Fatalf("user_icon = %s after the retry", icon)
+			}
+		})
+	}
+}
+
+func TestMirrorOauthIconUserChosen(t *testing.T) {
+	const chosen = "https://cdn.example.com/avatars/user1/00112233445566778899aabbccddeeff/256.png"
+
+	test := newOauthIconTest(t, "/icon.png", chosen)
+	test.srv.respond("/icon.png", http.StatusOK, encodeTestImage(t, "png", halvesImage(80, 60)))
+
+	test.mirror(t)
+	if icon := test.userIcon(t); icon != chosen {
+		t.Fatalf("user_icon = %s, want the one the user chose", icon)
+	}
+	if n := test.srv.requestCount("/icon.png"); n != 0 {
+		t.Fatalf("icon fetched %d times for a user with their own", n)
+	}
+	if len(test.store.blobs) != 0 {
+		t.Fatalf("%d blobs stored", len(test.store.blobs))
+	}
+
+	// nor once the provider icon changes
+	test.setOauthIcon(t, "/icon2.png")
+	test.mirror(t)
+	if icon := test.userIcon(t); icon != chosen {
+		t.Fatalf("user_icon = %s, want the one the user chose", icon)
+	}
+}
+
+func TestMirrorOauthIconUserChoseAfterMirror(t *testing.T) {
+	test := newOauthIconTest(t, "/a.png", "")
+	png := encodeTestImage(t, "png", halvesImage(80, 60))
+	test.srv.respond("/a.png", http.StatusOK, png)
+	test.srv.respond("/b.png", http.StatusOK, png)
+
+	test.mirror(t)
+
+	// the user uploads their own avatar
+	const chosen = "https://example.com/mine.png"
+	dbUser, err := test.storage.Users().GetByUserId(context.Background(), "user1")
+	assertError(t, err, nil)
+	dbUser.UserIcon = chosen
+	assertError(t, test.storage.Users().Update(context.Background(), dbUser), nil)
+
+	test.setOauthIcon(t, "/b.png")
+	test.mirror(t)
+	if icon := test.userIcon(t); icon != chosen {
+		t.Fatalf("user_icon = %s, want the one the user chose", icon)
+	}
+}
+
+func TestPublicAddress(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"8.8.8.8", true},
+		{"2001:4860:4860::8888", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"100.64.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+		{"224.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := publicAddress(net.ParseIP(tt.ip)); got != tt.want {
+				t.Fatalf("publicAddress = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOauthIconHttpClientRefusesLoopback(t *testing.T) {
+	srv := newTestIconServer(t)
+	srv.respond("/icon.png", http.StatusOK, encodeTestImage(t, "png", halvesImage(80, 60)))
+
+	for _, u := range []string{srv.URL + "/icon.png", strings.Replace(srv.URL, "127.0.0.1", "localhost", 1) + "/icon.png"} {
+		req, err := http.NewRequest(http.MethodGet, u, nil)
+		assertError(t, err, nil)
+
+		resp, err := newOauthIconHttpClient().Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		if !errors.Is(err, errOauthIconAddress) {
+			t.Fatalf("GET %s: err = %v, want %v", u, err, errOauthIconAddress)
+		}
+	}
+	if n := srv.requestCount("/icon.png"); n != 0 {
+		t.Fatalf("icon fetched %d times", n)
+	}
+}
+
+func TestMirrorOauthIconRedirectToLoopback(t *testing.T) {
+	test := newOauthIconTest(t, "/icon", "")
+	// the stand-in provider is on loopback itself, so only the redirect
+	// check of the real client is kept
+	test.s.SetHttpClient(&http.Client{
+		Transport:     test.srv.Client().Transport,
+		CheckRedirect: checkOauthIconRedirect,
+	})
+	test.srv.respond("/internal.png", http.StatusOK, encodeTestImage(t, "png", halvesImage(80, 60)))
+	test.srv.redirect("/icon", test.srv.URL+"/internal.png")
+
+	test.mirror(t)
+	if n := test.srv.requestCount("/internal.png"); n != 0 {
+		t.Fatalf("redirect followed %d times", n)
+	}
+	if account := test.account(t); account.MirrorSource != account.OauthIcon || account.MirrorIcon != "" {
+		t.Fatalf("account = %+v, want the icon recorded as unusable", account)
+	}
+	if icon := test.userIcon(t); icon != "" {
+		t.Fatalf("user_icon = %s", icon)
+	}
+}
+
+func TestCheckOauthIconRedirectLimit(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://8.8.8.8/icon.png", nil)
+	assertError(t, err, nil)
+
+	via := make([]*http.Request, oauthIconMaxRedirects)
+	assertError(t, checkOauthIconRedirect(req, via), nil)
+	if err := checkOauthIconRedirect(req, append(via, req)); err == nil {
+		t.Fatalf("redirect %d followed", oauthIconMaxRedirects+1)
+	}
+
+	req.URL.Scheme = "file"
+	if err := checkOauthIconRedirect(req, nil); !errors.Is(err, errOauthIconAddress) {
+		t.Fatalf("redirect to file: err = %v", err)
+	}
+}
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"math/big"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	oauthClientIdLength = 16
+	oauthScopeOpenId    = "openid"
+	oauthScopeProfile   = "profile"
+
+	oauthGrantAuthorizationCode = "authorization_code"
+	oauthGrantRefreshToken      = "refresh_token"
+	oauthGrantClientCredentials = "client_credentials"
+)
+
+var oauthSupportedScopes = []string{oauthScopeOpenId, oauthScopeProfile}
+
+var oauthSupportedGrants = []string{oauthGrantAuthorizationCode, oauthGrantRefreshToken, oauthGrantClientCredentials}
+
+// OauthError is an error response of the OAuth2 endpoints (RFC 6749 5.2).
+type OauthError struct {
+	Code        string
+	Description string
+}
+
+func (e *OauthError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+func newOauthError(code string, description string) *OauthError {
+	return &OauthError{Code: code, Description: description}
+}
+
+type OauthProviderConfig struct {
+	// Issuer is the external URL of the API base path, e.g.
+	// https://api.example.com/api/v1/users. Empty disables the provider.
+	Issuer string
+	// AuthorizePage is the frontend page that signs the user in, asks for
+	// consent and calls Authorize.
+	AuthorizePage string
+	// SigningKey signs ID tokens and the access tokens of OAuth clients.
+	// Nil generates a key at startup, so they stop verifying on every
+	// restart.
+	SigningKey   *rsa.PrivateKey
+	CodeLifetime time.Duration
+}
+
+// NewOauthProviderConfigFromEnv reads OIDC_ISSUER, OIDC_AUTHORIZE_PAGE
+// (default <issuer>/authorize), OIDC_SIGNING_KEY (a PEM RSA private key)
+// and OIDC_CODE_LIFETIME.
+func NewOauthProviderConfigFromEnv() (c *OauthProviderConfig, err error) {
+	c = &OauthProviderConfig{
+		Issuer:       strings.TrimSuffix(os.Getenv("OIDC_ISSUER"), "/"),
+		CodeLifetime: time.Minute * 5,
+	}
+
+	if c.Issuer != "" {
+		u, err := url.Parse(c.Issuer)
+		if err != nil || u.Scheme != "https" && u.Scheme != "http" || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+			return nil, fmt.Errorf("OIDC_ISSUER env invalid: %s", c.Issuer)
+		}
+	}
+
+	c.AuthorizePage = os.Getenv("OIDC_AUTHORIZE_PAGE")
+	if c.AuthorizePage == "" && c.Issuer != "" {
+		c.AuthorizePage = c.Issuer + "/authorize"
+	}
+
+	if v := os.Getenv("OIDC_SIGNING_KEY"); v != "" {
+		c.SigningKey, err = parseRsaPrivateKey([]byte(v))
+		if err != nil {
+			return nil, fmt.Errorf("OIDC_SIGNING_KEY env invalid: %v", err)
+		}
+	}
+
+	if v := os.Getenv("OIDC_CODE_LIFETIME"); v != "" {
+		c.CodeLifetime, err = time.ParseDuration(v)
+		if err != nil || c.CodeLifetime <= 0 {
+			return nil, fmt.Errorf("OIDC_CODE_LIFETIME env invalid: %s", v)
+		}
+	}
+
+	return c, nil
+}
+
+func parseRsaPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("not an RSA key")
+	}
+
+	return rsaKey, nil
+}
+
+type oauthProvider struct {
+	config     *OauthProviderConfig
+	signingKey *rsa.PrivateKey
+	keyId      string
+}
+
+func (c *OauthProviderConfig) newOauthProvider() (p *oauthProvider, err error) {
+	if c.Issuer == "" {
+		return nil, nil
+	}
+
+	p = &oauthProvider{config: c, signingKey: c.SigningKey}
+	if p.signingKey == nil {
+		p.signingKey, err = rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sum := sha256.Sum256(p.signingKey.N.Bytes())
+	p.keyId = base64.RawURLEncoding.EncodeToString(sum[:12])
+
+	return p, nil
+}
+
+func (p *oauthProvider) discovery() map[string]interface{} {
+	issuer := p.config.Issuer
+	return map[string]interface{}{
+		"issuer":                                         issuer,
+		"authorization_endpoint":                         p.config.AuthorizePage,
+		"token_endpoint":                                 issuer + "/oauth2/token",
+		"userinfo_endpoint":                              issuer + "/oauth2/userinfo",
+		"jwks_uri":                                       issuer + "/oauth2/jwks",
+		"introspection_endpoint":                         issuer + "/introspect",
+		"revocation_endpoint":                            issuer + "/revoke",
+		"scopes_supported":                               oauthSupportedScopes,
+		"response_types_supported":                       []string{"code"},
+		"response_modes_supported":                       []string{"query"},
+		"grant_types_supported":                          oauthSupportedGrants,
+		"subject_types_supported":                        []string{"public"},
+		"id_token_signing_alg_values_supported":          []string{"RS256"},
+		"token_endpoint_auth_methods_supported":          []string{"client_secret_basic", "client_secret_post", "none"},
+		"introspection_endpoint_auth_methods_supported":  []string{"client_secret_basic", "client_secret_post"},
+		"revocation_endpoint_auth_methods_supported":     []string{"client_secret_basic", "client_secret_post"},
+		"code_challenge_methods_supported":               []string{"S256"},
+		"claims_supported":                               []string{"iss", "sub", "aud", "exp", "iat", "nonce", "name", "preferred_username", "picture"},
+		"authorization_response_iss_parameter_supported": true,
+	}
+}
+
+func (p *oauthProvider) jwks() map[string]interface{} {
+	key := &p.signingKey.PublicKey
+	return map[string]interface{}{
+		"keys": []map[string]interface{}{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": p.keyId,
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	}
+}
+
+type idTokenClaims struct {
+	jwt.StandardClaims
+	Nonce string `json:"nonce,omitempty"`
+}
+
+func (p *oauthProvider) newIdToken(userId string, clientId string, nonce string) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, idTokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    p.config.Issuer,
+			Subject:   userId,
+			Audience:  clientId,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(accessTokenLifetime).Unix(),
+		},
+		Nonce: nonce,
+	})
+	token.Header["kid"] = p.keyId
+
+	return token.SignedString(p.signingKey)
+}
+
+// accessTokenType is the typ header of the access tokens the provider signs
+// (RFC 9068 2.1), which keeps ID tokens from passing as access tokens.
+const accessTokenType = "at+jwt"
+
+// signAccessToken signs claims of an access token issued to an OAuth client
+// with the ID token key, so clients and resource servers can verify it
+// against the JWKS.
+func (p *oauthProvider) signAccessToken(claims accessTokenClaims) (string, error) {
+	claims.Issuer = p.config.Issuer
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = p.keyId
+	token.Header["typ"] = accessTokenType
+
+	return token.SignedString(p.signingKey)
+}
+
+// accessTokenKey returns the key that verifies t, an access token signed by
+// signAccessToken.
+func (p *oauthProvider) accessTokenKey(t *jwt.Token) (interface{}, error) {
+	if t.Header["kid"] != p.keyId || t.Header["typ"] != accessTokenType {
+		return nil, fmt.Errorf("unexpected key %v", t.Header["kid"])
+	}
+
+	return &p.signingKey.PublicKey, nil
+}
+
+func validOauthClientId(clientId string) bool {
+	return len(clientId) == oauthClientIdLength*2 && isLowerHex(clientId)
+}
+
+func isLowerHex(s string) bool {
+	for _, r := range s {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasField reports whether the space separated list contains field.
+func hasField(list string, field string) bool {
+	for _, v := range strings.Fields(list) {
+		if v == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scopeSubset reports whether every scope in scope is also in allowed.
+func scopeSubset(scope string, allowed string) bool {
+	for _, v := range strings.Fields(scope) {
+		if !hasField(allowed, v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func normalizeScope(scope string) string {
+	return strings.Join(strings.Fields(scope), " ")
+}
+
+// validCodeVerifier checks a PKCE code verifier (RFC 7636 4.1). Challenges
+// are the base64url SHA-256 of a verifier, which is 43 characters.
+func validCodeVerifier(verifier string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+
+	for _, r := range verifier {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-' || r == '.' || r == '_' || r == '~') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func codeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// validRedirectUri allows absolute URIs without a fragment (RFC 6749 3.1.2).
+// Plain http is only allowed to loopback addresses, for native apps.
+func validRedirectUri(redirectUri string) bool {
+	if len(redirectUri) > 1024 || strings.ContainsAny(redirectUri, " \t\r\n") {
+		return false
+	}
+
+	u, err := url.Parse(redirectUri)
+	if err != nil || !u.IsAbs() || u.Fragment != "" || u.Opaque != "" {
+		return false
+	}
+
+	if u.Scheme == "http" {
+		host := u.Hostname()
+		return host == "localhost" || host == "127.0.0.1" || host == "::1"
+	}
+
+	return u.Host != "" || u.Scheme != "https"
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"github.com/NeuronFramework/errors"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	passwordSaltLength = 16
+	passwordKeyLength  = 32
+	passwordMaxLength  = 128
+)
+
+type PasswordConfig struct {
+	// argon2id parameters for new hashes; stored hashes made with other
+	// parameters, or with bcrypt, are upgraded on the next login
+	Time    uint32
+	Memory  uint32 // KiB
+	Threads uint8
+
+	MinLength int
+	// MaxAttempts wrong passwords in a row lock the account's password
+	// login for Lockout.
+	MaxAttempts int32
+	Lockout     time.Duration
+}
+
+// NewPasswordConfigFromEnv reads PASSWORD_ARGON2_TIME, PASSWORD_ARGON2_MEMORY,
+// PASSWORD_ARGON2_THREADS, PASSWORD_MIN_LENGTH, PASSWORD_MAX_ATTEMPTS and
+// PASSWORD_LOCKOUT.
+func NewPasswordConfigFromEnv() (c *PasswordConfig, err error) {
+	c = &PasswordConfig{
+		Time:        3,
+		Memory:      64 * 1024,
+		Threads:     2,
+		MinLength:   8,
+		MaxAttempts: 5,
+		Lockout:     time.Minute * 15,
+	}
+
+	ints := []struct {
+		key  string
+		bits int
+		set  func(v uint64)
+	}{
+		{"PASSWORD_ARGON2_TIME", 32, func(v uint64) { c.Time = uint32(v) }},
+		{"PASSWORD_ARGON2_MEMORY", 32, func(v uint64) { c.Memory = uint32(v) }},
+		{"PASSWORD_ARGON2_THREADS", 8, func(v uint64) { c.Threads = uint8(v) }},
+		{"PASSWORD_MIN_LENGTH", 8, func(v uint64) { c.MinLength = int(v) }},
+		{"PASSWORD_MAX_ATTEMPTS", 31, func(v uint64) { c.MaxAttempts = int32(v) }},
+	}
+	for _, i := range ints {
+		if v := os.Getenv(i.key); v != "" {
+			n, err := strconv.ParseUint(v, 10, i.bits)
+			if err != nil || n == 0 {
+				return nil, fmt.Errorf("%s env invalid: %s", i.key, v)
+			}
+			i.set(n)
+		}
+	}
+
+	if v := os.Getenv("PASSWORD_LOCKOUT"); v != "" {
+		c.Lockout, err = time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("PASSWORD_LOCKOUT env invalid: %v", err)
+		}
+	}
+
+	return c, nil
+}
+
+func (c *PasswordConfig) validate(password string) error {
+	n := utf8.RuneCountInString(password)
+	if n < c.MinLength {
+		return errors.BadRequest("InvalidPassword", fmt.Sprintf("密码不能少于%d位", c.MinLength))
+	}
+	if n > passwordMaxLength {
+		return errors.BadRequest("InvalidPassword", "密码过长")
+	}
+
+	return nil
+}
+
+// hash returns password hashed with argon2id in the PHC string format,
+// e.g. $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>.
+func (c *PasswordConfig) hash(password string) (string, error) {
+	salt := make([]byte, passwordSaltLength)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, c.Time, c.Memory, c.Threads, passwordKeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, c.Memory, c.Time, c.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// verify checks password against a stored hash. rehash is set when the
+// password matched but the hash should be replaced with one made with the
+// current parameters.
+func (c *PasswordConfig) verify(stored string, password string) (ok bool, rehash bool, err error) {
+	if strings.HasPrefix(stored, "$2a$") || strings.HasPrefix(stored, "$2b$") || strings.HasPrefix(stored, "$2y$") {
+		err = bcrypt.CompareHashAndPassword([]byte(stored), []byte(password))
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return false, false, nil
+		}
+		return err == nil, true, err
+	}
+
+	var version int
+	var memory, iterations uint32
+	var threads uint8
+	parts := strings.Split(stored, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false, false, fmt.Errorf("unknown password hash format")
+	}
+	_, err = fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil {
+		return false, false, err
+	}
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &threads)
+	if err != nil {
+		return false, false, err
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, false, err
+	}
+
+	actual := argon2.IDKey([]byte(password), salt, iterations, memory, threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(actual, key) != 1 {
+		return false, false, nil
+	}
+
+	rehash = version != argon2.Version || memory != c.Memory || iterations != c.Time || threads != c.Threads
+	return true, rehash, nil
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"github.com/NeuronFramework/errors"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"testing"
+)
+
+func newTestPasswordConfig() *PasswordConfig {
+	return &PasswordConfig{Time: 1, Memory: 1024, Threads: 1, MinLength: 8, MaxAttempts: 3}
+}
+
+func TestPasswordVerify(t *testing.T) {
+	c := newTestPasswordConfig()
+
+	argon, err := c.hash("correct horse")
+	assertError(t, err, nil)
+	if !strings.HasPrefix(argon, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Fatalf("hash = %s", argon)
+	}
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	assertError(t, err, nil)
+
+	weaker := *c
+	weaker.Memory = 512
+	weak, err := weaker.hash("correct horse")
+	assertError(t, err, nil)
+
+	tests := []struct {
+		name       string
+		stored     string
+		password   string
+		wantOk     bool
+		wantRehash bool
+	}{
+		{"argon2id", argon, "correct horse", true, false},
+		{"argon2id, wrong password", argon, "correct horse!", false, false},
+		{"weaker argon2id", weak, "correct horse", true, true},
+		{"weaker argon2id, wrong password", weak, "battery staple", false, false},
+		{"bcrypt", string(legacy), "correct horse", true, true},
+		{"bcrypt, wrong password", string(legacy), "battery staple", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, rehash, err := c.verify(tt.stored, tt.password)
+			assertError(t, err, nil)
+			if ok != tt.wantOk || rehash != tt.wantRehash {
+				t.Fatalf("verify = %v, %v, want %v, %v", ok, rehash, tt.wantOk, tt.wantRehash)
+			}
+		})
+	}
+
+	// each hash has its own salt
+	again, err := c.hash("correct horse")
+	assertError(t, err, nil)
+	if again == argon {
+		t.Fatalf("two hashes of a password are equal")
+	}
+
+	if _, _, err := c.verify("$argon2i$v=19$m=1024,t=1,p=1$c2FsdA$a2V5", "correct horse"); err == nil {
+		t.Fatalf("unknown hash format accepted")
+	}
+}
+
+func TestPasswordValidate(t *testing.T) {
+	c := newTestPasswordConfig()
+	tooShort := errors.BadRequest("InvalidPassword", "密码不能少于8位")
+	tooLong := errors.BadRequest("InvalidPassword", "密码过长")
+
+	tests := []struct {
+		name     string
+		password string
+		want     error
+	}{
+		{"too short", "1234567", tooShort},
+		// characters, not bytes, are counted
+		{"too short multi-byte", "密码密码密码密", tooShort},
+		{"shortest", "12345678", nil},
+		{"longest", strings.Repeat("密", passwordMaxLength), nil},
+		{"too long", strings.Repeat("a", passwordMaxLength+1), tooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertError(t, c.validate(tt.password), tt.want)
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"fmt"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronUser/user/models"
+	"golang.org/x/text/language"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	// timezones are checked against the IANA database even on hosts
+	// without one
+	_ "time/tzdata"
+	"unicode"
+	"unicode/utf8"
+)
+
+// the varchar sizes of the user profile columns and of user_attribute
+const (
+	bioMaxLength            = 256
+	localeMaxLength         = 32
+	timezoneMaxLength       = 64
+	namespaceMaxLength      = 32
+	attributeKeyMaxLength   = 64
+	attributeValueMaxLength = 1024
+)
+
+// attributeMaxKeys is how many attributes a user may have in one namespace.
+const attributeMaxKeys = 100
+
+var genders = []string{"male", "female", "other"}
+
+// AttributeAccess is what users may do with their own attributes in a
+// namespace.
+type AttributeAccess string
+
+const (
+	AttributeAccessNone  AttributeAccess = "none"
+	AttributeAccessRead  AttributeAccess = "read"
+	AttributeAccessWrite AttributeAccess = "write"
+)
+
+type AttributeConfig struct {
+	// Namespaces are those attributes may be kept in, each with what users
+	// may do with their own attributes there. Services need the scopes of
+	// a namespace, see attributeScope, and admins may do anything.
+	Namespaces map[string]AttributeAccess
+}
+
+// NewAttributeConfigFromEnv reads USER_ATTRIBUTE_NAMESPACES, a comma
+// separated list of namespace=access, e.g.
+// USER_ATTRIBUTE_NAMESPACES=game=write,shop=read,risk=none. There are no
+// namespaces by default.
+func NewAttributeConfigFromEnv() (c *AttributeConfig, err error) {
+	c = &AttributeConfig{Namespaces: map[string]AttributeAccess{}}
+
+	if v := os.Getenv("USER_ATTRIBUTE_NAMESPACES"); v != "" {
+		for _, item := range strings.Split(v, ",") {
+			kv := strings.SplitN(strings.TrimSpace(item), "=", 2)
+			if len(kv) != 2 || !validNamespace(kv[0]) {
+				return nil, fmt.Errorf("USER_ATTRIBUTE_NAMESPACES env invalid: %q", item)
+			}
+
+			access := AttributeAccess(kv[1])
+			if access != AttributeAccessNone && access != AttributeAccessRead && access != AttributeAccessWrite {
+				return nil, fmt.Errorf("USER_ATTRIBUTE_NAMESPACES env invalid: %q, want none, read or write", item)
+			}
+			c.Namespaces[kv[0]] = access
+		}
+	}
+
+	return c, nil
+}
+
+// attributeScope grants a service access to the attributes of every user in
+// namespace. The write scope includes read.
+func attributeScope(namespace string, access AttributeAccess) string {
+	return "attributes:" + namespace + ":" + string(access)
+}
+
+// scopes are the attribute scopes services may be granted, sorted.
+func (c *AttributeConfig) scopes() (scopes []string) {
+	for namespace := range c.Namespaces {
+		scopes = append(scopes, attributeScope(namespace, AttributeAccessRead), attributeScope(namespace, AttributeAccessWrite))
+	}
+	sort.Strings(scopes)
+
+	return scopes
+}
+
+// allowed reports whether principal may read, or write, the attributes of
+// userId in namespace. An empty userId, for several users at once, is never
+// the principal's own.
+func (c *AttributeConfig) allowed(principal *models.Principal, userId string, namespace string, access AttributeAccess) bool {
+	userAccess, ok := c.Namespaces[namespace]
+	if !ok {
+		return false
+	}
+
+	if PrincipalHasScopes(principal, []string{ScopeUserAdmin}) ||
+		PrincipalHasScopes(principal, []string{attributeScope(namespace, AttributeAccessWrite)}) ||
+		PrincipalHasScopes(principal, []string{attributeScope(namespace, access)}) {
+		return true
+	}
+
+	if principal.ClientId != "" || userId == "" || principal.UserId != userId {
+		return false
+	}
+	if access == AttributeAccessWrite {
+		return userAccess == AttributeAccessWrite && PrincipalHasScopes(principal, []string{ScopeUserWrite})
+	}
+	return userAccess != AttributeAccessNone && PrincipalHasScopes(principal, []string{ScopeUserRead})
+}
+
+func validNamespace(namespace string) bool {
+	if namespace == "" || len(namespace) > namespaceMaxLength {
+		return false
+	}
+
+	for _, r := range namespace {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r == '-' || r == '_') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func validateAttributeKey(key string) error {
+	if key == "" || len(key) > attributeKeyMaxLength {
+		return errors.BadRequest("InvalidAttributeKey", "属性名不合法")
+	}
+
+	for _, r := range key {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-' || r == '_' || r == '.') {
+			return errors.BadRequest("InvalidAttributeKey", "属性名不合法")
+		}
+	}
+
+	return nil
+}
+
+func validateAttributeValue(value string) error {
+	if !utf8.ValidString(value) || utf8.RuneCountInString(value) > attributeValueMaxLength {
+		return errors.BadRequest("InvalidAttributeValue", "属性值不合法")
+	}
+
+	return nil
+}
+
+// normalizeProfile checks the fields of p and returns them in the form they
+// are stored in.
+func normalizeProfile(p *models.Profile) (r *models.Profile, err error) {
+	r = &models.Profile{}
+
+	r.Bio = strings.TrimSpace(p.Bio)
+	if !utf8.ValidString(r.Bio) || utf8.RuneCountInString(r.Bio) > bioMaxLength {
+		return nil, errors.BadRequest("InvalidBio", "个人简介不能超过256个字")
+	}
+	for _, c := range r.Bio {
+		if unicode.IsControl(c) && c != '\n' {
+			return nil, errors.BadRequest("InvalidBio", "个人简介包含非法字符")
+		}
+	}
+
+	r.Gender = p.Gender
+	if r.Gender != "" && !hasField(strings.Join(genders, " "), r.Gender) {
+		return nil, errors.BadRequest("InvalidGender", "性别不合法")
+	}
+
+	r.Birthday = p.Birthday
+	if r.Birthday != "" {
+		birthday, err := time.Parse("2006-01-02", r.Birthday)
+		if err != nil || birthday.Year() < 1900 || birthday.After(time.Now()) {
+			return nil, errors.BadRequest("InvalidBirthday", "生日不合法")
+		}
+	}
+
+	if p.Locale != "" {
+		tag, err := language.Parse(p.Locale)
+		if err != nil || len(tag.String()) > localeMaxLength {
+			return nil, errors.BadRequest("InvalidLocale", "语言不合法")
+		}
+		r.Locale = tag.String()
+	}
+
+	r.Timezone = p.Timezone
+	if r.Timezone != "" {
+		// LoadLocation also takes "Local", the zone of this server
+		_, err := time.LoadLocation(r.Timezone)
+		if err != nil || r.Timezone == "Local" || len(r.Timezone) > timezoneMaxLength {
+			return nil, errors.BadRequest("InvalidTimezone", "时区不合法")
+		}
+	}
+
+	return r, nil
+}
+
+// the fields GetUserInfo and BatchGetUserInfo may be asked for besides
+// attributes; userId is always returned
+var userInfoStandardFields = []string{"name", "icon", "bio", "gender", "birthday", "locale", "timezone"}
+
+// userInfoDefaultFields are returned when no fields are asked for.
+var userInfoDefaultFields = []string{"name", "icon"}
+
+// userInfoFields is a field selection: the standard fields, and the
+// namespaces whose attributes are returned.
+type userInfoFields struct {
+	standard   map[string]bool
+	namespaces []string
+}
+
+// parseUserInfoFields checks a selection of fields of userId that principal
+// asked for. "attributes.<namespace>" selects the attributes of a namespace
+// and "attributes" those of every namespace principal may read. userId is
+// empty when principal reads several users at once.
+func (s *UserService) parseUserInfoFields(principal *models.Principal, userId string, fields []string) (r *userInfoFields, err error) {
+	if len(fields) == 0 {
+		fields = userInfoDefaultFields
+	}
+
+	r = &userInfoFields{standard: map[string]bool{}}
+	selected := map[string]bool{}
+	for _, field := range fields {
+		switch {
+		case field == "userId":
+		case hasField(strings.Join(userInfoStandardFields, " "), field):
+			r.standard[field] = true
+		case field == "attributes":
+			for namespace := range s.attributeConfig.Namespaces {
+				if s.attributeConfig.allowed(principal, userId, namespace, AttributeAccessRead) {
+					selected[namespace] = true
+				}
+			}
+		case strings.HasPrefix(field, "attributes."):
+			namespace := strings.TrimPrefix(field, "attributes.")
+			if _, ok := s.attributeConfig.Namespaces[namespace]; !ok {
+				return nil, errors.BadRequest("UnknownNamespace", "属性命名空间不存在")
+			}
+			if !s.attributeConfig.allowed(principal, userId, namespace, AttributeAccessRead) {
+				return nil, errors.BadRequest("NamespaceNotReadable", "无权读取该命名空间的属性")
+			}
+			selected[namespace] = true
+		default:
+			return nil, errors.BadRequest("UnknownField", "字段不存在")
+		}
+	}
+
+	for namespace := range selected {
+		r.namespaces = append(r.namespaces, namespace)
+	}
+	sort.Strings(r.namespaces)
+
+	return r, nil
+}
+
+// apply clears the standard fields of userInfo that weren't selected.
+func (f *userInfoFields) apply(userInfo *models.UserInfo) {
+	for _, v := range []struct {
+		field string
+		value *string
+	}{
+		{"name", &userInfo.Name},
+		{"icon", &userInfo.Icon},
+		{"bio", &userInfo.Bio},
+		{"gender", &userInfo.Gender},
+		{"birthday", &userInfo.Birthday},
+		{"locale", &userInfo.Locale},
+		{"timezone", &userInfo.Timezone},
+	} {
+		if !f.standard[v.field] {
+			*v.value = ""
+		}
+	}
+}
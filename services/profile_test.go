@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages/user_db"
+	"strings"
+	"testing"
+)
+
+var errNamespaceNotReadable = errors.BadRequest("NamespaceNotReadable", "无权读取该命名空间的属性")
+
+func newTestAttributeConfig() *AttributeConfig {
+	return &AttributeConfig{Namespaces: map[string]AttributeAccess{
+		"game": AttributeAccessWrite,
+		"shop": AttributeAccessRead,
+		"risk": AttributeAccessNone,
+	}}
+}
+
+func testUserPrincipal(userId string, scopes ...string) *models.Principal {
+	return &models.Principal{UserId: userId, Scopes: scopes, AuthMethod: models.AuthMethodBearer}
+}
+
+func testServicePrincipal(scopes ...string) *models.Principal {
+	return &models.Principal{ClientId: "service1", Scopes: scopes, AuthMethod: models.AuthMethodBasic}
+}
+
+func TestAttributeAllowed(t *testing.T) {
+	user := testUserPrincipal("user1", strings.Fields(userScope(nil))...)
+	readOnlyUser := testUserPrincipal("user1", ScopeUserRead)
+	admin := testUserPrincipal("admin1", strings.Fields(userScope([]string{RoleAdmin}))...)
+	gameReader := testServicePrincipal(attributeScope("game", AttributeAccessRead))
+	gameWriter := testServicePrincipal(attributeScope("game", AttributeAccessWrite))
+	adminService := testServicePrincipal(ScopeUserAdmin)
+	otherService := testServicePrincipal(ScopeUserRead)
+
+	tests := []struct {
+		name      string
+		principal *models.Principal
+		userId    string
+		namespace string
+		access    AttributeAccess
+		want      bool
+	}{
+		// users with their own attributes, as the namespace allows
+		{"user write ns, read", user, "user1", "game", AttributeAccessRead, true},
+		{"user write ns, write", user, "user1", "game", AttributeAccessWrite, true},
+		{"user read ns, read", user, "user1", "shop", AttributeAccessRead, true},
+		{"user read ns, write", user, "user1", "shop", AttributeAccessWrite, false},
+		{"user none ns, read", user, "user1", "risk", AttributeAccessRead, false},
+		{"user none ns, write", user, "user1", "risk", AttributeAccessWrite, false},
+		{"user without user:write", readOnlyUser, "user1", "game", AttributeAccessWrite, false},
+		{"user, another user's", user, "user2", "game", AttributeAccessRead, false},
+		{"user, several users", user, "", "game", AttributeAccessRead, false},
+		{"user, unknown ns", user, "user1", "chat", AttributeAccessRead, false},
+
+		// services by their namespace scopes, for any user
+		{"service read scope, read", gameReader, "user2", "game", AttributeAccessRead, true},
+		{"service read scope, write", gameReader, "user2", "game", AttributeAccessWrite, false},
+		{"service read scope, other ns", gameReader, "user2", "shop", AttributeAccessRead, false},
+		{"service write scope, read", gameWriter, "user2", "game", AttributeAccessRead, true},
+		{"service write scope, write", gameWriter, "user2", "game", AttributeAccessWrite, true},
+		{"service write scope, several users", gameWriter, "", "game", AttributeAccessWrite, true},
+		{"service without scope", otherService, "user2", "shop", AttributeAccessRead, false},
+
+		// admins anywhere, even where users have no access
+		{"admin, none ns, write", admin, "user2", "risk", AttributeAccessWrite, true},
+		{"admin service, none ns, read", adminService, "", "risk", AttributeAccessRead, true},
+		{"admin, unknown ns", admin, "user2", "chat", AttributeAccessRead, false},
+	}
+
+	c := newTestAttributeConfig()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.allowed(tt.principal, tt.userId, tt.namespace, tt.access); got != tt.want {
+				t.Fatalf("allowed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchGetUserInfoAttributes(t *testing.T) {
+	s, storage, _ := newTestService(t)
+	s.attributeConfig = newTestAttributeConfig()
+	ctx := context.Background()
+
+	for _, userId := range []string{"user1", "user2"} {
+		assertError(t, storage.Users().Insert(ctx, &user_db.User{UserId: userId, UserName: userId}), nil)
+		assertError(t, storage.Attributes().Insert(ctx, &user_db.UserAttribute{
+			UserId: userId, Namespace: "game", AttrKey: "level", AttrValue: "7",
+		}), nil)
+	}
+
+	// users may read their own game attributes, but not in a batch, not
+	// even their own
+	user := testUserPrincipal("user1", strings.Fields(userScope(nil))...)
+	_, err := s.BatchGetUserInfo(newTestContext(), user, []string{"user1"}, []string{"attributes.game"})
+	assertError(t, err, errNamespaceNotReadable)
+
+	list, err := s.BatchGetUserInfo(newTestContext(), user, []string{"user1", "user2"}, []string{"name", "attributes"})
+	assertError(t, err, nil)
+	if len(list) != 2 {
+		t.Fatalf("%d users, want 2", len(list))
+	}
+	for _, v := range list {
+		if len(v.Attributes) != 0 {
+			t.Fatalf("user %s got attributes %v", v.UserID, v.Attributes)
+		}
+	}
+
+	// a service with the namespace's scope gets them for every user
+	service := testServicePrincipal(attributeScope("game", AttributeAccessRead))
+	list, err = s.BatchGetUserInfo(newTestContext(), service, []string{"user2", "user1", "user2", "nobody"}, []string{"attributes"})
+	assertError(t, err, nil)
+	if len(list) != 2 || list[0].UserID != "user2" || list[1].UserID != "user1" {
+		t.Fatalf("list = %+v, want user2 and user1", list)
+	}
+	for _, v := range list {
+		if v.Attributes["game"]["level"] != "7" || len(v.Attributes) != 1 {
+			t.Fatalf("user %s got attributes %v, want game only", v.UserID, v.Attributes)
+		}
+	}
+}
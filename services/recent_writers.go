@@ -0,0 +1,84 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// recentWriters remembers users whose rows were just written on the primary,
+// so that their own reads can skip a replica that may not have caught up.
+// Other instances learn of a write through the hint the API hands back to
+// the client, see ReadPrimaryUntil.
+type recentWriters struct {
+	mutex  sync.Mutex
+	window time.Duration
+	// until is when reads of a user may go to the replica again
+	until map[string]time.Time
+}
+
+func newRecentWriters(window time.Duration) *recentWriters {
+	return &recentWriters{window: window, until: make(map[string]time.Time)}
+}
+
+func (w *recentWriters) Mark(userId string) {
+	w.MarkUntil(userId, time.Now().Add(w.window))
+}
+
+// MarkUntil keeps reads of userId on the primary until until, but no longer
+// than the window from now.
+func (w *recentWriters) MarkUntil(userId string, until time.Time) {
+	if w.window <= 0 {
+		return
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	now := time.Now()
+	if max := now.Add(w.window); until.After(max) {
+		until = max
+	}
+	if !until.After(now) || !until.After(w.until[userId]) {
+		return
+	}
+
+	for k, v := range w.until {
+		if !v.After(now) {
+			delete(w.until, k)
+		}
+	}
+	w.until[userId] = until
+}
+
+func (w *recentWriters) Contains(userId string) bool {
+	if w.window <= 0 {
+		return false
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	t, ok := w.until[userId]
+	if !ok {
+		return false
+	}
+	if !t.After(time.Now()) {
+		delete(w.until, userId)
+		return false
+	}
+
+	return true
+}
+
+// ReadYourWritesWindow is how long after a write the writer's reads skip
+// the replica, or 0 if they never do.
+func (s *UserService) ReadYourWritesWindow() time.Duration {
+	return s.recentWriters.window
+}
+
+// ReadPrimaryUntil keeps the reads of userId on the primary until until. The
+// API calls it with the hint a client got back from a write, which may have
+// been served by another instance.
+func (s *UserService) ReadPrimaryUntil(userId string, until time.Time) {
+	s.recentWriters.MarkUntil(userId, until)
+}
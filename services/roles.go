@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages"
+	"sort"
+	"strings"
+)
+
+// Scopes name what a principal may do on the API. Operations declare the
+// scopes they require in api/swagger.json (x-scopes).
+const (
+	// ScopeUserRead reads the signed in user's account, or, for services,
+	// any user's profile
+	ScopeUserRead = "user:read"
+	// ScopeUserWrite changes the signed in user's account
+	ScopeUserWrite = "user:write"
+	// ScopeUserAdmin manages other users' accounts
+	ScopeUserAdmin = "user:admin"
+)
+
+const RoleAdmin = "admin"
+
+// defaultUserScopes are granted to every user, whatever their roles.
+var defaultUserScopes = []string{ScopeUserRead, ScopeUserWrite}
+
+// roleScopes are the scopes each role adds. Roles missing here are unknown
+// and can't be granted.
+var roleScopes = map[string][]string{
+	RoleAdmin: {ScopeUserAdmin},
+}
+
+// mfaRoles can only be held by users with TOTP enabled. Their users can't
+// log in without it, and users who turned it off some other way don't get
+// their scopes.
+var mfaRoles = []string{RoleAdmin}
+
+// serviceScopes may be granted to our own services, i.e. OAuth clients
+// limited to client_credentials, besides the attribute scopes of each
+// namespace (attributeScope).
+var serviceScopes = []string{ScopeUserRead, ScopeUserAdmin}
+
+// userScope is the space separated scope of a user with roles.
+func userScope(roles []string) string {
+	scopes := append([]string{}, defaultUserScopes...)
+	for _, role := range roles {
+		for _, v := range roleScopes[role] {
+			if !hasField(strings.Join(scopes, " "), v) {
+				scopes = append(scopes, v)
+			}
+		}
+	}
+
+	return strings.Join(scopes, " ")
+}
+
+// listRoles returns the known roles of userId, sorted.
+func listRoles(ctx context.Context, tx storages.Storage, userId string) (roles []string, err error) {
+	dbRoles, err := tx.Roles().ListByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range dbRoles {
+		if _, ok := roleScopes[v.Role]; ok {
+			roles = append(roles, v.Role)
+		}
+	}
+	sort.Strings(roles)
+
+	return roles, nil
+}
+
+// sessionRoles returns the roles userId holds when signed in: its known
+// roles, less mfaRoles while it has no TOTP enabled.
+func sessionRoles(ctx context.Context, tx storages.Storage, userId string) (roles []string, err error) {
+	roles, err = listRoles(ctx, tx, userId)
+	if err != nil || !requiresMfa(roles) {
+		return roles, err
+	}
+
+	enabled, err := totpEnabled(ctx, tx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		roles = withoutMfaRoles(roles)
+	}
+
+	return roles, nil
+}
+
+// requiresMfa reports whether any of roles is one of mfaRoles.
+func requiresMfa(roles []string) bool {
+	for _, role := range roles {
+		if hasField(strings.Join(mfaRoles, " "), role) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withoutMfaRoles returns roles less mfaRoles.
+func withoutMfaRoles(roles []string) (r []string) {
+	for _, role := range roles {
+		if !requiresMfa([]string{role}) {
+			r = append(r, role)
+		}
+	}
+
+	return r
+}
+
+// PrincipalHasScopes reports whether principal holds every scope of scopes.
+func PrincipalHasScopes(principal *models.Principal, scopes []string) bool {
+	held := strings.Join(principal.Scopes, " ")
+	for _, v := range scopes {
+		if !hasField(held, v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// principalName identifies principal in user_operation records.
+func principalName(principal *models.Principal) string {
+	if principal.ClientId != "" {
+		return "service:" + principal.ClientId
+	}
+
+	return "user:" + principal.UserId
+}
@@ -1,23 +1,157 @@
 package services
 
 import (
+	"fmt"
 	"github.com/NeuronFramework/log"
-	"github.com/NeuronUser/user/storages/user_db"
+	"github.com/NeuronUser/user/blobstore"
+	"github.com/NeuronUser/user/storages"
+	"github.com/go-webauthn/webauthn/webauthn"
 	"go.uber.org/zap"
+	"os"
+	"time"
 )
 
 type UserService struct {
-	logger *zap.Logger
-	userDB *user_db.DB
+	logger        *zap.Logger
+	storage       storages.Storage
+	recentWriters *recentWriters
+	smsConfig     *SmsConfig
+	smsSender     SmsSender
+	secretPepper  []byte
+	// accessTokenSecret signs access and MFA tokens
+	accessTokenSecret []byte
+
+	passwordConfig    *PasswordConfig
+	dummyPasswordHash string
+
+	userNameConfig  *UserNameConfig
+	attributeConfig *AttributeConfig
+
+	// blobStore is nil when BLOB_STORE_DIR is not set
+	blobStore    blobstore.Store
+	avatarConfig *AvatarConfig
+	httpClient   HttpClient
+
+	totpIssuer string
+
+	// webAuthn is nil when WEBAUTHN_RP_ID is not set
+	webAuthn *webauthn.WebAuthn
+
+	// oauthProvider is nil when OIDC_ISSUER is not set
+	oauthProvider *oauthProvider
 }
 
 func NewUserService() (s *UserService, err error) {
+	storage, err := storages.NewStorageFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewUserServiceWithStorage(storage)
+}
+
+func NewUserServiceWithStorage(storage storages.Storage) (s *UserService, err error) {
 	s = &UserService{}
 	s.logger = log.TypedLogger(s)
-	s.userDB, err = user_db.NewDB()
+	s.storage = storage
+
+	readYourWritesWindow := time.Second * 5
+	if v := os.Getenv("DB_READ_YOUR_WRITES_WINDOW"); v != "" {
+		readYourWritesWindow, err = time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("DB_READ_YOUR_WRITES_WINDOW env invalid: %v", err)
+		}
+	}
+	s.recentWriters = newRecentWriters(readYourWritesWindow)
+
+	pepper := os.Getenv("SECRET_PEPPER")
+	if pepper == "" {
+		return nil, fmt.Errorf("SECRET_PEPPER env nil")
+	}
+	s.secretPepper = []byte(pepper)
+
+	accessTokenSecret := os.Getenv("ACCESS_TOKEN_SECRET")
+	if accessTokenSecret == "" {
+		return nil, fmt.Errorf("ACCESS_TOKEN_SECRET env nil")
+	}
+	if len(accessTokenSecret) < accessTokenSecretMinLength {
+		return nil, fmt.Errorf("ACCESS_TOKEN_SECRET env invalid: shorter than %d bytes", accessTokenSecretMinLength)
+	}
+	s.accessTokenSecret = []byte(accessTokenSecret)
+
+	s.smsConfig, err = NewSmsConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	s.smsSender = newLogSmsSender()
+
+	s.passwordConfig, err = NewPasswordConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	s.dummyPasswordHash, err = s.passwordConfig.hash("")
+	if err != nil {
+		return nil, err
+	}
+
+	s.userNameConfig, err = NewUserNameConfigFromEnv()
 	if err != nil {
 		return nil, err
 	}
 
+	s.attributeConfig, err = NewAttributeConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	s.blobStore, err = blobstore.NewStoreFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	s.avatarConfig, err = NewAvatarConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	s.httpClient = newOauthIconHttpClient()
+
+	// shown as the account's title in authenticator apps
+	s.totpIssuer = "NeuronUser"
+	if v := os.Getenv("TOTP_ISSUER"); v != "" {
+		s.totpIssuer = v
+	}
+
+	webAuthnConfig, err := NewWebAuthnConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	s.webAuthn, err = webAuthnConfig.newWebAuthn()
+	if err != nil {
+		return nil, err
+	}
+
+	oauthProviderConfig, err := NewOauthProviderConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	s.oauthProvider, err = oauthProviderConfig.newOauthProvider()
+	if err != nil {
+		return nil, err
+	}
+	if s.oauthProvider != nil && oauthProviderConfig.SigningKey == nil {
+		s.logger.Warn("OIDC_SIGNING_KEY not set, ID tokens are signed with a key generated at startup")
+	}
+
 	return s, nil
 }
+
+func (s *UserService) SetSmsSender(sender SmsSender) {
+	s.smsSender = sender
+}
+
+func (s *UserService) SetBlobStore(store blobstore.Store) {
+	s.blobStore = store
+}
+
+func (s *UserService) SetHttpClient(client HttpClient) {
+	s.httpClient = client
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronFramework/restful"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"go.uber.org/zap"
+	"io"
+	"strings"
+)
+
+const avatarKeyPrefix = "avatars/"
+
+// avatar versions are random, so every upload gets new URLs that caches
+// can't confuse with the old ones
+const avatarVersionLength = 8
+
+// UpdateUserIcon makes the avatar variants of an uploaded image, stores
+// them under avatars/<userId>/<version>/ and points user_icon at the
+// largest. The avatar it replaces is deleted if it was stored by us.
+func (s *UserService) UpdateUserIcon(ctx *restful.Context, userId string, r io.Reader) (userInfo *models.UserInfo, err error) {
+	if s.blobStore == nil {
+		return nil, errors.BadRequest("AvatarUploadDisabled", "暂不支持上传头像")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, s.avatarConfig.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.avatarConfig.MaxSize {
+		return nil, errors.BadRequest("AvatarTooLarge", "头像图片过大")
+	}
+
+	variants, err := s.avatarConfig.makeAvatarVariants(data)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, icon, err := s.storeAvatar(ctx, userId, variants)
+	if err != nil {
+		return nil, err
+	}
+
+	var dbUser *user_db.User
+	oldIcon := ""
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) (err error) {
+		dbUser, err = tx.Users().GetByUserIdForUpdate(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if dbUser == nil {
+			return errors.NotFound("用户信息不存在")
+		}
+
+		oldIcon = dbUser.UserIcon
+		dbUser.UserIcon = icon
+		return tx.Users().Update(ctx, dbUser)
+	})
+	if err != nil {
+		s.deleteAvatar(ctx, prefix)
+		return nil, err
+	}
+
+	s.recentWriters.Mark(userId)
+
+	if oldPrefix := s.avatarPrefix(userId, oldIcon); oldPrefix != "" {
+		s.deleteAvatar(ctx, oldPrefix)
+	}
+
+	return fromUserInfo(dbUser), nil
+}
+
+// storeAvatar stores variants as a new avatar of userId. It returns their
+// key prefix and the URL of the largest.
+func (s *UserService) storeAvatar(ctx context.Context, userId string, variants []*avatarVariant) (prefix string, icon string, err error) {
+	version, err := randomHex(avatarVersionLength)
+	if err != nil {
+		return "", "", err
+	}
+
+	prefix = avatarKeyPrefix + userId + "/" + version + "/"
+	icon = s.blobStore.URL(prefix + variants[0].name)
+	if len(icon) > userIconMaxLength {
+		return "", "", fmt.Errorf("avatar url %s longer than user_icon", icon)
+	}
+
+	for _, v := range variants {
+		err = s.blobStore.Put(ctx, prefix+v.name, v.contentType, v.data)
+		if err != nil {
+			s.deleteAvatar(ctx, prefix)
+			return "", "", err
+		}
+	}
+
+	return prefix, icon, nil
+}
+
+// avatarPrefix returns the key prefix of the avatar variants icon, a
+// user_icon of userId, belongs to, or "" if icon isn't stored by us.
+func (s *UserService) avatarPrefix(userId string, icon string) string {
+	base := s.blobStore.URL(avatarKeyPrefix + userId + "/")
+	if !strings.HasPrefix(icon, base) {
+		return ""
+	}
+
+	version := strings.SplitN(strings.TrimPrefix(icon, base), "/", 2)[0]
+	if _, err := hex.DecodeString(version); err != nil || len(version) != avatarVersionLength*2 {
+		return ""
+	}
+
+	return avatarKeyPrefix + userId + "/" + version + "/"
+}
+
+// deleteAvatar removes the variants under prefix. Failures only leave
+// unreferenced files behind, so they are logged rather than returned.
+func (s *UserService) deleteAvatar(ctx context.Context, prefix string) {
+	err := s.blobStore.DeletePrefix(ctx, prefix)
+	if err != nil {
+		s.logger.Warn("deleteAvatar", zap.String("prefix", prefix), zap.Error(err))
+	}
+}
@@ -1,19 +1,69 @@
 package services
 
 import (
+	"context"
 	"github.com/NeuronFramework/errors"
 	"github.com/NeuronFramework/restful"
 	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages"
 )
 
-func (s *UserService) GetUserInfo(ctx *restful.Context, userId string) (userInfo *models.UserInfo, err error) {
-	dbUserInfo, err := s.userDB.User.GetQuery().UserId_Equal(userId).QueryOne(ctx, nil)
+// GetUserInfo returns the fields of userId that principal asked for, see
+// parseUserInfoFields.
+func (s *UserService) GetUserInfo(ctx *restful.Context, principal *models.Principal, userId string, fields []string) (userInfo *models.UserInfo, err error) {
+	selection, err := s.parseUserInfoFields(principal, userId, fields)
 	if err != nil {
 		return nil, err
 	}
-	if dbUserInfo == nil {
+
+	userInfo, err = s.getUserInfo(ctx, userId, selection)
+	if err != nil {
+		return nil, err
+	}
+	if userInfo == nil {
 		return nil, errors.NotFound("用户信息不存在")
 	}
 
-	return fromUserInfo(dbUserInfo), nil
+	return userInfo, nil
+}
+
+// getUserInfo returns the selected fields of userId, or nil if there is no
+// such user.
+func (s *UserService) getUserInfo(ctx context.Context, userId string, selection *userInfoFields) (userInfo *models.UserInfo, err error) {
+	var queryCtx context.Context = ctx
+	if s.recentWriters.Contains(userId) {
+		queryCtx = storages.WithPrimary(ctx)
+	}
+
+	dbUserInfo, err := s.storage.Users().GetByUserId(queryCtx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if dbUserInfo == nil {
+		return nil, nil
+	}
+
+	userInfo = fromUserInfo(dbUserInfo)
+	selection.apply(userInfo)
+
+	if len(selection.namespaces) == 0 {
+		return userInfo, nil
+	}
+
+	dbAttributes, err := s.storage.Attributes().ListByUserId(queryCtx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	userInfo.Attributes = make(map[string]map[string]string, len(selection.namespaces))
+	for _, namespace := range selection.namespaces {
+		userInfo.Attributes[namespace] = map[string]string{}
+	}
+	for _, v := range dbAttributes {
+		if values, ok := userInfo.Attributes[v.Namespace]; ok {
+			values[v.AttrKey] = v.AttrValue
+		}
+	}
+
+	return userInfo, nil
 }
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"github.com/NeuronUser/user/storages"
+	"go.uber.org/zap"
+)
+
+// HashLegacySecrets rewrites the refresh tokens stored in plaintext before
+// secrets were hashed. It can be stopped and run again at any time. Legacy
+// SMS codes are left alone: they expire within minutes and are removed by
+// the janitor.
+func (s *UserService) HashLegacySecrets(ctx context.Context, batchSize int64) (hashed int64, err error) {
+	afterId := uint64(0)
+	for {
+		list, err := s.storage.Tokens().ListRefreshTokens(ctx, afterId, batchSize)
+		if err != nil {
+			return hashed, err
+		}
+
+		for _, v := range list {
+			afterId = v.Id
+			if isHashedSecret(v.RefreshToken) {
+				continue
+			}
+			if !isLegacyToken(v.RefreshToken) {
+				s.logger.Warn("HashLegacySecrets: unexpected refresh token format, skipped", zap.Uint64("id", v.Id))
+				continue
+			}
+
+			err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+				// re-read under lock so a concurrent logout isn't overwritten
+				dbToken, err := tx.Tokens().GetRefreshTokenForUpdate(ctx, v.RefreshToken)
+				if err != nil || dbToken == nil {
+					return err
+				}
+
+				dbToken.RefreshToken = s.hashSecret(dbToken.RefreshToken)
+				return tx.Tokens().UpdateRefreshToken(ctx, dbToken)
+			})
+			if err != nil {
+				return hashed, err
+			}
+			hashed++
+		}
+
+		if int64(len(list)) < batchSize {
+			return hashed, nil
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronFramework/restful"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"go.uber.org/zap"
+	"strings"
+	"unicode/utf8"
+)
+
+const impersonationReasonMaxLength = 256
+
+// Impersonate lets actor, a support staff member with user:admin, see the
+// app as userId. It returns an access token only; sensitive operations
+// refuse it. The actor, the user and the reason are recorded.
+func (s *UserService) Impersonate(ctx *restful.Context, actor *models.Principal, userId string, reason string, userAgent string) (token *models.Token, err error) {
+	if actor.UserId == "" || actor.ActorId != "" {
+		return nil, errors.BadRequest("ImpersonationNotAllowed", "不能代入其他用户")
+	}
+	if userId == actor.UserId {
+		return nil, errors.BadRequest("ImpersonationNotAllowed", "不能代入自己")
+	}
+	if !validUserId(userId) {
+		return nil, errors.NotFound("用户信息不存在")
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" || utf8.RuneCountInString(reason) > impersonationReasonMaxLength {
+		return nil, errors.BadRequest("InvalidImpersonationReason", "请填写代入原因")
+	}
+
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		dbUser, err := tx.Users().GetByUserId(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if dbUser == nil {
+			return errors.NotFound("用户信息不存在")
+		}
+
+		accessToken, err := s.newImpersonationToken(userId, actor.UserId)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Tokens().InsertAccessToken(ctx, &user_db.AccessToken{
+			UserId:      userId,
+			AccessToken: accessToken,
+		})
+		if err != nil {
+			return err
+		}
+
+		token = &models.Token{AccessToken: accessToken}
+		return tx.Operations().Insert(ctx, &user_db.UserOperation{
+			UserId:        userId,
+			OperationType: "Impersonate",
+			UserAgent:     truncate(userAgent, userAgentMaxLength),
+			ActorId:       actor.UserId,
+			Reason:        reason,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("impersonate", zap.String("actorId", actor.UserId), zap.String("userId", userId))
+
+	return token, nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"github.com/dgrijalva/jwt-go"
+	"strings"
+	"testing"
+)
+
+// newTestAdmin gives actorId the admin role, with TOTP enabled as it must
+// be.
+func newTestAdmin(t *testing.T, storage *storages.MemoryStorage, actorId string) *user_db.UserRole {
+	t.Helper()
+
+	ctx := context.Background()
+	role := &user_db.UserRole{UserId: actorId, Role: RoleAdmin}
+	assertError(t, storage.Roles().Insert(ctx, role), nil)
+	assertError(t, storage.TotpAccounts().Insert(ctx, &user_db.TotpAccount{UserId: actorId, IsEnabled: 1}), nil)
+	return role
+}
+
+func impersonate(t *testing.T, s *UserService, actorId string, userId string) string {
+	t.Helper()
+
+	actor := &models.Principal{UserId: actorId, AuthMethod: models.AuthMethodBearer}
+	token, err := s.Impersonate(newTestContext(), actor, userId, "ticket 42", "test")
+	assertError(t, err, nil)
+	return token.AccessToken
+}
+
+func TestImpersonationToken(t *testing.T) {
+	s, storage, sender := newTestService(t)
+	ctx := context.Background()
+
+	newTestAdmin(t, storage, "support1")
+	_, user := smsLogin(t, s, sender, "13800000000")
+
+	token := impersonate(t, s, "support1", user.UserId)
+	principal, err := s.AuthenticateAccessToken(ctx, token)
+	assertError(t, err, nil)
+	if principal.UserId != user.UserId || principal.ActorId != "support1" {
+		t.Fatalf("principal = %+v", principal)
+	}
+
+	// the user's own roles never reach the support staff member
+	newTestAdmin(t, storage, user.UserId)
+	principal, err = s.AuthenticateAccessToken(ctx, token)
+	assertError(t, err, nil)
+	if strings.Join(principal.Scopes, " ") != userScope(nil) || len(principal.Roles) != 0 {
+		t.Fatalf("impersonation principal = %+v", principal)
+	}
+}
+
+func TestImpersonationTokenNotRecorded(t *testing.T) {
+	s, storage, _ := newTestService(t)
+
+	newTestAdmin(t, storage, "support1")
+
+	// signed like one, but never issued by Impersonate
+	token, err := s.signAccessToken(accessTokenClaims{
+		StandardClaims: jwt.StandardClaims{Subject: "user1"},
+		Scope:          userScope(nil),
+		Actor:          &actorClaims{Subject: "support1"},
+	}, impersonationTokenLifetime)
+	assertError(t, err, nil)
+
+	_, err = s.AuthenticateAccessToken(context.Background(), token)
+	assertError(t, err, errTokenRevoked)
+}
+
+func TestImpersonationTokenRevoked(t *testing.T) {
+	s, storage, sender := newTestService(t)
+	ctx := context.Background()
+
+	newTestAdmin(t, storage, "support1")
+	_, user := smsLogin(t, s, sender, "13800000000")
+	token := impersonate(t, s, "support1", user.UserId)
+
+	admin := &models.Principal{ClientId: "ops", AuthMethod: models.AuthMethodBasic}
+	assertError(t, s.RevokeUserSessions(newTestContext(), user.UserId, admin), nil)
+
+	_, err := s.AuthenticateAccessToken(ctx, token)
+	assertError(t, err, errTokenRevoked)
+}
+
+func TestImpersonationTokenActorLosesAdmin(t *testing.T) {
+	s, storage, sender := newTestService(t)
+	ctx := context.Background()
+
+	role := newTestAdmin(t, storage, "support1")
+	_, user := smsLogin(t, s, sender, "13800000000")
+	token := impersonate(t, s, "support1", user.UserId)
+
+	assertError(t, storage.Roles().Delete(ctx, role), nil)
+
+	_, err := s.AuthenticateAccessToken(ctx, token)
+	if err == nil {
+		t.Fatalf("token of an actor who lost %s accepted", RoleAdmin)
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronFramework/restful"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"strings"
+)
+
+const batchGetUserInfoMaxCount = 100
+
+// AuthenticateService checks the Basic credentials of a service calling the
+// private API. It returns nil, and no error, if they are not those of a
+// client limited to client_credentials.
+func (s *UserService) AuthenticateService(ctx context.Context, clientId string, clientSecret string) (principal *models.Principal, err error) {
+	dbClient, err := s.authenticateServiceClient(ctx, clientId, clientSecret)
+	if err != nil {
+		if _, ok := err.(*OauthError); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if dbClient.GrantTypes != oauthGrantClientCredentials {
+		return nil, nil
+	}
+
+	return &models.Principal{
+		ClientId:   dbClient.ClientId,
+		Scopes:     strings.Fields(dbClient.Scope),
+		AuthMethod: models.AuthMethodBasic,
+	}, nil
+}
+
+// validUserId rejects anything that can't be a user id before it reaches the
+// query builders, which inline values into the SQL text.
+func validUserId(userId string) bool {
+	if userId == "" || len(userId) > 32 {
+		return false
+	}
+
+	for _, r := range userId {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-' || r == '_') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// BatchGetUserInfo returns the fields principal asked for of the users of
+// userIds that exist, in the order asked for. Unknown and duplicate ids are
+// left out. The fields are checked once for all users, with no userId, so
+// the access users have to their own attributes never applies here: only
+// admins and services with a namespace's scope get its attributes.
+func (s *UserService) BatchGetUserInfo(ctx *restful.Context, principal *models.Principal, userIds []string, fields []string) (list []*models.UserInfo, err error) {
+	if len(userIds) > batchGetUserInfoMaxCount {
+		return nil, errors.BadRequest("TooManyUserIds", "一次最多查询100个用户")
+	}
+
+	selection, err := s.parseUserInfoFields(principal, "", fields)
+	if err != nil {
+		return nil, err
+	}
+
+	list = make([]*models.UserInfo, 0, len(userIds))
+	seen := make(map[string]bool, len(userIds))
+	for _, userId := range userIds {
+		if seen[userId] || !validUserId(userId) {
+			continue
+		}
+		seen[userId] = true
+
+		userInfo, err := s.getUserInfo(ctx, userId, selection)
+		if err != nil {
+			return nil, err
+		}
+		if userInfo != nil {
+			list = append(list, userInfo)
+		}
+	}
+
+	return list, nil
+}
+
+// RevokeUserSessions signs a user out of every session and revokes the
+// impersonation tokens issued for them, e.g. when their account was
+// compromised. admin, a service or an admin user, is recorded.
+func (s *UserService) RevokeUserSessions(ctx *restful.Context, userId string, admin *models.Principal) (err error) {
+	if !validUserId(userId) {
+		return errors.NotFound("用户信息不存在")
+	}
+
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		dbUser, err := tx.Users().GetByUserId(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if dbUser == nil {
+			return errors.NotFound("用户信息不存在")
+		}
+
+		dbTokens, err := tx.Tokens().ListRefreshTokensByUserId(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		_, err = logoutRefreshTokens(ctx, tx, dbTokens)
+		if err != nil {
+			return err
+		}
+
+		// impersonation tokens belong to no session
+		dbAccessTokens, err := tx.Tokens().ListSessionlessAccessTokensForUpdate(ctx, userId)
+		if err != nil {
+			return err
+		}
+		err = revokeAccessTokens(ctx, tx, dbAccessTokens)
+		if err != nil {
+			return err
+		}
+
+		return tx.Operations().Insert(ctx, &user_db.UserOperation{
+			UserId:        userId,
+			OperationType: "RevokeUserSessions",
+			UserAgent:     truncate(principalName(admin), userAgentMaxLength),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.recentWriters.Mark(userId)
+	return nil
+}
@@ -0,0 +1,496 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronFramework/restful"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"net/url"
+	"strings"
+	"time"
+)
+
+func (s *UserService) checkOauthProviderEnabled() error {
+	if s.oauthProvider == nil {
+		return errors.BadRequest("OauthProviderDisabled", "未开启第三方应用登录")
+	}
+
+	return nil
+}
+
+// OauthProviderEnabled reports whether OIDC_ISSUER is set.
+func (s *UserService) OauthProviderEnabled() bool {
+	return s.oauthProvider != nil
+}
+
+// OauthDiscovery is the OpenID Provider Metadata served at
+// /.well-known/openid-configuration.
+func (s *UserService) OauthDiscovery() map[string]interface{} {
+	return s.oauthProvider.discovery()
+}
+
+// OauthJwks is the JSON Web Key Set ID tokens are verified with.
+func (s *UserService) OauthJwks() map[string]interface{} {
+	return s.oauthProvider.jwks()
+}
+
+// RegisterOauthClient registers an application. Public clients, such as
+// native and single page apps, get no secret and must use PKCE alone.
+func (s *UserService) RegisterOauthClient(ctx context.Context, client *models.OauthClient, public bool) (r *models.OauthClient, err error) {
+	if client.ClientName == "" || len(client.ClientName) > 128 {
+		return nil, errors.BadRequest("InvalidClientName", "应用名称不合法")
+	}
+
+	grantTypes := strings.Join(client.GrantTypes, " ")
+	if grantTypes == "" || !scopeSubset(grantTypes, strings.Join(oauthSupportedGrants, " ")) {
+		return nil, errors.BadRequest("InvalidGrantType", "授权类型不支持")
+	}
+
+	// services using client_credentials alone never redirect users
+	if len(client.RedirectUris) == 0 && hasField(grantTypes, oauthGrantAuthorizationCode) || len(strings.Join(client.RedirectUris, " ")) > 2048 {
+		return nil, errors.BadRequest("InvalidRedirectUri", "回调地址不合法")
+	}
+	for _, v := range client.RedirectUris {
+		if !validRedirectUri(v) {
+			return nil, errors.BadRequest("InvalidRedirectUri", "回调地址不合法")
+		}
+	}
+	if public && hasField(grantTypes, oauthGrantClientCredentials) {
+		return nil, errors.BadRequest("InvalidGrantType", "公开应用不能使用 client_credentials")
+	}
+
+	allowedScopes := strings.Join(oauthSupportedScopes, " ")
+	if grantTypes == oauthGrantClientCredentials {
+		allowedScopes += " " + strings.Join(serviceScopes, " ") + " " + strings.Join(s.attributeConfig.scopes(), " ")
+	}
+
+	scope := normalizeScope(client.Scope)
+	if !scopeSubset(scope, allowedScopes) {
+		return nil, errors.BadRequest("InvalidScope", "授权范围不支持")
+	}
+
+	r = &models.OauthClient{
+		ClientName:   client.ClientName,
+		RedirectUris: client.RedirectUris,
+		GrantTypes:   client.GrantTypes,
+		Scope:        scope,
+	}
+
+	r.ClientId, err = randomHex(oauthClientIdLength)
+	if err != nil {
+		return nil, err
+	}
+
+	hashedSecret := ""
+	if !public {
+		r.ClientSecret, err = randomHex(32)
+		if err != nil {
+			return nil, err
+		}
+		hashedSecret = s.hashSecret(r.ClientSecret)
+	}
+
+	err = s.storage.OauthClients().Insert(ctx, &user_db.OauthClient{
+		ClientId:     r.ClientId,
+		ClientSecret: hashedSecret,
+		ClientName:   r.ClientName,
+		RedirectUris: strings.Join(r.RedirectUris, " "),
+		GrantTypes:   grantTypes,
+		Scope:        r.Scope,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Authorize issues an authorization code to the client of r for the signed
+// in user, who has consented on the authorize page. It returns the URI to
+// send the browser back to, which carries any error that the client should
+// see; errors that make the redirect URI untrustworthy are returned instead.
+func (s *UserService) Authorize(ctx *restful.Context, userId string, r *models.AuthorizeRequest, userAgent string) (redirectUri string, err error) {
+	err = s.checkOauthProviderEnabled()
+	if err != nil {
+		return "", err
+	}
+
+	if !validOauthClientId(r.ClientId) {
+		return "", errors.BadRequest("InvalidOauthClient", "应用不存在")
+	}
+
+	client, err := s.storage.OauthClients().GetByClientId(ctx, r.ClientId)
+	if err != nil {
+		return "", err
+	}
+	if client == nil {
+		return "", errors.BadRequest("InvalidOauthClient", "应用不存在")
+	}
+
+	if !hasField(client.RedirectUris, r.RedirectUri) {
+		return "", errors.BadRequest("InvalidRedirectUri", "回调地址不匹配")
+	}
+
+	redirect := func(params url.Values) (string, error) {
+		u, err := url.Parse(r.RedirectUri)
+		if err != nil {
+			return "", err
+		}
+
+		q := u.Query()
+		for k, v := range params {
+			q[k] = v
+		}
+		if r.State != "" {
+			q.Set("state", r.State)
+		}
+		q.Set("iss", s.oauthProvider.config.Issuer)
+		u.RawQuery = q.Encode()
+
+		return u.String(), nil
+	}
+	redirectError := func(e *OauthError) (string, error) {
+		return redirect(url.Values{"error": {e.Code}, "error_description": {e.Description}})
+	}
+
+	if r.ResponseType != "code" {
+		return redirectError(newOauthError("unsupported_response_type", "response_type must be code"))
+	}
+	if !hasField(client.GrantTypes, oauthGrantAuthorizationCode) {
+		return redirectError(newOauthError("unauthorized_client", "client may not use the authorization code grant"))
+	}
+	if r.CodeChallengeMethod != "S256" || len(r.CodeChallenge) != 43 || !validCodeVerifier(r.CodeChallenge) {
+		return redirectError(newOauthError("invalid_request", "PKCE with code_challenge_method S256 is required"))
+	}
+	if len(r.Nonce) > 256 {
+		return redirectError(newOauthError("invalid_request", "nonce too long"))
+	}
+
+	scope := normalizeScope(r.Scope)
+	if scope == "" {
+		scope = client.Scope
+	}
+	if !scopeSubset(scope, client.Scope) {
+		return redirectError(newOauthError("invalid_scope", "scope not allowed for client"))
+	}
+
+	code, err := randomHex(32)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		err := tx.OauthCodes().Insert(ctx, &user_db.OauthAuthorizationCode{
+			Code:          s.hashSecret(code),
+			ClientId:      client.ClientId,
+			UserId:        userId,
+			RedirectUri:   r.RedirectUri,
+			Scope:         scope,
+			Nonce:         r.Nonce,
+			CodeChallenge: r.CodeChallenge,
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Operations().Insert(ctx, &user_db.UserOperation{
+			UserId:        userId,
+			OperationType: "AuthorizeOauthClient",
+			UserAgent:     truncate(userAgent, userAgentMaxLength),
+		})
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return redirect(url.Values{"code": {code}})
+}
+
+// authenticateOauthClient checks the credentials the token endpoint was
+// called with. Public clients have no secret and send none.
+func (s *UserService) authenticateOauthClient(ctx context.Context, clientId string, clientSecret string) (client *user_db.OauthClient, err error) {
+	if !validOauthClientId(clientId) {
+		return nil, newOauthError("invalid_client", "client authentication failed")
+	}
+
+	client, err = s.storage.OauthClients().GetByClientId(ctx, clientId)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, newOauthError("invalid_client", "client authentication failed")
+	}
+
+	if client.ClientSecret == "" && clientSecret == "" {
+		return client, nil
+	}
+	if client.ClientSecret == "" || !s.secretEqual(client.ClientSecret, clientSecret) {
+		return nil, newOauthError("invalid_client", "client authentication failed")
+	}
+
+	return client, nil
+}
+
+// OauthToken is the OAuth2 token endpoint. Errors the client caused are
+// *OauthError.
+func (s *UserService) OauthToken(ctx *restful.Context, r *models.OauthTokenRequest, userAgent string, clientIp string) (token *models.OauthToken, err error) {
+	err = s.checkOauthProviderEnabled()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := s.authenticateOauthClient(ctx, r.ClientId, r.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.GrantType == "" {
+		return nil, newOauthError("invalid_request", "grant_type is required")
+	}
+	if !hasField(client.GrantTypes, r.GrantType) {
+		return nil, newOauthError("unauthorized_client", "client may not use this grant type")
+	}
+
+	switch r.GrantType {
+	case oauthGrantAuthorizationCode:
+		return s.exchangeOauthCode(ctx, client, r, userAgent, clientIp)
+	case oauthGrantRefreshToken:
+		return s.refreshOauthToken(ctx, client, r, userAgent, clientIp)
+	case oauthGrantClientCredentials:
+		return s.issueClientCredentialsToken(ctx, client, r)
+	default:
+		return nil, newOauthError("unsupported_grant_type", "grant type not supported")
+	}
+}
+
+// exchangeOauthCode redeems an authorization code for a new session. A code
+// presented twice was intercepted, so the session it was exchanged for is
+// revoked (RFC 6749 4.1.2).
+func (s *UserService) exchangeOauthCode(ctx context.Context, client *user_db.OauthClient, r *models.OauthTokenRequest, userAgent string, clientIp string) (token *models.OauthToken, err error) {
+	if r.Code == "" || len(r.Code) > 128 || !validCodeVerifier(r.CodeVerifier) {
+		return nil, newOauthError("invalid_grant", "invalid authorization code")
+	}
+
+	var verifyErr error
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		verifyErr = nil
+
+		code, err := tx.OauthCodes().GetByCodeForUpdate(ctx, s.hashSecret(r.Code))
+		if err != nil {
+			return err
+		}
+		if code == nil || code.ClientId != client.ClientId {
+			verifyErr = newOauthError("invalid_grant", "invalid authorization code")
+			return nil
+		}
+
+		if code.IsUsed == 1 {
+			if code.SessionId != "" {
+				family, err := tx.Tokens().ListRefreshTokensBySessionIdForUpdate(ctx, code.SessionId)
+				if err != nil {
+					return err
+				}
+				_, err = logoutRefreshTokens(ctx, tx, family)
+				if err != nil {
+					return err
+				}
+			}
+			verifyErr = newOauthError("invalid_grant", "invalid authorization code")
+			return nil
+		}
+
+		challenge := codeChallengeS256(r.CodeVerifier)
+		if time.Since(code.CreateTime) > s.oauthProvider.config.CodeLifetime ||
+			code.RedirectUri != r.RedirectUri ||
+			subtle.ConstantTimeCompare([]byte(challenge), []byte(code.CodeChallenge)) != 1 {
+			verifyErr = newOauthError("invalid_grant", "invalid authorization code")
+			return nil
+		}
+
+		sessionId, err := randomHex(16)
+		if err != nil {
+			return err
+		}
+
+		pair, err := s.issueSessionToken(ctx, tx, &user_db.RefreshToken{
+			UserId:    code.UserId,
+			SessionId: sessionId,
+			UserAgent: truncate(userAgent, userAgentMaxLength),
+			ClientIp:  clientIp,
+			LoginTime: time.Now(),
+			ClientId:  client.ClientId,
+			Scope:     code.Scope,
+		}, "")
+		if err != nil {
+			return err
+		}
+
+		code.IsUsed = 1
+		code.SessionId = sessionId
+		err = tx.OauthCodes().Update(ctx, code)
+		if err != nil {
+			return err
+		}
+
+		token = fromOauthToken(pair, code.Scope)
+		if hasField(code.Scope, oauthScopeOpenId) {
+			token.IdToken, err = s.oauthProvider.newIdToken(code.UserId, client.ClientId, code.Nonce)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if verifyErr != nil {
+		return nil, verifyErr
+	}
+
+	return token, nil
+}
+
+// refreshOauthToken rotates a refresh token issued to client. The new access
+// token has the requested scope, by default the scope of the session; asking
+// for more than that is an error. The new refresh token keeps the session
+// scope (RFC 6749 6).
+func (s *UserService) refreshOauthToken(ctx context.Context, client *user_db.OauthClient, r *models.OauthTokenRequest, userAgent string, clientIp string) (token *models.OauthToken, err error) {
+	if r.RefreshToken == "" {
+		return nil, newOauthError("invalid_request", "refresh_token is required")
+	}
+
+	scope := normalizeScope(r.Scope)
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		pair, session, err := s.rotateRefreshToken(ctx, tx, r.RefreshToken, client.ClientId, scope, userAgent, clientIp)
+		if err != nil || pair == nil {
+			return err
+		}
+
+		// rolls the rotation back
+		if !scopeSubset(scope, session.Scope) {
+			return newOauthError("invalid_scope", "scope exceeds the granted scope")
+		}
+		if scope == "" {
+			scope = session.Scope
+		}
+
+		token = fromOauthToken(pair, scope)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, newOauthError("invalid_grant", "invalid refresh token")
+	}
+
+	return token, nil
+}
+
+// issueClientCredentialsToken issues an access token for the client itself,
+// whose subject and audience are the client id. There is no refresh token.
+func (s *UserService) issueClientCredentialsToken(ctx context.Context, client *user_db.OauthClient, r *models.OauthTokenRequest) (token *models.OauthToken, err error) {
+	if client.ClientSecret == "" {
+		return nil, newOauthError("unauthorized_client", "public clients may not use client_credentials")
+	}
+
+	scope := normalizeScope(r.Scope)
+	if scope == "" {
+		scope = client.Scope
+	}
+	if !scopeSubset(scope, client.Scope) {
+		return nil, newOauthError("invalid_scope", "scope not allowed for client")
+	}
+
+	accessToken, err := s.newAccessToken(client.ClientId, client.ClientId, "", scope, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.storage.Tokens().InsertAccessToken(ctx, &user_db.AccessToken{AccessToken: accessToken})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OauthToken{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(accessTokenLifetime / time.Second),
+		Scope:       scope,
+	}, nil
+}
+
+func fromOauthToken(p *models.Token, scope string) *models.OauthToken {
+	return &models.OauthToken{
+		AccessToken:  p.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(accessTokenLifetime / time.Second),
+		RefreshToken: p.RefreshToken,
+		Scope:        scope,
+	}
+}
+
+// OauthUserInfo returns the OpenID Connect claims of the user accessToken
+// was issued to, as allowed by its scope.
+func (s *UserService) OauthUserInfo(ctx *restful.Context, accessToken string) (claims map[string]interface{}, err error) {
+	err = s.checkOauthProviderEnabled()
+	if err != nil {
+		return nil, err
+	}
+
+	tokenClaims, err := s.parseAccessTokenClaims(accessToken)
+	if err != nil || tokenClaims.ClientId == "" || tokenClaims.Subject == tokenClaims.ClientId {
+		return nil, newOauthError("invalid_token", "invalid access token")
+	}
+	if !hasField(tokenClaims.Scope, oauthScopeOpenId) {
+		return nil, newOauthError("insufficient_scope", "openid scope required")
+	}
+
+	active, err := s.accessTokenActive(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, newOauthError("invalid_token", "invalid access token")
+	}
+
+	userInfo, err := s.getUserInfo(ctx, tokenClaims.Subject, &userInfoFields{standard: map[string]bool{
+		"name":     true,
+		"icon":     true,
+		"gender":   true,
+		"birthday": true,
+		"locale":   true,
+		"timezone": true,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	if userInfo == nil {
+		return nil, errors.NotFound("用户信息不存在")
+	}
+
+	claims = map[string]interface{}{"sub": userInfo.UserID}
+	if hasField(tokenClaims.Scope, oauthScopeProfile) {
+		claims["name"] = userInfo.Name
+		claims["preferred_username"] = userInfo.Name
+		// the other standard claims (OIDC Core 5.1) only if set
+		for k, v := range map[string]string{
+			"picture":   userInfo.Icon,
+			"gender":    userInfo.Gender,
+			"birthdate": userInfo.Birthday,
+			"locale":    userInfo.Locale,
+			"zoneinfo":  userInfo.Timezone,
+		} {
+			if v != "" {
+				claims[k] = v
+			}
+		}
+	}
+
+	return claims, nil
+}
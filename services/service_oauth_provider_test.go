@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"testing"
+	"time"
+)
+
+var errScopeExceeded = newOauthError("invalid_scope", "scope exceeds the granted scope")
+
+func TestRefreshOauthTokenNarrowsScope(t *testing.T) {
+	t.Setenv("OIDC_ISSUER", "https://api.example.com/api/v1/users")
+	s, _, _ := newTestService(t)
+	ctx := context.Background()
+
+	client := &user_db.OauthClient{ClientId: "0123456789abcdef0123456789abcdef"}
+	var pair *models.Token
+	err := s.storage.Transaction(ctx, func(tx storages.Storage) (err error) {
+		pair, err = s.issueSessionToken(ctx, tx, &user_db.RefreshToken{
+			UserId:    "user1",
+			SessionId: "session1",
+			LoginTime: time.Now(),
+			ClientId:  client.ClientId,
+			Scope:     "openid profile",
+		}, "")
+		return err
+	})
+	assertError(t, err, nil)
+
+	refresh := func(refreshToken string, scope string, want string) *models.OauthToken {
+		t.Helper()
+
+		token, err := s.refreshOauthToken(ctx, client, &models.OauthTokenRequest{RefreshToken: refreshToken, Scope: scope}, "test", "10.0.0.1")
+		assertError(t, err, nil)
+		claims, err := s.parseAccessTokenClaims(token.AccessToken)
+		assertError(t, err, nil)
+		if token.Scope != want || claims.Scope != want {
+			t.Fatalf("refresh with scope %q: scope = %q, token scope = %q, want %q", scope, token.Scope, claims.Scope, want)
+		}
+		return token
+	}
+
+	token := refresh(pair.RefreshToken, " profile ", "profile")
+
+	// the refresh token keeps the scope of the session
+	token = refresh(token.RefreshToken, "", "openid profile")
+
+	_, err = s.refreshOauthToken(ctx, client, &models.OauthTokenRequest{RefreshToken: token.RefreshToken, Scope: "openid email"}, "test", "10.0.0.1")
+	assertError(t, err, errScopeExceeded)
+
+	// the refused request rotated nothing
+	refresh(token.RefreshToken, "openid", "openid")
+}
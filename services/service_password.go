@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronFramework/restful"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"time"
+)
+
+func (s *UserService) passwordLocked(account *user_db.PasswordAccount) bool {
+	return account.FailedCount >= s.passwordConfig.MaxAttempts &&
+		time.Since(account.UpdateTime) < s.passwordConfig.Lockout
+}
+
+// passwordAttempt is a password checked against an account before any
+// transaction is opened, so that the slow hash doesn't run while the
+// account's row is locked.
+type passwordAttempt struct {
+	userId string
+	// hash is the stored hash the password was checked against.
+	hash   string
+	ok     bool
+	rehash bool
+}
+
+// checkPassword verifies password against the account of userId, without
+// locking it. It returns a nil attempt, having spent the time of a real
+// check, if there is no such account, and verifyErr if it is locked.
+func (s *UserService) checkPassword(ctx context.Context, userId string, password string) (attempt *passwordAttempt, verifyErr error, err error) {
+	var account *user_db.PasswordAccount
+	if userId != "" {
+		account, err = s.storage.PasswordAccounts().GetByUserId(storages.WithPrimary(ctx), userId)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if account == nil {
+		// spend the same time as a real check so that response times
+		// don't tell which user names have a password
+		s.passwordConfig.verify(s.dummyPasswordHash, password)
+		return nil, nil, nil
+	}
+	if s.passwordLocked(account) {
+		return nil, errors.BadRequest("PasswordLocked", "密码错误次数过多，请稍后再试"), nil
+	}
+
+	attempt = &passwordAttempt{userId: userId, hash: account.PasswordHash}
+	attempt.ok, attempt.rehash, err = s.passwordConfig.verify(account.PasswordHash, password)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return attempt, nil, nil
+}
+
+// recordPasswordAttempt locks the account attempt was checked against and
+// counts a failed attempt in tx. verifyErr is wrong if the attempt failed
+// or the password was changed since it was checked, and PasswordLocked if
+// concurrent attempts locked the account meanwhile. Otherwise the locked
+// account is returned for the caller to update.
+func (s *UserService) recordPasswordAttempt(ctx context.Context, tx storages.Storage, attempt *passwordAttempt, wrong error) (account *user_db.PasswordAccount, verifyErr error, err error) {
+	account, err = tx.PasswordAccounts().GetByUserIdForUpdate(ctx, attempt.userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if account == nil || account.PasswordHash != attempt.hash {
+		return nil, wrong, nil
+	}
+	if s.passwordLocked(account) {
+		return nil, errors.BadRequest("PasswordLocked", "密码错误次数过多，请稍后再试"), nil
+	}
+
+	if !attempt.ok {
+		account.FailedCount++
+		return nil, wrong, tx.PasswordAccounts().Update(ctx, account)
+	}
+
+	return account, nil, nil
+}
+
+// SetPassword adds a password credential to a user that has none.
+func (s *UserService) SetPassword(ctx *restful.Context, userId string, password string, userAgent string) (err error) {
+	err = s.passwordConfig.validate(password)
+	if err != nil {
+		return err
+	}
+
+	passwordHash, err := s.passwordConfig.hash(password)
+	if err != nil {
+		return err
+	}
+
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		account, err := tx.PasswordAccounts().GetByUserIdForUpdate(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if account != nil {
+			return errors.BadRequest("PasswordExists", "已设置密码")
+		}
+
+		err = tx.PasswordAccounts().Insert(ctx, &user_db.PasswordAccount{
+			UserId:       userId,
+			PasswordHash: passwordHash,
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Operations().Insert(ctx, &user_db.UserOperation{
+			UserId:        userId,
+			OperationType: "SetPassword",
+			UserAgent:     truncate(userAgent, userAgentMaxLength),
+		})
+	})
+	if err == storages.ErrDuplicate {
+		return errors.BadRequest("PasswordExists", "已设置密码")
+	}
+
+	return err
+}
+
+func (s *UserService) ChangePassword(ctx *restful.Context, userId string, oldPassword string, newPassword string, userAgent string) (err error) {
+	err = s.passwordConfig.validate(newPassword)
+	if err != nil {
+		return err
+	}
+
+	passwordHash, err := s.passwordConfig.hash(newPassword)
+	if err != nil {
+		return err
+	}
+
+	attempt, verifyErr, err := s.checkPassword(ctx, userId, oldPassword)
+	if err != nil {
+		return err
+	}
+	if verifyErr != nil {
+		return verifyErr
+	}
+	if attempt == nil {
+		return errors.BadRequest("PasswordNotSet", "未设置密码")
+	}
+
+	// a wrong old password must still commit the failed attempt
+	wrong := errors.BadRequest("WrongPassword", "密码错误")
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		var account *user_db.PasswordAccount
+		account, verifyErr, err = s.recordPasswordAttempt(ctx, tx, attempt, wrong)
+		if err != nil || verifyErr != nil {
+			return err
+		}
+
+		account.PasswordHash = passwordHash
+		account.FailedCount = 0
+		err = tx.PasswordAccounts().Update(ctx, account)
+		if err != nil {
+			return err
+		}
+
+		return tx.Operations().Insert(ctx, &user_db.UserOperation{
+			UserId:        userId,
+			OperationType: "ChangePassword",
+			UserAgent:     truncate(userAgent, userAgentMaxLength),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return verifyErr
+}
+
+func (s *UserService) PasswordLogin(ctx *restful.Context, userName string, password string, userAgent string, clientIp string) (token *models.Token, err error) {
+	userName = normalizeUserName(userName)
+	err = validateUserName(userName)
+	if err != nil {
+		return nil, err
+	}
+
+	wrong := errors.BadRequest("WrongPassword", "用户名或密码错误")
+
+	// the password is checked before the transaction, which then only
+	// records the outcome, so that Argon2id doesn't run while the account
+	// is locked
+	user, err := s.storage.Users().GetByUserName(storages.WithPrimary(ctx), userName)
+	if err != nil {
+		return nil, err
+	}
+	userId := ""
+	if user != nil {
+		userId = user.UserId
+	}
+
+	attempt, verifyErr, err := s.checkPassword(ctx, userId, password)
+	if err != nil {
+		return nil, err
+	}
+	if verifyErr != nil {
+		return nil, verifyErr
+	}
+	if attempt == nil {
+		return nil, wrong
+	}
+
+	rehashed := ""
+	if attempt.ok && attempt.rehash {
+		rehashed, err = s.passwordConfig.hash(password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		var account *user_db.PasswordAccount
+		account, verifyErr, err = s.recordPasswordAttempt(ctx, tx, attempt, wrong)
+		if err != nil || verifyErr != nil {
+			return err
+		}
+
+		if rehashed != "" || account.FailedCount != 0 {
+			if rehashed != "" {
+				account.PasswordHash = rehashed
+			}
+			account.FailedCount = 0
+			err = tx.PasswordAccounts().Update(ctx, account)
+			if err != nil {
+				return err
+			}
+		}
+
+		token, err = s.completeLogin(ctx, tx, userId, userAgent, clientIp)
+		if err != nil {
+			return err
+		}
+
+		return tx.Operations().Insert(ctx, &user_db.UserOperation{
+			UserId:        userId,
+			OperationType: "PasswordLogin",
+			UserAgent:     truncate(userAgent, userAgentMaxLength),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if verifyErr != nil {
+		return nil, verifyErr
+	}
+
+	return token, nil
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"testing"
+)
+
+var (
+	errWrongPassword  = errors.BadRequest("WrongPassword", "用户名或密码错误")
+	errPasswordLocked = errors.BadRequest("PasswordLocked", "密码错误次数过多，请稍后再试")
+)
+
+// newPasswordTestUser signs a user up by SMS, stores passwordHash as their
+// password and returns their id and user name.
+func newPasswordTestUser(t *testing.T, s *UserService, storage *storages.MemoryStorage, sender *testSmsSender, passwordHash string) (string, string) {
+	t.Helper()
+
+	_, principal := smsLogin(t, s, sender, "13800000000")
+	dbUser, err := storage.Users().GetByUserId(context.Background(), principal.UserId)
+	assertError(t, err, nil)
+
+	err = storage.PasswordAccounts().Insert(context.Background(), &user_db.PasswordAccount{
+		UserId:       principal.UserId,
+		PasswordHash: passwordHash,
+	})
+	assertError(t, err, nil)
+
+	return principal.UserId, dbUser.UserName
+}
+
+func TestPasswordLoginRehash(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	assertError(t, err, nil)
+
+	weaker := *newTestPasswordConfig()
+	weaker.Memory = 512
+	weak, err := weaker.hash("correct horse")
+	assertError(t, err, nil)
+
+	tests := []struct {
+		name   string
+		stored string
+	}{
+		{"bcrypt", string(legacy)},
+		{"weaker argon2id", weak},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, storage, sender := newTestService(t)
+			userId, userName := newPasswordTestUser(t, s, storage, sender, tt.stored)
+
+			// a wrong password leaves the hash alone
+			_, err := s.PasswordLogin(newTestContext(), userName, "battery staple", "test", "10.0.0.1")
+			assertError(t, err, errWrongPassword)
+
+			token, err := s.PasswordLogin(newTestContext(), userName, "correct horse", "test", "10.0.0.1")
+			assertError(t, err, nil)
+			if token.AccessToken == "" {
+				t.Fatalf("login got %+v", token)
+			}
+
+			account, err := storage.PasswordAccounts().GetByUserId(context.Background(), userId)
+			assertError(t, err, nil)
+			if account.PasswordHash == tt.stored {
+				t.Fatalf("hash not replaced")
+			}
+			if ok, rehash, err := s.passwordConfig.verify(account.PasswordHash, "correct horse"); err != nil || !ok || rehash {
+				t.Fatalf("new hash %s: verify = %v, %v, %v", account.PasswordHash, ok, rehash, err)
+			}
+			if account.FailedCount != 0 {
+				t.Fatalf("failed_count = %d after a login", account.FailedCount)
+			}
+		})
+	}
+}
+
+func TestPasswordLoginLockout(t *testing.T) {
+	s, storage, sender := newTestService(t)
+	s.passwordConfig.MaxAttempts = 3
+
+	hash, err := s.passwordConfig.hash("correct horse")
+	assertError(t, err, nil)
+	userId, userName := newPasswordTestUser(t, s, storage, sender, hash)
+
+	for i := int32(0); i < s.passwordConfig.MaxAttempts; i++ {
+		_, err := s.PasswordLogin(newTestContext(), userName, "battery staple", "test", "10.0.0.1")
+		assertError(t, err, errWrongPassword)
+	}
+
+	// locked, for the right password and for ChangePassword too
+	_, err = s.PasswordLogin(newTestContext(), userName, "correct horse", "test", "10.0.0.1")
+	assertError(t, err, errPasswordLocked)
+	assertError(t, s.ChangePassword(newTestContext(), userId, "correct horse", "new password", "test"), errPasswordLocked)
+
+	// as if the lockout had passed
+	s.passwordConfig.Lockout = 0
+	_, err = s.PasswordLogin(newTestContext(), userName, "correct horse", "test", "10.0.0.1")
+	assertError(t, err, nil)
+
+	account, err := storage.PasswordAccounts().GetByUserId(context.Background(), userId)
+	assertError(t, err, nil)
+	if account.FailedCount != 0 {
+		t.Fatalf("failed_count = %d after a login", account.FailedCount)
+	}
+}
+
+func TestSetAndChangePassword(t *testing.T) {
+	s, storage, sender := newTestService(t)
+	_, principal := smsLogin(t, s, sender, "13800000000")
+	dbUser, err := storage.Users().GetByUserId(context.Background(), principal.UserId)
+	assertError(t, err, nil)
+
+	assertError(t, s.SetPassword(newTestContext(), principal.UserId, "short", "test"),
+		errors.BadRequest("InvalidPassword", "密码不能少于8位"))
+	assertError(t, s.SetPassword(newTestContext(), principal.UserId, "correct horse", "test"), nil)
+	assertError(t, s.SetPassword(newTestContext(), principal.UserId, "correct horse", "test"),
+		errors.BadRequest("PasswordExists", "已设置密码"))
+
+	assertError(t, s.ChangePassword(newTestContext(), principal.UserId, "wrong password", "battery staple", "test"),
+		errors.BadRequest("WrongPassword", "密码错误"))
+	assertError(t, s.ChangePassword(newTestContext(), principal.UserId, "correct horse", strings.Repeat("a", passwordMaxLength+1), "test"),
+		errors.BadRequest("InvalidPassword", "密码过长"))
+	assertError(t, s.ChangePassword(newTestContext(), principal.UserId, "correct horse", "battery staple", "test"), nil)
+
+	_, err = s.PasswordLogin(newTestContext(), dbUser.UserName, "correct horse", "test", "10.0.0.1")
+	assertError(t, err, errWrongPassword)
+	_, err = s.PasswordLogin(newTestContext(), dbUser.UserName, "battery staple", "test", "10.0.0.1")
+	assertError(t, err, nil)
+}
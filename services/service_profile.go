@@ -0,0 +1,141 @@
+package services
+
+import (
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronFramework/restful"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+)
+
+// UpdateProfile replaces the standard profile fields of userId, and returns
+// them along with the user's name and icon.
+func (s *UserService) UpdateProfile(ctx *restful.Context, userId string, profile *models.Profile) (userInfo *models.UserInfo, err error) {
+	profile, err = normalizeProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var dbUser *user_db.User
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) (err error) {
+		dbUser, err = tx.Users().GetByUserIdForUpdate(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if dbUser == nil {
+			return errors.NotFound("用户信息不存在")
+		}
+
+		dbUser.Bio = profile.Bio
+		dbUser.Gender = profile.Gender
+		dbUser.Birthday = profile.Birthday
+		dbUser.Locale = profile.Locale
+		dbUser.Timezone = profile.Timezone
+		return tx.Users().Update(ctx, dbUser)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.recentWriters.Mark(userId)
+
+	return fromUserInfo(dbUser), nil
+}
+
+// SetUserAttributes sets the attributes of userId in namespace to values,
+// for principal: the user, a service or an admin. Keys missing from values
+// are kept, and an empty value removes its key. It returns all attributes of
+// the namespace.
+func (s *UserService) SetUserAttributes(ctx *restful.Context, principal *models.Principal, userId string, namespace string, values map[string]string) (attributes map[string]string, err error) {
+	if !validUserId(userId) {
+		return nil, errors.NotFound("用户信息不存在")
+	}
+	if _, ok := s.attributeConfig.Namespaces[namespace]; !ok {
+		return nil, errors.BadRequest("UnknownNamespace", "属性命名空间不存在")
+	}
+	if !s.attributeConfig.allowed(principal, userId, namespace, AttributeAccessWrite) {
+		return nil, errors.BadRequest("NamespaceNotWritable", "无权修改该命名空间的属性")
+	}
+	if len(values) > attributeMaxKeys {
+		return nil, errors.BadRequest("TooManyAttributes", "属性数量超过限制")
+	}
+	for key, value := range values {
+		err = validateAttributeKey(key)
+		if err != nil {
+			return nil, err
+		}
+		err = validateAttributeValue(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		dbUser, err := tx.Users().GetByUserId(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if dbUser == nil {
+			return errors.NotFound("用户信息不存在")
+		}
+
+		dbAttributes, err := tx.Attributes().ListByNamespaceForUpdate(ctx, userId, namespace)
+		if err != nil {
+			return err
+		}
+
+		existing := make(map[string]*user_db.UserAttribute, len(dbAttributes))
+		for _, v := range dbAttributes {
+			existing[v.AttrKey] = v
+		}
+
+		attributes = make(map[string]string, len(dbAttributes)+len(values))
+		for _, v := range dbAttributes {
+			attributes[v.AttrKey] = v.AttrValue
+		}
+		for key, value := range values {
+			if value == "" {
+				delete(attributes, key)
+			} else {
+				attributes[key] = value
+			}
+		}
+		if len(attributes) > attributeMaxKeys {
+			return errors.BadRequest("TooManyAttributes", "属性数量超过限制")
+		}
+
+		for key, value := range values {
+			dbAttribute := existing[key]
+			switch {
+			case dbAttribute == nil && value == "":
+			case dbAttribute == nil:
+				err = tx.Attributes().Insert(ctx, &user_db.UserAttribute{
+					UserId:    userId,
+					Namespace: namespace,
+					AttrKey:   key,
+					AttrValue: value,
+				})
+			case value == "":
+				err = tx.Attributes().Delete(ctx, dbAttribute)
+			case dbAttribute.AttrValue != value:
+				dbAttribute.AttrValue = value
+				err = tx.Attributes().Update(ctx, dbAttribute)
+			}
+			if err != nil {
+				if err == storages.ErrDuplicate {
+					return errors.BadRequest("AttributeConflict", "属性正在被修改，请稍后重试")
+				}
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.recentWriters.Mark(userId)
+
+	return attributes, nil
+}
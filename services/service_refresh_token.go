@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronFramework/restful"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"go.uber.org/zap"
+	"time"
+)
+
+// RefreshToken exchanges a refresh token for a new token pair in the same
+// session. The old refresh token can't be used again: presenting a token
+// that was already rotated means it was stolen, so the whole session is
+// revoked.
+func (s *UserService) RefreshToken(ctx *restful.Context, refreshToken string, userAgent string, clientIp string) (token *models.Token, err error) {
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		token, _, err = s.rotateRefreshToken(ctx, tx, refreshToken, "", "", userAgent, clientIp)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, errors.BadRequest("InvalidRefreshToken", "登录已失效，请重新登录")
+	}
+
+	return token, nil
+}
+
+// rotateRefreshToken replaces refreshToken, which must have been issued to
+// clientId ("" for our own apps), with a new token pair whose access token
+// has scope, as for issueSessionToken. It returns a nil
+// token, and nil error so that any revocation is committed, if refreshToken
+// is not valid.
+func (s *UserService) rotateRefreshToken(ctx context.Context, tx storages.Storage, refreshToken string, clientId string, scope string, userAgent string, clientIp string) (token *models.Token, session *user_db.RefreshToken, err error) {
+	dbToken, err := s.getRefreshTokenForUpdate(ctx, tx, refreshToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	if dbToken == nil || dbToken.ClientId != clientId {
+		return nil, nil, nil
+	}
+	if dbToken.IsLogout == 1 {
+		return nil, nil, s.revokeReusedSession(ctx, tx, dbToken, userAgent, clientIp)
+	}
+
+	dbToken.IsLogout = 1
+	dbToken.LogoutTime = time.Now()
+	err = tx.Tokens().UpdateRefreshToken(ctx, dbToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	session = &user_db.RefreshToken{}
+	*session = *dbToken
+	session.UserAgent = truncate(userAgent, userAgentMaxLength)
+	session.ClientIp = clientIp
+	token, err = s.issueSessionToken(ctx, tx, session, scope)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return token, session, nil
+}
+
+// revokeReusedSession is called with a refresh token that is no longer
+// valid. If a newer token was issued to its session, dbToken was rotated and
+// is being replayed: every token of the session (the token family) is
+// revoked and the reuse is recorded. A token that was simply logged out is
+// the newest of its session and changes nothing.
+func (s *UserService) revokeReusedSession(ctx context.Context, tx storages.Storage, dbToken *user_db.RefreshToken, userAgent string, clientIp string) error {
+	if dbToken.SessionId == "" {
+		return nil
+	}
+
+	family, err := tx.Tokens().ListRefreshTokensBySessionIdForUpdate(ctx, dbToken.SessionId)
+	if err != nil {
+		return err
+	}
+	if len(family) == 0 || family[0].Id == dbToken.Id {
+		return nil
+	}
+
+	revoked, err := logoutRefreshTokens(ctx, tx, family)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Warn("refresh token reused",
+		zap.String("userId", dbToken.UserId),
+		zap.String("sessionId", dbToken.SessionId),
+		zap.String("clientIp", clientIp),
+		zap.Int("revoked", revoked))
+
+	return tx.Operations().Insert(ctx, &user_db.UserOperation{
+		UserId:        dbToken.UserId,
+		OperationType: "RefreshTokenReuse",
+		UserAgent:     truncate(userAgent, userAgentMaxLength),
+	})
+}
+
+// logoutRefreshTokens logs out the tokens of list that are still live, and
+// revokes the access tokens of the sessions they belonged to.
+func logoutRefreshTokens(ctx context.Context, tx storages.Storage, list []*user_db.RefreshToken) (n int, err error) {
+	sessions := make(map[string]bool)
+	for _, v := range list {
+		if v.IsLogout == 1 {
+			continue
+		}
+
+		v.IsLogout = 1
+		v.LogoutTime = time.Now()
+		err = tx.Tokens().UpdateRefreshToken(ctx, v)
+		if err != nil {
+			return n, err
+		}
+		n++
+
+		if v.SessionId != "" && !sessions[v.SessionId] {
+			sessions[v.SessionId] = true
+			err = revokeSessionAccessTokens(ctx, tx, v.SessionId)
+			if err != nil {
+				return n, err
+			}
+		}
+	}
+
+	return n, nil
+}
+
+// revokeSessionAccessTokens revokes the access tokens issued to a session.
+func revokeSessionAccessTokens(ctx context.Context, tx storages.Storage, sessionId string) error {
+	dbTokens, err := tx.Tokens().ListAccessTokensBySessionIdForUpdate(ctx, sessionId)
+	if err != nil {
+		return err
+	}
+
+	return revokeAccessTokens(ctx, tx, dbTokens)
+}
+
+// revokeAccessTokens revokes the tokens of list that are still live.
+func revokeAccessTokens(ctx context.Context, tx storages.Storage, list []*user_db.AccessToken) error {
+	for _, v := range list {
+		if v.IsRevoked == 1 {
+			continue
+		}
+
+		v.IsRevoked = 1
+		if err := tx.Tokens().UpdateAccessToken(ctx, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *UserService) Logout(ctx *restful.Context, refreshToken string) (err error) {
+	return s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		dbToken, err := s.getRefreshTokenForUpdate(ctx, tx, refreshToken)
+		if err != nil {
+			return err
+		}
+		if dbToken == nil {
+			return nil
+		}
+
+		_, err = logoutRefreshTokens(ctx, tx, []*user_db.RefreshToken{dbToken})
+		return err
+	})
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+)
+
+// GrantRole gives userId role. Access tokens get its scopes from the next
+// login or refresh. mfaRoles can only be given to users with TOTP enabled.
+func (s *UserService) GrantRole(ctx context.Context, userId string, role string) (err error) {
+	if _, ok := roleScopes[role]; !ok {
+		return errors.BadRequest("InvalidRole", "角色不存在")
+	}
+	if !validUserId(userId) {
+		return errors.NotFound("用户信息不存在")
+	}
+
+	return s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		dbUser, err := tx.Users().GetByUserId(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if dbUser == nil {
+			return errors.NotFound("用户信息不存在")
+		}
+
+		if requiresMfa([]string{role}) {
+			enabled, err := totpEnabled(ctx, tx, userId)
+			if err != nil {
+				return err
+			}
+			if !enabled {
+				return errors.BadRequest("MfaRequired", "该用户须先开启两步验证")
+			}
+		}
+
+		err = tx.Roles().Insert(ctx, &user_db.UserRole{UserId: userId, Role: role})
+		if err == storages.ErrDuplicate {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		return tx.Operations().Insert(ctx, &user_db.UserOperation{
+			UserId:        userId,
+			OperationType: "GrantRole",
+		})
+	})
+}
+
+// RevokeRole takes role away from userId. Access tokens already issued lose
+// its scopes at once, as BearerAuth reads the roles of every request.
+func (s *UserService) RevokeRole(ctx context.Context, userId string, role string) (err error) {
+	if !validUserId(userId) {
+		return errors.NotFound("用户信息不存在")
+	}
+
+	return s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		dbRoles, err := tx.Roles().ListByUserId(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range dbRoles {
+			if v.Role != role {
+				continue
+			}
+
+			err = tx.Roles().Delete(ctx, v)
+			if err != nil {
+				return err
+			}
+
+			return tx.Operations().Insert(ctx, &user_db.UserOperation{
+				UserId:        userId,
+				OperationType: "RevokeRole",
+			})
+		}
+
+		return nil
+	})
+}
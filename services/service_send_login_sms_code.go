@@ -0,0 +1,69 @@
+package services
+
+import (
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronFramework/restful"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"time"
+)
+
+func (s *UserService) SendLoginSmsCode(ctx *restful.Context, phoneNumber string, clientIp string) (err error) {
+	err = validatePhoneNumber(phoneNumber)
+	if err != nil {
+		return err
+	}
+
+	smsCode, err := newSmsCode()
+	if err != nil {
+		return err
+	}
+
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		now := time.Now()
+
+		// locks the phone's latest code so concurrent requests are counted
+		// one after another
+		latest, err := tx.LoginSmsCodes().GetLatestByPhoneNumberForUpdate(ctx, phoneNumber)
+		if err != nil {
+			return err
+		}
+		if latest != nil {
+			if latest.VerifyCount >= s.smsConfig.MaxAttempts && now.Sub(latest.UpdateTime) < s.smsConfig.Lockout {
+				return errors.BadRequest("SmsCodeLocked", "验证码错误次数过多，请稍后再试")
+			}
+			if now.Sub(latest.CreateTime) < s.smsConfig.SendInterval {
+				return errors.BadRequest("SmsCodeTooFrequent", "验证码发送过于频繁，请稍后再试")
+			}
+		}
+
+		sent, err := tx.LoginSmsCodes().ListByPhoneNumber(ctx, phoneNumber, s.smsConfig.PhoneDailyLimit)
+		if err != nil {
+			return err
+		}
+		if int64(len(sent)) >= s.smsConfig.PhoneDailyLimit && now.Sub(sent[len(sent)-1].CreateTime) < time.Hour*24 {
+			return errors.BadRequest("SmsCodeQuotaExceeded", "今日验证码发送次数已达上限")
+		}
+
+		// requests whose IP is unknown share one bucket rather than going
+		// uncounted
+		sent, err = tx.LoginSmsCodes().ListByClientIp(ctx, clientIp, s.smsConfig.IpHourlyLimit)
+		if err != nil {
+			return err
+		}
+		if int64(len(sent)) >= s.smsConfig.IpHourlyLimit && now.Sub(sent[len(sent)-1].CreateTime) < time.Hour {
+			return errors.BadRequest("SmsCodeQuotaExceeded", "验证码发送过于频繁，请稍后再试")
+		}
+
+		return tx.LoginSmsCodes().Insert(ctx, &user_db.LoginSmsCode{
+			PhoneNumber: phoneNumber,
+			SmsCode:     s.hashSecret(smsCode),
+			ClientIp:    clientIp,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.smsSender.SendLoginSmsCode(ctx, phoneNumber, smsCode)
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronFramework/restful"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"sort"
+)
+
+// ListMySessions returns the user's signed in sessions, most recently used
+// first. currentSessionId is the session asking, if known.
+func (s *UserService) ListMySessions(ctx *restful.Context, userId string, currentSessionId string) (sessions []*models.Session, err error) {
+	var queryCtx context.Context = ctx
+	if s.recentWriters.Contains(userId) {
+		queryCtx = storages.WithPrimary(ctx)
+	}
+
+	dbTokens, err := s.storage.Tokens().ListRefreshTokensByUserId(queryCtx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions = make([]*models.Session, 0)
+	for _, v := range dbTokens {
+		if v.IsLogout == 1 {
+			continue
+		}
+		session := fromSession(v)
+		session.Current = currentSessionId != "" && v.SessionId == currentSessionId
+		sessions = append(sessions, session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedTime.After(sessions[j].LastUsedTime)
+	})
+
+	return sessions, nil
+}
+
+// RevokeSession signs the user out of one of their sessions and revokes the
+// access tokens already issued to it.
+func (s *UserService) RevokeSession(ctx *restful.Context, userId string, sessionId string, userAgent string) (err error) {
+	if _, err := hex.DecodeString(sessionId); err != nil || len(sessionId) != 32 {
+		return errors.NotFound("会话不存在")
+	}
+
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		dbTokens, err := tx.Tokens().ListRefreshTokensByUserId(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		session := make([]*user_db.RefreshToken, 0)
+		for _, v := range dbTokens {
+			if v.SessionId == sessionId {
+				session = append(session, v)
+			}
+		}
+
+		n, err := logoutRefreshTokens(ctx, tx, session)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return errors.NotFound("会话不存在")
+		}
+
+		return tx.Operations().Insert(ctx, &user_db.UserOperation{
+			UserId:        userId,
+			OperationType: "RevokeSession",
+			UserAgent:     truncate(userAgent, userAgentMaxLength),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.recentWriters.Mark(userId)
+	return nil
+}
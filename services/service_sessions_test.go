@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronUser/user/models"
+	"testing"
+)
+
+var (
+	errSessionNotFound = errors.NotFound("会话不存在")
+	errTokenRevoked    = errors.Unknown("验证失败： token revoked")
+)
+
+// smsLogin signs phone in and returns its token and principal.
+func smsLogin(t *testing.T, s *UserService, sender *testSmsSender, phone string) (*models.Token, *models.Principal) {
+	t.Helper()
+
+	ctx := newTestContext()
+	s.smsConfig.SendInterval = 0
+	assertError(t, s.SendLoginSmsCode(ctx, phone, "10.0.0.1"), nil)
+	token, err := s.SmsLogin(ctx, phone, sender.code(phone), "test", "10.0.0.1")
+	assertError(t, err, nil)
+
+	principal, err := s.AuthenticateAccessToken(context.Background(), token.AccessToken)
+	assertError(t, err, nil)
+	if principal.SessionId == "" {
+		t.Fatalf("access token carries no session")
+	}
+	return token, principal
+}
+
+func TestRevokeSessionRevokesAccessTokens(t *testing.T) {
+	s, storage, sender := newTestService(t)
+	ctx := newTestContext()
+
+	token, principal := smsLogin(t, s, sender, "13800000000")
+	other, _ := smsLogin(t, s, sender, "13800000000")
+
+	assertError(t, s.RevokeSession(ctx, principal.UserId, principal.SessionId, "test"), nil)
+
+	_, err := s.AuthenticateAccessToken(context.Background(), token.AccessToken)
+	assertError(t, err, errTokenRevoked)
+	dbToken, err := storage.Tokens().GetAccessToken(context.Background(), token.AccessToken)
+	assertError(t, err, nil)
+	if dbToken.IsRevoked != 1 {
+		t.Fatalf("access token of the revoked session is not marked revoked")
+	}
+
+	// the user's other session is untouched
+	_, err = s.AuthenticateAccessToken(context.Background(), other.AccessToken)
+	assertError(t, err, nil)
+
+	assertError(t, s.RevokeSession(ctx, principal.UserId, principal.SessionId, "test"), errSessionNotFound)
+}
+
+func TestLogoutRevokesAccessTokens(t *testing.T) {
+	s, _, sender := newTestService(t)
+
+	token, _ := smsLogin(t, s, sender, "13800000000")
+	assertError(t, s.Logout(newTestContext(), token.RefreshToken), nil)
+
+	_, err := s.AuthenticateAccessToken(context.Background(), token.AccessToken)
+	assertError(t, err, errTokenRevoked)
+}
+
+func TestRevokeUserSessionsRevokesAccessTokens(t *testing.T) {
+	s, _, sender := newTestService(t)
+
+	first, principal := smsLogin(t, s, sender, "13800000000")
+	second, _ := smsLogin(t, s, sender, "13800000000")
+
+	admin := &models.Principal{ClientId: "ops", AuthMethod: models.AuthMethodBasic}
+	assertError(t, s.RevokeUserSessions(newTestContext(), principal.UserId, admin), nil)
+
+	for _, token := range []*models.Token{first, second} {
+		_, err := s.AuthenticateAccessToken(context.Background(), token.AccessToken)
+		assertError(t, err, errTokenRevoked)
+	}
+}
+
+func TestAuthenticateAccessTokenSignedOutSession(t *testing.T) {
+	s, storage, sender := newTestService(t)
+	ctx := context.Background()
+
+	token, principal := smsLogin(t, s, sender, "13800000000")
+
+	// a session signed out before its access tokens were revoked with it
+	family, err := storage.Tokens().ListRefreshTokensBySessionId(ctx, principal.SessionId)
+	assertError(t, err, nil)
+	family[0].IsLogout = 1
+	assertError(t, storage.Tokens().UpdateRefreshToken(ctx, family[0]), nil)
+
+	_, err = s.AuthenticateAccessToken(ctx, token.AccessToken)
+	assertError(t, err, errTokenRevoked)
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronFramework/restful"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"time"
+	"unicode/utf8"
+)
+
+const userAgentMaxLength = 256
+
+func (s *UserService) SmsLogin(ctx *restful.Context, phoneNumber string, smsCode string, userAgent string, clientIp string) (token *models.Token, err error) {
+	err = validatePhoneNumber(phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// a wrong guess must still commit the attempt, so verification
+	// failures are reported through verifyErr instead of rolling back
+	var verifyErr error
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		verifyErr = nil
+
+		code, err := tx.LoginSmsCodes().GetLatestByPhoneNumberForUpdate(ctx, phoneNumber)
+		if err != nil {
+			return err
+		}
+		if code == nil || code.IsUsed == 1 || time.Since(code.CreateTime) > s.smsConfig.CodeLifetime {
+			verifyErr = errors.BadRequest("InvalidSmsCode", "验证码错误或已过期")
+			return nil
+		}
+		if code.VerifyCount >= s.smsConfig.MaxAttempts {
+			verifyErr = errors.BadRequest("SmsCodeLocked", "验证码错误次数过多，请稍后再试")
+			return nil
+		}
+
+		code.VerifyCount++
+		if !s.secretEqual(code.SmsCode, smsCode) {
+			verifyErr = errors.BadRequest("InvalidSmsCode", "验证码错误或已过期")
+			return tx.LoginSmsCodes().Update(ctx, code)
+		}
+
+		code.IsUsed = 1
+		return tx.LoginSmsCodes().Update(ctx, code)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if verifyErr != nil {
+		return nil, verifyErr
+	}
+
+	// the code is spent even if the login below fails, or a failing login
+	// could be retried with it without ever counting an attempt
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		userId, err := s.getOrCreatePhoneUser(ctx, tx, phoneNumber)
+		if err != nil {
+			return err
+		}
+
+		token, err = s.completeLogin(ctx, tx, userId, userAgent, clientIp)
+		if err != nil {
+			return err
+		}
+
+		return tx.Operations().Insert(ctx, &user_db.UserOperation{
+			UserId:        userId,
+			OperationType: "SmsLogin",
+			UserAgent:     truncate(userAgent, userAgentMaxLength),
+			PhoneNumber:   phoneNumber,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// getOrCreatePhoneUser returns the user bound to phoneNumber, registering a
+// new one on first login. New users are named after the end of their phone
+// number until they choose a name.
+func (s *UserService) getOrCreatePhoneUser(ctx *restful.Context, tx storages.Storage, phoneNumber string) (userId string, err error) {
+	account, err := tx.PhoneAccounts().GetByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		return "", err
+	}
+	if account != nil {
+		return account.UserId, nil
+	}
+
+	userId, err = randomHex(16)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.insertUser(ctx, tx, &user_db.User{UserId: userId}, phoneUserName(phoneNumber))
+	if err != nil {
+		return "", err
+	}
+
+	err = tx.PhoneAccounts().Insert(ctx, &user_db.PhoneAccount{
+		UserId:      userId,
+		PhoneNumber: phoneNumber,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return userId, nil
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	return string([]rune(s)[:n])
+}
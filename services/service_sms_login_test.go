@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronUser/user/storages/user_db"
+	"testing"
+)
+
+var (
+	errSmsCodeTooFrequent = errors.BadRequest("SmsCodeTooFrequent", "验证码发送过于频繁，请稍后再试")
+	errSmsPhoneQuota      = errors.BadRequest("SmsCodeQuotaExceeded", "今日验证码发送次数已达上限")
+	errSmsIpQuota         = errors.BadRequest("SmsCodeQuotaExceeded", "验证码发送过于频繁，请稍后再试")
+	errSmsCodeLocked      = errors.BadRequest("SmsCodeLocked", "验证码错误次数过多，请稍后再试")
+	errInvalidSmsCode     = errors.BadRequest("InvalidSmsCode", "验证码错误或已过期")
+	errMfaRequired        = errors.BadRequest("MfaRequired", "管理员账号须开启两步验证后才能登录")
+)
+
+// wrongSmsCode returns a code of the same length that differs from code.
+func wrongSmsCode(code string) string {
+	last := code[len(code)-1]
+	return code[:len(code)-1] + string('0'+(last-'0'+1)%10)
+}
+
+func TestSendLoginSmsCodeInterval(t *testing.T) {
+	s, _, _ := newTestService(t)
+	ctx := newTestContext()
+
+	assertError(t, s.SendLoginSmsCode(ctx, "13800000000", "10.0.0.1"), nil)
+	assertError(t, s.SendLoginSmsCode(ctx, "13800000000", "10.0.0.2"), errSmsCodeTooFrequent)
+
+	s.smsConfig.SendInterval = 0
+	assertError(t, s.SendLoginSmsCode(ctx, "13800000000", "10.0.0.2"), nil)
+}
+
+func TestSendLoginSmsCodePhoneDailyLimit(t *testing.T) {
+	s, _, _ := newTestService(t)
+	ctx := newTestContext()
+	s.smsConfig.SendInterval = 0
+	s.smsConfig.PhoneDailyLimit = 3
+
+	for i := 0; i < 3; i++ {
+		assertError(t, s.SendLoginSmsCode(ctx, "13800000000", fmt.Sprintf("10.0.0.%d", i)), nil)
+	}
+	assertError(t, s.SendLoginSmsCode(ctx, "13800000000", "10.0.0.9"), errSmsPhoneQuota)
+
+	// other phones have their own quota
+	assertError(t, s.SendLoginSmsCode(ctx, "13900000000", "10.0.0.9"), nil)
+}
+
+func TestSendLoginSmsCodeIpHourlyLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		clientIp string
+	}{
+		{"known ip", "10.0.0.1"},
+		// requests without an IP share a bucket instead of going uncounted
+		{"missing ip", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, _ := newTestService(t)
+			ctx := newTestContext()
+			s.smsConfig.IpHourlyLimit = 2
+
+			assertError(t, s.SendLoginSmsCode(ctx, "13800000001", tt.clientIp), nil)
+			assertError(t, s.SendLoginSmsCode(ctx, "13800000002", tt.clientIp), nil)
+			assertError(t, s.SendLoginSmsCode(ctx, "13800000003", tt.clientIp), errSmsIpQuota)
+
+			assertError(t, s.SendLoginSmsCode(ctx, "13800000003", "10.0.0.99"), nil)
+		})
+	}
+}
+
+func TestSmsLoginLockout(t *testing.T) {
+	s, _, sender := newTestService(t)
+	ctx := newTestContext()
+	s.smsConfig.SendInterval = 0
+
+	const phone = "13800000000"
+	assertError(t, s.SendLoginSmsCode(ctx, phone, "10.0.0.1"), nil)
+	code := sender.code(phone)
+
+	for i := int32(0); i < s.smsConfig.MaxAttempts; i++ {
+		_, err := s.SmsLogin(ctx, phone, wrongSmsCode(code), "test", "10.0.0.1")
+		assertError(t, err, errInvalidSmsCode)
+	}
+
+	// the code is burnt, even the right one is refused now
+	_, err := s.SmsLogin(ctx, phone, code, "test", "10.0.0.1")
+	assertError(t, err, errSmsCodeLocked)
+
+	// and no new code is sent until the lockout is over
+	assertError(t, s.SendLoginSmsCode(ctx, phone, "10.0.0.1"), errSmsCodeLocked)
+
+	s.smsConfig.Lockout = 0
+	assertError(t, s.SendLoginSmsCode(ctx, phone, "10.0.0.1"), nil)
+	_, err = s.SmsLogin(ctx, phone, sender.code(phone), "test", "10.0.0.1")
+	assertError(t, err, nil)
+}
+
+func TestSmsLoginCodeUsedOnce(t *testing.T) {
+	s, _, sender := newTestService(t)
+	ctx := newTestContext()
+
+	const phone = "13800000000"
+	assertError(t, s.SendLoginSmsCode(ctx, phone, "10.0.0.1"), nil)
+	code := sender.code(phone)
+
+	_, err := s.SmsLogin(ctx, phone, code, "test", "10.0.0.1")
+	assertError(t, err, nil)
+	_, err = s.SmsLogin(ctx, phone, code, "test", "10.0.0.1")
+	assertError(t, err, errInvalidSmsCode)
+}
+
+func TestSmsLoginFailedLoginUsesCode(t *testing.T) {
+	s, storage, sender := newTestService(t)
+	ctx := newTestContext()
+	s.smsConfig.SendInterval = 0
+
+	const phone = "13800000000"
+	_, principal := smsLogin(t, s, sender, phone)
+
+	// admins without TOTP can't get tokens
+	role := &user_db.UserRole{UserId: principal.UserId, Role: RoleAdmin}
+	assertError(t, storage.Roles().Insert(context.Background(), role), nil)
+
+	assertError(t, s.SendLoginSmsCode(ctx, phone, "10.0.0.1"), nil)
+	code := sender.code(phone)
+	_, err := s.SmsLogin(ctx, phone, code, "test", "10.0.0.1")
+	assertError(t, err, errMfaRequired)
+
+	// the failed login spent the code and counted the attempt
+	dbCode, err := storage.LoginSmsCodes().GetLatestByPhoneNumberForUpdate(context.Background(), phone)
+	assertError(t, err, nil)
+	if dbCode.IsUsed != 1 || dbCode.VerifyCount != 1 {
+		t.Fatalf("code used = %d, verify count = %d, want 1 and 1", dbCode.IsUsed, dbCode.VerifyCount)
+	}
+	_, err = s.SmsLogin(ctx, phone, code, "test", "10.0.0.1")
+	assertError(t, err, errInvalidSmsCode)
+}
+
+func TestSmsLoginExpiredCode(t *testing.T) {
+	s, _, sender := newTestService(t)
+	ctx := newTestContext()
+	s.smsConfig.CodeLifetime = 0
+
+	const phone = "13800000000"
+	assertError(t, s.SendLoginSmsCode(ctx, phone, "10.0.0.1"), nil)
+
+	_, err := s.SmsLogin(ctx, phone, sender.code(phone), "test", "10.0.0.1")
+	assertError(t, err, errInvalidSmsCode)
+}
+
+func TestSmsLoginGetOrCreatePhoneUser(t *testing.T) {
+	s, storage, sender := newTestService(t)
+	ctx := newTestContext()
+	s.smsConfig.SendInterval = 0
+
+	login := func(phone string) string {
+		t.Helper()
+
+		assertError(t, s.SendLoginSmsCode(ctx, phone, "10.0.0.1"), nil)
+		token, err := s.SmsLogin(ctx, phone, sender.code(phone), "test", "10.0.0.1")
+		assertError(t, err, nil)
+
+		principal, err := s.ParseAccessToken(token.AccessToken)
+		assertError(t, err, nil)
+		return principal.UserId
+	}
+
+	first := login("13800001234")
+	if again := login("13800001234"); again != first {
+		t.Fatalf("second login got user %s, want %s", again, first)
+	}
+
+	user, err := storage.Users().GetByUserId(context.Background(), first)
+	assertError(t, err, nil)
+	if user == nil || user.UserName != phoneUserName("13800001234") {
+		t.Fatalf("user = %+v, want one named %s", user, phoneUserName("13800001234"))
+	}
+
+	// another phone ending in the same digits registers another user under
+	// a name of its own
+	other := login("13900001234")
+	if other == first {
+		t.Fatalf("second phone logged into the first phone's user")
+	}
+	otherUser, err := storage.Users().GetByUserId(context.Background(), other)
+	assertError(t, err, nil)
+	if otherUser.UserName == user.UserName {
+		t.Fatalf("both users named %s", user.UserName)
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"github.com/NeuronFramework/restful"
+	"github.com/NeuronUser/user/storages"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testSmsSender keeps the last code sent to each phone.
+type testSmsSender struct {
+	mutex sync.Mutex
+	codes map[string]string
+}
+
+func (s *testSmsSender) SendLoginSmsCode(ctx context.Context, phoneNumber string, smsCode string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.codes[phoneNumber] = smsCode
+	return nil
+}
+
+func (s *testSmsSender) code(phoneNumber string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.codes[phoneNumber]
+}
+
+// newTestService returns a UserService on a MemoryStorage with the default
+// config, cheap password hashing, and an SMS sender the test can read
+// codes back from.
+func newTestService(t *testing.T) (*UserService, *storages.MemoryStorage, *testSmsSender) {
+	t.Helper()
+
+	t.Setenv("SECRET_PEPPER", "test-pepper")
+	t.Setenv("ACCESS_TOKEN_SECRET", strings.Repeat("k", accessTokenSecretMinLength))
+	t.Setenv("PASSWORD_ARGON2_TIME", "1")
+	t.Setenv("PASSWORD_ARGON2_MEMORY", "1024")
+
+	storage := storages.NewMemoryStorage()
+	s, err := NewUserServiceWithStorage(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sender := &testSmsSender{codes: map[string]string{}}
+	s.SetSmsSender(sender)
+
+	return s, storage, sender
+}
+
+func newTestContext() *restful.Context {
+	return restful.NewContext(httptest.NewRequest("POST", "/", nil))
+}
+
+// assertError fails t unless err is the error want describes, comparing
+// their messages since the errors package builds a new value each time.
+func assertError(t *testing.T, err error, want error) {
+	t.Helper()
+
+	switch {
+	case want == nil && err != nil:
+		t.Fatalf("err = %v, want nil", err)
+	case want != nil && (err == nil || err.Error() != want.Error()):
+		t.Fatalf("err = %v, want %v", err, want)
+	}
+}
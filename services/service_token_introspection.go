@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"github.com/NeuronFramework/restful"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+)
+
+// authenticateServiceClient checks the credentials introspection and
+// revocation were called with. Only confidential clients allowed the
+// client_credentials grant, i.e. our own services, may call them.
+func (s *UserService) authenticateServiceClient(ctx context.Context, clientId string, clientSecret string) (client *user_db.OauthClient, err error) {
+	if clientSecret == "" {
+		return nil, newOauthError("invalid_client", "client authentication failed")
+	}
+
+	client, err = s.authenticateOauthClient(ctx, clientId, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasField(client.GrantTypes, oauthGrantClientCredentials) {
+		return nil, newOauthError("unauthorized_client", "client may not introspect or revoke tokens")
+	}
+
+	return client, nil
+}
+
+// IntrospectToken tells the calling service whether token, an access or
+// refresh token, is active (RFC 7662). Access tokens are inactive once
+// revoked or once the session they were issued to has signed out, the same
+// as in BearerAuth. Errors the client caused are *OauthError; unknown
+// tokens are merely inactive.
+func (s *UserService) IntrospectToken(ctx *restful.Context, clientId string, clientSecret string, token string) (r *models.TokenIntrospection, err error) {
+	_, err = s.authenticateServiceClient(ctx, clientId, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if token == "" {
+		return nil, newOauthError("invalid_request", "token is required")
+	}
+
+	// revocations must show up at once, so nothing is read from a replica
+	queryCtx := storages.WithPrimary(ctx)
+
+	if !isJwt(token) {
+		return s.introspectRefreshToken(queryCtx, token)
+	}
+
+	// expired and forged access tokens are merely inactive
+	claims, err := s.parseAccessTokenClaims(token)
+	if err != nil {
+		return &models.TokenIntrospection{}, nil
+	}
+
+	return s.introspectAccessToken(queryCtx, token, claims)
+}
+
+func (s *UserService) introspectAccessToken(ctx context.Context, token string, claims *accessTokenClaims) (r *models.TokenIntrospection, err error) {
+	if claims.Audience == mfaTokenAudience {
+		return &models.TokenIntrospection{}, nil
+	}
+
+	active, err := s.accessTokenActive(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return &models.TokenIntrospection{}, nil
+	}
+
+	return &models.TokenIntrospection{
+		Active:    true,
+		Subject:   claims.Subject,
+		ClientId:  claims.ClientId,
+		Scope:     claims.Scope,
+		TokenType: "Bearer",
+		ExpiresAt: claims.ExpiresAt,
+		IssuedAt:  claims.IssuedAt,
+	}, nil
+}
+
+func (s *UserService) introspectRefreshToken(ctx context.Context, token string) (r *models.TokenIntrospection, err error) {
+	dbToken, err := s.getRefreshToken(ctx, s.storage, token)
+	if err != nil {
+		return nil, err
+	}
+	if dbToken == nil || dbToken.IsLogout == 1 {
+		return &models.TokenIntrospection{}, nil
+	}
+
+	return &models.TokenIntrospection{
+		Active:    true,
+		Subject:   dbToken.UserId,
+		ClientId:  dbToken.ClientId,
+		Scope:     dbToken.Scope,
+		TokenType: "refresh_token",
+		IssuedAt:  dbToken.CreateTime.Unix(),
+	}, nil
+}
+
+// sessionActive reports whether the newest refresh token of a session is
+// live. Rotation logs out the old token only after issuing a new one, so a
+// session is signed out exactly when its newest token is.
+func (s *UserService) sessionActive(ctx context.Context, tx storages.Storage, sessionId string) (bool, error) {
+	family, err := tx.Tokens().ListRefreshTokensBySessionId(ctx, sessionId)
+	if err != nil {
+		return false, err
+	}
+
+	return len(family) > 0 && family[0].IsLogout == 0, nil
+}
+
+// RevokeToken revokes an access or refresh token for the calling service
+// (RFC 7009). Revoking a refresh token signs its whole session out, which
+// also makes the session's access tokens inactive. Unknown and already
+// revoked tokens are not an error.
+func (s *UserService) RevokeToken(ctx *restful.Context, clientId string, clientSecret string, token string) (err error) {
+	_, err = s.authenticateServiceClient(ctx, clientId, clientSecret)
+	if err != nil {
+		return err
+	}
+
+	if token == "" {
+		return newOauthError("invalid_request", "token is required")
+	}
+
+	userId := ""
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		userId = ""
+
+		if isJwt(token) {
+			// an expired access token can't be used anyway
+			if _, err := s.parseAccessTokenClaims(token); err != nil {
+				return nil
+			}
+
+			dbToken, err := tx.Tokens().GetAccessToken(ctx, token)
+			if err != nil {
+				return err
+			}
+			if dbToken == nil || dbToken.IsRevoked == 1 {
+				return nil
+			}
+
+			userId = dbToken.UserId
+			dbToken.IsRevoked = 1
+			return tx.Tokens().UpdateAccessToken(ctx, dbToken)
+		}
+
+		dbToken, err := s.getRefreshTokenForUpdate(ctx, tx, token)
+		if err != nil || dbToken == nil {
+			return err
+		}
+
+		family := []*user_db.RefreshToken{dbToken}
+		if dbToken.SessionId != "" {
+			family, err = tx.Tokens().ListRefreshTokensBySessionIdForUpdate(ctx, dbToken.SessionId)
+			if err != nil {
+				return err
+			}
+		}
+
+		n, err := logoutRefreshTokens(ctx, tx, family)
+		if err != nil || n == 0 {
+			return err
+		}
+
+		userId = dbToken.UserId
+		return tx.Operations().Insert(ctx, &user_db.UserOperation{
+			UserId:        dbToken.UserId,
+			OperationType: "RevokeToken",
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if userId != "" {
+		s.recentWriters.Mark(userId)
+	}
+
+	return nil
+}
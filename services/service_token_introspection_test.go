@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages/user_db"
+	"github.com/dgrijalva/jwt-go"
+	"testing"
+	"time"
+)
+
+// newServiceClient registers a client allowed to introspect and revoke
+// tokens.
+func newServiceClient(t *testing.T, s *UserService) *models.OauthClient {
+	t.Helper()
+
+	client, err := s.RegisterOauthClient(context.Background(), &models.OauthClient{
+		ClientName: "test service",
+		GrantTypes: []string{oauthGrantClientCredentials},
+	}, false)
+	assertError(t, err, nil)
+	return client
+}
+
+func introspect(t *testing.T, s *UserService, client *models.OauthClient, token string) *models.TokenIntrospection {
+	t.Helper()
+
+	r, err := s.IntrospectToken(newTestContext(), client.ClientId, client.ClientSecret, token)
+	assertError(t, err, nil)
+	return r
+}
+
+func TestIntrospectToken(t *testing.T) {
+	s, _, sender := newTestService(t)
+	client := newServiceClient(t, s)
+
+	token, principal := smsLogin(t, s, sender, "13800000000")
+
+	access := introspect(t, s, client, token.AccessToken)
+	if !access.Active || access.TokenType != "Bearer" || access.Subject != principal.UserId {
+		t.Fatalf("access token introspected as %+v", access)
+	}
+	refresh := introspect(t, s, client, token.RefreshToken)
+	if !refresh.Active || refresh.TokenType != "refresh_token" || refresh.Subject != principal.UserId {
+		t.Fatalf("refresh token introspected as %+v", refresh)
+	}
+
+	for _, v := range []string{"a.b.c", "not-a-token"} {
+		if introspect(t, s, client, v).Active {
+			t.Fatalf("%q introspected as active", v)
+		}
+	}
+
+	// revoking the refresh token signs the session out
+	assertError(t, s.RevokeToken(newTestContext(), client.ClientId, client.ClientSecret, token.RefreshToken), nil)
+	for _, v := range []string{token.AccessToken, token.RefreshToken} {
+		if introspect(t, s, client, v).Active {
+			t.Fatalf("token of a revoked session introspected as active")
+		}
+	}
+}
+
+func TestExpiredAccessToken(t *testing.T) {
+	s, storage, _ := newTestService(t)
+	client := newServiceClient(t, s)
+
+	token, err := s.signAccessToken(accessTokenClaims{
+		StandardClaims: jwt.StandardClaims{Subject: "user1"},
+		SessionId:      "session1",
+		Scope:          userScope(nil),
+	}, -time.Minute)
+	assertError(t, err, nil)
+	dbToken := &user_db.AccessToken{UserId: "user1", AccessToken: token, SessionId: "session1"}
+	assertError(t, storage.Tokens().InsertAccessToken(context.Background(), dbToken), nil)
+
+	if introspect(t, s, client, token).Active {
+		t.Fatalf("expired access token introspected as active")
+	}
+
+	assertError(t, s.RevokeToken(newTestContext(), client.ClientId, client.ClientSecret, token), nil)
+	dbToken, err = storage.Tokens().GetAccessToken(context.Background(), token)
+	assertError(t, err, nil)
+	if dbToken.IsRevoked != 0 {
+		t.Fatalf("revoking an expired access token changed it")
+	}
+}
@@ -0,0 +1,323 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronFramework/restful"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	recoveryCodeCount    = 10
+	recoveryCodeLength   = 10
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+	mfaMaxAttempts = 5
+	mfaLockout     = time.Minute * 15
+)
+
+func newRecoveryCode() (string, error) {
+	buf := make([]byte, recoveryCodeLength)
+	max := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		buf[i] = recoveryCodeAlphabet[n.Int64()]
+	}
+
+	return string(buf[:recoveryCodeLength/2]) + "-" + string(buf[recoveryCodeLength/2:]), nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.Replace(code, "-", "", -1)
+	return strings.Replace(code, " ", "", -1)
+}
+
+func mfaLocked(account *user_db.TotpAccount) bool {
+	return account.FailedCount >= mfaMaxAttempts && time.Since(account.UpdateTime) < mfaLockout
+}
+
+// checkMfaCode accepts a TOTP code or an unused recovery code and records
+// the outcome on account.
+func (s *UserService) checkMfaCode(ctx context.Context, tx storages.Storage, account *user_db.TotpAccount, code string) (ok bool, err error) {
+	secret, err := s.decryptSecret(account.TotpSecret)
+	if err != nil {
+		return false, err
+	}
+
+	step, ok := verifyTotp(secret, code, time.Now(), account.LastUsedStep)
+	if ok {
+		account.LastUsedStep = step
+	} else if account.IsEnabled == 1 {
+		recoveryCodes, err := tx.RecoveryCodes().ListByUserId(ctx, account.UserId)
+		if err != nil {
+			return false, err
+		}
+
+		code = normalizeRecoveryCode(code)
+		for _, v := range recoveryCodes {
+			if v.IsUsed == 0 && s.secretEqual(v.CodeHash, code) {
+				v.IsUsed = 1
+				err = tx.RecoveryCodes().Update(ctx, v)
+				if err != nil {
+					return false, err
+				}
+				ok = true
+				break
+			}
+		}
+	}
+
+	if ok {
+		account.FailedCount = 0
+	} else {
+		account.FailedCount++
+	}
+
+	return ok, tx.TotpAccounts().Update(ctx, account)
+}
+
+// EnrollTotp starts (or restarts) TOTP enrollment. TOTP is not required
+// until ConfirmTotp is called with a code from the authenticator.
+func (s *UserService) EnrollTotp(ctx *restful.Context, userId string) (enrollment *models.TotpEnrollment, err error) {
+	user, err := s.storage.Users().GetByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.NotFound("用户信息不存在")
+	}
+
+	secret, err := newTotpSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := s.encryptSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		account, err := tx.TotpAccounts().GetByUserIdForUpdate(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if account == nil {
+			return tx.TotpAccounts().Insert(ctx, &user_db.TotpAccount{
+				UserId:     userId,
+				TotpSecret: encrypted,
+			})
+		}
+		if account.IsEnabled == 1 {
+			return errors.BadRequest("TotpEnabled", "已开启两步验证")
+		}
+
+		account.TotpSecret = encrypted
+		account.LastUsedStep = 0
+		account.FailedCount = 0
+		return tx.TotpAccounts().Update(ctx, account)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TotpEnrollment{
+		Secret: totpEncoding.EncodeToString(secret),
+		Uri:    totpUri(s.totpIssuer, user.UserName, secret),
+	}, nil
+}
+
+// ConfirmTotp turns TOTP on and returns the recovery codes, which are only
+// ever shown this once.
+func (s *UserService) ConfirmTotp(ctx *restful.Context, userId string, code string, userAgent string) (recoveryCodes []string, err error) {
+	recoveryCodes = make([]string, recoveryCodeCount)
+	for i := range recoveryCodes {
+		recoveryCodes[i], err = newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var verifyErr error
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		verifyErr = nil
+
+		account, err := tx.TotpAccounts().GetByUserIdForUpdate(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if account == nil {
+			return errors.BadRequest("TotpNotEnrolled", "请先获取两步验证密钥")
+		}
+		if account.IsEnabled == 1 {
+			return errors.BadRequest("TotpEnabled", "已开启两步验证")
+		}
+		if mfaLocked(account) {
+			return errors.BadRequest("MfaLocked", "验证码错误次数过多，请稍后再试")
+		}
+
+		ok, err := s.checkMfaCode(ctx, tx, account, code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			verifyErr = errors.BadRequest("WrongMfaCode", "验证码错误")
+			return nil
+		}
+
+		account.IsEnabled = 1
+		err = tx.TotpAccounts().Update(ctx, account)
+		if err != nil {
+			return err
+		}
+
+		err = tx.RecoveryCodes().DeleteByUserId(ctx, userId)
+		if err != nil {
+			return err
+		}
+		for _, v := range recoveryCodes {
+			err = tx.RecoveryCodes().Insert(ctx, &user_db.MfaRecoveryCode{
+				UserId:   userId,
+				CodeHash: s.hashSecret(normalizeRecoveryCode(v)),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Operations().Insert(ctx, &user_db.UserOperation{
+			UserId:        userId,
+			OperationType: "EnableTotp",
+			UserAgent:     truncate(userAgent, userAgentMaxLength),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if verifyErr != nil {
+		return nil, verifyErr
+	}
+
+	return recoveryCodes, nil
+}
+
+// DisableTotp turns TOTP off given a current TOTP or recovery code. Users
+// holding one of mfaRoles must keep it.
+func (s *UserService) DisableTotp(ctx *restful.Context, userId string, code string, userAgent string) (err error) {
+	var verifyErr error
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		verifyErr = nil
+
+		account, err := tx.TotpAccounts().GetByUserIdForUpdate(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if account == nil || account.IsEnabled == 0 {
+			return errors.BadRequest("TotpNotEnabled", "未开启两步验证")
+		}
+		if mfaLocked(account) {
+			return errors.BadRequest("MfaLocked", "验证码错误次数过多，请稍后再试")
+		}
+
+		roles, err := listRoles(ctx, tx, userId)
+		if err != nil {
+			return err
+		}
+		if requiresMfa(roles) {
+			return errors.BadRequest("MfaRequired", "管理员账号不能关闭两步验证")
+		}
+
+		ok, err := s.checkMfaCode(ctx, tx, account, code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			verifyErr = errors.BadRequest("WrongMfaCode", "验证码错误")
+			return nil
+		}
+
+		err = tx.TotpAccounts().Delete(ctx, account)
+		if err != nil {
+			return err
+		}
+
+		err = tx.RecoveryCodes().DeleteByUserId(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		return tx.Operations().Insert(ctx, &user_db.UserOperation{
+			UserId:        userId,
+			OperationType: "DisableTotp",
+			UserAgent:     truncate(userAgent, userAgentMaxLength),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return verifyErr
+}
+
+// VerifyMfa finishes a login that completeLogin answered with an MFA token.
+func (s *UserService) VerifyMfa(ctx *restful.Context, mfaToken string, code string, userAgent string, clientIp string) (token *models.Token, err error) {
+	userId, err := s.parseMfaToken(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var verifyErr error
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		verifyErr = nil
+
+		account, err := tx.TotpAccounts().GetByUserIdForUpdate(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if account == nil || account.IsEnabled == 0 {
+			return errors.BadRequest("InvalidMfaToken", "验证已过期，请重新登录")
+		}
+		if mfaLocked(account) {
+			verifyErr = errors.BadRequest("MfaLocked", "验证码错误次数过多，请稍后再试")
+			return nil
+		}
+
+		ok, err := s.checkMfaCode(ctx, tx, account, code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			verifyErr = errors.BadRequest("WrongMfaCode", "验证码错误")
+			return nil
+		}
+
+		token, err = s.issueToken(ctx, tx, userId, userAgent, clientIp)
+		if err != nil {
+			return err
+		}
+
+		return tx.Operations().Insert(ctx, &user_db.UserOperation{
+			UserId:        userId,
+			OperationType: "VerifyMfa",
+			UserAgent:     truncate(userAgent, userAgentMaxLength),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if verifyErr != nil {
+		return nil, verifyErr
+	}
+
+	return token, nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages/user_db"
+	"testing"
+	"time"
+)
+
+var (
+	errWrongMfaCode = errors.BadRequest("WrongMfaCode", "验证码错误")
+	errMfaLocked    = errors.BadRequest("MfaLocked", "验证码错误次数过多，请稍后再试")
+)
+
+// testTotpCode returns the code offset steps from now.
+func testTotpCode(secret []byte, offset int64) string {
+	return totpCode(secret, time.Now().Unix()/totpPeriod+offset)
+}
+
+// wrongTotpCode returns a code valid at no step near now.
+func wrongTotpCode(secret []byte) string {
+	for i := 0; ; i++ {
+		code := totpCode(secret, int64(i))
+		if _, ok := verifyTotp(secret, code, time.Now(), 0); !ok {
+			return code
+		}
+	}
+}
+
+// enableTestTotp enrolls userId and confirms with the current code, and
+// returns the secret and recovery codes.
+func enableTestTotp(t *testing.T, s *UserService, userId string) ([]byte, []string) {
+	t.Helper()
+
+	enrollment, err := s.EnrollTotp(newTestContext(), userId)
+	assertError(t, err, nil)
+	secret, err := totpEncoding.DecodeString(enrollment.Secret)
+	assertError(t, err, nil)
+
+	recoveryCodes, err := s.ConfirmTotp(newTestContext(), userId, testTotpCode(secret, 0), "test")
+	assertError(t, err, nil)
+	if len(recoveryCodes) != recoveryCodeCount {
+		t.Fatalf("%d recovery codes, want %d", len(recoveryCodes), recoveryCodeCount)
+	}
+	return secret, recoveryCodes
+}
+
+// smsLoginToken signs phone in and returns the token, which is only an MFA
+// token for users with TOTP on.
+func smsLoginToken(t *testing.T, s *UserService, sender *testSmsSender, phone string) *models.Token {
+	t.Helper()
+
+	ctx := newTestContext()
+	assertError(t, s.SendLoginSmsCode(ctx, phone, "10.0.0.1"), nil)
+	token, err := s.SmsLogin(ctx, phone, sender.code(phone), "test", "10.0.0.1")
+	assertError(t, err, nil)
+	return token
+}
+
+func TestTotpFlow(t *testing.T) {
+	s, storage, sender := newTestService(t)
+	_, user := smsLogin(t, s, sender, "13800000000")
+
+	enrollment, err := s.EnrollTotp(newTestContext(), user.UserId)
+	assertError(t, err, nil)
+	secret, err := totpEncoding.DecodeString(enrollment.Secret)
+	assertError(t, err, nil)
+
+	// enrolling alone doesn't require TOTP
+	if token := smsLoginToken(t, s, sender, "13800000000"); token.AccessToken == "" || token.MfaToken != "" {
+		t.Fatalf("login before confirming got %+v", token)
+	}
+
+	_, err = s.ConfirmTotp(newTestContext(), user.UserId, wrongTotpCode(secret), "test")
+	assertError(t, err, errWrongMfaCode)
+	code := testTotpCode(secret, 0)
+	recoveryCodes, err := s.ConfirmTotp(newTestContext(), user.UserId, code, "test")
+	assertError(t, err, nil)
+
+	// a password or SMS login now only gets an MFA token
+	token := smsLoginToken(t, s, sender, "13800000000")
+	if token.AccessToken != "" || token.MfaToken == "" {
+		t.Fatalf("login with TOTP on got %+v, want an MFA token only", token)
+	}
+
+	// the code used to confirm can't be used again
+	_, err = s.VerifyMfa(newTestContext(), token.MfaToken, code, "test", "10.0.0.1")
+	assertError(t, err, errWrongMfaCode)
+
+	verified, err := s.VerifyMfa(newTestContext(), token.MfaToken, testTotpCode(secret, 1), "test", "10.0.0.1")
+	assertError(t, err, nil)
+	if _, err := s.AuthenticateAccessToken(context.Background(), verified.AccessToken); err != nil {
+		t.Fatalf("access token after VerifyMfa: %v", err)
+	}
+
+	// a recovery code works once
+	token = smsLoginToken(t, s, sender, "13800000000")
+	_, err = s.VerifyMfa(newTestContext(), token.MfaToken, recoveryCodes[0], "test", "10.0.0.1")
+	assertError(t, err, nil)
+	_, err = s.VerifyMfa(newTestContext(), token.MfaToken, recoveryCodes[0], "test", "10.0.0.1")
+	assertError(t, err, errWrongMfaCode)
+
+	// the TOTP steps around now are used up, so disable with a recovery code
+	assertError(t, s.DisableTotp(newTestContext(), user.UserId, wrongTotpCode(secret), "test"), errWrongMfaCode)
+	assertError(t, s.DisableTotp(newTestContext(), user.UserId, recoveryCodes[1], "test"), nil)
+
+	account, err := storage.TotpAccounts().GetByUserId(context.Background(), user.UserId)
+	assertError(t, err, nil)
+	if account != nil {
+		t.Fatalf("TOTP account left after disabling: %+v", account)
+	}
+	if token := smsLoginToken(t, s, sender, "13800000000"); token.AccessToken == "" || token.MfaToken != "" {
+		t.Fatalf("login after disabling got %+v", token)
+	}
+}
+
+func TestVerifyMfaLockout(t *testing.T) {
+	s, storage, sender := newTestService(t)
+	_, user := smsLogin(t, s, sender, "13800000000")
+	secret, _ := enableTestTotp(t, s, user.UserId)
+
+	token := smsLoginToken(t, s, sender, "13800000000")
+	for i := 0; i < mfaMaxAttempts; i++ {
+		_, err := s.VerifyMfa(newTestContext(), token.MfaToken, wrongTotpCode(secret), "test", "10.0.0.1")
+		assertError(t, err, errWrongMfaCode)
+	}
+
+	// locked, even for the right code, which isn't consumed
+	code := testTotpCode(secret, 1)
+	_, err := s.VerifyMfa(newTestContext(), token.MfaToken, code, "test", "10.0.0.1")
+	assertError(t, err, errMfaLocked)
+	assertError(t, s.DisableTotp(newTestContext(), user.UserId, code, "test"), errMfaLocked)
+
+	account, err := storage.TotpAccounts().GetByUserId(context.Background(), user.UserId)
+	assertError(t, err, nil)
+	if account.FailedCount != mfaMaxAttempts {
+		t.Fatalf("failed_count = %d, want %d", account.FailedCount, mfaMaxAttempts)
+	}
+}
+
+func TestMfaLocked(t *testing.T) {
+	tests := []struct {
+		name        string
+		failedCount int32
+		updated     time.Duration
+		want        bool
+	}{
+		{"below the limit", mfaMaxAttempts - 1, 0, false},
+		{"at the limit", mfaMaxAttempts, 0, true},
+		{"within the lockout", mfaMaxAttempts, mfaLockout - time.Minute, true},
+		{"after the lockout", mfaMaxAttempts, mfaLockout + time.Second, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := &user_db.TotpAccount{FailedCount: tt.failedCount, UpdateTime: time.Now().Add(-tt.updated)}
+			if got := mfaLocked(account); got != tt.want {
+				t.Fatalf("mfaLocked = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
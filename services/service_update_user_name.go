@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronFramework/restful"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"time"
+)
+
+// UpdateUserName renames userId at most once per RenameInterval. The old
+// name is kept in user_name_history and reserved for userId for
+// ReservePeriod, so that nobody can pose as them by taking it right away.
+func (s *UserService) UpdateUserName(ctx *restful.Context, userId string, userName string) (err error) {
+	userName = normalizeUserName(userName)
+	err = validateUserName(userName)
+	if err != nil {
+		return err
+	}
+
+	if s.userNameConfig.blocked(userName) {
+		return errors.BadRequest("UserNameNotAllowed", "该用户名不可用")
+	}
+
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) (err error) {
+		now := time.Now()
+
+		dbUser, err := tx.Users().GetByUserIdForUpdate(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if dbUser == nil {
+			return errors.NotFound("用户信息不存在")
+		}
+		if dbUser.UserName == userName {
+			return nil
+		}
+
+		history, err := tx.UserNameHistory().ListByUserId(ctx, userId, 1)
+		if err != nil {
+			return err
+		}
+		if len(history) > 0 && now.Sub(history[0].CreateTime) < s.userNameConfig.RenameInterval {
+			return errors.BadRequest("RenameTooFrequent", "修改用户名过于频繁，请稍后再试")
+		}
+
+		dbOther, err := tx.Users().GetByUserName(ctx, userName)
+		if err != nil {
+			return err
+		}
+		if dbOther != nil && dbOther.UserId != userId {
+			return errors.BadRequest("UserNameExists", "用户名已存在")
+		}
+
+		oldName := dbUser.UserName
+		dbUser.UserName = userName
+		err = tx.Users().Update(ctx, dbUser)
+		if err != nil {
+			if err == storages.ErrDuplicate {
+				return errors.BadRequest("UserNameExists", "用户名已存在")
+			}
+			return err
+		}
+
+		// checked only once the name is ours: a concurrent rename away from
+		// it holds the name until it commits, together with its reservation
+		reserved, err := userNameReserved(ctx, tx, userName, userId, now)
+		if err != nil {
+			return err
+		}
+		if reserved {
+			return errors.BadRequest("UserNameExists", "用户名已存在")
+		}
+
+		// users used to be named by their user id at signup, which is never
+		// worth reserving
+		reservedUntil := now.Add(s.userNameConfig.ReservePeriod)
+		if oldName == userId {
+			reservedUntil = now
+		}
+
+		return tx.UserNameHistory().Insert(ctx, &user_db.UserNameHistory{
+			UserId:        userId,
+			UserName:      oldName,
+			ReservedUntil: reservedUntil,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.recentWriters.Mark(userId)
+
+	return nil
+}
+
+// userNameReserved reports whether userName is still reserved at now for a
+// user other than userId.
+func userNameReserved(ctx context.Context, tx storages.Storage, userName string, userId string, now time.Time) (bool, error) {
+	history, err := tx.UserNameHistory().ListByUserName(ctx, userName)
+	if err != nil {
+		return false, err
+	}
+
+	for _, v := range history {
+		if v.UserId != userId && v.ReservedUntil.After(now) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronFramework/restful"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"go.uber.org/zap"
+)
+
+func (s *UserService) checkWebAuthnEnabled() error {
+	if s.webAuthn == nil {
+		return errors.BadRequest("WebAuthnDisabled", "未开启通行密钥登录")
+	}
+
+	return nil
+}
+
+// newWebAuthnChallenge stores session as a one-time webauthn_session row.
+// userId is empty for a login, whose user is only known at the finish.
+func (s *UserService) newWebAuthnChallenge(ctx context.Context, userId string, options interface{}, session *webauthn.SessionData) (challenge *models.WebAuthnChallenge, err error) {
+	sessionId, err := randomHex(webAuthnSessionIdLength)
+	if err != nil {
+		return nil, err
+	}
+
+	optionsData, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionData, err := json.Marshal(session)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.storage.WebAuthnSessions().Insert(ctx, &user_db.WebauthnSession{
+		SessionId:   sessionId,
+		UserId:      userId,
+		SessionData: string(sessionData),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WebAuthnChallenge{SessionId: sessionId, Options: optionsData}, nil
+}
+
+// useWebAuthnSession marks the session used, so each challenge is answered
+// at most once whether or not the answer verifies.
+func useWebAuthnSession(ctx context.Context, tx storages.Storage, sessionId string, userId string) (session *webauthn.SessionData, err error) {
+	dbSession, err := tx.WebAuthnSessions().GetBySessionIdForUpdate(ctx, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	if dbSession == nil || dbSession.IsUsed == 1 || dbSession.UserId != userId {
+		return nil, nil
+	}
+
+	dbSession.IsUsed = 1
+	err = tx.WebAuthnSessions().Update(ctx, dbSession)
+	if err != nil {
+		return nil, err
+	}
+
+	session = &webauthn.SessionData{}
+	err = json.Unmarshal([]byte(dbSession.SessionData), session)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (s *UserService) getWebAuthnUser(ctx context.Context, tx storages.Storage, userId string) (*webAuthnUser, error) {
+	user, err := tx.Users().GetByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.NotFound("用户信息不存在")
+	}
+
+	records, err := tx.WebAuthnCredentials().ListByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return newWebAuthnUser(user, records)
+}
+
+// BeginWebAuthnRegistration starts adding a passkey to the signed in user.
+func (s *UserService) BeginWebAuthnRegistration(ctx *restful.Context, userId string) (challenge *models.WebAuthnChallenge, err error) {
+	err = s.checkWebAuthnEnabled()
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.getWebAuthnUser(ctx, s.storage, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	options, session, err := s.webAuthn.BeginRegistration(user,
+		webauthn.WithExclusions(webauthn.Credentials(user.credentials).CredentialDescriptors()),
+		webauthn.WithAuthenticatorSelection(protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationRequired,
+		}))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newWebAuthnChallenge(ctx, userId, options, session)
+}
+
+// FinishWebAuthnRegistration verifies the authenticator's attestation and
+// saves the new passkey.
+func (s *UserService) FinishWebAuthnRegistration(ctx *restful.Context, userId string, sessionId string, credential []byte, userAgent string) (err error) {
+	err = s.checkWebAuthnEnabled()
+	if err != nil {
+		return err
+	}
+
+	if !validWebAuthnSessionId(sessionId) {
+		return errors.BadRequest("InvalidWebAuthnSession", "验证已过期，请重试")
+	}
+
+	response, err := protocol.ParseCredentialCreationResponseBytes(credential)
+	if err != nil {
+		return errors.BadRequest("InvalidWebAuthnCredential", "通行密钥验证失败")
+	}
+
+	var verifyErr error
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		verifyErr = nil
+
+		session, err := useWebAuthnSession(ctx, tx, sessionId, userId)
+		if err != nil {
+			return err
+		}
+		if session == nil {
+			return errors.BadRequest("InvalidWebAuthnSession", "验证已过期，请重试")
+		}
+
+		user, err := s.getWebAuthnUser(ctx, tx, userId)
+		if err != nil {
+			return err
+		}
+
+		created, err := s.webAuthn.CreateCredential(user, *session, response)
+		if err != nil {
+			s.logger.Info("CreateCredential", zap.Error(err))
+			verifyErr = errors.BadRequest("InvalidWebAuthnCredential", "通行密钥验证失败")
+			return nil
+		}
+
+		credentialData, err := json.Marshal(created)
+		if err != nil {
+			return err
+		}
+
+		err = tx.WebAuthnCredentials().Insert(ctx, &user_db.WebauthnCredential{
+			UserId:         userId,
+			CredentialId:   webAuthnCredentialId(created.ID),
+			CredentialData: string(credentialData),
+		})
+		if err == storages.ErrDuplicate {
+			verifyErr = errors.BadRequest("WebAuthnCredentialExists", "该通行密钥已注册")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		return tx.Operations().Insert(ctx, &user_db.UserOperation{
+			UserId:        userId,
+			OperationType: "RegisterWebAuthn",
+			UserAgent:     truncate(userAgent, userAgentMaxLength),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return verifyErr
+}
+
+// BeginWebAuthnLogin starts a passkey login. The authenticator picks the
+// account, so no user name is asked for.
+func (s *UserService) BeginWebAuthnLogin(ctx *restful.Context) (challenge *models.WebAuthnChallenge, err error) {
+	err = s.checkWebAuthnEnabled()
+	if err != nil {
+		return nil, err
+	}
+
+	options, session, err := s.webAuthn.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newWebAuthnChallenge(ctx, "", options, session)
+}
+
+// FinishWebAuthnLogin verifies a passkey assertion. Like every other login
+// it is followed by a TOTP check for users who have it enabled.
+func (s *UserService) FinishWebAuthnLogin(ctx *restful.Context, sessionId string, credential []byte, userAgent string, clientIp string) (token *models.Token, err error) {
+	err = s.checkWebAuthnEnabled()
+	if err != nil {
+		return nil, err
+	}
+
+	if !validWebAuthnSessionId(sessionId) {
+		return nil, errors.BadRequest("InvalidWebAuthnSession", "验证已过期，请重试")
+	}
+
+	response, err := protocol.ParseCredentialRequestResponseBytes(credential)
+	if err != nil {
+		return nil, errors.BadRequest("InvalidWebAuthnCredential", "通行密钥验证失败")
+	}
+
+	var verifyErr error
+	err = s.storage.Transaction(ctx, func(tx storages.Storage) error {
+		verifyErr = nil
+
+		session, err := useWebAuthnSession(ctx, tx, sessionId, "")
+		if err != nil {
+			return err
+		}
+		if session == nil {
+			return errors.BadRequest("InvalidWebAuthnSession", "验证已过期，请重试")
+		}
+
+		var record *user_db.WebauthnCredential
+		var lookupErr error
+		_, verified, err := s.webAuthn.ValidatePasskeyLogin(func(rawId, userHandle []byte) (webauthn.User, error) {
+			record, lookupErr = tx.WebAuthnCredentials().GetByCredentialIdForUpdate(ctx, webAuthnCredentialId(rawId))
+			if lookupErr != nil {
+				return nil, lookupErr
+			}
+			if record == nil || record.UserId != string(userHandle) {
+				return nil, errors.BadRequest("UnknownWebAuthnCredential", "通行密钥未注册")
+			}
+
+			var user *webAuthnUser
+			user, lookupErr = s.getWebAuthnUser(ctx, tx, record.UserId)
+			return user, lookupErr
+		}, *session, response)
+		if lookupErr != nil {
+			return lookupErr
+		}
+		if err != nil {
+			s.logger.Info("ValidatePasskeyLogin", zap.Error(err))
+			verifyErr = errors.BadRequest("InvalidWebAuthnCredential", "通行密钥验证失败")
+			return nil
+		}
+
+		// a signature counter that went backwards means the key was cloned
+		if verified.Authenticator.CloneWarning {
+			verifyErr = errors.BadRequest("InvalidWebAuthnCredential", "通行密钥验证失败")
+			return nil
+		}
+
+		credentialData, err := json.Marshal(verified)
+		if err != nil {
+			return err
+		}
+		record.CredentialData = string(credentialData)
+		err = tx.WebAuthnCredentials().Update(ctx, record)
+		if err != nil {
+			return err
+		}
+
+		token, err = s.completeLogin(ctx, tx, record.UserId, userAgent, clientIp)
+		if err != nil {
+			return err
+		}
+
+		return tx.Operations().Insert(ctx, &user_db.UserOperation{
+			UserId:        record.UserId,
+			OperationType: "WebAuthnLogin",
+			UserAgent:     truncate(userAgent, userAgentMaxLength),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if verifyErr != nil {
+		return nil, verifyErr
+	}
+
+	return token, nil
+}
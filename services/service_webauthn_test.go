@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages/user_db"
+	"testing"
+)
+
+var (
+	errInvalidWebAuthnSession    = errors.BadRequest("InvalidWebAuthnSession", "验证已过期，请重试")
+	errInvalidWebAuthnCredential = errors.BadRequest("InvalidWebAuthnCredential", "通行密钥验证失败")
+)
+
+const testRPID = "example.com"
+
+// authenticator data flags, WebAuthn 6.1
+const (
+	flagUserPresent        = 0x01
+	flagUserVerified       = 0x04
+	flagAttestedCredential = 0x40
+)
+
+// cborHead encodes the head of a CBOR data item of major type major and
+// argument n (RFC 8949 3).
+func cborHead(major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return []byte{major<<5 | byte(n)}
+	case n < 1<<8:
+		return []byte{major<<5 | 24, byte(n)}
+	case n < 1<<16:
+		return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(n))
+	}
+}
+
+func cborInt(v int64) []byte {
+	if v < 0 {
+		return cborHead(1, uint64(-1-v))
+	}
+	return cborHead(0, uint64(v))
+}
+
+func cborBytes(b []byte) []byte { return append(cborHead(2, uint64(len(b))), b...) }
+func cborText(s string) []byte  { return append(cborHead(3, uint64(len(s))), s...) }
+
+// cborMap encodes a map from kv, alternating encoded keys and values.
+func cborMap(kv ...[]byte) []byte {
+	b := cborHead(5, uint64(len(kv)/2))
+	for _, v := range kv {
+		b = append(b, v...)
+	}
+	return b
+}
+
+// testAuthenticator is a software passkey: an ES256 key, discoverable, that
+// always verifies its user.
+type testAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialId []byte
+	userId       string
+	origin       string
+	// rpId is hashed into authenticator data; a phishing page would make
+	// it differ from the RP's.
+	rpId      string
+	signCount uint32
+}
+
+func newTestAuthenticator(t *testing.T, userId string) *testAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credentialId := make([]byte, 16)
+	_, err = rand.Read(credentialId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testAuthenticator{
+		key:          key,
+		credentialId: credentialId,
+		userId:       userId,
+		origin:       "https://" + testRPID,
+		rpId:         testRPID,
+	}
+}
+
+func (a *testAuthenticator) authData(flags byte, attested []byte) []byte {
+	rpIdHash := sha256.Sum256([]byte(a.rpId))
+	b := append(rpIdHash[:], flags|flagUserPresent|flagUserVerified)
+	b = binary.BigEndian.AppendUint32(b, a.signCount)
+	return append(b, attested...)
+}
+
+func (a *testAuthenticator) clientData(typ string, challenge string) []byte {
+	data, _ := json.Marshal(map[string]string{
+		"type":      typ,
+		"challenge": challenge,
+		"origin":    a.origin,
+	})
+	return data
+}
+
+func (a *testAuthenticator) credentialJSON(t *testing.T, response map[string]string) []byte {
+	data, err := json.Marshal(map[string]interface{}{
+		"id":       base64.RawURLEncoding.EncodeToString(a.credentialId),
+		"rawId":    base64.RawURLEncoding.EncodeToString(a.credentialId),
+		"type":     "public-key",
+		"response": response,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// create answers a registration challenge with a "none" attestation.
+func (a *testAuthenticator) create(t *testing.T, challenge *models.WebAuthnChallenge) []byte {
+	x, y := a.key.PublicKey.X.FillBytes(make([]byte, 32)), a.key.PublicKey.Y.FillBytes(make([]byte, 32))
+	coseKey := cborMap(
+		cborInt(1), cborInt(2), // kty: EC2
+		cborInt(3), cborInt(-7), // alg: ES256
+		cborInt(-1), cborInt(1), // crv: P-256
+		cborInt(-2), cborBytes(x),
+		cborInt(-3), cborBytes(y),
+	)
+
+	attested := make([]byte, 16) // AAGUID
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(a.credentialId)))
+	attested = append(attested, a.credentialId...)
+	attested = append(attested, coseKey...)
+
+	attestationObject := cborMap(
+		cborText("fmt"), cborText("none"),
+		cborText("attStmt"), cborMap(),
+		cborText("authData"), cborBytes(a.authData(flagAttestedCredential, attested)),
+	)
+
+	return a.credentialJSON(t, map[string]string{
+		"clientDataJSON":    base64.RawURLEncoding.EncodeToString(a.clientData("webauthn.create", challengeOf(t, challenge))),
+		"attestationObject": base64.RawURLEncoding.EncodeToString(attestationObject),
+	})
+}
+
+// get answers a login challenge, counting the signature.
+func (a *testAuthenticator) get(t *testing.T, challenge *models.WebAuthnChallenge) []byte {
+	a.signCount++
+	authData := a.authData(0, nil)
+	clientData := a.clientData("webauthn.get", challengeOf(t, challenge))
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return a.credentialJSON(t, map[string]string{
+		"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+		"authenticatorData": base64.RawURLEncoding.EncodeToString(authData),
+		"signature":         base64.RawURLEncoding.EncodeToString(signature),
+		"userHandle":        base64.RawURLEncoding.EncodeToString([]byte(a.userId)),
+	})
+}
+
+// challengeOf returns the challenge of the options sent to the browser.
+func challengeOf(t *testing.T, challenge *models.WebAuthnChallenge) string {
+	options := struct {
+		PublicKey struct {
+			Challenge string `json:"challenge"`
+		} `json:"publicKey"`
+	}{}
+	err := json.Unmarshal(challenge.Options, &options)
+	if err != nil || options.PublicKey.Challenge == "" {
+		t.Fatalf("options %s have no challenge: %v", challenge.Options, err)
+	}
+
+	return options.PublicKey.Challenge
+}
+
+// newWebAuthnTestService returns a service with WebAuthn enabled for
+// testRPID and a user to register passkeys for.
+func newWebAuthnTestService(t *testing.T) (*UserService, string) {
+	t.Setenv("WEBAUTHN_RP_ID", testRPID)
+	s, storage, _ := newTestService(t)
+
+	userId, err := randomHex(16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = storage.Users().Insert(context.Background(), &user_db.User{UserId: userId, UserName: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s, userId
+}
+
+func registerTestAuthenticator(t *testing.T, s *UserService, a *testAuthenticator) error {
+	ctx := newTestContext()
+
+	challenge, err := s.BeginWebAuthnRegistration(ctx, a.userId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s.FinishWebAuthnRegistration(ctx, a.userId, challenge.SessionId, a.create(t, challenge), "test")
+}
+
+func loginTestAuthenticator(t *testing.T, s *UserService, a *testAuthenticator) (*models.Token, error) {
+	ctx := newTestContext()
+
+	challenge, err := s.BeginWebAuthnLogin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s.FinishWebAuthnLogin(ctx, challenge.SessionId, a.get(t, challenge), "test", "10.0.0.1")
+}
+
+func TestWebAuthnRegisterAndLogin(t *testing.T) {
+	s, userId := newWebAuthnTestService(t)
+	a := newTestAuthenticator(t, userId)
+
+	assertError(t, registerTestAuthenticator(t, s, a), nil)
+
+	token, err := loginTestAuthenticator(t, s, a)
+	assertError(t, err, nil)
+	principal, err := s.ParseAccessToken(token.AccessToken)
+	assertError(t, err, nil)
+	if principal.UserId != userId {
+		t.Fatalf("logged in as %s, want %s", principal.UserId, userId)
+	}
+
+	// each login moves the counter on
+	_, err = loginTestAuthenticator(t, s, a)
+	assertError(t, err, nil)
+}
+
+func TestWebAuthnRegisterTwice(t *testing.T) {
+	s, userId := newWebAuthnTestService(t)
+	a := newTestAuthenticator(t, userId)
+
+	assertError(t, registerTestAuthenticator(t, s, a), nil)
+
+	// the registered passkey is excluded from the options, but a client
+	// that ignores them must not create a second row for it
+	assertError(t, registerTestAuthenticator(t, s, a), errors.BadRequest("WebAuthnCredentialExists", "该通行密钥已注册"))
+}
+
+func TestWebAuthnChallengeReuse(t *testing.T) {
+	s, userId := newWebAuthnTestService(t)
+	a := newTestAuthenticator(t, userId)
+	ctx := newTestContext()
+
+	challenge, err := s.BeginWebAuthnRegistration(ctx, userId)
+	assertError(t, err, nil)
+	credential := a.create(t, challenge)
+	assertError(t, s.FinishWebAuthnRegistration(ctx, userId, challenge.SessionId, credential, "test"), nil)
+	assertError(t, s.FinishWebAuthnRegistration(ctx, userId, challenge.SessionId, credential, "test"), errInvalidWebAuthnSession)
+
+	challenge, err = s.BeginWebAuthnLogin(ctx)
+	assertError(t, err, nil)
+	_, err = s.FinishWebAuthnLogin(ctx, challenge.SessionId, a.get(t, challenge), "test", "10.0.0.1")
+	assertError(t, err, nil)
+
+	// replaying the assertion, or answering the used challenge anew
+	_, err = s.FinishWebAuthnLogin(ctx, challenge.SessionId, a.get(t, challenge), "test", "10.0.0.1")
+	assertError(t, err, errInvalidWebAuthnSession)
+
+	// a failed answer uses the challenge up too
+	challenge, err = s.BeginWebAuthnLogin(ctx)
+	assertError(t, err, nil)
+	a.rpId = "evil.example"
+	_, err = s.FinishWebAuthnLogin(ctx, challenge.SessionId, a.get(t, challenge), "test", "10.0.0.1")
+	assertError(t, err, errInvalidWebAuthnCredential)
+	a.rpId = testRPID
+	_, err = s.FinishWebAuthnLogin(ctx, challenge.SessionId, a.get(t, challenge), "test", "10.0.0.1")
+	assertError(t, err, errInvalidWebAuthnSession)
+
+	// a registration challenge can't be used to log in
+	challenge, err = s.BeginWebAuthnRegistration(ctx, userId)
+	assertError(t, err, nil)
+	_, err = s.FinishWebAuthnLogin(ctx, challenge.SessionId, a.get(t, challenge), "test", "10.0.0.1")
+	assertError(t, err, errInvalidWebAuthnSession)
+}
+
+func TestWebAuthnSignCountRegression(t *testing.T) {
+	s, userId := newWebAuthnTestService(t)
+	a := newTestAuthenticator(t, userId)
+	a.signCount = 10
+
+	assertError(t, registerTestAuthenticator(t, s, a), nil)
+
+	_, err := loginTestAuthenticator(t, s, a)
+	assertError(t, err, nil)
+
+	// a clone of the key, whose counter lags behind
+	clone := *a
+	clone.signCount = 5
+	_, err = loginTestAuthenticator(t, s, &clone)
+	assertError(t, err, errInvalidWebAuthnCredential)
+
+	// the same count again is a regression as well
+	clone.signCount = a.signCount - 1
+	_, err = loginTestAuthenticator(t, s, &clone)
+	assertError(t, err, errInvalidWebAuthnCredential)
+
+	_, err = loginTestAuthenticator(t, s, a)
+	assertError(t, err, nil)
+}
+
+func TestWebAuthnWrongRPIDHash(t *testing.T) {
+	s, userId := newWebAuthnTestService(t)
+	a := newTestAuthenticator(t, userId)
+
+	a.rpId = "evil.example"
+	assertError(t, registerTestAuthenticator(t, s, a), errInvalidWebAuthnCredential)
+
+	a.rpId = testRPID
+	assertError(t, registerTestAuthenticator(t, s, a), nil)
+
+	a.rpId = "evil.example"
+	_, err := loginTestAuthenticator(t, s, a)
+	assertError(t, err, errInvalidWebAuthnCredential)
+}
+
+func TestWebAuthnLoginWithTotp(t *testing.T) {
+	s, userId := newWebAuthnTestService(t)
+	a := newTestAuthenticator(t, userId)
+	assertError(t, registerTestAuthenticator(t, s, a), nil)
+
+	err := s.storage.TotpAccounts().Insert(context.Background(), &user_db.TotpAccount{UserId: userId, IsEnabled: 1})
+	assertError(t, err, nil)
+
+	token, err := loginTestAuthenticator(t, s, a)
+	assertError(t, err, nil)
+	if token.AccessToken != "" || token.MfaToken == "" {
+		t.Fatalf("passkey login of a TOTP user got %+v, want an MFA token only", token)
+	}
+}
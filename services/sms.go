@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronFramework/log"
+	"go.uber.org/zap"
+	"math/big"
+	"os"
+	"strconv"
+	"time"
+)
+
+const smsCodeLength = 6
+
+// SmsSender delivers login codes.
+type SmsSender interface {
+	SendLoginSmsCode(ctx context.Context, phoneNumber string, smsCode string) error
+}
+
+// logSmsSender is used when no SMS provider is configured; it only logs at
+// debug level so codes don't end up in production logs.
+type logSmsSender struct {
+	logger *zap.Logger
+}
+
+func newLogSmsSender() *logSmsSender {
+	s := &logSmsSender{}
+	s.logger = log.TypedLogger(s)
+	return s
+}
+
+func (s *logSmsSender) SendLoginSmsCode(ctx context.Context, phoneNumber string, smsCode string) error {
+	s.logger.Debug("SendLoginSmsCode", zap.String("phoneNumber", phoneNumber), zap.String("smsCode", smsCode))
+	return nil
+}
+
+type SmsConfig struct {
+	// CodeLifetime is how long a code can be used after it is sent.
+	CodeLifetime time.Duration
+	// SendInterval is the minimum time between two codes to one phone.
+	SendInterval time.Duration
+	// PhoneDailyLimit and IpHourlyLimit cap the codes sent to one phone in
+	// 24 hours and requested from one client IP in an hour.
+	PhoneDailyLimit int64
+	IpHourlyLimit   int64
+	// MaxAttempts wrong guesses burn a code and lock the phone out of
+	// logging in by SMS for Lockout.
+	MaxAttempts int32
+	Lockout     time.Duration
+}
+
+// NewSmsConfigFromEnv reads the SMS_* variables, see SmsConfig.
+func NewSmsConfigFromEnv() (c *SmsConfig, err error) {
+	c = &SmsConfig{
+		CodeLifetime:    time.Minute * 5,
+		SendInterval:    time.Minute,
+		PhoneDailyLimit: 10,
+		IpHourlyLimit:   20,
+		MaxAttempts:     5,
+		Lockout:         time.Minute * 30,
+	}
+
+	durations := []struct {
+		key string
+		v   *time.Duration
+	}{
+		{"SMS_CODE_LIFETIME", &c.CodeLifetime},
+		{"SMS_SEND_INTERVAL", &c.SendInterval},
+		{"SMS_LOCKOUT", &c.Lockout},
+	}
+	for _, d := range durations {
+		if v := os.Getenv(d.key); v != "" {
+			*d.v, err = time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s env invalid: %v", d.key, err)
+			}
+		}
+	}
+
+	limits := []struct {
+		key string
+		v   *int64
+	}{
+		{"SMS_PHONE_DAILY_LIMIT", &c.PhoneDailyLimit},
+		{"SMS_IP_HOURLY_LIMIT", &c.IpHourlyLimit},
+	}
+	for _, l := range limits {
+		if v := os.Getenv(l.key); v != "" {
+			*l.v, err = strconv.ParseInt(v, 10, 64)
+			if err != nil || *l.v <= 0 {
+				return nil, fmt.Errorf("%s env invalid: %s", l.key, v)
+			}
+		}
+	}
+
+	if v := os.Getenv("SMS_MAX_ATTEMPTS"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("SMS_MAX_ATTEMPTS env invalid: %s", v)
+		}
+		c.MaxAttempts = int32(n)
+	}
+
+	return c, nil
+}
+
+func newSmsCode() (string, error) {
+	return randomDigits(smsCodeLength)
+}
+
+// randomDigits returns n uniformly random decimal digits.
+func randomDigits(n int) (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < n; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+
+	v, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", n, v), nil
+}
+
+func validatePhoneNumber(phoneNumber string) error {
+	if len(phoneNumber) < 5 || len(phoneNumber) > 20 {
+		return errors.BadRequest("InvalidPhoneNumber", "手机号格式错误")
+	}
+
+	for i, r := range phoneNumber {
+		if r == '+' && i == 0 {
+			continue
+		}
+		if r < '0' || r > '9' {
+			return errors.BadRequest("InvalidPhoneNumber", "手机号格式错误")
+		}
+	}
+
+	return nil
+}
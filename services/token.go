@@ -0,0 +1,434 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronUser/user/models"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"github.com/dgrijalva/jwt-go"
+	"strings"
+	"time"
+)
+
+// accessTokenSecretMinLength is the shortest ACCESS_TOKEN_SECRET accepted,
+// the size of an HS256 key.
+const accessTokenSecretMinLength = 32
+
+const accessTokenLifetime = time.Hour * 2
+
+// impersonationTokenLifetime is short as the tokens can't be refreshed.
+const impersonationTokenLifetime = time.Minute * 15
+
+// MFA tokens are JWTs with this audience, which BearerAuth does not accept.
+const (
+	mfaTokenAudience = "mfa"
+	mfaTokenLifetime = time.Minute * 5
+)
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// isJwt tells access tokens, which are JWTs, from refresh tokens, which are
+// hex and never contain a dot.
+func isJwt(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// accessTokenClaims are the claims of an access token. Tokens issued to an
+// OAuth client are signed with the provider's RSA key and have the client
+// as their audience, so BearerAuth does not accept them. Our own apps'
+// tokens carry the user's roles and the scopes they grant.
+type accessTokenClaims struct {
+	jwt.StandardClaims
+	ClientId  string       `json:"client_id,omitempty"`
+	SessionId string       `json:"sid,omitempty"`
+	Scope     string       `json:"scope,omitempty"`
+	Roles     []string     `json:"roles,omitempty"`
+	Actor     *actorClaims `json:"act,omitempty"`
+}
+
+// actorClaims name who is acting as the subject of an impersonation token
+// (RFC 8693 4.1).
+type actorClaims struct {
+	Subject string `json:"sub"`
+}
+
+// parseAccessTokenClaims verifies token, an access token of our own apps
+// (HS256) or of an OAuth client (RS256, signed by the OAuth provider).
+func (s *UserService) parseAccessTokenClaims(token string) (claims *accessTokenClaims, err error) {
+	claims = &accessTokenClaims{}
+	t, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		switch {
+		case t.Method == jwt.SigningMethodHS256:
+			return s.accessTokenSecret, nil
+		case t.Method == jwt.SigningMethodRS256 && s.oauthProvider != nil:
+			return s.oauthProvider.accessTokenKey(t)
+		}
+		return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.Unknown("验证失败： claims.Subject nil")
+	}
+
+	// a client's token is only good signed by the provider, and ours only
+	// with our secret
+	if (claims.ClientId != "") != (t.Method == jwt.SigningMethodRS256) {
+		return nil, errors.Unknown("验证失败： unexpected signing method")
+	}
+	if claims.ClientId != "" && claims.Issuer != s.oauthProvider.config.Issuer {
+		return nil, errors.Unknown("验证失败： unexpected issuer")
+	}
+
+	return claims, nil
+}
+
+// ParseAccessToken verifies token and returns the user it was issued to.
+func (s *UserService) ParseAccessToken(token string) (principal *models.Principal, err error) {
+	claims, err := s.parseAccessTokenClaims(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Audience != "" {
+		return nil, errors.Unknown("验证失败： unexpected audience")
+	}
+
+	actorId := ""
+	if claims.Actor != nil {
+		if claims.Actor.Subject == "" {
+			return nil, errors.Unknown("验证失败： act.sub nil")
+		}
+		actorId = claims.Actor.Subject
+	}
+
+	// tokens issued before scopes existed carry none
+	scope := claims.Scope
+	if scope == "" {
+		scope = userScope(nil)
+	}
+
+	return &models.Principal{
+		UserId:     claims.Subject,
+		Roles:      claims.Roles,
+		Scopes:     strings.Fields(scope),
+		AuthMethod: models.AuthMethodBearer,
+		SessionId:  claims.SessionId,
+		ActorId:    actorId,
+		TokenId:    claims.Id,
+		IssuedAt:   time.Unix(claims.IssuedAt, 0),
+	}, nil
+}
+
+// AuthenticateAccessToken is ParseAccessToken for incoming requests: the
+// token must also not be revoked, and the session it was issued to must
+// still be signed in. Impersonation tokens must be recorded, so their act
+// claim is ours, and their actor must still be allowed to impersonate.
+// Tokens issued before sessions were recorded carry no session and are only
+// checked by signature until they expire.
+//
+// The roles and scopes of the principal come from the roles table, not the
+// token: it gets the scopes the token was issued with that the user's
+// current roles still grant.
+func (s *UserService) AuthenticateAccessToken(ctx context.Context, token string) (principal *models.Principal, err error) {
+	principal, err = s.ParseAccessToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	// revocations must show up on every instance at once, so nothing is read
+	// from a replica
+	ctx = storages.WithPrimary(ctx)
+
+	if principal.SessionId != "" || principal.ActorId != "" {
+		active, err := s.accessTokenActive(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		if !active {
+			return nil, errors.Unknown("验证失败： token revoked")
+		}
+	}
+
+	if principal.ActorId != "" {
+		actorRoles, err := sessionRoles(ctx, s.storage, principal.ActorId)
+		if err != nil {
+			return nil, err
+		}
+		if !hasField(userScope(actorRoles), ScopeUserAdmin) {
+			return nil, errors.Unknown("验证失败： actor may not impersonate")
+		}
+	}
+
+	// impersonation tokens get the default scopes, whatever the user's roles
+	var roles []string
+	if principal.ActorId == "" {
+		roles, err = sessionRoles(ctx, s.storage, principal.UserId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	granted := userScope(roles)
+	scopes := make([]string, 0, len(principal.Scopes))
+	for _, v := range principal.Scopes {
+		if hasField(granted, v) {
+			scopes = append(scopes, v)
+		}
+	}
+	principal.Roles = roles
+	principal.Scopes = scopes
+
+	return principal, nil
+}
+
+// accessTokenActive reports whether token, which verified, is recorded, not
+// revoked, and from a session that is still signed in. It reads the
+// primary, as revocations must show up at once.
+func (s *UserService) accessTokenActive(ctx context.Context, token string) (bool, error) {
+	ctx = storages.WithPrimary(ctx)
+
+	dbToken, err := s.storage.Tokens().GetAccessToken(ctx, token)
+	if err != nil {
+		return false, err
+	}
+	if dbToken == nil || dbToken.IsRevoked == 1 {
+		return false, nil
+	}
+
+	if dbToken.SessionId == "" {
+		return true, nil
+	}
+	return s.sessionActive(ctx, s.storage, dbToken.SessionId)
+}
+
+func (s *UserService) newMfaToken(userId string) (string, error) {
+	now := time.Now()
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{
+		Audience:  mfaTokenAudience,
+		Subject:   userId,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(mfaTokenLifetime).Unix(),
+	}).SignedString(s.accessTokenSecret)
+}
+
+func (s *UserService) parseMfaToken(token string) (userId string, err error) {
+	claims := jwt.StandardClaims{}
+	_, err = jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		return s.accessTokenSecret, nil
+	})
+	if err != nil || claims.Audience != mfaTokenAudience || claims.Subject == "" {
+		return "", errors.BadRequest("InvalidMfaToken", "验证已过期，请重新登录")
+	}
+
+	return claims.Subject, nil
+}
+
+// totpEnabled reports whether userId has confirmed a TOTP authenticator.
+func totpEnabled(ctx context.Context, tx storages.Storage, userId string) (bool, error) {
+	totp, err := tx.TotpAccounts().GetByUserId(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+
+	return totp != nil && totp.IsEnabled == 1, nil
+}
+
+// completeLogin is called by every login once the first factor checked out.
+// Users with TOTP enabled get an MFA token to pass to VerifyMfa instead of a
+// token pair, and users holding one of mfaRoles can't log in without it.
+func (s *UserService) completeLogin(ctx context.Context, tx storages.Storage, userId string, userAgent string, clientIp string) (token *models.Token, err error) {
+	enabled, err := totpEnabled(ctx, tx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if enabled {
+		mfaToken, err := s.newMfaToken(userId)
+		if err != nil {
+			return nil, err
+		}
+		return &models.Token{MfaToken: mfaToken}, nil
+	}
+
+	roles, err := listRoles(ctx, tx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if requiresMfa(roles) {
+		return nil, errors.BadRequest("MfaRequired", "管理员账号须开启两步验证后才能登录")
+	}
+
+	return s.issueToken(ctx, tx, userId, userAgent, clientIp)
+}
+
+// newAccessToken signs an access token for subject, a user or, for the
+// client_credentials grant, the client itself, which has no session.
+func (s *UserService) newAccessToken(subject string, clientId string, sessionId string, scope string, roles []string) (string, error) {
+	return s.signAccessToken(accessTokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			Audience: clientId,
+			Subject:  subject,
+		},
+		ClientId:  clientId,
+		SessionId: sessionId,
+		Scope:     scope,
+		Roles:     roles,
+	}, accessTokenLifetime)
+}
+
+// newImpersonationToken signs an access token for userId that actorId uses
+// to act as them. It has the default user scopes, whatever either user's
+// roles, and no session. It is only good once recorded in access_token.
+func (s *UserService) newImpersonationToken(userId string, actorId string) (string, error) {
+	return s.signAccessToken(accessTokenClaims{
+		StandardClaims: jwt.StandardClaims{Subject: userId},
+		Scope:          userScope(nil),
+		Actor:          &actorClaims{Subject: actorId},
+	}, impersonationTokenLifetime)
+}
+
+// signAccessToken sets the id and times of claims and signs them, with the
+// OAuth provider's key if they are for an OAuth client.
+func (s *UserService) signAccessToken(claims accessTokenClaims, lifetime time.Duration) (string, error) {
+	// the id keeps two tokens issued in the same second apart
+	tokenId, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims.Id = tokenId
+	claims.IssuedAt = now.Unix()
+	claims.ExpiresAt = now.Add(lifetime).Unix()
+
+	if claims.ClientId != "" {
+		if s.oauthProvider == nil {
+			return "", errors.Unknown("oauth provider disabled")
+		}
+		return s.oauthProvider.signAccessToken(claims)
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.accessTokenSecret)
+}
+
+// issueToken starts a new session for userId in tx and returns its first
+// token pair.
+func (s *UserService) issueToken(ctx context.Context, tx storages.Storage, userId string, userAgent string, clientIp string) (token *models.Token, err error) {
+	sessionId, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueSessionToken(ctx, tx, &user_db.RefreshToken{
+		UserId:    userId,
+		SessionId: sessionId,
+		UserAgent: truncate(userAgent, userAgentMaxLength),
+		ClientIp:  clientIp,
+		LoginTime: time.Now(),
+	}, "")
+}
+
+// issueSessionToken creates and stores a new access/refresh token pair for
+// session, whose user, session and client fields are set, in tx. Tokens for
+// our own apps get the scopes of the user's current roles (sessionRoles).
+// For a client, scope narrows the access token to part of session.Scope,
+// which the refresh token keeps; "" means all of it.
+func (s *UserService) issueSessionToken(ctx context.Context, tx storages.Storage, session *user_db.RefreshToken, scope string) (token *models.Token, err error) {
+	roles := []string(nil)
+	if scope == "" {
+		scope = session.Scope
+	}
+	if session.ClientId == "" {
+		roles, err = sessionRoles(ctx, tx, session.UserId)
+		if err != nil {
+			return nil, err
+		}
+		scope = userScope(roles)
+	}
+
+	accessToken, err := s.newAccessToken(session.UserId, session.ClientId, session.SessionId, scope, roles)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Tokens().InsertAccessToken(ctx, &user_db.AccessToken{
+		UserId:      session.UserId,
+		AccessToken: accessToken,
+		SessionId:   session.SessionId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	session.Id = 0
+	session.RefreshToken = s.hashSecret(refreshToken)
+	session.IsLogout = 0
+	session.LogoutTime = time.Now()
+	err = tx.Tokens().InsertRefreshToken(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Token{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// getRefreshToken looks refreshToken up like getRefreshTokenForUpdate,
+// without locking it.
+func (s *UserService) getRefreshToken(ctx context.Context, tx storages.Storage, refreshToken string) (dbToken *user_db.RefreshToken, err error) {
+	dbToken, err = tx.Tokens().GetRefreshToken(ctx, s.hashSecret(refreshToken))
+	if err != nil || dbToken != nil || !isLegacyToken(refreshToken) {
+		return dbToken, err
+	}
+
+	return tx.Tokens().GetRefreshToken(ctx, refreshToken)
+}
+
+// getRefreshTokenForUpdate looks refreshToken up by its hash, falling back to
+// the plaintext of rows HashLegacySecrets has not rewritten yet.
+func (s *UserService) getRefreshTokenForUpdate(ctx context.Context, tx storages.Storage, refreshToken string) (dbToken *user_db.RefreshToken, err error) {
+	dbToken, err = tx.Tokens().GetRefreshTokenForUpdate(ctx, s.hashSecret(refreshToken))
+	if err != nil || dbToken != nil || !isLegacyToken(refreshToken) {
+		return dbToken, err
+	}
+
+	return tx.Tokens().GetRefreshTokenForUpdate(ctx, refreshToken)
+}
+
+// isLegacyToken rejects anything that can't be a plaintext token before it
+// reaches the query builders, which inline values into the SQL text.
+func isLegacyToken(token string) bool {
+	if token == "" || len(token) > 128 || isHashedSecret(token) {
+		return false
+	}
+
+	for _, r := range token {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-' || r == '_') {
+			return false
+		}
+	}
+
+	return true
+}
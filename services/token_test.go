@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"github.com/NeuronUser/user/storages/user_db"
+	"github.com/dgrijalva/jwt-go"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOauthClientAccessTokenSigning(t *testing.T) {
+	t.Setenv("OIDC_ISSUER", "https://api.example.com/api/v1/users")
+	s, _, _ := newTestService(t)
+
+	const clientId = "0123456789abcdef0123456789abcdef"
+	token, err := s.newAccessToken("user1", clientId, "session1", "openid", nil)
+	assertError(t, err, nil)
+
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, &accessTokenClaims{})
+	assertError(t, err, nil)
+	if parsed.Method != jwt.SigningMethodRS256 || parsed.Header["kid"] != s.oauthProvider.keyId {
+		t.Fatalf("client token signed with %v, kid %v, want RS256 with the JWKS key", parsed.Header["alg"], parsed.Header["kid"])
+	}
+
+	claims, err := s.parseAccessTokenClaims(token)
+	assertError(t, err, nil)
+	if claims.ClientId != clientId || claims.Issuer != s.oauthProvider.config.Issuer {
+		t.Fatalf("claims = %+v", claims)
+	}
+
+	// our own apps' tokens stay HS256
+	token, err = s.newAccessToken("user1", "", "session1", userScope(nil), nil)
+	assertError(t, err, nil)
+	parsed, _, err = new(jwt.Parser).ParseUnverified(token, &accessTokenClaims{})
+	assertError(t, err, nil)
+	if parsed.Method != jwt.SigningMethodHS256 {
+		t.Fatalf("own token signed with %v, want HS256", parsed.Header["alg"])
+	}
+}
+
+func TestParseAccessTokenClaimsRefusesMismatchedKeys(t *testing.T) {
+	t.Setenv("OIDC_ISSUER", "https://api.example.com/api/v1/users")
+	s, _, _ := newTestService(t)
+
+	const clientId = "0123456789abcdef0123456789abcdef"
+	now := time.Now()
+	claims := accessTokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			Audience:  clientId,
+			Subject:   "user1",
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(time.Hour).Unix(),
+		},
+		ClientId: clientId,
+		Scope:    "openid",
+	}
+
+	idToken, err := s.oauthProvider.newIdToken("user1", clientId, "")
+	assertError(t, err, nil)
+
+	hsClientToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.accessTokenSecret)
+	assertError(t, err, nil)
+
+	ownClaims := claims
+	ownClaims.Audience, ownClaims.ClientId = "", ""
+	rsOwnToken, err := s.oauthProvider.signAccessToken(ownClaims)
+	assertError(t, err, nil)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"id token", idToken},
+		{"client token signed with the secret", hsClientToken},
+		{"own token signed with the provider key", rsOwnToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.parseAccessTokenClaims(tt.token); err == nil {
+				t.Fatalf("token accepted")
+			}
+		})
+	}
+}
+
+func TestAuthenticateAccessTokenRolesFromStorage(t *testing.T) {
+	s, storage, _ := newTestService(t)
+	ctx := context.Background()
+
+	// a token claiming admin, as one issued before a role was revoked or
+	// signed with a leaked secret would
+	token, err := s.signAccessToken(accessTokenClaims{
+		StandardClaims: jwt.StandardClaims{Subject: "user1"},
+		Scope:          userScope([]string{RoleAdmin}),
+		Roles:          []string{RoleAdmin},
+	}, accessTokenLifetime)
+	assertError(t, err, nil)
+
+	scopes := func() string {
+		t.Helper()
+
+		principal, err := s.AuthenticateAccessToken(ctx, token)
+		assertError(t, err, nil)
+		return strings.Join(principal.Scopes, " ")
+	}
+
+	if hasField(scopes(), ScopeUserAdmin) {
+		t.Fatalf("user without roles got %s", ScopeUserAdmin)
+	}
+
+	role := &user_db.UserRole{UserId: "user1", Role: RoleAdmin}
+	assertError(t, storage.Roles().Insert(ctx, role), nil)
+	if hasField(scopes(), ScopeUserAdmin) {
+		t.Fatalf("admin without TOTP got %s", ScopeUserAdmin)
+	}
+
+	assertError(t, storage.TotpAccounts().Insert(ctx, &user_db.TotpAccount{UserId: "user1", IsEnabled: 1}), nil)
+	if got := scopes(); got != userScope([]string{RoleAdmin}) {
+		t.Fatalf("admin got scopes %q", got)
+	}
+
+	assertError(t, storage.Roles().Delete(ctx, role), nil)
+	if got := scopes(); got != userScope(nil) {
+		t.Fatalf("after the role was revoked got scopes %q", got)
+	}
+}
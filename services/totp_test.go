@@ -0,0 +1,88 @@
+package services
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTotpCodeRfc6238(t *testing.T) {
+	// RFC 6238 Appendix B, SHA-1. The RFC gives 8 digits; 6 digits are the
+	// same truncation modulo 10^6, so its last 6.
+	secret := []byte("12345678901234567890")
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.FormatInt(tt.unix, 10), func(t *testing.T) {
+			if got := totpCode(secret, tt.unix/totpPeriod); got != tt.want {
+				t.Fatalf("totpCode = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyTotpSkew(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	now := time.Unix(1111111111, 0)
+	current := now.Unix() / totpPeriod
+
+	tests := []struct {
+		name   string
+		offset int64
+		ok     bool
+	}{
+		{"current", 0, true},
+		{"previous", -1, true},
+		{"next", 1, true},
+		{"two behind", -2, false},
+		{"two ahead", 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := verifyTotp(secret, totpCode(secret, current+tt.offset), now, 0)
+			if ok != tt.ok || ok && step != current+tt.offset {
+				t.Fatalf("verifyTotp = %d, %v, want %d, %v", step, ok, current+tt.offset, tt.ok)
+			}
+		})
+	}
+
+	// pasted codes may carry whitespace
+	if _, ok := verifyTotp(secret, " "+totpCode(secret, current)+"\n", now, 0); !ok {
+		t.Fatalf("code with surrounding space rejected")
+	}
+}
+
+func TestVerifyTotpReplay(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	now := time.Unix(1111111111, 0)
+	current := now.Unix() / totpPeriod
+
+	step, ok := verifyTotp(secret, totpCode(secret, current), now, 0)
+	if !ok || step != current {
+		t.Fatalf("verifyTotp = %d, %v, want %d", step, ok, current)
+	}
+
+	// neither the used step nor an earlier one is accepted again
+	if _, ok := verifyTotp(secret, totpCode(secret, current), now, current); ok {
+		t.Fatalf("code of the last used step accepted")
+	}
+	if _, ok := verifyTotp(secret, totpCode(secret, current-1), now, current); ok {
+		t.Fatalf("code of a step before the last used one accepted")
+	}
+
+	// a later step within the window still is
+	if step, ok := verifyTotp(secret, totpCode(secret, current+1), now, current); !ok || step != current+1 {
+		t.Fatalf("verifyTotp = %d, %v, want %d", step, ok, current+1)
+	}
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/NeuronFramework/errors"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"golang.org/x/text/unicode/norm"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// user.user_name and oauth_account.oauth_name are varchar(32), which MySQL
+// counts in characters.
+const userNameMaxLength = 32
+
+const (
+	// defaultUserName is the base of generated names when the caller has
+	// no usable candidate.
+	defaultUserName = "用户"
+	// userNameMaxAttempts names with random suffixes are tried after the
+	// candidate before falling back to the user id.
+	userNameMaxAttempts  = 5
+	userNameSuffixLength = 4
+)
+
+// defaultUserNameBlocklist keeps names that pass for the service itself
+// from being taken.
+var defaultUserNameBlocklist = []string{
+	"admin",
+	"administrator",
+	"root",
+	"system",
+	"support",
+	"official",
+	"security",
+	"moderator",
+	"neuron",
+	"neuronuser",
+}
+
+type UserNameConfig struct {
+	// RenameInterval is the minimum time between two renames by one user.
+	RenameInterval time.Duration
+	// ReservePeriod is how long a name stays reserved for the user who
+	// renamed away from it.
+	ReservePeriod time.Duration
+	// Blocklist entries are names nobody may take, compared ignoring case
+	// and spaces. An entry of the form *word* blocks every name containing
+	// word.
+	Blocklist []string
+}
+
+// NewUserNameConfigFromEnv reads USER_NAME_RENAME_INTERVAL,
+// USER_NAME_RESERVE_PERIOD and USER_NAME_BLOCKLIST_FILE, a file of
+// blocklist entries, one per line, added to the default ones. Lines
+// starting with # are ignored.
+func NewUserNameConfigFromEnv() (c *UserNameConfig, err error) {
+	c = &UserNameConfig{
+		RenameInterval: time.Hour * 24 * 30,
+		ReservePeriod:  time.Hour * 24 * 90,
+		Blocklist:      append([]string(nil), defaultUserNameBlocklist...),
+	}
+
+	durations := []struct {
+		key string
+		v   *time.Duration
+	}{
+		{"USER_NAME_RENAME_INTERVAL", &c.RenameInterval},
+		{"USER_NAME_RESERVE_PERIOD", &c.ReservePeriod},
+	}
+	for _, d := range durations {
+		if v := os.Getenv(d.key); v != "" {
+			*d.v, err = time.ParseDuration(v)
+			if err != nil || *d.v < 0 {
+				return nil, fmt.Errorf("%s env invalid: %s", d.key, v)
+			}
+		}
+	}
+
+	if v := os.Getenv("USER_NAME_BLOCKLIST_FILE"); v != "" {
+		data, err := os.ReadFile(v)
+		if err != nil {
+			return nil, fmt.Errorf("USER_NAME_BLOCKLIST_FILE env invalid: %v", err)
+		}
+
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !strings.HasPrefix(line, "#") {
+				c.Blocklist = append(c.Blocklist, line)
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// blocklistKey folds name for comparison with blocklist entries.
+func blocklistKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(normalizeUserName(name)), ""))
+}
+
+// blocked reports whether name, already normalized, matches the blocklist.
+func (c *UserNameConfig) blocked(name string) bool {
+	key := blocklistKey(name)
+	for _, entry := range c.Blocklist {
+		if len(entry) > 2 && strings.HasPrefix(entry, "*") && strings.HasSuffix(entry, "*") {
+			if word := blocklistKey(entry[1 : len(entry)-1]); word != "" && strings.Contains(key, word) {
+				return true
+			}
+		} else if blocklistKey(entry) == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeUserName returns the NFC form of name with surrounding space
+// trimmed and inner whitespace runs collapsed, so that visually identical
+// names map to the same udx_user_name key.
+func normalizeUserName(name string) string {
+	name = norm.NFC.String(name)
+
+	buf := strings.Builder{}
+	space := false
+	for _, r := range name {
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if space && buf.Len() > 0 {
+			buf.WriteRune(' ')
+		}
+		space = false
+		buf.WriteRune(r)
+	}
+
+	return buf.String()
+}
+
+func isAllowedNameRune(r rune) bool {
+	switch {
+	case r == utf8.RuneError:
+		return false
+	case r == '\u200d':
+		// zero width joiner, needed by emoji sequences
+		return true
+	case r == ' ':
+		return true
+	case r == '\'' || r == '"' || r == '`' || r == '\\' || r == '%':
+		// the generated query builders inline values into the SQL text,
+		// which they then pass to fmt.Sprintf as the format
+		return false
+	case unicode.IsControl(r), unicode.Is(unicode.Cf, r), unicode.IsSpace(r):
+		return false
+	default:
+		return unicode.IsPrint(r) || unicode.Is(unicode.So, r) || unicode.Is(unicode.Mn, r)
+	}
+}
+
+func validateUserName(name string) (err error) {
+	if name == "" {
+		return errors.BadRequest("InvalidUserName", "用户名不能为空")
+	}
+
+	if utf8.RuneCountInString(name) > userNameMaxLength {
+		return errors.BadRequest("InvalidUserName", "用户名过长")
+	}
+
+	for _, r := range name {
+		if !isAllowedNameRune(r) {
+			return errors.BadRequest("InvalidUserName", "用户名包含非法字符")
+		}
+	}
+
+	return nil
+}
+
+// isNameContinuation reports whether r belongs to the character before it,
+// as combining marks, joiners and emoji modifiers do.
+func isNameContinuation(r rune) bool {
+	return r == '\u200d' ||
+		r >= 0x1f3fb && r <= 0x1f3ff ||
+		unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc)
+}
+
+// truncateUserName cuts name to at most n characters without separating a
+// character from its marks or breaking up a joined emoji sequence.
+func truncateUserName(name string, n int) string {
+	runes := []rune(name)
+	if len(runes) <= n {
+		return name
+	}
+
+	i := n
+	for i > 0 && (isNameContinuation(runes[i]) || runes[i-1] == '\u200d') {
+		i--
+	}
+
+	return strings.TrimRight(string(runes[:i]), " ")
+}
+
+// userNameCandidate makes a valid user name of name, e.g. a nickname from
+// a social provider, by dropping the characters user names can't contain
+// and truncating it. It returns "" if nothing usable is left.
+func userNameCandidate(name string) string {
+	buf := strings.Builder{}
+	for _, r := range normalizeUserName(name) {
+		if isAllowedNameRune(r) {
+			buf.WriteRune(r)
+		}
+	}
+
+	return truncateUserName(normalizeUserName(buf.String()), userNameMaxLength)
+}
+
+// phoneUserName is the candidate name of users signing up by SMS.
+func phoneUserName(phoneNumber string) string {
+	if len(phoneNumber) > 4 {
+		phoneNumber = phoneNumber[len(phoneNumber)-4:]
+	}
+
+	return defaultUserName + phoneNumber
+}
+
+// insertUser inserts e, a new user, into tx under a free name. The first of
+// candidates that is usable is tried as is, then with random suffixes, and
+// finally the user id is used. Taken names are detected by the insert
+// failing on udx_user_name, which also covers concurrent signups, in a
+// savepoint of tx; names that are reserved or blocked are skipped.
+func (s *UserService) insertUser(ctx context.Context, tx storages.Storage, e *user_db.User, candidates ...string) (err error) {
+	base := defaultUserName
+	for _, v := range candidates {
+		if v = userNameCandidate(v); v != "" && !s.userNameConfig.blocked(v) {
+			base = v
+			break
+		}
+	}
+
+	now := time.Now()
+	for i := 0; i <= userNameMaxAttempts+1; i++ {
+		name := base
+		switch {
+		case i > userNameMaxAttempts:
+			name = e.UserId
+		case i > 0:
+			suffix, err := randomDigits(userNameSuffixLength)
+			if err != nil {
+				return err
+			}
+			name = truncateUserName(base, userNameMaxLength-userNameSuffixLength-1) + "_" + suffix
+		}
+
+		if s.userNameConfig.blocked(name) {
+			continue
+		}
+		reserved, err := userNameReserved(ctx, tx, name, e.UserId, now)
+		if err != nil {
+			return err
+		}
+		if reserved {
+			continue
+		}
+
+		// a taken name is only known once the insert fails, which must not
+		// leave the failed statement's locks in tx
+		e.UserName = name
+		err = tx.Transaction(ctx, func(tx storages.Storage) error {
+			return tx.Users().Insert(ctx, e)
+		})
+		if err != storages.ErrDuplicate {
+			return err
+		}
+	}
+
+	return errors.Unknown("生成用户名失败")
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"github.com/NeuronUser/user/storages"
+	"github.com/NeuronUser/user/storages/user_db"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// insertTestUser runs insertUser for userId in a transaction of its own and
+// returns the name it got.
+func insertTestUser(t *testing.T, s *UserService, storage *storages.MemoryStorage, userId string, candidates ...string) string {
+	t.Helper()
+
+	e := &user_db.User{UserId: userId}
+	err := storage.Transaction(context.Background(), func(tx storages.Storage) error {
+		return s.insertUser(context.Background(), tx, e, candidates...)
+	})
+	assertError(t, err, nil)
+
+	dbUser, err := storage.Users().GetByUserId(context.Background(), userId)
+	assertError(t, err, nil)
+	if dbUser == nil || dbUser.UserName != e.UserName {
+		t.Fatalf("stored user = %+v, want one named %s", dbUser, e.UserName)
+	}
+	return e.UserName
+}
+
+// hasNameSuffix reports whether name is base followed by _ and
+// userNameSuffixLength digits.
+func hasNameSuffix(name string, base string) bool {
+	suffix := strings.TrimPrefix(name, base+"_")
+	if suffix == name || len(suffix) != userNameSuffixLength {
+		return false
+	}
+
+	for _, r := range suffix {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func TestInsertUserNameCollision(t *testing.T) {
+	s, storage, _ := newTestService(t)
+
+	if got := insertTestUser(t, s, storage, "user1", "小明"); got != "小明" {
+		t.Fatalf("first user named %s, want 小明", got)
+	}
+
+	// the failed insert of the taken name is rolled back, the user is still
+	// created in the same transaction
+	got := insertTestUser(t, s, storage, "user2", "小明")
+	if !hasNameSuffix(got, "小明") {
+		t.Fatalf("second user named %s, want 小明 with a suffix", got)
+	}
+
+	users := 0
+	for _, userId := range []string{"user1", "user2"} {
+		if dbUser, _ := storage.Users().GetByUserId(context.Background(), userId); dbUser != nil {
+			users++
+		}
+	}
+	if users != 2 {
+		t.Fatalf("%d users stored, want 2", users)
+	}
+}
+
+func TestInsertUserNameFallsBackToUserId(t *testing.T) {
+	s, storage, _ := newTestService(t)
+	s.userNameConfig.Blocklist = append(s.userNameConfig.Blocklist, "*用户*")
+
+	// the candidate, the default name and every suffixed name are blocked
+	if got := insertTestUser(t, s, storage, "0123456789abcdef0123456789abcdef", "用户1234"); got != "0123456789abcdef0123456789abcdef" {
+		t.Fatalf("user named %s, want its user id", got)
+	}
+}
+
+func TestInsertUserNameTruncation(t *testing.T) {
+	tests := []struct {
+		name      string
+		candidate string
+		want      string
+	}{
+		{"multi-byte", strings.Repeat("名", 40), strings.Repeat("名", userNameMaxLength)},
+		// cutting at 32 would separate the skin tone from its emoji
+		{"emoji modifier", strings.Repeat("a", 31) + "👍🏽", strings.Repeat("a", 31)},
+		{"joined emoji", strings.Repeat("a", 30) + "👩‍💻", strings.Repeat("a", 30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, storage, _ := newTestService(t)
+
+			got := insertTestUser(t, s, storage, "user1", tt.candidate)
+			if got != tt.want {
+				t.Fatalf("user named %q, want %q", got, tt.want)
+			}
+
+			// a suffixed name still fits
+			got = insertTestUser(t, s, storage, "user2", tt.candidate)
+			if n := utf8.RuneCountInString(got); n > userNameMaxLength || !utf8.ValidString(got) {
+				t.Fatalf("suffixed name %q has %d characters", got, n)
+			}
+		})
+	}
+}
+
+func TestInsertUserNameReserved(t *testing.T) {
+	s, storage, _ := newTestService(t)
+
+	// user0 renamed away from 小明 and still holds it
+	err := storage.UserNameHistory().Insert(context.Background(), &user_db.UserNameHistory{
+		UserId:        "user0",
+		UserName:      "小明",
+		ReservedUntil: time.Now().Add(time.Hour),
+	})
+	assertError(t, err, nil)
+
+	if got := insertTestUser(t, s, storage, "user1", "小明"); !hasNameSuffix(got, "小明") {
+		t.Fatalf("user named %s, want 小明 with a suffix", got)
+	}
+}
+
+func TestInsertUserNameBlockedCandidate(t *testing.T) {
+	s, storage, _ := newTestService(t)
+
+	if got := insertTestUser(t, s, storage, "user1", "Admin", "小明"); got != "小明" {
+		t.Fatalf("user named %s, want the next candidate 小明", got)
+	}
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/NeuronUser/user/storages/user_db"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"os"
+	"strings"
+	"time"
+)
+
+const webAuthnSessionIdLength = 16
+
+type WebAuthnConfig struct {
+	// RPID is the domain passkeys are bound to. Empty disables WebAuthn.
+	RPID          string
+	RPDisplayName string
+	RPOrigins     []string
+	// Timeout is how long a ceremony may take from begin to finish.
+	Timeout time.Duration
+}
+
+// NewWebAuthnConfigFromEnv reads WEBAUTHN_RP_ID, WEBAUTHN_RP_NAME,
+// WEBAUTHN_RP_ORIGINS (comma separated, default https://<RP_ID>) and
+// WEBAUTHN_TIMEOUT.
+func NewWebAuthnConfigFromEnv() (c *WebAuthnConfig, err error) {
+	c = &WebAuthnConfig{
+		RPID:          os.Getenv("WEBAUTHN_RP_ID"),
+		RPDisplayName: "NeuronUser",
+		Timeout:       time.Minute * 5,
+	}
+
+	if v := os.Getenv("WEBAUTHN_RP_NAME"); v != "" {
+		c.RPDisplayName = v
+	}
+
+	if v := os.Getenv("WEBAUTHN_RP_ORIGINS"); v != "" {
+		for _, origin := range strings.Split(v, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				c.RPOrigins = append(c.RPOrigins, origin)
+			}
+		}
+	} else if c.RPID != "" {
+		c.RPOrigins = []string{"https://" + c.RPID}
+	}
+
+	if v := os.Getenv("WEBAUTHN_TIMEOUT"); v != "" {
+		c.Timeout, err = time.ParseDuration(v)
+		if err != nil || c.Timeout <= 0 {
+			return nil, fmt.Errorf("WEBAUTHN_TIMEOUT env invalid: %s", v)
+		}
+	}
+
+	return c, nil
+}
+
+func (c *WebAuthnConfig) newWebAuthn() (*webauthn.WebAuthn, error) {
+	if c.RPID == "" {
+		return nil, nil
+	}
+
+	timeout := webauthn.TimeoutConfig{Enforce: true, Timeout: c.Timeout, TimeoutUVD: c.Timeout}
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          c.RPID,
+		RPDisplayName: c.RPDisplayName,
+		RPOrigins:     c.RPOrigins,
+		Timeouts:      webauthn.TimeoutsConfig{Login: timeout, Registration: timeout},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("WEBAUTHN_* env invalid: %v", err)
+	}
+
+	return w, nil
+}
+
+// webAuthnUser adapts a user and their stored credentials to webauthn.User.
+// The user handle is the user id, so a discoverable login names its user.
+type webAuthnUser struct {
+	user        *user_db.User
+	credentials []webauthn.Credential
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte                         { return []byte(u.user.UserId) }
+func (u *webAuthnUser) WebAuthnName() string                       { return u.user.UserName }
+func (u *webAuthnUser) WebAuthnDisplayName() string                { return u.user.UserName }
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential { return u.credentials }
+
+func newWebAuthnUser(user *user_db.User, records []*user_db.WebauthnCredential) (*webAuthnUser, error) {
+	u := &webAuthnUser{user: user}
+	for _, v := range records {
+		credential := webauthn.Credential{}
+		err := json.Unmarshal([]byte(v.CredentialData), &credential)
+		if err != nil {
+			return nil, err
+		}
+		u.credentials = append(u.credentials, credential)
+	}
+
+	return u, nil
+}
+
+func webAuthnCredentialId(id []byte) string {
+	return base64.RawURLEncoding.EncodeToString(id)
+}
+
+// validWebAuthnSessionId checks a client supplied session id before it is
+// used in a query.
+func validWebAuthnSessionId(sessionId string) bool {
+	if len(sessionId) != webAuthnSessionIdLength*2 {
+		return false
+	}
+
+	_, err := hex.DecodeString(sessionId)
+	return err == nil
+}
@@ -0,0 +1,630 @@
+package storages
+
+import (
+	"context"
+	"fmt"
+	"github.com/NeuronFramework/sql/wrap"
+	"github.com/NeuronUser/user/storages/user_db"
+	"time"
+)
+
+// daoStorage implements Storage on top of the generated user_db DAOs.
+type daoStorage struct {
+	db *user_db.DB
+	tx *wrap.Tx
+	// savepoints is how many savepoints of tx enclose this storage
+	savepoints int
+}
+
+func NewDaoStorage(db *user_db.DB) Storage {
+	return &daoStorage{db: db}
+}
+
+func (s *daoStorage) Users() UserRepository                       { return &daoUsers{s} }
+func (s *daoStorage) PhoneAccounts() PhoneAccountRepository       { return &daoPhoneAccounts{s} }
+func (s *daoStorage) PasswordAccounts() PasswordAccountRepository { return &daoPasswordAccounts{s} }
+func (s *daoStorage) TotpAccounts() TotpAccountRepository         { return &daoTotpAccounts{s} }
+func (s *daoStorage) RecoveryCodes() RecoveryCodeRepository       { return &daoRecoveryCodes{s} }
+func (s *daoStorage) WebAuthnCredentials() WebAuthnCredentialRepository {
+	return &daoWebAuthnCredentials{s}
+}
+func (s *daoStorage) WebAuthnSessions() WebAuthnSessionRepository { return &daoWebAuthnSessions{s} }
+func (s *daoStorage) LoginSmsCodes() LoginSmsCodeRepository       { return &daoLoginSmsCodes{s} }
+func (s *daoStorage) OauthAccounts() OauthAccountRepository       { return &daoOauthAccounts{s} }
+func (s *daoStorage) OauthClients() OauthClientRepository         { return &daoOauthClients{s} }
+func (s *daoStorage) OauthCodes() OauthCodeRepository             { return &daoOauthCodes{s} }
+func (s *daoStorage) Tokens() TokenRepository                     { return &daoTokens{s} }
+func (s *daoStorage) Roles() RoleRepository                       { return &daoRoles{s} }
+func (s *daoStorage) Attributes() AttributeRepository             { return &daoAttributes{s} }
+func (s *daoStorage) Operations() OperationRepository             { return &daoOperations{s} }
+func (s *daoStorage) Expiry() ExpiryRepository                    { return &daoExpiry{s} }
+func (s *daoStorage) UserNameHistory() UserNameHistoryRepository {
+	return &daoUserNameHistory{s}
+}
+
+func (s *daoStorage) Transaction(ctx context.Context, fn func(tx Storage) error) error {
+	if s.tx != nil {
+		return s.savepoint(ctx, fn)
+	}
+
+	return s.db.TransactionReadCommitted(ctx, false, func(tx *wrap.Tx) error {
+		return fn(&daoStorage{db: s.db, tx: tx})
+	})
+}
+
+// savepoint runs fn in a savepoint of s.tx, rolling back to it if fn fails.
+// MySQL keeps the locks a failed statement took, so a caller that goes on
+// after an error, e.g. a duplicate key, must undo the statement this way.
+func (s *daoStorage) savepoint(ctx context.Context, fn func(tx Storage) error) error {
+	name := fmt.Sprintf("sp%d", s.savepoints+1)
+	_, err := s.tx.Exec(ctx, "SAVEPOINT "+name)
+	if err != nil {
+		return err
+	}
+
+	err = fn(&daoStorage{db: s.db, tx: s.tx, savepoints: s.savepoints + 1})
+	if err != nil {
+		_, rollbackErr := s.tx.Exec(ctx, "ROLLBACK TO SAVEPOINT "+name)
+		if rollbackErr != nil {
+			return rollbackErr
+		}
+		return err
+	}
+
+	_, err = s.tx.Exec(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
+
+// locking reports whether reads may add FOR UPDATE; SQLite has no row locks
+// and instead takes the database write lock when the transaction begins.
+func (s *daoStorage) locking() bool {
+	return s.tx != nil && s.db.Driver() != user_db.DriverSqlite
+}
+
+func convertError(err error) error {
+	if user_db.IsDuplicateEntry(err) {
+		return ErrDuplicate
+	}
+
+	return err
+}
+
+type daoUsers struct{ *daoStorage }
+
+func (r *daoUsers) GetByUserId(ctx context.Context, userId string) (*user_db.User, error) {
+	return r.db.User.GetQuery().UserId_Equal(userId).QueryOne(ctx, r.tx)
+}
+
+func (r *daoUsers) GetByUserIdForUpdate(ctx context.Context, userId string) (*user_db.User, error) {
+	ctx = user_db.WithPrimary(ctx)
+	q := r.db.User.GetQuery().UserId_Equal(userId)
+	if r.locking() {
+		q.ForUpdate()
+	}
+	return q.QueryOne(ctx, r.tx)
+}
+
+func (r *daoUsers) GetByUserName(ctx context.Context, userName string) (*user_db.User, error) {
+	return r.db.User.GetQuery().UserName_Equal(userName).QueryOne(ctx, r.tx)
+}
+
+func (r *daoUsers) Insert(ctx context.Context, e *user_db.User) error {
+	id, err := r.db.User.Insert(ctx, r.tx, e)
+	if err != nil {
+		return convertError(err)
+	}
+	e.Id = uint64(id)
+	return nil
+}
+
+func (r *daoUsers) Update(ctx context.Context, e *user_db.User) error {
+	return convertError(r.db.User.Update(ctx, r.tx, e))
+}
+
+type daoUserNameHistory struct{ *daoStorage }
+
+func (r *daoUserNameHistory) ListByUserId(ctx context.Context, userId string, limit int64) ([]*user_db.UserNameHistory, error) {
+	return r.db.UserNameHistory.GetQuery().
+		UserId_Equal(userId).
+		OrderBy(user_db.USER_NAME_HISTORY_FIELD_ID, false).
+		Limit(0, limit).
+		QueryList(ctx, r.tx)
+}
+
+func (r *daoUserNameHistory) ListByUserName(ctx context.Context, userName string) ([]*user_db.UserNameHistory, error) {
+	return r.db.UserNameHistory.GetQuery().
+		UserName_Equal(userName).
+		OrderBy(user_db.USER_NAME_HISTORY_FIELD_ID, false).
+		QueryList(ctx, r.tx)
+}
+
+func (r *daoUserNameHistory) Insert(ctx context.Context, e *user_db.UserNameHistory) error {
+	id, err := r.db.UserNameHistory.Insert(ctx, r.tx, e)
+	if err != nil {
+		return convertError(err)
+	}
+	e.Id = uint64(id)
+	return nil
+}
+
+type daoPhoneAccounts struct{ *daoStorage }
+
+func (r *daoPhoneAccounts) GetByPhoneNumber(ctx context.Context, phoneNumber string) (*user_db.PhoneAccount, error) {
+	return r.db.PhoneAccount.GetQuery().PhoneNumber_Equal(phoneNumber).QueryOne(ctx, r.tx)
+}
+
+func (r *daoPhoneAccounts) GetByUserId(ctx context.Context, userId string) (*user_db.PhoneAccount, error) {
+	return r.db.PhoneAccount.GetQuery().UserId_Equal(userId).QueryOne(ctx, r.tx)
+}
+
+func (r *daoPhoneAccounts) Insert(ctx context.Context, e *user_db.PhoneAccount) error {
+	id, err := r.db.PhoneAccount.Insert(ctx, r.tx, e)
+	if err != nil {
+		return convertError(err)
+	}
+	e.Id = uint64(id)
+	return nil
+}
+
+func (r *daoPhoneAccounts) Update(ctx context.Context, e *user_db.PhoneAccount) error {
+	return convertError(r.db.PhoneAccount.Update(ctx, r.tx, e))
+}
+
+type daoPasswordAccounts struct{ *daoStorage }
+
+func (r *daoPasswordAccounts) GetByUserId(ctx context.Context, userId string) (*user_db.PasswordAccount, error) {
+	return r.db.PasswordAccount.GetQuery().UserId_Equal(userId).QueryOne(ctx, r.tx)
+}
+
+func (r *daoPasswordAccounts) GetByUserIdForUpdate(ctx context.Context, userId string) (*user_db.PasswordAccount, error) {
+	ctx = user_db.WithPrimary(ctx)
+	q := r.db.PasswordAccount.GetQuery().UserId_Equal(userId)
+	if r.locking() {
+		q.ForUpdate()
+	}
+	return q.QueryOne(ctx, r.tx)
+}
+
+func (r *daoPasswordAccounts) Insert(ctx context.Context, e *user_db.PasswordAccount) error {
+	id, err := r.db.PasswordAccount.Insert(ctx, r.tx, e)
+	if err != nil {
+		return convertError(err)
+	}
+	e.Id = uint64(id)
+	return nil
+}
+
+func (r *daoPasswordAccounts) Update(ctx context.Context, e *user_db.PasswordAccount) error {
+	return convertError(r.db.PasswordAccount.Update(ctx, r.tx, e))
+}
+
+type daoTotpAccounts struct{ *daoStorage }
+
+func (r *daoTotpAccounts) GetByUserId(ctx context.Context, userId string) (*user_db.TotpAccount, error) {
+	return r.db.TotpAccount.GetQuery().UserId_Equal(userId).QueryOne(ctx, r.tx)
+}
+
+func (r *daoTotpAccounts) GetByUserIdForUpdate(ctx context.Context, userId string) (*user_db.TotpAccount, error) {
+	ctx = user_db.WithPrimary(ctx)
+	q := r.db.TotpAccount.GetQuery().UserId_Equal(userId)
+	if r.locking() {
+		q.ForUpdate()
+	}
+	return q.QueryOne(ctx, r.tx)
+}
+
+func (r *daoTotpAccounts) Insert(ctx context.Context, e *user_db.TotpAccount) error {
+	id, err := r.db.TotpAccount.Insert(ctx, r.tx, e)
+	if err != nil {
+		return convertError(err)
+	}
+	e.Id = uint64(id)
+	return nil
+}
+
+func (r *daoTotpAccounts) Update(ctx context.Context, e *user_db.TotpAccount) error {
+	return convertError(r.db.TotpAccount.Update(ctx, r.tx, e))
+}
+
+func (r *daoTotpAccounts) Delete(ctx context.Context, e *user_db.TotpAccount) error {
+	return r.db.TotpAccount.Delete(ctx, r.tx, e.Id)
+}
+
+type daoRecoveryCodes struct{ *daoStorage }
+
+func (r *daoRecoveryCodes) ListByUserId(ctx context.Context, userId string) ([]*user_db.MfaRecoveryCode, error) {
+	return r.db.MfaRecoveryCode.GetQuery().UserId_Equal(userId).QueryList(ctx, r.tx)
+}
+
+func (r *daoRecoveryCodes) Insert(ctx context.Context, e *user_db.MfaRecoveryCode) error {
+	id, err := r.db.MfaRecoveryCode.Insert(ctx, r.tx, e)
+	if err != nil {
+		return convertError(err)
+	}
+	e.Id = uint64(id)
+	return nil
+}
+
+func (r *daoRecoveryCodes) Update(ctx context.Context, e *user_db.MfaRecoveryCode) error {
+	return convertError(r.db.MfaRecoveryCode.Update(ctx, r.tx, e))
+}
+
+func (r *daoRecoveryCodes) DeleteByUserId(ctx context.Context, userId string) error {
+	list, err := r.ListByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range list {
+		err = r.db.MfaRecoveryCode.Delete(ctx, r.tx, v.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type daoRoles struct{ *daoStorage }
+
+func (r *daoRoles) ListByUserId(ctx context.Context, userId string) ([]*user_db.UserRole, error) {
+	return r.db.UserRole.GetQuery().UserId_Equal(userId).QueryList(ctx, r.tx)
+}
+
+func (r *daoRoles) Insert(ctx context.Context, e *user_db.UserRole) error {
+	id, err := r.db.UserRole.Insert(ctx, r.tx, e)
+	if err != nil {
+		return convertError(err)
+	}
+	e.Id = uint64(id)
+	return nil
+}
+
+func (r *daoRoles) Delete(ctx context.Context, e *user_db.UserRole) error {
+	return r.db.UserRole.Delete(ctx, r.tx, e.Id)
+}
+
+type daoAttributes struct{ *daoStorage }
+
+func (r *daoAttributes) ListByUserId(ctx context.Context, userId string) ([]*user_db.UserAttribute, error) {
+	return r.db.UserAttribute.GetQuery().UserId_Equal(userId).QueryList(ctx, r.tx)
+}
+
+func (r *daoAttributes) ListByNamespaceForUpdate(ctx context.Context, userId string, namespace string) ([]*user_db.UserAttribute, error) {
+	ctx = user_db.WithPrimary(ctx)
+	q := r.db.UserAttribute.GetQuery().
+		UserId_Equal(userId).
+		Namespace_Equal(namespace)
+	if r.locking() {
+		q.ForUpdate()
+	}
+	return q.QueryList(ctx, r.tx)
+}
+
+func (r *daoAttributes) Insert(ctx context.Context, e *user_db.UserAttribute) error {
+	id, err := r.db.UserAttribute.Insert(ctx, r.tx, e)
+	if err != nil {
+		return convertError(err)
+	}
+	e.Id = uint64(id)
+	return nil
+}
+
+func (r *daoAttributes) Update(ctx context.Context, e *user_db.UserAttribute) error {
+	return convertError(r.db.UserAttribute.Update(ctx, r.tx, e))
+}
+
+func (r *daoAttributes) Delete(ctx context.Context, e *user_db.UserAttribute) error {
+	return r.db.UserAttribute.Delete(ctx, r.tx, e.Id)
+}
+
+type daoLoginSmsCodes struct{ *daoStorage }
+
+func (r *daoLoginSmsCodes) GetLatestByPhoneNumberForUpdate(ctx context.Context, phoneNumber string) (*user_db.LoginSmsCode, error) {
+	ctx = user_db.WithPrimary(ctx)
+	q := r.db.LoginSmsCode.GetQuery().
+		PhoneNumber_Equal(phoneNumber).
+		OrderBy(user_db.LOGIN_SMS_CODE_FIELD_ID, false).
+		Limit(0, 1)
+	if r.locking() {
+		q.ForUpdate()
+	}
+	return q.QueryOne(ctx, r.tx)
+}
+
+func (r *daoLoginSmsCodes) ListByPhoneNumber(ctx context.Context, phoneNumber string, limit int64) ([]*user_db.LoginSmsCode, error) {
+	return r.db.LoginSmsCode.GetQuery().
+		PhoneNumber_Equal(phoneNumber).
+		OrderBy(user_db.LOGIN_SMS_CODE_FIELD_ID, false).
+		Limit(0, limit).
+		QueryList(ctx, r.tx)
+}
+
+func (r *daoLoginSmsCodes) ListByClientIp(ctx context.Context, clientIp string, limit int64) ([]*user_db.LoginSmsCode, error) {
+	return r.db.LoginSmsCode.GetQuery().
+		ClientIp_Equal(clientIp).
+		OrderBy(user_db.LOGIN_SMS_CODE_FIELD_ID, false).
+		Limit(0, limit).
+		QueryList(ctx, r.tx)
+}
+
+func (r *daoLoginSmsCodes) Insert(ctx context.Context, e *user_db.LoginSmsCode) error {
+	id, err := r.db.LoginSmsCode.Insert(ctx, r.tx, e)
+	if err != nil {
+		return convertError(err)
+	}
+	e.Id = uint64(id)
+	return nil
+}
+
+func (r *daoLoginSmsCodes) Update(ctx context.Context, e *user_db.LoginSmsCode) error {
+	return convertError(r.db.LoginSmsCode.Update(ctx, r.tx, e))
+}
+
+type daoWebAuthnCredentials struct{ *daoStorage }
+
+func (r *daoWebAuthnCredentials) ListByUserId(ctx context.Context, userId string) ([]*user_db.WebauthnCredential, error) {
+	return r.db.WebauthnCredential.GetQuery().UserId_Equal(userId).QueryList(ctx, r.tx)
+}
+
+func (r *daoWebAuthnCredentials) GetByCredentialIdForUpdate(ctx context.Context, credentialId string) (*user_db.WebauthnCredential, error) {
+	ctx = user_db.WithPrimary(ctx)
+	q := r.db.WebauthnCredential.GetQuery().CredentialId_Equal(credentialId)
+	if r.locking() {
+		q.ForUpdate()
+	}
+	return q.QueryOne(ctx, r.tx)
+}
+
+func (r *daoWebAuthnCredentials) Insert(ctx context.Context, e *user_db.WebauthnCredential) error {
+	id, err := r.db.WebauthnCredential.Insert(ctx, r.tx, e)
+	if err != nil {
+		return convertError(err)
+	}
+	e.Id = uint64(id)
+	return nil
+}
+
+func (r *daoWebAuthnCredentials) Update(ctx context.Context, e *user_db.WebauthnCredential) error {
+	return convertError(r.db.WebauthnCredential.Update(ctx, r.tx, e))
+}
+
+type daoWebAuthnSessions struct{ *daoStorage }
+
+func (r *daoWebAuthnSessions) GetBySessionIdForUpdate(ctx context.Context, sessionId string) (*user_db.WebauthnSession, error) {
+	ctx = user_db.WithPrimary(ctx)
+	q := r.db.WebauthnSession.GetQuery().SessionId_Equal(sessionId)
+	if r.locking() {
+		q.ForUpdate()
+	}
+	return q.QueryOne(ctx, r.tx)
+}
+
+func (r *daoWebAuthnSessions) Insert(ctx context.Context, e *user_db.WebauthnSession) error {
+	id, err := r.db.WebauthnSession.Insert(ctx, r.tx, e)
+	if err != nil {
+		return convertError(err)
+	}
+	e.Id = uint64(id)
+	return nil
+}
+
+func (r *daoWebAuthnSessions) Update(ctx context.Context, e *user_db.WebauthnSession) error {
+	return convertError(r.db.WebauthnSession.Update(ctx, r.tx, e))
+}
+
+type daoOauthAccounts struct{ *daoStorage }
+
+func (r *daoOauthAccounts) GetByOpenId(ctx context.Context, provider string, openId string) (*user_db.OauthAccount, error) {
+	return r.db.OauthAccount.GetQuery().
+		OauthProvider_Equal(provider).And().OauthOpenId_Equal(openId).
+		QueryOne(ctx, r.tx)
+}
+
+func (r *daoOauthAccounts) GetByOpenIdForUpdate(ctx context.Context, provider string, openId string) (*user_db.OauthAccount, error) {
+	ctx = user_db.WithPrimary(ctx)
+	q := r.db.OauthAccount.GetQuery().
+		OauthProvider_Equal(provider).And().OauthOpenId_Equal(openId)
+	if r.locking() {
+		q.ForUpdate()
+	}
+	return q.QueryOne(ctx, r.tx)
+}
+
+func (r *daoOauthAccounts) ListByUserId(ctx context.Context, userId string) ([]*user_db.OauthAccount, error) {
+	return r.db.OauthAccount.GetQuery().UserId_Equal(userId).QueryList(ctx, r.tx)
+}
+
+func (r *daoOauthAccounts) List(ctx context.Context, afterId uint64, limit int64) ([]*user_db.OauthAccount, error) {
+	return r.db.OauthAccount.GetQuery().
+		Id_Greater(afterId).
+		OrderBy(user_db.OAUTH_ACCOUNT_FIELD_ID, true).
+		Limit(0, limit).
+		QueryList(ctx, r.tx)
+}
+
+func (r *daoOauthAccounts) Insert(ctx context.Context, e *user_db.OauthAccount) error {
+	id, err := r.db.OauthAccount.Insert(ctx, r.tx, e)
+	if err != nil {
+		return convertError(err)
+	}
+	e.Id = uint64(id)
+	return nil
+}
+
+func (r *daoOauthAccounts) Update(ctx context.Context, e *user_db.OauthAccount) error {
+	return convertError(r.db.OauthAccount.Update(ctx, r.tx, e))
+}
+
+type daoOauthClients struct{ *daoStorage }
+
+func (r *daoOauthClients) GetByClientId(ctx context.Context, clientId string) (*user_db.OauthClient, error) {
+	return r.db.OauthClient.GetQuery().ClientId_Equal(clientId).QueryOne(ctx, r.tx)
+}
+
+func (r *daoOauthClients) Insert(ctx context.Context, e *user_db.OauthClient) error {
+	id, err := r.db.OauthClient.Insert(ctx, r.tx, e)
+	if err != nil {
+		return convertError(err)
+	}
+	e.Id = uint64(id)
+	return nil
+}
+
+func (r *daoOauthClients) Update(ctx context.Context, e *user_db.OauthClient) error {
+	return convertError(r.db.OauthClient.Update(ctx, r.tx, e))
+}
+
+type daoOauthCodes struct{ *daoStorage }
+
+func (r *daoOauthCodes) GetByCodeForUpdate(ctx context.Context, code string) (*user_db.OauthAuthorizationCode, error) {
+	ctx = user_db.WithPrimary(ctx)
+	q := r.db.OauthAuthorizationCode.GetQuery().Code_Equal(code)
+	if r.locking() {
+		q.ForUpdate()
+	}
+	return q.QueryOne(ctx, r.tx)
+}
+
+func (r *daoOauthCodes) Insert(ctx context.Context, e *user_db.OauthAuthorizationCode) error {
+	id, err := r.db.OauthAuthorizationCode.Insert(ctx, r.tx, e)
+	if err != nil {
+		return convertError(err)
+	}
+	e.Id = uint64(id)
+	return nil
+}
+
+func (r *daoOauthCodes) Update(ctx context.Context, e *user_db.OauthAuthorizationCode) error {
+	return convertError(r.db.OauthAuthorizationCode.Update(ctx, r.tx, e))
+}
+
+type daoTokens struct{ *daoStorage }
+
+func (r *daoTokens) GetAccessToken(ctx context.Context, accessToken string) (*user_db.AccessToken, error) {
+	return r.db.AccessToken.GetQuery().AccessToken_Equal(accessToken).QueryOne(ctx, r.tx)
+}
+
+func (r *daoTokens) InsertAccessToken(ctx context.Context, e *user_db.AccessToken) error {
+	id, err := r.db.AccessToken.Insert(ctx, r.tx, e)
+	if err != nil {
+		return convertError(err)
+	}
+	e.Id = uint64(id)
+	return nil
+}
+
+func (r *daoTokens) UpdateAccessToken(ctx context.Context, e *user_db.AccessToken) error {
+	return convertError(r.db.AccessToken.Update(ctx, r.tx, e))
+}
+
+func (r *daoTokens) ListAccessTokensBySessionIdForUpdate(ctx context.Context, sessionId string) ([]*user_db.AccessToken, error) {
+	ctx = user_db.WithPrimary(ctx)
+	q := r.db.AccessToken.GetQuery().
+		SessionId_Equal(sessionId).
+		OrderBy(user_db.ACCESS_TOKEN_FIELD_ID, false)
+	if r.locking() {
+		q.ForUpdate()
+	}
+	return q.QueryList(ctx, r.tx)
+}
+
+func (r *daoTokens) ListSessionlessAccessTokensForUpdate(ctx context.Context, userId string) ([]*user_db.AccessToken, error) {
+	ctx = user_db.WithPrimary(ctx)
+	q := r.db.AccessToken.GetQuery().
+		UserId_Equal(userId).
+		SessionId_Equal("").
+		OrderBy(user_db.ACCESS_TOKEN_FIELD_ID, false)
+	if r.locking() {
+		q.ForUpdate()
+	}
+	return q.QueryList(ctx, r.tx)
+}
+
+func (r *daoTokens) GetRefreshToken(ctx context.Context, refreshToken string) (*user_db.RefreshToken, error) {
+	return r.db.RefreshToken.GetQuery().RefreshToken_Equal(refreshToken).QueryOne(ctx, r.tx)
+}
+
+func (r *daoTokens) GetRefreshTokenForUpdate(ctx context.Context, refreshToken string) (*user_db.RefreshToken, error) {
+	ctx = user_db.WithPrimary(ctx)
+	q := r.db.RefreshToken.GetQuery().RefreshToken_Equal(refreshToken)
+	if r.locking() {
+		q.ForUpdate()
+	}
+	return q.QueryOne(ctx, r.tx)
+}
+
+func (r *daoTokens) ListRefreshTokensByUserId(ctx context.Context, userId string) ([]*user_db.RefreshToken, error) {
+	return r.db.RefreshToken.GetQuery().
+		UserId_Equal(userId).
+		OrderBy(user_db.REFRESH_TOKEN_FIELD_ID, false).
+		QueryList(ctx, r.tx)
+}
+
+func (r *daoTokens) ListRefreshTokensBySessionId(ctx context.Context, sessionId string) ([]*user_db.RefreshToken, error) {
+	return r.db.RefreshToken.GetQuery().
+		SessionId_Equal(sessionId).
+		OrderBy(user_db.REFRESH_TOKEN_FIELD_ID, false).
+		QueryList(ctx, r.tx)
+}
+
+func (r *daoTokens) ListRefreshTokensBySessionIdForUpdate(ctx context.Context, sessionId string) ([]*user_db.RefreshToken, error) {
+	ctx = user_db.WithPrimary(ctx)
+	q := r.db.RefreshToken.GetQuery().
+		SessionId_Equal(sessionId).
+		OrderBy(user_db.REFRESH_TOKEN_FIELD_ID, false)
+	if r.locking() {
+		q.ForUpdate()
+	}
+	return q.QueryList(ctx, r.tx)
+}
+
+func (r *daoTokens) ListRefreshTokens(ctx context.Context, afterId uint64, limit int64) ([]*user_db.RefreshToken, error) {
+	return r.db.RefreshToken.GetQuery().
+		Id_Greater(afterId).
+		OrderBy(user_db.REFRESH_TOKEN_FIELD_ID, true).
+		Limit(0, limit).
+		QueryList(ctx, r.tx)
+}
+
+func (r *daoTokens) InsertRefreshToken(ctx context.Context, e *user_db.RefreshToken) error {
+	id, err := r.db.RefreshToken.Insert(ctx, r.tx, e)
+	if err != nil {
+		return convertError(err)
+	}
+	e.Id = uint64(id)
+	return nil
+}
+
+func (r *daoTokens) UpdateRefreshToken(ctx context.Context, e *user_db.RefreshToken) error {
+	return convertError(r.db.RefreshToken.Update(ctx, r.tx, e))
+}
+
+type daoOperations struct{ *daoStorage }
+
+func (r *daoOperations) Insert(ctx context.Context, e *user_db.UserOperation) error {
+	id, err := r.db.UserOperation.Insert(ctx, r.tx, e)
+	if err != nil {
+		return convertError(err)
+	}
+	e.Id = uint64(id)
+	return nil
+}
+
+func (r *daoOperations) ListByUserId(ctx context.Context, userId string, offset int64, limit int64) ([]*user_db.UserOperation, error) {
+	return r.db.UserOperation.GetQuery().
+		UserId_Equal(userId).
+		OrderBy(user_db.USER_OPERATION_FIELD_ID, false).
+		Limit(offset, limit).
+		QueryList(ctx, r.tx)
+}
+
+type daoExpiry struct{ *daoStorage }
+
+func (r *daoExpiry) DeleteExpired(ctx context.Context, table string, before time.Time, limit int64) (int64, error) {
+	return r.db.DeleteExpired(ctx, table, before, limit)
+}
+
+func (r *daoExpiry) WithLock(ctx context.Context, name string, fn func() error) (bool, error) {
+	return r.db.WithLock(ctx, name, fn)
+}
@@ -0,0 +1,1280 @@
+package storages
+
+import (
+	"bytes"
+	"context"
+	"github.com/NeuronUser/user/storages/user_db"
+	"github.com/go-sql-driver/mysql"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MemoryStorage is a thread-safe in-memory Storage for tests and local demos.
+// It enforces the same unique keys as neuron-user_db.sql. Transactions hold
+// an exclusive lock and work on a copy of the tables which replaces the
+// original on commit. Using the MemoryStorage itself rather than the
+// callback's tx inside a Transaction callback panics: on a database that
+// call would run outside the transaction, here it would deadlock.
+type MemoryStorage struct {
+	mutex  sync.Mutex
+	tables *memoryTables
+	// owner is the id of the goroutine running a Transaction callback, 0
+	// if there is none.
+	owner uint64
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{tables: &memoryTables{lastId: make(map[string]uint64)}}
+}
+
+type memoryTables struct {
+	lastId         map[string]uint64
+	users          []user_db.User
+	userNames      []user_db.UserNameHistory
+	phoneAccounts  []user_db.PhoneAccount
+	passwords      []user_db.PasswordAccount
+	totpAccounts   []user_db.TotpAccount
+	recoveryCodes  []user_db.MfaRecoveryCode
+	webAuthnCreds  []user_db.WebauthnCredential
+	webAuthnSess   []user_db.WebauthnSession
+	loginSmsCodes  []user_db.LoginSmsCode
+	oauthAccounts  []user_db.OauthAccount
+	oauthClients   []user_db.OauthClient
+	oauthCodes     []user_db.OauthAuthorizationCode
+	accessTokens   []user_db.AccessToken
+	refreshTokens  []user_db.RefreshToken
+	userRoles      []user_db.UserRole
+	userAttrs      []user_db.UserAttribute
+	userOperations []user_db.UserOperation
+}
+
+func (t *memoryTables) clone() *memoryTables {
+	c := &memoryTables{lastId: make(map[string]uint64, len(t.lastId))}
+	for k, v := range t.lastId {
+		c.lastId[k] = v
+	}
+	c.users = append(c.users, t.users...)
+	c.userNames = append(c.userNames, t.userNames...)
+	c.phoneAccounts = append(c.phoneAccounts, t.phoneAccounts...)
+	c.passwords = append(c.passwords, t.passwords...)
+	c.totpAccounts = append(c.totpAccounts, t.totpAccounts...)
+	c.recoveryCodes = append(c.recoveryCodes, t.recoveryCodes...)
+	c.webAuthnCreds = append(c.webAuthnCreds, t.webAuthnCreds...)
+	c.webAuthnSess = append(c.webAuthnSess, t.webAuthnSess...)
+	c.loginSmsCodes = append(c.loginSmsCodes, t.loginSmsCodes...)
+	c.oauthAccounts = append(c.oauthAccounts, t.oauthAccounts...)
+	c.oauthClients = append(c.oauthClients, t.oauthClients...)
+	c.oauthCodes = append(c.oauthCodes, t.oauthCodes...)
+	c.accessTokens = append(c.accessTokens, t.accessTokens...)
+	c.refreshTokens = append(c.refreshTokens, t.refreshTokens...)
+	c.userRoles = append(c.userRoles, t.userRoles...)
+	c.userAttrs = append(c.userAttrs, t.userAttrs...)
+	c.userOperations = append(c.userOperations, t.userOperations...)
+	return c
+}
+
+func (t *memoryTables) nextId(table string) uint64 {
+	t.lastId[table]++
+	return t.lastId[table]
+}
+
+// memoryView is the Storage handed out by MemoryStorage; tables is set when
+// the view is bound to a transaction.
+type memoryView struct {
+	storage *MemoryStorage
+	tables  *memoryTables
+}
+
+func (s *MemoryStorage) view() *memoryView { return &memoryView{storage: s} }
+
+func (s *MemoryStorage) Users() UserRepository                 { return s.view().Users() }
+func (s *MemoryStorage) PhoneAccounts() PhoneAccountRepository { return s.view().PhoneAccounts() }
+func (s *MemoryStorage) PasswordAccounts() PasswordAccountRepository {
+	return s.view().PasswordAccounts()
+}
+func (s *MemoryStorage) TotpAccounts() TotpAccountRepository   { return s.view().TotpAccounts() }
+func (s *MemoryStorage) RecoveryCodes() RecoveryCodeRepository { return s.view().RecoveryCodes() }
+func (s *MemoryStorage) WebAuthnCredentials() WebAuthnCredentialRepository {
+	return s.view().WebAuthnCredentials()
+}
+func (s *MemoryStorage) WebAuthnSessions() WebAuthnSessionRepository {
+	return s.view().WebAuthnSessions()
+}
+func (s *MemoryStorage) LoginSmsCodes() LoginSmsCodeRepository { return s.view().LoginSmsCodes() }
+func (s *MemoryStorage) OauthAccounts() OauthAccountRepository { return s.view().OauthAccounts() }
+func (s *MemoryStorage) OauthClients() OauthClientRepository   { return s.view().OauthClients() }
+func (s *MemoryStorage) OauthCodes() OauthCodeRepository       { return s.view().OauthCodes() }
+func (s *MemoryStorage) Tokens() TokenRepository               { return s.view().Tokens() }
+func (s *MemoryStorage) Roles() RoleRepository                 { return s.view().Roles() }
+func (s *MemoryStorage) Attributes() AttributeRepository       { return s.view().Attributes() }
+func (s *MemoryStorage) Operations() OperationRepository       { return s.view().Operations() }
+func (s *MemoryStorage) Expiry() ExpiryRepository              { return s.view().Expiry() }
+func (s *MemoryStorage) UserNameHistory() UserNameHistoryRepository {
+	return s.view().UserNameHistory()
+}
+
+func (s *MemoryStorage) Transaction(ctx context.Context, fn func(tx Storage) error) error {
+	return s.view().Transaction(ctx, fn)
+}
+
+func (v *memoryView) Users() UserRepository                       { return &memoryUsers{v} }
+func (v *memoryView) PhoneAccounts() PhoneAccountRepository       { return &memoryPhoneAccounts{v} }
+func (v *memoryView) PasswordAccounts() PasswordAccountRepository { return &memoryPasswordAccounts{v} }
+func (v *memoryView) TotpAccounts() TotpAccountRepository         { return &memoryTotpAccounts{v} }
+func (v *memoryView) RecoveryCodes() RecoveryCodeRepository       { return &memoryRecoveryCodes{v} }
+func (v *memoryView) WebAuthnCredentials() WebAuthnCredentialRepository {
+	return &memoryWebAuthnCredentials{v}
+}
+func (v *memoryView) WebAuthnSessions() WebAuthnSessionRepository { return &memoryWebAuthnSessions{v} }
+func (v *memoryView) LoginSmsCodes() LoginSmsCodeRepository       { return &memoryLoginSmsCodes{v} }
+func (v *memoryView) OauthAccounts() OauthAccountRepository       { return &memoryOauthAccounts{v} }
+func (v *memoryView) OauthClients() OauthClientRepository         { return &memoryOauthClients{v} }
+func (v *memoryView) OauthCodes() OauthCodeRepository             { return &memoryOauthCodes{v} }
+func (v *memoryView) Tokens() TokenRepository                     { return &memoryTokens{v} }
+func (v *memoryView) Roles() RoleRepository                       { return &memoryRoles{v} }
+func (v *memoryView) Attributes() AttributeRepository             { return &memoryAttributes{v} }
+func (v *memoryView) Operations() OperationRepository             { return &memoryOperations{v} }
+func (v *memoryView) Expiry() ExpiryRepository                    { return &memoryExpiry{v} }
+func (v *memoryView) UserNameHistory() UserNameHistoryRepository {
+	return &memoryUserNameHistory{v}
+}
+
+func (v *memoryView) Transaction(ctx context.Context, fn func(tx Storage) error) error {
+	if v.tables != nil {
+		// a savepoint: only fn's changes are undone if it fails
+		tables := v.tables.clone()
+		err := fn(&memoryView{storage: v.storage, tables: tables})
+		if err != nil {
+			return err
+		}
+
+		*v.tables = *tables
+		return nil
+	}
+
+	v.lock()
+	defer v.storage.mutex.Unlock()
+
+	atomic.StoreUint64(&v.storage.owner, goroutineId())
+	defer atomic.StoreUint64(&v.storage.owner, 0)
+
+	tables := v.storage.tables.clone()
+	err := fn(&memoryView{storage: v.storage, tables: tables})
+	if err != nil {
+		return err
+	}
+
+	v.storage.tables = tables
+	return nil
+}
+
+func (v *memoryView) do(fn func(t *memoryTables) error) error {
+	if v.tables != nil {
+		return fn(v.tables)
+	}
+
+	v.lock()
+	defer v.storage.mutex.Unlock()
+	return fn(v.storage.tables)
+}
+
+// lock takes the storage mutex, panicking instead of deadlocking if the
+// calling goroutine is inside a Transaction callback and so already holds it.
+func (v *memoryView) lock() {
+	if owner := atomic.LoadUint64(&v.storage.owner); owner != 0 && owner == goroutineId() {
+		panic("storages: MemoryStorage used inside a Transaction callback, use the callback's tx instead")
+	}
+
+	v.storage.mutex.Lock()
+}
+
+// goroutineId returns the id of the calling goroutine, parsed from the
+// "goroutine N [...]" header of its stack trace.
+func goroutineId() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i > 0 {
+		buf = buf[:i]
+	}
+
+	id, _ := strconv.ParseUint(string(buf), 10, 64)
+	return id
+}
+
+type memoryUsers struct{ *memoryView }
+
+func (r *memoryUsers) find(t *memoryTables, match func(e *user_db.User) bool) *user_db.User {
+	for i := range t.users {
+		if match(&t.users[i]) {
+			e := t.users[i]
+			return &e
+		}
+	}
+	return nil
+}
+
+func (r *memoryUsers) GetByUserId(ctx context.Context, userId string) (e *user_db.User, err error) {
+	err = r.do(func(t *memoryTables) error {
+		e = r.find(t, func(u *user_db.User) bool { return u.UserId == userId })
+		return nil
+	})
+	return e, err
+}
+
+func (r *memoryUsers) GetByUserIdForUpdate(ctx context.Context, userId string) (*user_db.User, error) {
+	return r.GetByUserId(ctx, userId)
+}
+
+func (r *memoryUsers) GetByUserName(ctx context.Context, userName string) (e *user_db.User, err error) {
+	err = r.do(func(t *memoryTables) error {
+		// udx_user_name uses a case-insensitive collation
+		e = r.find(t, func(u *user_db.User) bool { return strings.EqualFold(u.UserName, userName) })
+		return nil
+	})
+	return e, err
+}
+
+func (r *memoryUsers) conflicts(t *memoryTables, e *user_db.User) bool {
+	for _, v := range t.users {
+		if v.Id == e.Id {
+			continue
+		}
+		if v.UserId == e.UserId || strings.EqualFold(v.UserName, e.UserName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryUsers) Insert(ctx context.Context, e *user_db.User) error {
+	return r.do(func(t *memoryTables) error {
+		e.Id = 0
+		if r.conflicts(t, e) {
+			return ErrDuplicate
+		}
+		e.Id = t.nextId(user_db.USER_TABLE_NAME)
+		e.CreateTime = time.Now()
+		e.UpdateTime = e.CreateTime
+		t.users = append(t.users, *e)
+		return nil
+	})
+}
+
+func (r *memoryUsers) Update(ctx context.Context, e *user_db.User) error {
+	return r.do(func(t *memoryTables) error {
+		if r.conflicts(t, e) {
+			return ErrDuplicate
+		}
+		for i := range t.users {
+			if t.users[i].Id == e.Id {
+				e.CreateTime = t.users[i].CreateTime
+				e.UpdateTime = time.Now()
+				t.users[i] = *e
+			}
+		}
+		return nil
+	})
+}
+
+type memoryUserNameHistory struct{ *memoryView }
+
+func (r *memoryUserNameHistory) list(match func(e *user_db.UserNameHistory) bool, limit int64) (list []*user_db.UserNameHistory, err error) {
+	list = make([]*user_db.UserNameHistory, 0)
+	err = r.do(func(t *memoryTables) error {
+		for i := len(t.userNames) - 1; i >= 0 && (limit < 0 || int64(len(list)) < limit); i-- {
+			if match(&t.userNames[i]) {
+				v := t.userNames[i]
+				list = append(list, &v)
+			}
+		}
+		return nil
+	})
+	return list, err
+}
+
+func (r *memoryUserNameHistory) ListByUserId(ctx context.Context, userId string, limit int64) ([]*user_db.UserNameHistory, error) {
+	return r.list(func(e *user_db.UserNameHistory) bool { return e.UserId == userId }, limit)
+}
+
+func (r *memoryUserNameHistory) ListByUserName(ctx context.Context, userName string) ([]*user_db.UserNameHistory, error) {
+	return r.list(func(e *user_db.UserNameHistory) bool { return strings.EqualFold(e.UserName, userName) }, -1)
+}
+
+func (r *memoryUserNameHistory) Insert(ctx context.Context, e *user_db.UserNameHistory) error {
+	return r.do(func(t *memoryTables) error {
+		e.Id = t.nextId(user_db.USER_NAME_HISTORY_TABLE_NAME)
+		e.CreateTime = time.Now()
+		e.UpdateTime = e.CreateTime
+		t.userNames = append(t.userNames, *e)
+		return nil
+	})
+}
+
+type memoryPhoneAccounts struct{ *memoryView }
+
+func (r *memoryPhoneAccounts) find(t *memoryTables, match func(e *user_db.PhoneAccount) bool) *user_db.PhoneAccount {
+	for i := range t.phoneAccounts {
+		if match(&t.phoneAccounts[i]) {
+			e := t.phoneAccounts[i]
+			return &e
+		}
+	}
+	return nil
+}
+
+func (r *memoryPhoneAccounts) GetByPhoneNumber(ctx context.Context, phoneNumber string) (e *user_db.PhoneAccount, err error) {
+	err = r.do(func(t *memoryTables) error {
+		e = r.find(t, func(p *user_db.PhoneAccount) bool { return p.PhoneNumber == phoneNumber })
+		return nil
+	})
+	return e, err
+}
+
+func (r *memoryPhoneAccounts) GetByUserId(ctx context.Context, userId string) (e *user_db.PhoneAccount, err error) {
+	err = r.do(func(t *memoryTables) error {
+		e = r.find(t, func(p *user_db.PhoneAccount) bool { return p.UserId == userId })
+		return nil
+	})
+	return e, err
+}
+
+func (r *memoryPhoneAccounts) conflicts(t *memoryTables, e *user_db.PhoneAccount) bool {
+	for _, v := range t.phoneAccounts {
+		if v.Id != e.Id && (v.PhoneNumber == e.PhoneNumber || v.UserId == e.UserId) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryPhoneAccounts) Insert(ctx context.Context, e *user_db.PhoneAccount) error {
+	return r.do(func(t *memoryTables) error {
+		e.Id = 0
+		if r.conflicts(t, e) {
+			return ErrDuplicate
+		}
+		e.Id = t.nextId(user_db.PHONE_ACCOUNT_TABLE_NAME)
+		e.CreateTime = time.Now()
+		e.UpdateTime = e.CreateTime
+		t.phoneAccounts = append(t.phoneAccounts, *e)
+		return nil
+	})
+}
+
+func (r *memoryPhoneAccounts) Update(ctx context.Context, e *user_db.PhoneAccount) error {
+	return r.do(func(t *memoryTables) error {
+		if r.conflicts(t, e) {
+			return ErrDuplicate
+		}
+		for i := range t.phoneAccounts {
+			if t.phoneAccounts[i].Id == e.Id {
+				e.CreateTime = t.phoneAccounts[i].CreateTime
+				e.UpdateTime = time.Now()
+				t.phoneAccounts[i] = *e
+			}
+		}
+		return nil
+	})
+}
+
+type memoryPasswordAccounts struct{ *memoryView }
+
+func (r *memoryPasswordAccounts) GetByUserId(ctx context.Context, userId string) (e *user_db.PasswordAccount, err error) {
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.passwords {
+			if t.passwords[i].UserId == userId {
+				v := t.passwords[i]
+				e = &v
+				break
+			}
+		}
+		return nil
+	})
+	return e, err
+}
+
+func (r *memoryPasswordAccounts) GetByUserIdForUpdate(ctx context.Context, userId string) (*user_db.PasswordAccount, error) {
+	return r.GetByUserId(ctx, userId)
+}
+
+func (r *memoryPasswordAccounts) Insert(ctx context.Context, e *user_db.PasswordAccount) error {
+	return r.do(func(t *memoryTables) error {
+		for _, v := range t.passwords {
+			if v.UserId == e.UserId {
+				return ErrDuplicate
+			}
+		}
+		e.Id = t.nextId(user_db.PASSWORD_ACCOUNT_TABLE_NAME)
+		e.CreateTime = time.Now()
+		e.UpdateTime = e.CreateTime
+		t.passwords = append(t.passwords, *e)
+		return nil
+	})
+}
+
+func (r *memoryPasswordAccounts) Update(ctx context.Context, e *user_db.PasswordAccount) error {
+	return r.do(func(t *memoryTables) error {
+		for _, v := range t.passwords {
+			if v.Id != e.Id && v.UserId == e.UserId {
+				return ErrDuplicate
+			}
+		}
+		for i := range t.passwords {
+			if t.passwords[i].Id == e.Id {
+				e.CreateTime = t.passwords[i].CreateTime
+				e.UpdateTime = time.Now()
+				t.passwords[i] = *e
+			}
+		}
+		return nil
+	})
+}
+
+type memoryTotpAccounts struct{ *memoryView }
+
+func (r *memoryTotpAccounts) GetByUserId(ctx context.Context, userId string) (e *user_db.TotpAccount, err error) {
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.totpAccounts {
+			if t.totpAccounts[i].UserId == userId {
+				v := t.totpAccounts[i]
+				e = &v
+				break
+			}
+		}
+		return nil
+	})
+	return e, err
+}
+
+func (r *memoryTotpAccounts) GetByUserIdForUpdate(ctx context.Context, userId string) (*user_db.TotpAccount, error) {
+	return r.GetByUserId(ctx, userId)
+}
+
+func (r *memoryTotpAccounts) Insert(ctx context.Context, e *user_db.TotpAccount) error {
+	return r.do(func(t *memoryTables) error {
+		for _, v := range t.totpAccounts {
+			if v.UserId == e.UserId {
+				return ErrDuplicate
+			}
+		}
+		e.Id = t.nextId(user_db.TOTP_ACCOUNT_TABLE_NAME)
+		e.CreateTime = time.Now()
+		e.UpdateTime = e.CreateTime
+		t.totpAccounts = append(t.totpAccounts, *e)
+		return nil
+	})
+}
+
+func (r *memoryTotpAccounts) Update(ctx context.Context, e *user_db.TotpAccount) error {
+	return r.do(func(t *memoryTables) error {
+		for _, v := range t.totpAccounts {
+			if v.Id != e.Id && v.UserId == e.UserId {
+				return ErrDuplicate
+			}
+		}
+		for i := range t.totpAccounts {
+			if t.totpAccounts[i].Id == e.Id {
+				e.CreateTime = t.totpAccounts[i].CreateTime
+				e.UpdateTime = time.Now()
+				t.totpAccounts[i] = *e
+			}
+		}
+		return nil
+	})
+}
+
+func (r *memoryTotpAccounts) Delete(ctx context.Context, e *user_db.TotpAccount) error {
+	return r.do(func(t *memoryTables) error {
+		kept := t.totpAccounts[:0]
+		for _, v := range t.totpAccounts {
+			if v.Id != e.Id {
+				kept = append(kept, v)
+			}
+		}
+		t.totpAccounts = kept
+		return nil
+	})
+}
+
+type memoryRecoveryCodes struct{ *memoryView }
+
+func (r *memoryRecoveryCodes) ListByUserId(ctx context.Context, userId string) (list []*user_db.MfaRecoveryCode, err error) {
+	list = make([]*user_db.MfaRecoveryCode, 0)
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.recoveryCodes {
+			if t.recoveryCodes[i].UserId == userId {
+				v := t.recoveryCodes[i]
+				list = append(list, &v)
+			}
+		}
+		return nil
+	})
+	return list, err
+}
+
+func (r *memoryRecoveryCodes) Insert(ctx context.Context, e *user_db.MfaRecoveryCode) error {
+	return r.do(func(t *memoryTables) error {
+		e.Id = t.nextId(user_db.MFA_RECOVERY_CODE_TABLE_NAME)
+		e.CreateTime = time.Now()
+		e.UpdateTime = e.CreateTime
+		t.recoveryCodes = append(t.recoveryCodes, *e)
+		return nil
+	})
+}
+
+func (r *memoryRecoveryCodes) Update(ctx context.Context, e *user_db.MfaRecoveryCode) error {
+	return r.do(func(t *memoryTables) error {
+		for i := range t.recoveryCodes {
+			if t.recoveryCodes[i].Id == e.Id {
+				e.CreateTime = t.recoveryCodes[i].CreateTime
+				e.UpdateTime = time.Now()
+				t.recoveryCodes[i] = *e
+			}
+		}
+		return nil
+	})
+}
+
+func (r *memoryRecoveryCodes) DeleteByUserId(ctx context.Context, userId string) error {
+	return r.do(func(t *memoryTables) error {
+		kept := t.recoveryCodes[:0]
+		for _, v := range t.recoveryCodes {
+			if v.UserId != userId {
+				kept = append(kept, v)
+			}
+		}
+		t.recoveryCodes = kept
+		return nil
+	})
+}
+
+type memoryRoles struct{ *memoryView }
+
+func (r *memoryRoles) ListByUserId(ctx context.Context, userId string) (list []*user_db.UserRole, err error) {
+	list = make([]*user_db.UserRole, 0)
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.userRoles {
+			if t.userRoles[i].UserId == userId {
+				v := t.userRoles[i]
+				list = append(list, &v)
+			}
+		}
+		return nil
+	})
+	return list, err
+}
+
+func (r *memoryRoles) Insert(ctx context.Context, e *user_db.UserRole) error {
+	return r.do(func(t *memoryTables) error {
+		for _, v := range t.userRoles {
+			if v.UserId == e.UserId && v.Role == e.Role {
+				return ErrDuplicate
+			}
+		}
+		e.Id = t.nextId(user_db.USER_ROLE_TABLE_NAME)
+		e.CreateTime = time.Now()
+		e.UpdateTime = e.CreateTime
+		t.userRoles = append(t.userRoles, *e)
+		return nil
+	})
+}
+
+func (r *memoryRoles) Delete(ctx context.Context, e *user_db.UserRole) error {
+	return r.do(func(t *memoryTables) error {
+		kept := t.userRoles[:0]
+		for _, v := range t.userRoles {
+			if v.Id != e.Id {
+				kept = append(kept, v)
+			}
+		}
+		t.userRoles = kept
+		return nil
+	})
+}
+
+type memoryAttributes struct{ *memoryView }
+
+func (r *memoryAttributes) list(match func(e *user_db.UserAttribute) bool) (list []*user_db.UserAttribute, err error) {
+	list = make([]*user_db.UserAttribute, 0)
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.userAttrs {
+			if match(&t.userAttrs[i]) {
+				v := t.userAttrs[i]
+				list = append(list, &v)
+			}
+		}
+		return nil
+	})
+	return list, err
+}
+
+func (r *memoryAttributes) ListByUserId(ctx context.Context, userId string) ([]*user_db.UserAttribute, error) {
+	return r.list(func(e *user_db.UserAttribute) bool { return e.UserId == userId })
+}
+
+func (r *memoryAttributes) ListByNamespaceForUpdate(ctx context.Context, userId string, namespace string) ([]*user_db.UserAttribute, error) {
+	return r.list(func(e *user_db.UserAttribute) bool { return e.UserId == userId && e.Namespace == namespace })
+}
+
+func (r *memoryAttributes) conflicts(t *memoryTables, e *user_db.UserAttribute) bool {
+	for _, v := range t.userAttrs {
+		if v.Id != e.Id && v.UserId == e.UserId && v.Namespace == e.Namespace && v.AttrKey == e.AttrKey {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryAttributes) Insert(ctx context.Context, e *user_db.UserAttribute) error {
+	return r.do(func(t *memoryTables) error {
+		e.Id = 0
+		if r.conflicts(t, e) {
+			return ErrDuplicate
+		}
+		e.Id = t.nextId(user_db.USER_ATTRIBUTE_TABLE_NAME)
+		e.CreateTime = time.Now()
+		e.UpdateTime = e.CreateTime
+		t.userAttrs = append(t.userAttrs, *e)
+		return nil
+	})
+}
+
+func (r *memoryAttributes) Update(ctx context.Context, e *user_db.UserAttribute) error {
+	return r.do(func(t *memoryTables) error {
+		if r.conflicts(t, e) {
+			return ErrDuplicate
+		}
+		for i := range t.userAttrs {
+			if t.userAttrs[i].Id == e.Id {
+				e.CreateTime = t.userAttrs[i].CreateTime
+				e.UpdateTime = time.Now()
+				t.userAttrs[i] = *e
+			}
+		}
+		return nil
+	})
+}
+
+func (r *memoryAttributes) Delete(ctx context.Context, e *user_db.UserAttribute) error {
+	return r.do(func(t *memoryTables) error {
+		kept := t.userAttrs[:0]
+		for _, v := range t.userAttrs {
+			if v.Id != e.Id {
+				kept = append(kept, v)
+			}
+		}
+		t.userAttrs = kept
+		return nil
+	})
+}
+
+type memoryWebAuthnCredentials struct{ *memoryView }
+
+func (r *memoryWebAuthnCredentials) ListByUserId(ctx context.Context, userId string) (list []*user_db.WebauthnCredential, err error) {
+	list = make([]*user_db.WebauthnCredential, 0)
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.webAuthnCreds {
+			if t.webAuthnCreds[i].UserId == userId {
+				v := t.webAuthnCreds[i]
+				list = append(list, &v)
+			}
+		}
+		return nil
+	})
+	return list, err
+}
+
+func (r *memoryWebAuthnCredentials) GetByCredentialIdForUpdate(ctx context.Context, credentialId string) (e *user_db.WebauthnCredential, err error) {
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.webAuthnCreds {
+			if t.webAuthnCreds[i].CredentialId == credentialId {
+				v := t.webAuthnCreds[i]
+				e = &v
+				break
+			}
+		}
+		return nil
+	})
+	return e, err
+}
+
+func (r *memoryWebAuthnCredentials) Insert(ctx context.Context, e *user_db.WebauthnCredential) error {
+	return r.do(func(t *memoryTables) error {
+		for _, v := range t.webAuthnCreds {
+			if v.CredentialId == e.CredentialId {
+				return ErrDuplicate
+			}
+		}
+		e.Id = t.nextId(user_db.WEBAUTHN_CREDENTIAL_TABLE_NAME)
+		e.CreateTime = time.Now()
+		e.UpdateTime = e.CreateTime
+		t.webAuthnCreds = append(t.webAuthnCreds, *e)
+		return nil
+	})
+}
+
+func (r *memoryWebAuthnCredentials) Update(ctx context.Context, e *user_db.WebauthnCredential) error {
+	return r.do(func(t *memoryTables) error {
+		for _, v := range t.webAuthnCreds {
+			if v.Id != e.Id && v.CredentialId == e.CredentialId {
+				return ErrDuplicate
+			}
+		}
+		for i := range t.webAuthnCreds {
+			if t.webAuthnCreds[i].Id == e.Id {
+				e.CreateTime = t.webAuthnCreds[i].CreateTime
+				e.UpdateTime = time.Now()
+				t.webAuthnCreds[i] = *e
+			}
+		}
+		return nil
+	})
+}
+
+type memoryWebAuthnSessions struct{ *memoryView }
+
+func (r *memoryWebAuthnSessions) GetBySessionIdForUpdate(ctx context.Context, sessionId string) (e *user_db.WebauthnSession, err error) {
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.webAuthnSess {
+			if t.webAuthnSess[i].SessionId == sessionId {
+				v := t.webAuthnSess[i]
+				e = &v
+				break
+			}
+		}
+		return nil
+	})
+	return e, err
+}
+
+func (r *memoryWebAuthnSessions) Insert(ctx context.Context, e *user_db.WebauthnSession) error {
+	return r.do(func(t *memoryTables) error {
+		for _, v := range t.webAuthnSess {
+			if v.SessionId == e.SessionId {
+				return ErrDuplicate
+			}
+		}
+		e.Id = t.nextId(user_db.WEBAUTHN_SESSION_TABLE_NAME)
+		e.CreateTime = time.Now()
+		e.UpdateTime = e.CreateTime
+		t.webAuthnSess = append(t.webAuthnSess, *e)
+		return nil
+	})
+}
+
+func (r *memoryWebAuthnSessions) Update(ctx context.Context, e *user_db.WebauthnSession) error {
+	return r.do(func(t *memoryTables) error {
+		for i := range t.webAuthnSess {
+			if t.webAuthnSess[i].Id == e.Id {
+				e.CreateTime = t.webAuthnSess[i].CreateTime
+				e.UpdateTime = time.Now()
+				t.webAuthnSess[i] = *e
+			}
+		}
+		return nil
+	})
+}
+
+type memoryLoginSmsCodes struct{ *memoryView }
+
+func (r *memoryLoginSmsCodes) list(match func(e *user_db.LoginSmsCode) bool, limit int64) (list []*user_db.LoginSmsCode, err error) {
+	list = make([]*user_db.LoginSmsCode, 0)
+	err = r.do(func(t *memoryTables) error {
+		for i := len(t.loginSmsCodes) - 1; i >= 0 && int64(len(list)) < limit; i-- {
+			if match(&t.loginSmsCodes[i]) {
+				v := t.loginSmsCodes[i]
+				list = append(list, &v)
+			}
+		}
+		return nil
+	})
+	return list, err
+}
+
+func (r *memoryLoginSmsCodes) GetLatestByPhoneNumberForUpdate(ctx context.Context, phoneNumber string) (*user_db.LoginSmsCode, error) {
+	list, err := r.ListByPhoneNumber(ctx, phoneNumber, 1)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+func (r *memoryLoginSmsCodes) ListByPhoneNumber(ctx context.Context, phoneNumber string, limit int64) ([]*user_db.LoginSmsCode, error) {
+	return r.list(func(e *user_db.LoginSmsCode) bool { return e.PhoneNumber == phoneNumber }, limit)
+}
+
+func (r *memoryLoginSmsCodes) ListByClientIp(ctx context.Context, clientIp string, limit int64) ([]*user_db.LoginSmsCode, error) {
+	return r.list(func(e *user_db.LoginSmsCode) bool { return e.ClientIp == clientIp }, limit)
+}
+
+func (r *memoryLoginSmsCodes) Insert(ctx context.Context, e *user_db.LoginSmsCode) error {
+	return r.do(func(t *memoryTables) error {
+		e.Id = t.nextId(user_db.LOGIN_SMS_CODE_TABLE_NAME)
+		e.CreateTime = time.Now()
+		e.UpdateTime = e.CreateTime
+		t.loginSmsCodes = append(t.loginSmsCodes, *e)
+		return nil
+	})
+}
+
+func (r *memoryLoginSmsCodes) Update(ctx context.Context, e *user_db.LoginSmsCode) error {
+	return r.do(func(t *memoryTables) error {
+		for i := range t.loginSmsCodes {
+			if t.loginSmsCodes[i].Id == e.Id {
+				e.CreateTime = t.loginSmsCodes[i].CreateTime
+				e.UpdateTime = time.Now()
+				t.loginSmsCodes[i] = *e
+			}
+		}
+		return nil
+	})
+}
+
+type memoryOauthAccounts struct{ *memoryView }
+
+func (r *memoryOauthAccounts) GetByOpenId(ctx context.Context, provider string, openId string) (e *user_db.OauthAccount, err error) {
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.oauthAccounts {
+			if t.oauthAccounts[i].OauthProvider == provider && t.oauthAccounts[i].OauthOpenId == openId {
+				v := t.oauthAccounts[i]
+				e = &v
+				break
+			}
+		}
+		return nil
+	})
+	return e, err
+}
+
+func (r *memoryOauthAccounts) GetByOpenIdForUpdate(ctx context.Context, provider string, openId string) (*user_db.OauthAccount, error) {
+	return r.GetByOpenId(ctx, provider, openId)
+}
+
+func (r *memoryOauthAccounts) ListByUserId(ctx context.Context, userId string) (list []*user_db.OauthAccount, err error) {
+	list = make([]*user_db.OauthAccount, 0)
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.oauthAccounts {
+			if t.oauthAccounts[i].UserId == userId {
+				v := t.oauthAccounts[i]
+				list = append(list, &v)
+			}
+		}
+		return nil
+	})
+	return list, err
+}
+
+func (r *memoryOauthAccounts) List(ctx context.Context, afterId uint64, limit int64) (list []*user_db.OauthAccount, err error) {
+	list = make([]*user_db.OauthAccount, 0)
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.oauthAccounts {
+			if t.oauthAccounts[i].Id > afterId {
+				v := t.oauthAccounts[i]
+				list = append(list, &v)
+			}
+		}
+		return nil
+	})
+	sort.Slice(list, func(i, j int) bool { return list[i].Id < list[j].Id })
+	if int64(len(list)) > limit {
+		list = list[:limit]
+	}
+	return list, err
+}
+
+func (r *memoryOauthAccounts) conflicts(t *memoryTables, e *user_db.OauthAccount) bool {
+	for _, v := range t.oauthAccounts {
+		if v.Id != e.Id && v.OauthProvider == e.OauthProvider && v.OauthOpenId == e.OauthOpenId {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryOauthAccounts) Insert(ctx context.Context, e *user_db.OauthAccount) error {
+	return r.do(func(t *memoryTables) error {
+		e.Id = 0
+		if r.conflicts(t, e) {
+			return ErrDuplicate
+		}
+		e.Id = t.nextId(user_db.OAUTH_ACCOUNT_TABLE_NAME)
+		e.CreateTime = time.Now()
+		e.UpdateTime = mysql.NullTime{Time: e.CreateTime, Valid: true}
+		t.oauthAccounts = append(t.oauthAccounts, *e)
+		return nil
+	})
+}
+
+func (r *memoryOauthAccounts) Update(ctx context.Context, e *user_db.OauthAccount) error {
+	return r.do(func(t *memoryTables) error {
+		if r.conflicts(t, e) {
+			return ErrDuplicate
+		}
+		for i := range t.oauthAccounts {
+			if t.oauthAccounts[i].Id == e.Id {
+				e.CreateTime = t.oauthAccounts[i].CreateTime
+				e.UpdateTime = mysql.NullTime{Time: time.Now(), Valid: true}
+				t.oauthAccounts[i] = *e
+			}
+		}
+		return nil
+	})
+}
+
+type memoryOauthClients struct{ *memoryView }
+
+func (r *memoryOauthClients) GetByClientId(ctx context.Context, clientId string) (e *user_db.OauthClient, err error) {
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.oauthClients {
+			if t.oauthClients[i].ClientId == clientId {
+				v := t.oauthClients[i]
+				e = &v
+				break
+			}
+		}
+		return nil
+	})
+	return e, err
+}
+
+func (r *memoryOauthClients) Insert(ctx context.Context, e *user_db.OauthClient) error {
+	return r.do(func(t *memoryTables) error {
+		for _, v := range t.oauthClients {
+			if v.ClientId == e.ClientId {
+				return ErrDuplicate
+			}
+		}
+		e.Id = t.nextId(user_db.OAUTH_CLIENT_TABLE_NAME)
+		e.CreateTime = time.Now()
+		e.UpdateTime = e.CreateTime
+		t.oauthClients = append(t.oauthClients, *e)
+		return nil
+	})
+}
+
+func (r *memoryOauthClients) Update(ctx context.Context, e *user_db.OauthClient) error {
+	return r.do(func(t *memoryTables) error {
+		for _, v := range t.oauthClients {
+			if v.Id != e.Id && v.ClientId == e.ClientId {
+				return ErrDuplicate
+			}
+		}
+		for i := range t.oauthClients {
+			if t.oauthClients[i].Id == e.Id {
+				e.CreateTime = t.oauthClients[i].CreateTime
+				e.UpdateTime = time.Now()
+				t.oauthClients[i] = *e
+			}
+		}
+		return nil
+	})
+}
+
+type memoryOauthCodes struct{ *memoryView }
+
+func (r *memoryOauthCodes) GetByCodeForUpdate(ctx context.Context, code string) (e *user_db.OauthAuthorizationCode, err error) {
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.oauthCodes {
+			if t.oauthCodes[i].Code == code {
+				v := t.oauthCodes[i]
+				e = &v
+				break
+			}
+		}
+		return nil
+	})
+	return e, err
+}
+
+func (r *memoryOauthCodes) Insert(ctx context.Context, e *user_db.OauthAuthorizationCode) error {
+	return r.do(func(t *memoryTables) error {
+		for _, v := range t.oauthCodes {
+			if v.Code == e.Code {
+				return ErrDuplicate
+			}
+		}
+		e.Id = t.nextId(user_db.OAUTH_AUTHORIZATION_CODE_TABLE_NAME)
+		e.CreateTime = time.Now()
+		e.UpdateTime = e.CreateTime
+		t.oauthCodes = append(t.oauthCodes, *e)
+		return nil
+	})
+}
+
+func (r *memoryOauthCodes) Update(ctx context.Context, e *user_db.OauthAuthorizationCode) error {
+	return r.do(func(t *memoryTables) error {
+		for i := range t.oauthCodes {
+			if t.oauthCodes[i].Id == e.Id {
+				e.CreateTime = t.oauthCodes[i].CreateTime
+				e.UpdateTime = time.Now()
+				t.oauthCodes[i] = *e
+			}
+		}
+		return nil
+	})
+}
+
+type memoryTokens struct{ *memoryView }
+
+func (r *memoryTokens) GetAccessToken(ctx context.Context, accessToken string) (e *user_db.AccessToken, err error) {
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.accessTokens {
+			if t.accessTokens[i].AccessToken == accessToken {
+				v := t.accessTokens[i]
+				e = &v
+				break
+			}
+		}
+		return nil
+	})
+	return e, err
+}
+
+func (r *memoryTokens) InsertAccessToken(ctx context.Context, e *user_db.AccessToken) error {
+	return r.do(func(t *memoryTables) error {
+		for _, v := range t.accessTokens {
+			if v.AccessToken == e.AccessToken {
+				return ErrDuplicate
+			}
+		}
+		e.Id = t.nextId(user_db.ACCESS_TOKEN_TABLE_NAME)
+		e.CreateTime = time.Now()
+		e.UpdateTime = e.CreateTime
+		t.accessTokens = append(t.accessTokens, *e)
+		return nil
+	})
+}
+
+func (r *memoryTokens) UpdateAccessToken(ctx context.Context, e *user_db.AccessToken) error {
+	return r.do(func(t *memoryTables) error {
+		for _, v := range t.accessTokens {
+			if v.Id != e.Id && v.AccessToken == e.AccessToken {
+				return ErrDuplicate
+			}
+		}
+		for i := range t.accessTokens {
+			if t.accessTokens[i].Id == e.Id {
+				e.CreateTime = t.accessTokens[i].CreateTime
+				e.UpdateTime = time.Now()
+				t.accessTokens[i] = *e
+			}
+		}
+		return nil
+	})
+}
+
+func (r *memoryTokens) ListAccessTokensBySessionIdForUpdate(ctx context.Context, sessionId string) (list []*user_db.AccessToken, err error) {
+	list = make([]*user_db.AccessToken, 0)
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.accessTokens {
+			if t.accessTokens[i].SessionId == sessionId {
+				v := t.accessTokens[i]
+				list = append(list, &v)
+			}
+		}
+		return nil
+	})
+	sort.Slice(list, func(i, j int) bool { return list[i].Id > list[j].Id })
+	return list, err
+}
+
+func (r *memoryTokens) ListSessionlessAccessTokensForUpdate(ctx context.Context, userId string) (list []*user_db.AccessToken, err error) {
+	list = make([]*user_db.AccessToken, 0)
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.accessTokens {
+			if t.accessTokens[i].UserId == userId && t.accessTokens[i].SessionId == "" {
+				v := t.accessTokens[i]
+				list = append(list, &v)
+			}
+		}
+		return nil
+	})
+	sort.Slice(list, func(i, j int) bool { return list[i].Id > list[j].Id })
+	return list, err
+}
+
+func (r *memoryTokens) GetRefreshToken(ctx context.Context, refreshToken string) (e *user_db.RefreshToken, err error) {
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.refreshTokens {
+			if t.refreshTokens[i].RefreshToken == refreshToken {
+				v := t.refreshTokens[i]
+				e = &v
+				break
+			}
+		}
+		return nil
+	})
+	return e, err
+}
+
+func (r *memoryTokens) GetRefreshTokenForUpdate(ctx context.Context, refreshToken string) (*user_db.RefreshToken, error) {
+	return r.GetRefreshToken(ctx, refreshToken)
+}
+
+func (r *memoryTokens) ListRefreshTokensByUserId(ctx context.Context, userId string) (list []*user_db.RefreshToken, err error) {
+	list = make([]*user_db.RefreshToken, 0)
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.refreshTokens {
+			if t.refreshTokens[i].UserId == userId {
+				v := t.refreshTokens[i]
+				list = append(list, &v)
+			}
+		}
+		return nil
+	})
+	sort.Slice(list, func(i, j int) bool { return list[i].Id > list[j].Id })
+	return list, err
+}
+
+func (r *memoryTokens) ListRefreshTokensBySessionId(ctx context.Context, sessionId string) (list []*user_db.RefreshToken, err error) {
+	list = make([]*user_db.RefreshToken, 0)
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.refreshTokens {
+			if t.refreshTokens[i].SessionId == sessionId {
+				v := t.refreshTokens[i]
+				list = append(list, &v)
+			}
+		}
+		return nil
+	})
+	sort.Slice(list, func(i, j int) bool { return list[i].Id > list[j].Id })
+	return list, err
+}
+
+func (r *memoryTokens) ListRefreshTokensBySessionIdForUpdate(ctx context.Context, sessionId string) ([]*user_db.RefreshToken, error) {
+	return r.ListRefreshTokensBySessionId(ctx, sessionId)
+}
+
+func (r *memoryTokens) ListRefreshTokens(ctx context.Context, afterId uint64, limit int64) (list []*user_db.RefreshToken, err error) {
+	list = make([]*user_db.RefreshToken, 0)
+	err = r.do(func(t *memoryTables) error {
+		for i := range t.refreshTokens {
+			if t.refreshTokens[i].Id > afterId {
+				v := t.refreshTokens[i]
+				list = append(list, &v)
+			}
+		}
+		return nil
+	})
+	sort.Slice(list, func(i, j int) bool { return list[i].Id < list[j].Id })
+	if int64(len(list)) > limit {
+		list = list[:limit]
+	}
+	return list, err
+}
+
+func (r *memoryTokens) InsertRefreshToken(ctx context.Context, e *user_db.RefreshToken) error {
+	return r.do(func(t *memoryTables) error {
+		for _, v := range t.refreshTokens {
+			if v.RefreshToken == e.RefreshToken {
+				return ErrDuplicate
+			}
+		}
+		e.Id = t.nextId(user_db.REFRESH_TOKEN_TABLE_NAME)
+		e.CreateTime = time.Now()
+		e.UpdateTime = e.CreateTime
+		t.refreshTokens = append(t.refreshTokens, *e)
+		return nil
+	})
+}
+
+func (r *memoryTokens) UpdateRefreshToken(ctx context.Context, e *user_db.RefreshToken) error {
+	return r.do(func(t *memoryTables) error {
+		for _, v := range t.refreshTokens {
+			if v.Id != e.Id && v.RefreshToken == e.RefreshToken {
+				return ErrDuplicate
+			}
+		}
+		for i := range t.refreshTokens {
+			if t.refreshTokens[i].Id == e.Id {
+				e.CreateTime = t.refreshTokens[i].CreateTime
+				e.UpdateTime = time.Now()
+				t.refreshTokens[i] = *e
+			}
+		}
+		return nil
+	})
+}
+
+type memoryOperations struct{ *memoryView }
+
+func (r *memoryOperations) Insert(ctx context.Context, e *user_db.UserOperation) error {
+	return r.do(func(t *memoryTables) error {
+		e.Id = t.nextId(user_db.USER_OPERATION_TABLE_NAME)
+		e.CreateTime = time.Now()
+		t.userOperations = append(t.userOperations, *e)
+		return nil
+	})
+}
+
+func (r *memoryOperations) ListByUserId(ctx context.Context, userId string, offset int64, limit int64) (list []*user_db.UserOperation, err error) {
+	list = make([]*user_db.UserOperation, 0)
+	err = r.do(func(t *memoryTables) error {
+		for i := len(t.userOperations) - 1; i >= 0; i-- {
+			if t.userOperations[i].UserId != userId {
+				continue
+			}
+			if offset > 0 {
+				offset--
+				continue
+			}
+			if int64(len(list)) >= limit {
+				break
+			}
+			v := t.userOperations[i]
+			list = append(list, &v)
+		}
+		return nil
+	})
+	return list, err
+}
+
+type memoryExpiry struct{ *memoryView }
+
+// DeleteExpired ignores oauth_state, which MemoryStorage does not keep.
+func (r *memoryExpiry) DeleteExpired(ctx context.Context, table string, before time.Time, limit int64) (n int64, err error) {
+	err = r.do(func(t *memoryTables) error {
+		switch table {
+		case user_db.LOGIN_SMS_CODE_TABLE_NAME:
+			n = deleteExpired(&t.loginSmsCodes, func(e *user_db.LoginSmsCode) time.Time { return e.UpdateTime }, before, limit)
+		case user_db.OAUTH_AUTHORIZATION_CODE_TABLE_NAME:
+			n = deleteExpired(&t.oauthCodes, func(e *user_db.OauthAuthorizationCode) time.Time { return e.UpdateTime }, before, limit)
+		case user_db.WEBAUTHN_SESSION_TABLE_NAME:
+			n = deleteExpired(&t.webAuthnSess, func(e *user_db.WebauthnSession) time.Time { return e.UpdateTime }, before, limit)
+		case user_db.ACCESS_TOKEN_TABLE_NAME:
+			n = deleteExpired(&t.accessTokens, func(e *user_db.AccessToken) time.Time { return e.UpdateTime }, before, limit)
+		case user_db.REFRESH_TOKEN_TABLE_NAME:
+			n = deleteExpired(&t.refreshTokens, func(e *user_db.RefreshToken) time.Time { return e.UpdateTime }, before, limit)
+		}
+		return nil
+	})
+	return n, err
+}
+
+// deleteExpired removes from rows at most limit rows whose updateTime is
+// before the given time, keeping the order of the rest, and returns how many
+// it removed.
+func deleteExpired[T any](rows *[]T, updateTime func(e *T) time.Time, before time.Time, limit int64) (n int64) {
+	kept := (*rows)[:0]
+	for i := range *rows {
+		if n < limit && updateTime(&(*rows)[i]).Before(before) {
+			n++
+			continue
+		}
+		kept = append(kept, (*rows)[i])
+	}
+	*rows = kept
+
+	return n
+}
+
+// WithLock always runs fn, a MemoryStorage is never shared between processes.
+func (r *memoryExpiry) WithLock(ctx context.Context, name string, fn func() error) (bool, error) {
+	return true, fn()
+}
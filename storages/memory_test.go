@@ -0,0 +1,512 @@
+package storages
+
+import (
+	"context"
+	"errors"
+	"github.com/NeuronUser/user/storages/user_db"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryUniqueKeys(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		first  func(s Storage) error
+		second func(s Storage) error
+		want   error
+	}{
+		{
+			name:   "user id",
+			first:  func(s Storage) error { return s.Users().Insert(ctx, &user_db.User{UserId: "u1", UserName: "alice"}) },
+			second: func(s Storage) error { return s.Users().Insert(ctx, &user_db.User{UserId: "u1", UserName: "bob"}) },
+			want:   ErrDuplicate,
+		},
+		{
+			name:   "user name ignores case",
+			first:  func(s Storage) error { return s.Users().Insert(ctx, &user_db.User{UserId: "u1", UserName: "alice"}) },
+			second: func(s Storage) error { return s.Users().Insert(ctx, &user_db.User{UserId: "u2", UserName: "ALICE"}) },
+			want:   ErrDuplicate,
+		},
+		{
+			name:  "rename to a taken name",
+			first: func(s Storage) error { return s.Users().Insert(ctx, &user_db.User{UserId: "u1", UserName: "alice"}) },
+			second: func(s Storage) error {
+				e := &user_db.User{UserId: "u2", UserName: "bob"}
+				if err := s.Users().Insert(ctx, e); err != nil {
+					return err
+				}
+				e.UserName = "Alice"
+				return s.Users().Update(ctx, e)
+			},
+			want: ErrDuplicate,
+		},
+		{
+			name: "phone number",
+			first: func(s Storage) error {
+				return s.PhoneAccounts().Insert(ctx, &user_db.PhoneAccount{UserId: "u1", PhoneNumber: "13800000000"})
+			},
+			second: func(s Storage) error {
+				return s.PhoneAccounts().Insert(ctx, &user_db.PhoneAccount{UserId: "u2", PhoneNumber: "13800000000"})
+			},
+			want: ErrDuplicate,
+		},
+		{
+			name: "one phone number per user",
+			first: func(s Storage) error {
+				return s.PhoneAccounts().Insert(ctx, &user_db.PhoneAccount{UserId: "u1", PhoneNumber: "13800000000"})
+			},
+			second: func(s Storage) error {
+				return s.PhoneAccounts().Insert(ctx, &user_db.PhoneAccount{UserId: "u1", PhoneNumber: "13900000000"})
+			},
+			want: ErrDuplicate,
+		},
+		{
+			name: "oauth open id",
+			first: func(s Storage) error {
+				return s.OauthAccounts().Insert(ctx, &user_db.OauthAccount{UserId: "u1", OauthProvider: "github", OauthOpenId: "42"})
+			},
+			second: func(s Storage) error {
+				return s.OauthAccounts().Insert(ctx, &user_db.OauthAccount{UserId: "u2", OauthProvider: "github", OauthOpenId: "42"})
+			},
+			want: ErrDuplicate,
+		},
+		{
+			name: "same open id at another provider",
+			first: func(s Storage) error {
+				return s.OauthAccounts().Insert(ctx, &user_db.OauthAccount{UserId: "u1", OauthProvider: "github", OauthOpenId: "42"})
+			},
+			second: func(s Storage) error {
+				return s.OauthAccounts().Insert(ctx, &user_db.OauthAccount{UserId: "u2", OauthProvider: "gitlab", OauthOpenId: "42"})
+			},
+		},
+		{
+			name: "attribute key",
+			first: func(s Storage) error {
+				return s.Attributes().Insert(ctx, &user_db.UserAttribute{UserId: "u1", Namespace: "game", AttrKey: "level", AttrValue: "1"})
+			},
+			second: func(s Storage) error {
+				return s.Attributes().Insert(ctx, &user_db.UserAttribute{UserId: "u1", Namespace: "game", AttrKey: "level", AttrValue: "2"})
+			},
+			want: ErrDuplicate,
+		},
+		{
+			name: "attribute keys are case-sensitive",
+			first: func(s Storage) error {
+				return s.Attributes().Insert(ctx, &user_db.UserAttribute{UserId: "u1", Namespace: "game", AttrKey: "level", AttrValue: "1"})
+			},
+			second: func(s Storage) error {
+				return s.Attributes().Insert(ctx, &user_db.UserAttribute{UserId: "u1", Namespace: "game", AttrKey: "Level", AttrValue: "2"})
+			},
+		},
+		{
+			name: "same attribute key in another namespace",
+			first: func(s Storage) error {
+				return s.Attributes().Insert(ctx, &user_db.UserAttribute{UserId: "u1", Namespace: "game", AttrKey: "level", AttrValue: "1"})
+			},
+			second: func(s Storage) error {
+				return s.Attributes().Insert(ctx, &user_db.UserAttribute{UserId: "u1", Namespace: "shop", AttrKey: "level", AttrValue: "2"})
+			},
+		},
+		{
+			name: "refresh token",
+			first: func(s Storage) error {
+				return s.Tokens().InsertRefreshToken(ctx, &user_db.RefreshToken{UserId: "u1", RefreshToken: "t1", SessionId: "s1"})
+			},
+			second: func(s Storage) error {
+				return s.Tokens().InsertRefreshToken(ctx, &user_db.RefreshToken{UserId: "u2", RefreshToken: "t1", SessionId: "s2"})
+			},
+			want: ErrDuplicate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewMemoryStorage()
+			if err := tt.first(s); err != nil {
+				t.Fatalf("first: %v", err)
+			}
+			if err := tt.second(s); err != tt.want {
+				t.Fatalf("second: got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryInsertDoesNotKeepCallerId(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+
+	e := &user_db.User{UserId: "u1", UserName: "alice"}
+	if err := s.Users().Insert(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	// reusing the entity must not make the second insert look like an
+	// update of the first row
+	e.UserId, e.UserName = "u2", "bob"
+	if err := s.Users().Insert(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Id != 2 {
+		t.Fatalf("Id = %d, want 2", e.Id)
+	}
+}
+
+func TestMemoryTransactionRollback(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+	if err := s.Users().Insert(ctx, &user_db.User{UserId: "u1", UserName: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	failed := errors.New("failed")
+	err := s.Transaction(ctx, func(tx Storage) error {
+		e, err := tx.Users().GetByUserIdForUpdate(ctx, "u1")
+		if err != nil {
+			return err
+		}
+		e.UserName = "bob"
+		if err = tx.Users().Update(ctx, e); err != nil {
+			return err
+		}
+		if err = tx.Users().Insert(ctx, &user_db.User{UserId: "u2", UserName: "carol"}); err != nil {
+			return err
+		}
+
+		// the transaction sees its own writes
+		e, err = tx.Users().GetByUserName(ctx, "bob")
+		if err != nil || e == nil {
+			t.Errorf("GetByUserName in tx = %v, %v", e, err)
+		}
+
+		return failed
+	})
+	if err != failed {
+		t.Fatalf("Transaction = %v, want %v", err, failed)
+	}
+
+	e, err := s.Users().GetByUserId(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.UserName != "alice" {
+		t.Errorf("UserName = %q after rollback, want alice", e.UserName)
+	}
+	if e, _ = s.Users().GetByUserId(ctx, "u2"); e != nil {
+		t.Errorf("insert kept after rollback")
+	}
+
+	// ids handed out by the rolled back transaction are reused, which is
+	// fine since nothing refers to them
+	e = &user_db.User{UserId: "u3", UserName: "dave"}
+	if err = s.Users().Insert(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Id != 2 {
+		t.Errorf("Id = %d, want 2", e.Id)
+	}
+}
+
+func TestMemoryTransactionCommit(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+
+	err := s.Transaction(ctx, func(tx Storage) error {
+		if err := tx.Users().Insert(ctx, &user_db.User{UserId: "u1", UserName: "alice"}); err != nil {
+			return err
+		}
+		// a nested transaction that succeeds commits with the outer one
+		return tx.Transaction(ctx, func(tx Storage) error {
+			return tx.PhoneAccounts().Insert(ctx, &user_db.PhoneAccount{UserId: "u1", PhoneNumber: "13800000000"})
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if e, _ := s.Users().GetByUserId(ctx, "u1"); e == nil {
+		t.Errorf("user missing after commit")
+	}
+	if e, _ := s.PhoneAccounts().GetByUserId(ctx, "u1"); e == nil {
+		t.Errorf("phone account missing after commit")
+	}
+}
+
+func TestMemoryNestedTransactionRollback(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+
+	err := s.Transaction(ctx, func(tx Storage) error {
+		if err := tx.Users().Insert(ctx, &user_db.User{UserId: "u1", UserName: "alice"}); err != nil {
+			return err
+		}
+
+		err := tx.Transaction(ctx, func(tx Storage) error {
+			if err := tx.PhoneAccounts().Insert(ctx, &user_db.PhoneAccount{UserId: "u1", PhoneNumber: "13800000000"}); err != nil {
+				return err
+			}
+			return tx.Users().Insert(ctx, &user_db.User{UserId: "u2", UserName: "Alice"})
+		})
+		if err != ErrDuplicate {
+			t.Errorf("nested err = %v, want ErrDuplicate", err)
+		}
+
+		// only the savepoint is undone
+		return tx.Users().Insert(ctx, &user_db.User{UserId: "u2", UserName: "bob"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, userId := range []string{"u1", "u2"} {
+		if e, _ := s.Users().GetByUserId(ctx, userId); e == nil {
+			t.Errorf("user %s missing after commit", userId)
+		}
+	}
+	if e, _ := s.PhoneAccounts().GetByUserId(ctx, "u1"); e != nil {
+		t.Errorf("phone account of the failed savepoint was committed")
+	}
+}
+
+func TestMemoryDuplicateInTransaction(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+	if err := s.Users().Insert(ctx, &user_db.User{UserId: "u1", UserName: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := s.Transaction(ctx, func(tx Storage) error {
+		return tx.Users().Insert(ctx, &user_db.User{UserId: "u2", UserName: "Alice"})
+	})
+	if err != ErrDuplicate {
+		t.Fatalf("Transaction = %v, want ErrDuplicate", err)
+	}
+}
+
+func TestMemoryForUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		// setup inserts the row
+		setup func(s Storage) error
+		// increment reads the row for update and writes it back changed
+		increment func(tx Storage) error
+		// value reads the committed value
+		value func(s Storage) (string, error)
+	}{
+		{
+			name:  "user",
+			setup: func(s Storage) error { return s.Users().Insert(ctx, &user_db.User{UserId: "u1", UserName: "n"}) },
+			increment: func(tx Storage) error {
+				e, err := tx.Users().GetByUserIdForUpdate(ctx, "u1")
+				if err != nil {
+					return err
+				}
+				e.UserName += "n"
+				return tx.Users().Update(ctx, e)
+			},
+			value: func(s Storage) (string, error) {
+				e, err := s.Users().GetByUserId(ctx, "u1")
+				if err != nil {
+					return "", err
+				}
+				return e.UserName, nil
+			},
+		},
+		{
+			name: "password account",
+			setup: func(s Storage) error {
+				return s.PasswordAccounts().Insert(ctx, &user_db.PasswordAccount{UserId: "u1", PasswordHash: "n"})
+			},
+			increment: func(tx Storage) error {
+				e, err := tx.PasswordAccounts().GetByUserIdForUpdate(ctx, "u1")
+				if err != nil {
+					return err
+				}
+				e.PasswordHash += "n"
+				return tx.PasswordAccounts().Update(ctx, e)
+			},
+			value: func(s Storage) (string, error) {
+				e, err := s.PasswordAccounts().GetByUserId(ctx, "u1")
+				if err != nil {
+					return "", err
+				}
+				return e.PasswordHash, nil
+			},
+		},
+		{
+			name: "attributes",
+			setup: func(s Storage) error {
+				return s.Attributes().Insert(ctx, &user_db.UserAttribute{UserId: "u1", Namespace: "game", AttrKey: "k", AttrValue: "n"})
+			},
+			increment: func(tx Storage) error {
+				list, err := tx.Attributes().ListByNamespaceForUpdate(ctx, "u1", "game")
+				if err != nil {
+					return err
+				}
+				list[0].AttrValue += "n"
+				return tx.Attributes().Update(ctx, list[0])
+			},
+			value: func(s Storage) (string, error) {
+				list, err := s.Attributes().ListByUserId(ctx, "u1")
+				if err != nil {
+					return "", err
+				}
+				return list[0].AttrValue, nil
+			},
+		},
+		{
+			name: "refresh token",
+			setup: func(s Storage) error {
+				return s.Tokens().InsertRefreshToken(ctx, &user_db.RefreshToken{UserId: "u1", RefreshToken: "t1", SessionId: "s1", Scope: "n"})
+			},
+			increment: func(tx Storage) error {
+				e, err := tx.Tokens().GetRefreshTokenForUpdate(ctx, "t1")
+				if err != nil {
+					return err
+				}
+				e.Scope += "n"
+				return tx.Tokens().UpdateRefreshToken(ctx, e)
+			},
+			value: func(s Storage) (string, error) {
+				e, err := s.Tokens().GetRefreshToken(ctx, "t1")
+				if err != nil {
+					return "", err
+				}
+				return e.Scope, nil
+			},
+		},
+		{
+			name: "session refresh tokens",
+			setup: func(s Storage) error {
+				return s.Tokens().InsertRefreshToken(ctx, &user_db.RefreshToken{UserId: "u1", RefreshToken: "t1", SessionId: "s1", Scope: "n"})
+			},
+			increment: func(tx Storage) error {
+				list, err := tx.Tokens().ListRefreshTokensBySessionIdForUpdate(ctx, "s1")
+				if err != nil {
+					return err
+				}
+				list[0].Scope += "n"
+				return tx.Tokens().UpdateRefreshToken(ctx, list[0])
+			},
+			value: func(s Storage) (string, error) {
+				e, err := s.Tokens().GetRefreshToken(ctx, "t1")
+				if err != nil {
+					return "", err
+				}
+				return e.Scope, nil
+			},
+		},
+	}
+
+	const workers = 8
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewMemoryStorage()
+			if err := tt.setup(s); err != nil {
+				t.Fatal(err)
+			}
+
+			// concurrent read-modify-write transactions must not lose
+			// updates
+			wg := sync.WaitGroup{}
+			errs := make(chan error, workers)
+			for i := 0; i < workers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs <- s.Transaction(ctx, tt.increment)
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			v, err := tt.value(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := strings.Repeat("n", workers+1); v != want {
+				t.Errorf("value = %q, want %q", v, want)
+			}
+		})
+	}
+}
+
+func TestMemoryTransactionBlocksOtherWriters(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+
+	inTx := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Transaction(ctx, func(tx Storage) error {
+			if err := tx.Users().Insert(ctx, &user_db.User{UserId: "u1", UserName: "alice"}); err != nil {
+				return err
+			}
+			close(inTx)
+			<-release
+			return nil
+		})
+	}()
+	<-inTx
+
+	inserted := make(chan error, 1)
+	go func() {
+		inserted <- s.Users().Insert(ctx, &user_db.User{UserId: "u2", UserName: "alice"})
+	}()
+
+	select {
+	case err := <-inserted:
+		t.Fatalf("insert finished while the transaction was open: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if err := <-inserted; err != ErrDuplicate {
+		t.Fatalf("insert after commit = %v, want ErrDuplicate", err)
+	}
+}
+
+func TestMemoryStorageInsideTransactionPanics(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+
+	for _, fn := range []func(tx Storage) error{
+		func(tx Storage) error {
+			_, err := s.Users().GetByUserId(ctx, "u1")
+			return err
+		},
+		func(tx Storage) error {
+			return s.Transaction(ctx, func(Storage) error { return nil })
+		},
+	} {
+		func() {
+			defer func() {
+				r := recover()
+				if msg, _ := r.(string); !strings.Contains(msg, "use the callback's tx") {
+					t.Errorf("recover() = %v, want the MemoryStorage misuse panic", r)
+				}
+			}()
+			_ = s.Transaction(ctx, fn)
+		}()
+	}
+
+	// the panics unwound the transactions and released the lock
+	if err := s.Users().Insert(ctx, &user_db.User{UserId: "u1", UserName: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,22 @@
+package storages
+
+import (
+	"github.com/NeuronUser/user/storages/user_db"
+	"os"
+)
+
+// NewStorageFromEnv selects the backend from the DB env: "memory://" gives an
+// empty MemoryStorage, "sqlite://path" a SQLite file and anything else is a
+// MySQL address for user_db.
+func NewStorageFromEnv() (Storage, error) {
+	if os.Getenv("DB") == "memory://" {
+		return NewMemoryStorage(), nil
+	}
+
+	db, err := user_db.NewDB()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDaoStorage(db), nil
+}
@@ -0,0 +1,183 @@
+package storages
+
+import (
+	"context"
+	"errors"
+	"github.com/NeuronUser/user/storages/user_db"
+	"time"
+)
+
+// ErrDuplicate is returned by Insert/Update when a unique key would be violated.
+var ErrDuplicate = errors.New("storages: duplicate entry")
+
+type UserRepository interface {
+	GetByUserId(ctx context.Context, userId string) (*user_db.User, error)
+	GetByUserIdForUpdate(ctx context.Context, userId string) (*user_db.User, error)
+	GetByUserName(ctx context.Context, userName string) (*user_db.User, error)
+	Insert(ctx context.Context, e *user_db.User) error
+	Update(ctx context.Context, e *user_db.User) error
+}
+
+// UserNameHistoryRepository lists names newest first. Names are compared
+// case-insensitively, like udx_user_name.
+type UserNameHistoryRepository interface {
+	ListByUserId(ctx context.Context, userId string, limit int64) ([]*user_db.UserNameHistory, error)
+	ListByUserName(ctx context.Context, userName string) ([]*user_db.UserNameHistory, error)
+	Insert(ctx context.Context, e *user_db.UserNameHistory) error
+}
+
+type PhoneAccountRepository interface {
+	GetByPhoneNumber(ctx context.Context, phoneNumber string) (*user_db.PhoneAccount, error)
+	GetByUserId(ctx context.Context, userId string) (*user_db.PhoneAccount, error)
+	Insert(ctx context.Context, e *user_db.PhoneAccount) error
+	Update(ctx context.Context, e *user_db.PhoneAccount) error
+}
+
+type PasswordAccountRepository interface {
+	GetByUserId(ctx context.Context, userId string) (*user_db.PasswordAccount, error)
+	GetByUserIdForUpdate(ctx context.Context, userId string) (*user_db.PasswordAccount, error)
+	Insert(ctx context.Context, e *user_db.PasswordAccount) error
+	Update(ctx context.Context, e *user_db.PasswordAccount) error
+}
+
+type TotpAccountRepository interface {
+	GetByUserId(ctx context.Context, userId string) (*user_db.TotpAccount, error)
+	GetByUserIdForUpdate(ctx context.Context, userId string) (*user_db.TotpAccount, error)
+	Insert(ctx context.Context, e *user_db.TotpAccount) error
+	Update(ctx context.Context, e *user_db.TotpAccount) error
+	Delete(ctx context.Context, e *user_db.TotpAccount) error
+}
+
+type RecoveryCodeRepository interface {
+	ListByUserId(ctx context.Context, userId string) ([]*user_db.MfaRecoveryCode, error)
+	Insert(ctx context.Context, e *user_db.MfaRecoveryCode) error
+	Update(ctx context.Context, e *user_db.MfaRecoveryCode) error
+	DeleteByUserId(ctx context.Context, userId string) error
+}
+
+type WebAuthnCredentialRepository interface {
+	ListByUserId(ctx context.Context, userId string) ([]*user_db.WebauthnCredential, error)
+	GetByCredentialIdForUpdate(ctx context.Context, credentialId string) (*user_db.WebauthnCredential, error)
+	Insert(ctx context.Context, e *user_db.WebauthnCredential) error
+	Update(ctx context.Context, e *user_db.WebauthnCredential) error
+}
+
+type WebAuthnSessionRepository interface {
+	GetBySessionIdForUpdate(ctx context.Context, sessionId string) (*user_db.WebauthnSession, error)
+	Insert(ctx context.Context, e *user_db.WebauthnSession) error
+	Update(ctx context.Context, e *user_db.WebauthnSession) error
+}
+
+// LoginSmsCodeRepository lists codes newest first.
+type LoginSmsCodeRepository interface {
+	GetLatestByPhoneNumberForUpdate(ctx context.Context, phoneNumber string) (*user_db.LoginSmsCode, error)
+	ListByPhoneNumber(ctx context.Context, phoneNumber string, limit int64) ([]*user_db.LoginSmsCode, error)
+	ListByClientIp(ctx context.Context, clientIp string, limit int64) ([]*user_db.LoginSmsCode, error)
+	Insert(ctx context.Context, e *user_db.LoginSmsCode) error
+	Update(ctx context.Context, e *user_db.LoginSmsCode) error
+}
+
+type OauthAccountRepository interface {
+	GetByOpenId(ctx context.Context, provider string, openId string) (*user_db.OauthAccount, error)
+	GetByOpenIdForUpdate(ctx context.Context, provider string, openId string) (*user_db.OauthAccount, error)
+	ListByUserId(ctx context.Context, userId string) ([]*user_db.OauthAccount, error)
+	// List pages through all accounts in id order.
+	List(ctx context.Context, afterId uint64, limit int64) ([]*user_db.OauthAccount, error)
+	Insert(ctx context.Context, e *user_db.OauthAccount) error
+	Update(ctx context.Context, e *user_db.OauthAccount) error
+}
+
+type OauthClientRepository interface {
+	GetByClientId(ctx context.Context, clientId string) (*user_db.OauthClient, error)
+	Insert(ctx context.Context, e *user_db.OauthClient) error
+	Update(ctx context.Context, e *user_db.OauthClient) error
+}
+
+type OauthCodeRepository interface {
+	GetByCodeForUpdate(ctx context.Context, code string) (*user_db.OauthAuthorizationCode, error)
+	Insert(ctx context.Context, e *user_db.OauthAuthorizationCode) error
+	Update(ctx context.Context, e *user_db.OauthAuthorizationCode) error
+}
+
+type TokenRepository interface {
+	GetAccessToken(ctx context.Context, accessToken string) (*user_db.AccessToken, error)
+	InsertAccessToken(ctx context.Context, e *user_db.AccessToken) error
+	UpdateAccessToken(ctx context.Context, e *user_db.AccessToken) error
+	ListAccessTokensBySessionIdForUpdate(ctx context.Context, sessionId string) ([]*user_db.AccessToken, error)
+	// ListSessionlessAccessTokensForUpdate lists the access tokens of userId
+	// that belong to no session, such as impersonation tokens.
+	ListSessionlessAccessTokensForUpdate(ctx context.Context, userId string) ([]*user_db.AccessToken, error)
+	GetRefreshToken(ctx context.Context, refreshToken string) (*user_db.RefreshToken, error)
+	GetRefreshTokenForUpdate(ctx context.Context, refreshToken string) (*user_db.RefreshToken, error)
+	ListRefreshTokensByUserId(ctx context.Context, userId string) ([]*user_db.RefreshToken, error)
+	// ListRefreshTokensBySessionId lists the tokens a session has been issued,
+	// newest first.
+	ListRefreshTokensBySessionId(ctx context.Context, sessionId string) ([]*user_db.RefreshToken, error)
+	ListRefreshTokensBySessionIdForUpdate(ctx context.Context, sessionId string) ([]*user_db.RefreshToken, error)
+	// ListRefreshTokens pages through all refresh tokens in id order.
+	ListRefreshTokens(ctx context.Context, afterId uint64, limit int64) ([]*user_db.RefreshToken, error)
+	InsertRefreshToken(ctx context.Context, e *user_db.RefreshToken) error
+	UpdateRefreshToken(ctx context.Context, e *user_db.RefreshToken) error
+}
+
+type RoleRepository interface {
+	ListByUserId(ctx context.Context, userId string) ([]*user_db.UserRole, error)
+	Insert(ctx context.Context, e *user_db.UserRole) error
+	Delete(ctx context.Context, e *user_db.UserRole) error
+}
+
+// AttributeRepository keeps app specific values of users, one per user,
+// namespace and key. Keys are compared case-sensitively.
+type AttributeRepository interface {
+	ListByUserId(ctx context.Context, userId string) ([]*user_db.UserAttribute, error)
+	ListByNamespaceForUpdate(ctx context.Context, userId string, namespace string) ([]*user_db.UserAttribute, error)
+	Insert(ctx context.Context, e *user_db.UserAttribute) error
+	Update(ctx context.Context, e *user_db.UserAttribute) error
+	Delete(ctx context.Context, e *user_db.UserAttribute) error
+}
+
+type OperationRepository interface {
+	Insert(ctx context.Context, e *user_db.UserOperation) error
+	ListByUserId(ctx context.Context, userId string, offset int64, limit int64) ([]*user_db.UserOperation, error)
+}
+
+// ExpiryRepository removes rows that have outlived their use. Tables are
+// named by the user_db *_TABLE_NAME constants.
+type ExpiryRepository interface {
+	DeleteExpired(ctx context.Context, table string, before time.Time, limit int64) (n int64, err error)
+	// WithLock runs fn unless another instance sharing the storage holds the
+	// lock name, in which case it returns false.
+	WithLock(ctx context.Context, name string, fn func() error) (locked bool, err error)
+}
+
+// Storage groups the repositories. Repositories returned by a Storage passed
+// to a Transaction callback all share that transaction; the transaction is
+// committed if the callback returns nil and rolled back otherwise. Calling
+// Transaction on such a Storage starts a savepoint instead, which rolls back
+// only what its own callback did.
+type Storage interface {
+	Users() UserRepository
+	UserNameHistory() UserNameHistoryRepository
+	PhoneAccounts() PhoneAccountRepository
+	PasswordAccounts() PasswordAccountRepository
+	TotpAccounts() TotpAccountRepository
+	RecoveryCodes() RecoveryCodeRepository
+	WebAuthnCredentials() WebAuthnCredentialRepository
+	WebAuthnSessions() WebAuthnSessionRepository
+	LoginSmsCodes() LoginSmsCodeRepository
+	OauthAccounts() OauthAccountRepository
+	OauthClients() OauthClientRepository
+	OauthCodes() OauthCodeRepository
+	Tokens() TokenRepository
+	Roles() RoleRepository
+	Attributes() AttributeRepository
+	Operations() OperationRepository
+	Expiry() ExpiryRepository
+	Transaction(ctx context.Context, fn func(tx Storage) error) error
+}
+
+// WithPrimary marks ctx so that reads made with it see the latest writes,
+// bypassing any read replica.
+func WithPrimary(ctx context.Context) context.Context {
+	return user_db.WithPrimary(ctx)
+}
@@ -0,0 +1,250 @@
+package user_db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/NeuronFramework/log"
+	"github.com/NeuronFramework/sql/wrap"
+	"github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DriverMysql  = "mysql"
+	DriverSqlite = "sqlite"
+)
+
+const sqliteScheme = "sqlite://"
+
+const (
+	defaultDatabase         = "neuron-user"
+	defaultCollation        = "utf8mb4_unicode_520_ci"
+	defaultDialTimeout      = time.Second * 5
+	defaultReadTimeout      = time.Second * 30
+	defaultWriteTimeout     = time.Second * 30
+	defaultMaxOpenConns     = 64
+	defaultMaxIdleConns     = 16
+	defaultConnMaxLifetime  = time.Minute * 5
+	defaultConnectTimeout   = time.Minute
+	defaultRetryInterval    = time.Millisecond * 500
+	defaultMaxRetryInterval = time.Second * 10
+)
+
+type Config struct {
+	Driver           string
+	Addr             string
+	ReplicaAddr      string
+	Database         string
+	Collation        string
+	TLS              string
+	DialTimeout      time.Duration
+	ReadTimeout      time.Duration
+	WriteTimeout     time.Duration
+	MaxOpenConns     int
+	MaxIdleConns     int
+	ConnMaxLifetime  time.Duration
+	ConnectTimeout   time.Duration
+	RetryInterval    time.Duration
+	MaxRetryInterval time.Duration
+}
+
+func NewConfig(addr string) *Config {
+	return &Config{
+		Driver:           DriverMysql,
+		Addr:             addr,
+		Database:         defaultDatabase,
+		Collation:        defaultCollation,
+		DialTimeout:      defaultDialTimeout,
+		ReadTimeout:      defaultReadTimeout,
+		WriteTimeout:     defaultWriteTimeout,
+		MaxOpenConns:     defaultMaxOpenConns,
+		MaxIdleConns:     defaultMaxIdleConns,
+		ConnMaxLifetime:  defaultConnMaxLifetime,
+		ConnectTimeout:   defaultConnectTimeout,
+		RetryInterval:    defaultRetryInterval,
+		MaxRetryInterval: defaultMaxRetryInterval,
+	}
+}
+
+// NewConfigFromEnv keeps DB as "user:password@tcp(host:port)" and reads
+// everything else, including an optional DB_REPLICA address of the same
+// form, from DB_* variables. A DB of the form "sqlite://path/to/file.db"
+// selects the SQLite backend instead.
+func NewConfigFromEnv() (c *Config, err error) {
+	addr := os.Getenv("DB")
+	if addr == "" {
+		return nil, fmt.Errorf("DB env nil")
+	}
+
+	if strings.HasPrefix(addr, sqliteScheme) {
+		c = NewConfig(strings.TrimPrefix(addr, sqliteScheme))
+		c.Driver = DriverSqlite
+		return c, nil
+	}
+
+	c = NewConfig(addr)
+	envString("DB_REPLICA", &c.ReplicaAddr)
+	envString("DB_NAME", &c.Database)
+	envString("DB_COLLATION", &c.Collation)
+	envString("DB_TLS", &c.TLS)
+
+	durations := []struct {
+		key string
+		v   *time.Duration
+	}{
+		{"DB_DIAL_TIMEOUT", &c.DialTimeout},
+		{"DB_READ_TIMEOUT", &c.ReadTimeout},
+		{"DB_WRITE_TIMEOUT", &c.WriteTimeout},
+		{"DB_CONN_MAX_LIFETIME", &c.ConnMaxLifetime},
+		{"DB_CONNECT_TIMEOUT", &c.ConnectTimeout},
+		{"DB_RETRY_INTERVAL", &c.RetryInterval},
+		{"DB_MAX_RETRY_INTERVAL", &c.MaxRetryInterval},
+	}
+	for _, d := range durations {
+		err = envDuration(d.key, d.v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = envInt("DB_MAX_OPEN_CONNS", &c.MaxOpenConns)
+	if err != nil {
+		return nil, err
+	}
+
+	err = envInt("DB_MAX_IDLE_CONNS", &c.MaxIdleConns)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *Config) DSN(addr string) (dsn string, err error) {
+	mysqlConfig, err := mysql.ParseDSN(addr + "/" + c.Database)
+	if err != nil {
+		return "", err
+	}
+
+	mysqlConfig.ParseTime = true
+	// the connection charset follows from the collation; a charset param
+	// would make the driver send SET NAMES <charset>, which resets the
+	// collation to the charset's default
+	mysqlConfig.Collation = c.Collation
+	mysqlConfig.TLSConfig = c.TLS
+	mysqlConfig.Timeout = c.DialTimeout
+	mysqlConfig.ReadTimeout = c.ReadTimeout
+	mysqlConfig.WriteTimeout = c.WriteTimeout
+	delete(mysqlConfig.Params, "charset")
+
+	return mysqlConfig.FormatDSN(), nil
+}
+
+// Connect opens a pool to addr and pings it until it answers or
+// ctx/ConnectTimeout expires, backing off exponentially between attempts.
+func (c *Config) Connect(ctx context.Context, addr string) (db *wrap.DB, err error) {
+	logger := log.TypedLogger(c)
+
+	dsn, err := c.DSN(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err = wrap.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(c.MaxOpenConns)
+	db.SetMaxIdleConns(c.MaxIdleConns)
+	db.SetConnMaxLifetime(c.ConnMaxLifetime)
+
+	if c.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.ConnectTimeout)
+		defer cancel()
+	}
+
+	interval := c.RetryInterval
+	if interval <= 0 {
+		interval = defaultRetryInterval
+	}
+	for attempt := 1; ; attempt++ {
+		err = db.Ping(ctx)
+		if err == nil {
+			return db, nil
+		}
+
+		logger.Warn("ping", zap.Int("attempt", attempt), zap.Duration("retryIn", interval), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			db.Close()
+			return nil, fmt.Errorf("connect %s: %v (last error: %v)", c.Database, ctx.Err(), err)
+		case <-time.After(interval):
+		}
+
+		interval *= 2
+		if c.MaxRetryInterval > 0 && interval > c.MaxRetryInterval {
+			interval = c.MaxRetryInterval
+		}
+	}
+}
+
+// openLocks opens the pool WithLock takes named locks on. Its connections
+// are closed as soon as they are released, so a lock can't outlive its
+// WithLock call on an idle connection.
+func (c *Config) openLocks() (db *sql.DB, err error) {
+	dsn, err := c.DSN(c.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err = sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxIdleConns(0)
+	db.SetConnMaxLifetime(c.ConnMaxLifetime)
+
+	return db, nil
+}
+
+func envString(key string, v *string) {
+	s := os.Getenv(key)
+	if s != "" {
+		*v = s
+	}
+}
+
+func envInt(key string, v *int) (err error) {
+	s := os.Getenv(key)
+	if s == "" {
+		return nil
+	}
+
+	*v, err = strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("%s env invalid: %v", key, err)
+	}
+
+	return nil
+}
+
+func envDuration(key string, v *time.Duration) (err error) {
+	s := os.Getenv(key)
+	if s == "" {
+		return nil
+	}
+
+	*v, err = time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("%s env invalid: %v", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package user_db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/NeuronFramework/sql/wrap"
+)
+
+// DB and its constructors used to be emitted by the generator, which only
+// connects to the DB env. They live here so that regenerating doesn't drop
+// the config and the replica; gen.sh strips the generated ones, and writes
+// daos, the DAO of every table, from the generated file.
+type DB struct {
+	wrap.DB
+	config  *Config
+	replica *wrap.DB
+	locks   *sql.DB
+	daos
+}
+
+func NewDB() (d *DB, err error) {
+	config, err := NewConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDBWithConfig(config)
+}
+
+func NewDBWithConfig(config *Config) (d *DB, err error) {
+	d = &DB{}
+
+	err = d.connect(context.Background(), config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.newDaos()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
@@ -0,0 +1,62 @@
+package user_db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const sqliteTimeFormat = "2006-01-02 15:04:05"
+
+// DeleteExpired deletes at most limit rows of table whose update_time is
+// before the given time, oldest first, and returns how many were deleted.
+// table must be one of the *_TABLE_NAME constants.
+func (d *DB) DeleteExpired(ctx context.Context, table string, before time.Time, limit int64) (n int64, err error) {
+	var result sql.Result
+	if d.Driver() == DriverSqlite {
+		// SQLite is usually built without DELETE ... LIMIT
+		result, err = d.DB.Exec(ctx,
+			"DELETE FROM "+table+" WHERE id IN (SELECT id FROM "+table+" WHERE update_time<? ORDER BY update_time LIMIT ?)",
+			before.UTC().Format(sqliteTimeFormat), limit)
+	} else {
+		result, err = d.DB.Exec(ctx,
+			"DELETE FROM `"+table+"` WHERE update_time<? ORDER BY update_time LIMIT ?",
+			before, limit)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// WithLock runs fn while holding the MySQL named lock name, so that only one
+// of several instances sharing the database does the work. It returns false
+// without calling fn if another session holds the lock. GET_LOCK belongs to
+// a connection, so the lock is taken on one reserved for the duration of fn,
+// outside any transaction. SQLite has no such locks and always runs fn.
+func (d *DB) WithLock(ctx context.Context, name string, fn func() error) (locked bool, err error) {
+	if d.Driver() == DriverSqlite {
+		return true, fn()
+	}
+
+	conn, err := d.locks.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	// the pool keeps no idle connections, so closing conn also ends the
+	// session and frees the lock should RELEASE_LOCK fail
+	defer conn.Close()
+
+	got := sql.NullInt64{}
+	err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?,0)", name).Scan(&got)
+	if err != nil {
+		return false, err
+	}
+	if got.Int64 != 1 {
+		return false, nil
+	}
+	defer conn.ExecContext(context.Background(), "DO RELEASE_LOCK(?)", name)
+
+	return true, fn()
+}
@@ -1,3 +1,60 @@
 #!/usr/bin/env bash
+set -euo pipefail
 
-mysql-orm-gen -sql_file=./neuron-user_db.sql -orm_file=./neuron-user_db-gen.go -package_name="user_db"
\ No newline at end of file
+orm_file=./neuron-user_db-gen.go
+daos_file=./neuron-user_db-daos-gen.go
+
+fail() {
+	echo "gen.sh: $*" >&2
+	exit 1
+}
+
+mysql-orm-gen -sql_file=./neuron-user_db.sql -orm_file=$orm_file -package_name="user_db"
+
+# DB and NewDB are defined in db.go, which adds the config and the replica.
+# The generator emits them last, after the DAOs; they are cut from there to
+# the end of the file, so check that nothing else would be cut with them.
+[ "$(grep -c '^type DB struct {$' $orm_file)" = 1 ] ||
+	fail "want one 'type DB struct {' in $orm_file"
+cut=$(sed -n '/^type DB struct {$/,$p' $orm_file | grep -E '^(func|type|var|const) ' |
+	grep -vE '^(type DB struct \{|func NewDB\(|func NewDBWithConfig\()' || true)
+[ -z "$cut" ] || fail "$orm_file declares after DB what gen.sh would cut: $cut"
+
+sed -i -e '/^type DB struct {$/,$d' $orm_file
+# and the blank line before it
+sed -i -e '${/^$/d}' $orm_file
+
+# the generated NewDB was the only user of os
+if ! grep -qE '(^|[^A-Za-z0-9_.])os\.' $orm_file; then
+	sed -i -e '/^\t"os"$/d' $orm_file
+fi
+
+# DB embeds daos, so every generated DAO is wired in
+daos=$(sed -nE 's/^type ([A-Za-z0-9]+)Dao struct \{$/\1/p' $orm_file)
+[ -n "$daos" ] || fail "no DAOs found in $orm_file"
+{
+	echo "// Code generated by gen.sh from $(basename $orm_file). DO NOT EDIT."
+	echo
+	echo "package user_db"
+	echo
+	echo "// daos holds the DAO of every table in neuron-user_db.sql."
+	echo "type daos struct {"
+	for v in $daos; do
+		echo "	$v *${v}Dao"
+	done
+	echo "}"
+	echo
+	echo "func (d *DB) newDaos() (err error) {"
+	for v in $daos; do
+		echo "	d.$v, err = New${v}Dao(d)"
+		echo "	if err != nil {"
+		echo "		return err"
+		echo "	}"
+		echo
+	done
+	echo "	return nil"
+	echo "}"
+} >$daos_file
+
+gofmt -w $daos_file
+go build . || fail "generated code doesn't build"
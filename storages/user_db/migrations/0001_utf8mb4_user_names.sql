@@ -0,0 +1,12 @@
+-- Convert the tables holding user-facing names to utf8mb4 so names imported
+-- from social providers (emoji, supplementary CJK) can be stored.
+--
+-- utf8mb4_unicode_520_ci is used instead of utf8mb4_unicode_ci because the
+-- latter weighs every supplementary character the same, which would make
+-- udx_user_name treat any two emoji names as duplicates.
+
+ALTER TABLE `user`
+  CONVERT TO CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_520_ci;
+
+ALTER TABLE `oauth_account`
+  CONVERT TO CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_520_ci;
@@ -0,0 +1,9 @@
+-- Track where each SMS code was requested from, how many times it has been
+-- guessed and whether it has been consumed, for send quotas and brute-force
+-- protection.
+
+ALTER TABLE `login_sms_code`
+  ADD COLUMN `client_ip` varchar(64) NOT NULL DEFAULT '' AFTER `sms_code`,
+  ADD COLUMN `verify_count` int(11) NOT NULL DEFAULT '0' AFTER `client_ip`,
+  ADD COLUMN `is_used` tinyint(1) NOT NULL DEFAULT '0' AFTER `verify_count`,
+  ADD KEY `idx_client_ip` (`client_ip`);
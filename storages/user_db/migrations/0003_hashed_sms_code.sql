@@ -0,0 +1,5 @@
+-- SMS codes are stored as "v1:" + hex HMAC-SHA256, which does not fit the
+-- old varchar(8). refresh_token.refresh_token is already varchar(128).
+
+ALTER TABLE `login_sms_code`
+  MODIFY COLUMN `sms_code` varchar(128) NOT NULL;
@@ -0,0 +1,15 @@
+-- Optional password credential. password_hash is a PHC-style argon2id string
+-- or an imported bcrypt hash; failed_count counts wrong passwords since the
+-- last successful login.
+
+CREATE TABLE `password_account` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` varchar(32) NOT NULL,
+  `password_hash` varchar(256) NOT NULL,
+  `failed_count` int(11) NOT NULL DEFAULT '0',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_user_id` (`user_id`),
+  KEY `idx_update` (`update_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
@@ -0,0 +1,29 @@
+-- TOTP second factor. totp_secret is encrypted with a key derived from
+-- SECRET_PEPPER; last_used_step stops a code from being replayed within its
+-- window. Recovery codes are stored as keyed hashes.
+
+CREATE TABLE `totp_account` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` varchar(32) NOT NULL,
+  `totp_secret` varchar(256) NOT NULL,
+  `is_enabled` tinyint(1) NOT NULL DEFAULT '0',
+  `last_used_step` bigint(20) NOT NULL DEFAULT '0',
+  `failed_count` int(11) NOT NULL DEFAULT '0',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_user_id` (`user_id`),
+  KEY `idx_update` (`update_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE `mfa_recovery_code` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` varchar(32) NOT NULL,
+  `code_hash` varchar(128) NOT NULL,
+  `is_used` tinyint(1) NOT NULL DEFAULT '0',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_user_id` (`user_id`),
+  KEY `idx_update` (`update_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
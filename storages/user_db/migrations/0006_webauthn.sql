@@ -0,0 +1,31 @@
+-- WebAuthn passkeys. webauthn_session holds the one-time challenge of a
+-- registration or login ceremony, like oauth_state; user_id is empty for a
+-- login, where the user is only known from the credential. credential_data
+-- is the JSON of the verified credential and is updated on every login to
+-- keep the signature counter.
+
+CREATE TABLE `webauthn_credential` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` varchar(32) NOT NULL,
+  `credential_id` varchar(255) NOT NULL,
+  `credential_data` text NOT NULL,
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_credential_id` (`credential_id`),
+  KEY `idx_user_id` (`user_id`),
+  KEY `idx_update` (`update_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE `webauthn_session` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `session_id` varchar(128) NOT NULL,
+  `user_id` varchar(32) NOT NULL,
+  `session_data` text NOT NULL,
+  `is_used` tinyint(1) NOT NULL DEFAULT '0',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_session_id` (`session_id`),
+  KEY `idx_update` (`update_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
@@ -0,0 +1,17 @@
+-- A session is one login: the refresh token it was issued and every token
+-- rotated from it share session_id. user_agent and client_ip are those of
+-- the latest refresh, login_time is when the session started. Existing rows
+-- become sessions of their own.
+
+ALTER TABLE `refresh_token`
+  ADD COLUMN `session_id` varchar(32) NOT NULL DEFAULT '' AFTER `refresh_token`,
+  ADD COLUMN `user_agent` varchar(256) NOT NULL DEFAULT '' AFTER `session_id`,
+  ADD COLUMN `client_ip` varchar(64) NOT NULL DEFAULT '' AFTER `user_agent`,
+  ADD COLUMN `login_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER `client_ip`,
+  ADD KEY `idx_session_id` (`session_id`);
+
+UPDATE `refresh_token` SET
+  `session_id` = LOWER(LPAD(HEX(`id`), 32, '0')),
+  `login_time` = `create_time`,
+  -- keep update_time, which the janitor expires sessions by
+  `update_time` = `update_time`;
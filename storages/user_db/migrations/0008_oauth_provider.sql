@@ -0,0 +1,43 @@
+-- OAuth2 / OpenID Connect provider. oauth_client holds registered client
+-- applications; client_secret is a keyed hash and empty for public clients.
+-- redirect_uris, grant_types and scope are space separated lists.
+-- oauth_authorization_code holds issued codes by keyed hash; session_id is
+-- the session the code was exchanged for, revoked if the code is replayed.
+-- Refresh tokens issued to a client record its client_id and scope.
+
+CREATE TABLE `oauth_authorization_code` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `code` varchar(128) NOT NULL,
+  `client_id` varchar(64) NOT NULL,
+  `user_id` varchar(32) NOT NULL,
+  `redirect_uri` varchar(1024) NOT NULL,
+  `scope` varchar(256) NOT NULL,
+  `nonce` varchar(256) NOT NULL,
+  `code_challenge` varchar(128) NOT NULL,
+  `session_id` varchar(32) NOT NULL DEFAULT '',
+  `is_used` tinyint(1) NOT NULL DEFAULT '0',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_code` (`code`),
+  KEY `idx_update` (`update_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE `oauth_client` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `client_id` varchar(64) NOT NULL,
+  `client_secret` varchar(128) NOT NULL,
+  `client_name` varchar(128) NOT NULL,
+  `redirect_uris` varchar(2048) NOT NULL,
+  `grant_types` varchar(256) NOT NULL,
+  `scope` varchar(256) NOT NULL,
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_client_id` (`client_id`),
+  KEY `idx_update` (`update_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+
+ALTER TABLE `refresh_token`
+  ADD COLUMN `client_id` varchar(64) NOT NULL DEFAULT '' AFTER `login_time`,
+  ADD COLUMN `scope` varchar(256) NOT NULL DEFAULT '' AFTER `client_id`;
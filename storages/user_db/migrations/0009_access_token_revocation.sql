@@ -0,0 +1,7 @@
+-- Access tokens record the session they were issued to, so introspection
+-- reports them inactive once the session is signed out, and can be revoked
+-- on their own.
+
+ALTER TABLE `access_token`
+  ADD COLUMN `session_id` varchar(32) NOT NULL DEFAULT '' AFTER `access_token`,
+  ADD COLUMN `is_revoked` tinyint(1) NOT NULL DEFAULT '0' AFTER `session_id`;
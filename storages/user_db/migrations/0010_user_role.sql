@@ -0,0 +1,12 @@
+-- Roles granted to users, e.g. admin. A user's roles decide the scopes put
+-- in their access tokens.
+
+CREATE TABLE `user_role` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` varchar(32) NOT NULL,
+  `role` varchar(32) NOT NULL,
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_user_role` (`user_id`,`role`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
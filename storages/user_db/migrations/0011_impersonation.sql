@@ -0,0 +1,7 @@
+-- Operations done by support staff impersonating a user record the staff
+-- member as actor_id, next to the user in user_id, and the stated reason.
+
+ALTER TABLE `user_operation`
+  ADD COLUMN `actor_id` varchar(32) NOT NULL DEFAULT '' AFTER `phone_number`,
+  ADD COLUMN `reason` varchar(256) NOT NULL DEFAULT '' AFTER `actor_id`,
+  ADD KEY `idx_actor_id` (`actor_id`);
@@ -0,0 +1,7 @@
+-- Provider avatars are copied into our blob store. mirror_source is the
+-- oauth_icon last copied and mirror_icon the URL of the copy, so a changed
+-- oauth_icon is noticed and copied again.
+
+ALTER TABLE `oauth_account`
+  ADD COLUMN `mirror_source` varchar(256) NOT NULL DEFAULT '' AFTER `oauth_icon`,
+  ADD COLUMN `mirror_icon` varchar(256) NOT NULL DEFAULT '' AFTER `mirror_source`;
@@ -0,0 +1,23 @@
+-- Typed profile fields on user, and user_attribute for app specific values.
+-- Attributes are grouped by namespace, which decides who may read and write
+-- them. birthday is YYYY-MM-DD, or empty when unset.
+
+ALTER TABLE `user`
+  ADD COLUMN `bio` varchar(256) NOT NULL DEFAULT '' AFTER `user_icon`,
+  ADD COLUMN `gender` varchar(16) NOT NULL DEFAULT '' AFTER `bio`,
+  ADD COLUMN `birthday` varchar(10) NOT NULL DEFAULT '' AFTER `gender`,
+  ADD COLUMN `locale` varchar(32) NOT NULL DEFAULT '' AFTER `birthday`,
+  ADD COLUMN `timezone` varchar(64) NOT NULL DEFAULT '' AFTER `locale`;
+
+CREATE TABLE `user_attribute` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` varchar(32) NOT NULL,
+  `namespace` varchar(32) NOT NULL,
+  `attr_key` varchar(64) NOT NULL,
+  `attr_value` varchar(1024) NOT NULL,
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_user_attribute` (`user_id`,`namespace`,`attr_key`),
+  KEY `idx_update` (`update_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
@@ -0,0 +1,5 @@
+-- Ending a session revokes the access tokens issued to it, which looks them
+-- up by session.
+
+ALTER TABLE `access_token`
+  ADD KEY `idx_session_id` (`session_id`);
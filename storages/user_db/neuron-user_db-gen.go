@@ -9,7 +9,6 @@ import (
 	"github.com/NeuronFramework/sql/wrap"
 	"github.com/go-sql-driver/mysql"
 	"go.uber.org/zap"
-	"os"
 	"strings"
 	"time"
 )
@@ -66,15 +65,19 @@ type ACCESS_TOKEN_FIELD string
 const ACCESS_TOKEN_FIELD_ID = ACCESS_TOKEN_FIELD("id")
 const ACCESS_TOKEN_FIELD_USER_ID = ACCESS_TOKEN_FIELD("user_id")
 const ACCESS_TOKEN_FIELD_ACCESS_TOKEN = ACCESS_TOKEN_FIELD("access_token")
+const ACCESS_TOKEN_FIELD_SESSION_ID = ACCESS_TOKEN_FIELD("session_id")
+const ACCESS_TOKEN_FIELD_IS_REVOKED = ACCESS_TOKEN_FIELD("is_revoked")
 const ACCESS_TOKEN_FIELD_CREATE_TIME = ACCESS_TOKEN_FIELD("create_time")
 const ACCESS_TOKEN_FIELD_UPDATE_TIME = ACCESS_TOKEN_FIELD("update_time")
 
-const ACCESS_TOKEN_ALL_FIELDS_STRING = "id,user_id,access_token,create_time,update_time"
+const ACCESS_TOKEN_ALL_FIELDS_STRING = "id,user_id,access_token,session_id,is_revoked,create_time,update_time"
 
 var ACCESS_TOKEN_ALL_FIELDS = []string{
 	"id",
 	"user_id",
 	"access_token",
+	"session_id",
+	"is_revoked",
 	"create_time",
 	"update_time",
 }
@@ -83,6 +86,8 @@ type AccessToken struct {
 	Id          uint64 //size=20
 	UserId      string //size=32
 	AccessToken string //size=1024
+	SessionId   string //size=32
+	IsRevoked   int32  //size=1
 	CreateTime  time.Time
 	UpdateTime  time.Time
 }
@@ -229,6 +234,42 @@ func (q *AccessTokenQuery) AccessToken_Greater(v string) *AccessTokenQuery {
 func (q *AccessTokenQuery) AccessToken_GreaterEqual(v string) *AccessTokenQuery {
 	return q.w("access_token>='" + fmt.Sprint(v) + "'")
 }
+func (q *AccessTokenQuery) SessionId_Equal(v string) *AccessTokenQuery {
+	return q.w("session_id='" + fmt.Sprint(v) + "'")
+}
+func (q *AccessTokenQuery) SessionId_NotEqual(v string) *AccessTokenQuery {
+	return q.w("session_id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *AccessTokenQuery) SessionId_Less(v string) *AccessTokenQuery {
+	return q.w("session_id<'" + fmt.Sprint(v) + "'")
+}
+func (q *AccessTokenQuery) SessionId_LessEqual(v string) *AccessTokenQuery {
+	return q.w("session_id<='" + fmt.Sprint(v) + "'")
+}
+func (q *AccessTokenQuery) SessionId_Greater(v string) *AccessTokenQuery {
+	return q.w("session_id>'" + fmt.Sprint(v) + "'")
+}
+func (q *AccessTokenQuery) SessionId_GreaterEqual(v string) *AccessTokenQuery {
+	return q.w("session_id>='" + fmt.Sprint(v) + "'")
+}
+func (q *AccessTokenQuery) IsRevoked_Equal(v int32) *AccessTokenQuery {
+	return q.w("is_revoked='" + fmt.Sprint(v) + "'")
+}
+func (q *AccessTokenQuery) IsRevoked_NotEqual(v int32) *AccessTokenQuery {
+	return q.w("is_revoked<>'" + fmt.Sprint(v) + "'")
+}
+func (q *AccessTokenQuery) IsRevoked_Less(v int32) *AccessTokenQuery {
+	return q.w("is_revoked<'" + fmt.Sprint(v) + "'")
+}
+func (q *AccessTokenQuery) IsRevoked_LessEqual(v int32) *AccessTokenQuery {
+	return q.w("is_revoked<='" + fmt.Sprint(v) + "'")
+}
+func (q *AccessTokenQuery) IsRevoked_Greater(v int32) *AccessTokenQuery {
+	return q.w("is_revoked>'" + fmt.Sprint(v) + "'")
+}
+func (q *AccessTokenQuery) IsRevoked_GreaterEqual(v int32) *AccessTokenQuery {
+	return q.w("is_revoked>='" + fmt.Sprint(v) + "'")
+}
 func (q *AccessTokenQuery) CreateTime_Equal(v time.Time) *AccessTokenQuery {
 	return q.w("create_time='" + fmt.Sprint(v) + "'")
 }
@@ -306,12 +347,12 @@ func (dao *AccessTokenDao) init() (err error) {
 }
 
 func (dao *AccessTokenDao) prepareInsertStmt() (err error) {
-	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO access_token (user_id,access_token) VALUES (?,?)")
+	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO access_token (user_id,access_token,session_id,is_revoked) VALUES (?,?,?,?)")
 	return err
 }
 
 func (dao *AccessTokenDao) prepareUpdateStmt() (err error) {
-	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE access_token SET user_id=?,access_token=? WHERE id=?")
+	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE access_token SET user_id=?,access_token=?,session_id=?,is_revoked=? WHERE id=?")
 	return err
 }
 
@@ -326,7 +367,7 @@ func (dao *AccessTokenDao) Insert(ctx context.Context, tx *wrap.Tx, e *AccessTok
 		stmt = tx.Stmt(ctx, stmt)
 	}
 
-	result, err := stmt.Exec(ctx, e.UserId, e.AccessToken)
+	result, err := stmt.Exec(ctx, e.UserId, e.AccessToken, e.SessionId, e.IsRevoked)
 	if err != nil {
 		return 0, err
 	}
@@ -345,7 +386,7 @@ func (dao *AccessTokenDao) Update(ctx context.Context, tx *wrap.Tx, e *AccessTok
 		stmt = tx.Stmt(ctx, stmt)
 	}
 
-	_, err = stmt.Exec(ctx, e.UserId, e.AccessToken, e.Id)
+	_, err = stmt.Exec(ctx, e.UserId, e.AccessToken, e.SessionId, e.IsRevoked, e.Id)
 	if err != nil {
 		return err
 	}
@@ -369,7 +410,7 @@ func (dao *AccessTokenDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (
 
 func (dao *AccessTokenDao) scanRow(row *wrap.Row) (*AccessToken, error) {
 	e := &AccessToken{}
-	err := row.Scan(&e.Id, &e.UserId, &e.AccessToken, &e.CreateTime, &e.UpdateTime)
+	err := row.Scan(&e.Id, &e.UserId, &e.AccessToken, &e.SessionId, &e.IsRevoked, &e.CreateTime, &e.UpdateTime)
 	if err != nil {
 		if err == wrap.ErrNoRows {
 			return nil, nil
@@ -385,7 +426,7 @@ func (dao *AccessTokenDao) scanRows(rows *wrap.Rows) (list []*AccessToken, err e
 	list = make([]*AccessToken, 0)
 	for rows.Next() {
 		e := AccessToken{}
-		err = rows.Scan(&e.Id, &e.UserId, &e.AccessToken, &e.CreateTime, &e.UpdateTime)
+		err = rows.Scan(&e.Id, &e.UserId, &e.AccessToken, &e.SessionId, &e.IsRevoked, &e.CreateTime, &e.UpdateTime)
 		if err != nil {
 			return nil, err
 		}
@@ -467,15 +508,21 @@ type LOGIN_SMS_CODE_FIELD string
 const LOGIN_SMS_CODE_FIELD_ID = LOGIN_SMS_CODE_FIELD("id")
 const LOGIN_SMS_CODE_FIELD_PHONE_NUMBER = LOGIN_SMS_CODE_FIELD("phone_number")
 const LOGIN_SMS_CODE_FIELD_SMS_CODE = LOGIN_SMS_CODE_FIELD("sms_code")
+const LOGIN_SMS_CODE_FIELD_CLIENT_IP = LOGIN_SMS_CODE_FIELD("client_ip")
+const LOGIN_SMS_CODE_FIELD_VERIFY_COUNT = LOGIN_SMS_CODE_FIELD("verify_count")
+const LOGIN_SMS_CODE_FIELD_IS_USED = LOGIN_SMS_CODE_FIELD("is_used")
 const LOGIN_SMS_CODE_FIELD_CREATE_TIME = LOGIN_SMS_CODE_FIELD("create_time")
 const LOGIN_SMS_CODE_FIELD_UPDATE_TIME = LOGIN_SMS_CODE_FIELD("update_time")
 
-const LOGIN_SMS_CODE_ALL_FIELDS_STRING = "id,phone_number,sms_code,create_time,update_time"
+const LOGIN_SMS_CODE_ALL_FIELDS_STRING = "id,phone_number,sms_code,client_ip,verify_count,is_used,create_time,update_time"
 
 var LOGIN_SMS_CODE_ALL_FIELDS = []string{
 	"id",
 	"phone_number",
 	"sms_code",
+	"client_ip",
+	"verify_count",
+	"is_used",
 	"create_time",
 	"update_time",
 }
@@ -483,7 +530,10 @@ var LOGIN_SMS_CODE_ALL_FIELDS = []string{
 type LoginSmsCode struct {
 	Id          uint64 //size=20
 	PhoneNumber string //size=32
-	SmsCode     string //size=8
+	SmsCode     string //size=128
+	ClientIp    string //size=64
+	VerifyCount int32  //size=11
+	IsUsed      int32  //size=1
 	CreateTime  time.Time
 	UpdateTime  time.Time
 }
@@ -632,6 +682,60 @@ func (q *LoginSmsCodeQuery) SmsCode_Greater(v string) *LoginSmsCodeQuery {
 func (q *LoginSmsCodeQuery) SmsCode_GreaterEqual(v string) *LoginSmsCodeQuery {
 	return q.w("sms_code>='" + fmt.Sprint(v) + "'")
 }
+func (q *LoginSmsCodeQuery) ClientIp_Equal(v string) *LoginSmsCodeQuery {
+	return q.w("client_ip='" + fmt.Sprint(v) + "'")
+}
+func (q *LoginSmsCodeQuery) ClientIp_NotEqual(v string) *LoginSmsCodeQuery {
+	return q.w("client_ip<>'" + fmt.Sprint(v) + "'")
+}
+func (q *LoginSmsCodeQuery) ClientIp_Less(v string) *LoginSmsCodeQuery {
+	return q.w("client_ip<'" + fmt.Sprint(v) + "'")
+}
+func (q *LoginSmsCodeQuery) ClientIp_LessEqual(v string) *LoginSmsCodeQuery {
+	return q.w("client_ip<='" + fmt.Sprint(v) + "'")
+}
+func (q *LoginSmsCodeQuery) ClientIp_Greater(v string) *LoginSmsCodeQuery {
+	return q.w("client_ip>'" + fmt.Sprint(v) + "'")
+}
+func (q *LoginSmsCodeQuery) ClientIp_GreaterEqual(v string) *LoginSmsCodeQuery {
+	return q.w("client_ip>='" + fmt.Sprint(v) + "'")
+}
+func (q *LoginSmsCodeQuery) VerifyCount_Equal(v int32) *LoginSmsCodeQuery {
+	return q.w("verify_count='" + fmt.Sprint(v) + "'")
+}
+func (q *LoginSmsCodeQuery) VerifyCount_NotEqual(v int32) *LoginSmsCodeQuery {
+	return q.w("verify_count<>'" + fmt.Sprint(v) + "'")
+}
+func (q *LoginSmsCodeQuery) VerifyCount_Less(v int32) *LoginSmsCodeQuery {
+	return q.w("verify_count<'" + fmt.Sprint(v) + "'")
+}
+func (q *LoginSmsCodeQuery) VerifyCount_LessEqual(v int32) *LoginSmsCodeQuery {
+	return q.w("verify_count<='" + fmt.Sprint(v) + "'")
+}
+func (q *LoginSmsCodeQuery) VerifyCount_Greater(v int32) *LoginSmsCodeQuery {
+	return q.w("verify_count>'" + fmt.Sprint(v) + "'")
+}
+func (q *LoginSmsCodeQuery) VerifyCount_GreaterEqual(v int32) *LoginSmsCodeQuery {
+	return q.w("verify_count>='" + fmt.Sprint(v) + "'")
+}
+func (q *LoginSmsCodeQuery) IsUsed_Equal(v int32) *LoginSmsCodeQuery {
+	return q.w("is_used='" + fmt.Sprint(v) + "'")
+}
+func (q *LoginSmsCodeQuery) IsUsed_NotEqual(v int32) *LoginSmsCodeQuery {
+	return q.w("is_used<>'" + fmt.Sprint(v) + "'")
+}
+func (q *LoginSmsCodeQuery) IsUsed_Less(v int32) *LoginSmsCodeQuery {
+	return q.w("is_used<'" + fmt.Sprint(v) + "'")
+}
+func (q *LoginSmsCodeQuery) IsUsed_LessEqual(v int32) *LoginSmsCodeQuery {
+	return q.w("is_used<='" + fmt.Sprint(v) + "'")
+}
+func (q *LoginSmsCodeQuery) IsUsed_Greater(v int32) *LoginSmsCodeQuery {
+	return q.w("is_used>'" + fmt.Sprint(v) + "'")
+}
+func (q *LoginSmsCodeQuery) IsUsed_GreaterEqual(v int32) *LoginSmsCodeQuery {
+	return q.w("is_used>='" + fmt.Sprint(v) + "'")
+}
 func (q *LoginSmsCodeQuery) CreateTime_Equal(v time.Time) *LoginSmsCodeQuery {
 	return q.w("create_time='" + fmt.Sprint(v) + "'")
 }
@@ -709,12 +813,12 @@ func (dao *LoginSmsCodeDao) init() (err error) {
 }
 
 func (dao *LoginSmsCodeDao) prepareInsertStmt() (err error) {
-	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO login_sms_code (phone_number,sms_code) VALUES (?,?)")
+	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO login_sms_code (phone_number,sms_code,client_ip,verify_count,is_used) VALUES (?,?,?,?,?)")
 	return err
 }
 
 func (dao *LoginSmsCodeDao) prepareUpdateStmt() (err error) {
-	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE login_sms_code SET phone_number=?,sms_code=? WHERE id=?")
+	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE login_sms_code SET phone_number=?,sms_code=?,client_ip=?,verify_count=?,is_used=? WHERE id=?")
 	return err
 }
 
@@ -729,7 +833,7 @@ func (dao *LoginSmsCodeDao) Insert(ctx context.Context, tx *wrap.Tx, e *LoginSms
 		stmt = tx.Stmt(ctx, stmt)
 	}
 
-	result, err := stmt.Exec(ctx, e.PhoneNumber, e.SmsCode)
+	result, err := stmt.Exec(ctx, e.PhoneNumber, e.SmsCode, e.ClientIp, e.VerifyCount, e.IsUsed)
 	if err != nil {
 		return 0, err
 	}
@@ -748,7 +852,7 @@ func (dao *LoginSmsCodeDao) Update(ctx context.Context, tx *wrap.Tx, e *LoginSms
 		stmt = tx.Stmt(ctx, stmt)
 	}
 
-	_, err = stmt.Exec(ctx, e.PhoneNumber, e.SmsCode, e.Id)
+	_, err = stmt.Exec(ctx, e.PhoneNumber, e.SmsCode, e.ClientIp, e.VerifyCount, e.IsUsed, e.Id)
 	if err != nil {
 		return err
 	}
@@ -772,7 +876,7 @@ func (dao *LoginSmsCodeDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64)
 
 func (dao *LoginSmsCodeDao) scanRow(row *wrap.Row) (*LoginSmsCode, error) {
 	e := &LoginSmsCode{}
-	err := row.Scan(&e.Id, &e.PhoneNumber, &e.SmsCode, &e.CreateTime, &e.UpdateTime)
+	err := row.Scan(&e.Id, &e.PhoneNumber, &e.SmsCode, &e.ClientIp, &e.VerifyCount, &e.IsUsed, &e.CreateTime, &e.UpdateTime)
 	if err != nil {
 		if err == wrap.ErrNoRows {
 			return nil, nil
@@ -788,7 +892,7 @@ func (dao *LoginSmsCodeDao) scanRows(rows *wrap.Rows) (list []*LoginSmsCode, err
 	list = make([]*LoginSmsCode, 0)
 	for rows.Next() {
 		e := LoginSmsCode{}
-		err = rows.Scan(&e.Id, &e.PhoneNumber, &e.SmsCode, &e.CreateTime, &e.UpdateTime)
+		err = rows.Scan(&e.Id, &e.PhoneNumber, &e.SmsCode, &e.ClientIp, &e.VerifyCount, &e.IsUsed, &e.CreateTime, &e.UpdateTime)
 		if err != nil {
 			return nil, err
 		}
@@ -863,82 +967,76 @@ func (dao *LoginSmsCodeDao) GetQuery() *LoginSmsCodeQuery {
 	return NewLoginSmsCodeQuery(dao)
 }
 
-const OAUTH_ACCOUNT_TABLE_NAME = "oauth_account"
+const MFA_RECOVERY_CODE_TABLE_NAME = "mfa_recovery_code"
 
-type OAUTH_ACCOUNT_FIELD string
+type MFA_RECOVERY_CODE_FIELD string
 
-const OAUTH_ACCOUNT_FIELD_ID = OAUTH_ACCOUNT_FIELD("id")
-const OAUTH_ACCOUNT_FIELD_USER_ID = OAUTH_ACCOUNT_FIELD("user_id")
-const OAUTH_ACCOUNT_FIELD_OAUTH_PROVIDER = OAUTH_ACCOUNT_FIELD("oauth_provider")
-const OAUTH_ACCOUNT_FIELD_OAUTH_OPEN_ID = OAUTH_ACCOUNT_FIELD("oauth_open_id")
-const OAUTH_ACCOUNT_FIELD_OAUTH_NAME = OAUTH_ACCOUNT_FIELD("oauth_name")
-const OAUTH_ACCOUNT_FIELD_OAUTH_ICON = OAUTH_ACCOUNT_FIELD("oauth_icon")
-const OAUTH_ACCOUNT_FIELD_CREATE_TIME = OAUTH_ACCOUNT_FIELD("create_time")
-const OAUTH_ACCOUNT_FIELD_UPDATE_TIME = OAUTH_ACCOUNT_FIELD("update_time")
+const MFA_RECOVERY_CODE_FIELD_ID = MFA_RECOVERY_CODE_FIELD("id")
+const MFA_RECOVERY_CODE_FIELD_USER_ID = MFA_RECOVERY_CODE_FIELD("user_id")
+const MFA_RECOVERY_CODE_FIELD_CODE_HASH = MFA_RECOVERY_CODE_FIELD("code_hash")
+const MFA_RECOVERY_CODE_FIELD_IS_USED = MFA_RECOVERY_CODE_FIELD("is_used")
+const MFA_RECOVERY_CODE_FIELD_CREATE_TIME = MFA_RECOVERY_CODE_FIELD("create_time")
+const MFA_RECOVERY_CODE_FIELD_UPDATE_TIME = MFA_RECOVERY_CODE_FIELD("update_time")
 
-const OAUTH_ACCOUNT_ALL_FIELDS_STRING = "id,user_id,oauth_provider,oauth_open_id,oauth_name,oauth_icon,create_time,update_time"
+const MFA_RECOVERY_CODE_ALL_FIELDS_STRING = "id,user_id,code_hash,is_used,create_time,update_time"
 
-var OAUTH_ACCOUNT_ALL_FIELDS = []string{
+var MFA_RECOVERY_CODE_ALL_FIELDS = []string{
 	"id",
 	"user_id",
-	"oauth_provider",
-	"oauth_open_id",
-	"oauth_name",
-	"oauth_icon",
+	"code_hash",
+	"is_used",
 	"create_time",
 	"update_time",
 }
 
-type OauthAccount struct {
-	Id            uint64 //size=20
-	UserId        string //size=32
-	OauthProvider string //size=32
-	OauthOpenId   string //size=128
-	OauthName     string //size=32
-	OauthIcon     string //size=256
-	CreateTime    time.Time
-	UpdateTime    mysql.NullTime
+type MfaRecoveryCode struct {
+	Id         uint64 //size=20
+	UserId     string //size=32
+	CodeHash   string //size=128
+	IsUsed     int32  //size=1
+	CreateTime time.Time
+	UpdateTime time.Time
 }
 
-type OauthAccountQuery struct {
+type MfaRecoveryCodeQuery struct {
 	BaseQuery
-	dao *OauthAccountDao
+	dao *MfaRecoveryCodeDao
 }
 
-func NewOauthAccountQuery(dao *OauthAccountDao) *OauthAccountQuery {
-	q := &OauthAccountQuery{}
+func NewMfaRecoveryCodeQuery(dao *MfaRecoveryCodeDao) *MfaRecoveryCodeQuery {
+	q := &MfaRecoveryCodeQuery{}
 	q.dao = dao
 
 	return q
 }
 
-func (q *OauthAccountQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*OauthAccount, error) {
+func (q *MfaRecoveryCodeQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*MfaRecoveryCode, error) {
 	return q.dao.QueryOne(ctx, tx, q.buildQueryString())
 }
 
-func (q *OauthAccountQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*OauthAccount, err error) {
+func (q *MfaRecoveryCodeQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*MfaRecoveryCode, err error) {
 	return q.dao.QueryList(ctx, tx, q.buildQueryString())
 }
 
-func (q *OauthAccountQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
+func (q *MfaRecoveryCodeQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
 	return q.dao.QueryCount(ctx, tx, q.buildQueryString())
 }
 
-func (q *OauthAccountQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
+func (q *MfaRecoveryCodeQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
 	return q.dao.QueryGroupBy(ctx, tx, q.groupByFields, q.buildQueryString())
 }
 
-func (q *OauthAccountQuery) ForUpdate() *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) ForUpdate() *MfaRecoveryCodeQuery {
 	q.forUpdate = true
 	return q
 }
 
-func (q *OauthAccountQuery) ForShare() *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) ForShare() *MfaRecoveryCodeQuery {
 	q.forShare = true
 	return q
 }
 
-func (q *OauthAccountQuery) GroupBy(fields ...OAUTH_ACCOUNT_FIELD) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) GroupBy(fields ...MFA_RECOVERY_CODE_FIELD) *MfaRecoveryCodeQuery {
 	q.groupByFields = make([]string, len(fields))
 	for i, v := range fields {
 		q.groupByFields[i] = string(v)
@@ -946,12 +1044,12 @@ func (q *OauthAccountQuery) GroupBy(fields ...OAUTH_ACCOUNT_FIELD) *OauthAccount
 	return q
 }
 
-func (q *OauthAccountQuery) Limit(startIncluded int64, count int64) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) Limit(startIncluded int64, count int64) *MfaRecoveryCodeQuery {
 	q.limit = fmt.Sprintf(" limit %d,%d", startIncluded, count)
 	return q
 }
 
-func (q *OauthAccountQuery) OrderBy(fieldName OAUTH_ACCOUNT_FIELD, asc bool) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) OrderBy(fieldName MFA_RECOVERY_CODE_FIELD, asc bool) *MfaRecoveryCodeQuery {
 	if q.order != "" {
 		q.order += ","
 	}
@@ -965,7 +1063,7 @@ func (q *OauthAccountQuery) OrderBy(fieldName OAUTH_ACCOUNT_FIELD, asc bool) *Oa
 	return q
 }
 
-func (q *OauthAccountQuery) OrderByGroupCount(asc bool) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) OrderByGroupCount(asc bool) *MfaRecoveryCodeQuery {
 	if q.order != "" {
 		q.order += ","
 	}
@@ -979,167 +1077,127 @@ func (q *OauthAccountQuery) OrderByGroupCount(asc bool) *OauthAccountQuery {
 	return q
 }
 
-func (q *OauthAccountQuery) w(format string, a ...interface{}) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) w(format string, a ...interface{}) *MfaRecoveryCodeQuery {
 	q.where += fmt.Sprintf(format, a...)
 	return q
 }
 
-func (q *OauthAccountQuery) Left() *OauthAccountQuery  { return q.w(" ( ") }
-func (q *OauthAccountQuery) Right() *OauthAccountQuery { return q.w(" ) ") }
-func (q *OauthAccountQuery) And() *OauthAccountQuery   { return q.w(" AND ") }
-func (q *OauthAccountQuery) Or() *OauthAccountQuery    { return q.w(" OR ") }
-func (q *OauthAccountQuery) Not() *OauthAccountQuery   { return q.w(" NOT ") }
+func (q *MfaRecoveryCodeQuery) Left() *MfaRecoveryCodeQuery  { return q.w(" ( ") }
+func (q *MfaRecoveryCodeQuery) Right() *MfaRecoveryCodeQuery { return q.w(" ) ") }
+func (q *MfaRecoveryCodeQuery) And() *MfaRecoveryCodeQuery   { return q.w(" AND ") }
+func (q *MfaRecoveryCodeQuery) Or() *MfaRecoveryCodeQuery    { return q.w(" OR ") }
+func (q *MfaRecoveryCodeQuery) Not() *MfaRecoveryCodeQuery   { return q.w(" NOT ") }
 
-func (q *OauthAccountQuery) Id_Equal(v uint64) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) Id_Equal(v uint64) *MfaRecoveryCodeQuery {
 	return q.w("id='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) Id_NotEqual(v uint64) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) Id_NotEqual(v uint64) *MfaRecoveryCodeQuery {
 	return q.w("id<>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) Id_Less(v uint64) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) Id_Less(v uint64) *MfaRecoveryCodeQuery {
 	return q.w("id<'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) Id_LessEqual(v uint64) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) Id_LessEqual(v uint64) *MfaRecoveryCodeQuery {
 	return q.w("id<='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) Id_Greater(v uint64) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) Id_Greater(v uint64) *MfaRecoveryCodeQuery {
 	return q.w("id>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) Id_GreaterEqual(v uint64) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) Id_GreaterEqual(v uint64) *MfaRecoveryCodeQuery {
 	return q.w("id>='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) UserId_Equal(v string) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) UserId_Equal(v string) *MfaRecoveryCodeQuery {
 	return q.w("user_id='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) UserId_NotEqual(v string) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) UserId_NotEqual(v string) *MfaRecoveryCodeQuery {
 	return q.w("user_id<>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) UserId_Less(v string) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) UserId_Less(v string) *MfaRecoveryCodeQuery {
 	return q.w("user_id<'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) UserId_LessEqual(v string) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) UserId_LessEqual(v string) *MfaRecoveryCodeQuery {
 	return q.w("user_id<='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) UserId_Greater(v string) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) UserId_Greater(v string) *MfaRecoveryCodeQuery {
 	return q.w("user_id>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) UserId_GreaterEqual(v string) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) UserId_GreaterEqual(v string) *MfaRecoveryCodeQuery {
 	return q.w("user_id>='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) OauthProvider_Equal(v string) *OauthAccountQuery {
-	return q.w("oauth_provider='" + fmt.Sprint(v) + "'")
-}
-func (q *OauthAccountQuery) OauthProvider_NotEqual(v string) *OauthAccountQuery {
-	return q.w("oauth_provider<>'" + fmt.Sprint(v) + "'")
-}
-func (q *OauthAccountQuery) OauthProvider_Less(v string) *OauthAccountQuery {
-	return q.w("oauth_provider<'" + fmt.Sprint(v) + "'")
-}
-func (q *OauthAccountQuery) OauthProvider_LessEqual(v string) *OauthAccountQuery {
-	return q.w("oauth_provider<='" + fmt.Sprint(v) + "'")
-}
-func (q *OauthAccountQuery) OauthProvider_Greater(v string) *OauthAccountQuery {
-	return q.w("oauth_provider>'" + fmt.Sprint(v) + "'")
-}
-func (q *OauthAccountQuery) OauthProvider_GreaterEqual(v string) *OauthAccountQuery {
-	return q.w("oauth_provider>='" + fmt.Sprint(v) + "'")
-}
-func (q *OauthAccountQuery) OauthOpenId_Equal(v string) *OauthAccountQuery {
-	return q.w("oauth_open_id='" + fmt.Sprint(v) + "'")
-}
-func (q *OauthAccountQuery) OauthOpenId_NotEqual(v string) *OauthAccountQuery {
-	return q.w("oauth_open_id<>'" + fmt.Sprint(v) + "'")
-}
-func (q *OauthAccountQuery) OauthOpenId_Less(v string) *OauthAccountQuery {
-	return q.w("oauth_open_id<'" + fmt.Sprint(v) + "'")
-}
-func (q *OauthAccountQuery) OauthOpenId_LessEqual(v string) *OauthAccountQuery {
-	return q.w("oauth_open_id<='" + fmt.Sprint(v) + "'")
-}
-func (q *OauthAccountQuery) OauthOpenId_Greater(v string) *OauthAccountQuery {
-	return q.w("oauth_open_id>'" + fmt.Sprint(v) + "'")
-}
-func (q *OauthAccountQuery) OauthOpenId_GreaterEqual(v string) *OauthAccountQuery {
-	return q.w("oauth_open_id>='" + fmt.Sprint(v) + "'")
-}
-func (q *OauthAccountQuery) OauthName_Equal(v string) *OauthAccountQuery {
-	return q.w("oauth_name='" + fmt.Sprint(v) + "'")
+func (q *MfaRecoveryCodeQuery) CodeHash_Equal(v string) *MfaRecoveryCodeQuery {
+	return q.w("code_hash='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) OauthName_NotEqual(v string) *OauthAccountQuery {
-	return q.w("oauth_name<>'" + fmt.Sprint(v) + "'")
+func (q *MfaRecoveryCodeQuery) CodeHash_NotEqual(v string) *MfaRecoveryCodeQuery {
+	return q.w("code_hash<>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) OauthName_Less(v string) *OauthAccountQuery {
-	return q.w("oauth_name<'" + fmt.Sprint(v) + "'")
+func (q *MfaRecoveryCodeQuery) CodeHash_Less(v string) *MfaRecoveryCodeQuery {
+	return q.w("code_hash<'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) OauthName_LessEqual(v string) *OauthAccountQuery {
-	return q.w("oauth_name<='" + fmt.Sprint(v) + "'")
+func (q *MfaRecoveryCodeQuery) CodeHash_LessEqual(v string) *MfaRecoveryCodeQuery {
+	return q.w("code_hash<='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) OauthName_Greater(v string) *OauthAccountQuery {
-	return q.w("oauth_name>'" + fmt.Sprint(v) + "'")
+func (q *MfaRecoveryCodeQuery) CodeHash_Greater(v string) *MfaRecoveryCodeQuery {
+	return q.w("code_hash>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) OauthName_GreaterEqual(v string) *OauthAccountQuery {
-	return q.w("oauth_name>='" + fmt.Sprint(v) + "'")
+func (q *MfaRecoveryCodeQuery) CodeHash_GreaterEqual(v string) *MfaRecoveryCodeQuery {
+	return q.w("code_hash>='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) OauthIcon_Equal(v string) *OauthAccountQuery {
-	return q.w("oauth_icon='" + fmt.Sprint(v) + "'")
+func (q *MfaRecoveryCodeQuery) IsUsed_Equal(v int32) *MfaRecoveryCodeQuery {
+	return q.w("is_used='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) OauthIcon_NotEqual(v string) *OauthAccountQuery {
-	return q.w("oauth_icon<>'" + fmt.Sprint(v) + "'")
+func (q *MfaRecoveryCodeQuery) IsUsed_NotEqual(v int32) *MfaRecoveryCodeQuery {
+	return q.w("is_used<>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) OauthIcon_Less(v string) *OauthAccountQuery {
-	return q.w("oauth_icon<'" + fmt.Sprint(v) + "'")
+func (q *MfaRecoveryCodeQuery) IsUsed_Less(v int32) *MfaRecoveryCodeQuery {
+	return q.w("is_used<'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) OauthIcon_LessEqual(v string) *OauthAccountQuery {
-	return q.w("oauth_icon<='" + fmt.Sprint(v) + "'")
+func (q *MfaRecoveryCodeQuery) IsUsed_LessEqual(v int32) *MfaRecoveryCodeQuery {
+	return q.w("is_used<='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) OauthIcon_Greater(v string) *OauthAccountQuery {
-	return q.w("oauth_icon>'" + fmt.Sprint(v) + "'")
+func (q *MfaRecoveryCodeQuery) IsUsed_Greater(v int32) *MfaRecoveryCodeQuery {
+	return q.w("is_used>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) OauthIcon_GreaterEqual(v string) *OauthAccountQuery {
-	return q.w("oauth_icon>='" + fmt.Sprint(v) + "'")
+func (q *MfaRecoveryCodeQuery) IsUsed_GreaterEqual(v int32) *MfaRecoveryCodeQuery {
+	return q.w("is_used>='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) CreateTime_Equal(v time.Time) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) CreateTime_Equal(v time.Time) *MfaRecoveryCodeQuery {
 	return q.w("create_time='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) CreateTime_NotEqual(v time.Time) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) CreateTime_NotEqual(v time.Time) *MfaRecoveryCodeQuery {
 	return q.w("create_time<>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) CreateTime_Less(v time.Time) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) CreateTime_Less(v time.Time) *MfaRecoveryCodeQuery {
 	return q.w("create_time<'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) CreateTime_LessEqual(v time.Time) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) CreateTime_LessEqual(v time.Time) *MfaRecoveryCodeQuery {
 	return q.w("create_time<='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) CreateTime_Greater(v time.Time) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) CreateTime_Greater(v time.Time) *MfaRecoveryCodeQuery {
 	return q.w("create_time>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) CreateTime_GreaterEqual(v time.Time) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) CreateTime_GreaterEqual(v time.Time) *MfaRecoveryCodeQuery {
 	return q.w("create_time>='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) UpdateTime_Equal(v time.Time) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) UpdateTime_Equal(v time.Time) *MfaRecoveryCodeQuery {
 	return q.w("update_time='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) UpdateTime_NotEqual(v time.Time) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) UpdateTime_NotEqual(v time.Time) *MfaRecoveryCodeQuery {
 	return q.w("update_time<>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) UpdateTime_Less(v time.Time) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) UpdateTime_Less(v time.Time) *MfaRecoveryCodeQuery {
 	return q.w("update_time<'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) UpdateTime_LessEqual(v time.Time) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) UpdateTime_LessEqual(v time.Time) *MfaRecoveryCodeQuery {
 	return q.w("update_time<='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) UpdateTime_Greater(v time.Time) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) UpdateTime_Greater(v time.Time) *MfaRecoveryCodeQuery {
 	return q.w("update_time>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) UpdateTime_GreaterEqual(v time.Time) *OauthAccountQuery {
+func (q *MfaRecoveryCodeQuery) UpdateTime_GreaterEqual(v time.Time) *MfaRecoveryCodeQuery {
 	return q.w("update_time>='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthAccountQuery) UpdateTime_IsNull() *OauthAccountQuery { return q.w("update_time IS NULL") }
-func (q *OauthAccountQuery) UpdateTime_NotNull() *OauthAccountQuery {
-	return q.w("update_time IS NOT NULL")
-}
 
-type OauthAccountDao struct {
+type MfaRecoveryCodeDao struct {
 	logger     *zap.Logger
 	db         *DB
 	insertStmt *wrap.Stmt
@@ -1147,8 +1205,8 @@ type OauthAccountDao struct {
 	deleteStmt *wrap.Stmt
 }
 
-func NewOauthAccountDao(db *DB) (t *OauthAccountDao, err error) {
-	t = &OauthAccountDao{}
+func NewMfaRecoveryCodeDao(db *DB) (t *MfaRecoveryCodeDao, err error) {
+	t = &MfaRecoveryCodeDao{}
 	t.logger = log.TypedLogger(t)
 	t.db = db
 	err = t.init()
@@ -1159,7 +1217,7 @@ func NewOauthAccountDao(db *DB) (t *OauthAccountDao, err error) {
 	return t, nil
 }
 
-func (dao *OauthAccountDao) init() (err error) {
+func (dao *MfaRecoveryCodeDao) init() (err error) {
 	err = dao.prepareInsertStmt()
 	if err != nil {
 		return err
@@ -1178,28 +1236,28 @@ func (dao *OauthAccountDao) init() (err error) {
 	return nil
 }
 
-func (dao *OauthAccountDao) prepareInsertStmt() (err error) {
-	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO oauth_account (user_id,oauth_provider,oauth_open_id,oauth_name,oauth_icon) VALUES (?,?,?,?,?)")
+func (dao *MfaRecoveryCodeDao) prepareInsertStmt() (err error) {
+	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO mfa_recovery_code (user_id,code_hash,is_used) VALUES (?,?,?)")
 	return err
 }
 
-func (dao *OauthAccountDao) prepareUpdateStmt() (err error) {
-	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE oauth_account SET user_id=?,oauth_provider=?,oauth_open_id=?,oauth_name=?,oauth_icon=? WHERE id=?")
+func (dao *MfaRecoveryCodeDao) prepareUpdateStmt() (err error) {
+	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE mfa_recovery_code SET user_id=?,code_hash=?,is_used=? WHERE id=?")
 	return err
 }
 
-func (dao *OauthAccountDao) prepareDeleteStmt() (err error) {
-	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM oauth_account WHERE id=?")
+func (dao *MfaRecoveryCodeDao) prepareDeleteStmt() (err error) {
+	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM mfa_recovery_code WHERE id=?")
 	return err
 }
 
-func (dao *OauthAccountDao) Insert(ctx context.Context, tx *wrap.Tx, e *OauthAccount) (id int64, err error) {
+func (dao *MfaRecoveryCodeDao) Insert(ctx context.Context, tx *wrap.Tx, e *MfaRecoveryCode) (id int64, err error) {
 	stmt := dao.insertStmt
 	if tx != nil {
 		stmt = tx.Stmt(ctx, stmt)
 	}
 
-	result, err := stmt.Exec(ctx, e.UserId, e.OauthProvider, e.OauthOpenId, e.OauthName, e.OauthIcon)
+	result, err := stmt.Exec(ctx, e.UserId, e.CodeHash, e.IsUsed)
 	if err != nil {
 		return 0, err
 	}
@@ -1212,13 +1270,13 @@ func (dao *OauthAccountDao) Insert(ctx context.Context, tx *wrap.Tx, e *OauthAcc
 	return id, nil
 }
 
-func (dao *OauthAccountDao) Update(ctx context.Context, tx *wrap.Tx, e *OauthAccount) (err error) {
+func (dao *MfaRecoveryCodeDao) Update(ctx context.Context, tx *wrap.Tx, e *MfaRecoveryCode) (err error) {
 	stmt := dao.updateStmt
 	if tx != nil {
 		stmt = tx.Stmt(ctx, stmt)
 	}
 
-	_, err = stmt.Exec(ctx, e.UserId, e.OauthProvider, e.OauthOpenId, e.OauthName, e.OauthIcon, e.Id)
+	_, err = stmt.Exec(ctx, e.UserId, e.CodeHash, e.IsUsed, e.Id)
 	if err != nil {
 		return err
 	}
@@ -1226,7 +1284,7 @@ func (dao *OauthAccountDao) Update(ctx context.Context, tx *wrap.Tx, e *OauthAcc
 	return nil
 }
 
-func (dao *OauthAccountDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
+func (dao *MfaRecoveryCodeDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
 	stmt := dao.deleteStmt
 	if tx != nil {
 		stmt = tx.Stmt(ctx, stmt)
@@ -1240,9 +1298,9 @@ func (dao *OauthAccountDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64)
 	return nil
 }
 
-func (dao *OauthAccountDao) scanRow(row *wrap.Row) (*OauthAccount, error) {
-	e := &OauthAccount{}
-	err := row.Scan(&e.Id, &e.UserId, &e.OauthProvider, &e.OauthOpenId, &e.OauthName, &e.OauthIcon, &e.CreateTime, &e.UpdateTime)
+func (dao *MfaRecoveryCodeDao) scanRow(row *wrap.Row) (*MfaRecoveryCode, error) {
+	e := &MfaRecoveryCode{}
+	err := row.Scan(&e.Id, &e.UserId, &e.CodeHash, &e.IsUsed, &e.CreateTime, &e.UpdateTime)
 	if err != nil {
 		if err == wrap.ErrNoRows {
 			return nil, nil
@@ -1254,11 +1312,11 @@ func (dao *OauthAccountDao) scanRow(row *wrap.Row) (*OauthAccount, error) {
 	return e, nil
 }
 
-func (dao *OauthAccountDao) scanRows(rows *wrap.Rows) (list []*OauthAccount, err error) {
-	list = make([]*OauthAccount, 0)
+func (dao *MfaRecoveryCodeDao) scanRows(rows *wrap.Rows) (list []*MfaRecoveryCode, err error) {
+	list = make([]*MfaRecoveryCode, 0)
 	for rows.Next() {
-		e := OauthAccount{}
-		err = rows.Scan(&e.Id, &e.UserId, &e.OauthProvider, &e.OauthOpenId, &e.OauthName, &e.OauthIcon, &e.CreateTime, &e.UpdateTime)
+		e := MfaRecoveryCode{}
+		err = rows.Scan(&e.Id, &e.UserId, &e.CodeHash, &e.IsUsed, &e.CreateTime, &e.UpdateTime)
 		if err != nil {
 			return nil, err
 		}
@@ -1272,8 +1330,8 @@ func (dao *OauthAccountDao) scanRows(rows *wrap.Rows) (list []*OauthAccount, err
 	return list, nil
 }
 
-func (dao *OauthAccountDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*OauthAccount, error) {
-	querySql := "SELECT " + OAUTH_ACCOUNT_ALL_FIELDS_STRING + " FROM oauth_account " + query
+func (dao *MfaRecoveryCodeDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*MfaRecoveryCode, error) {
+	querySql := "SELECT " + MFA_RECOVERY_CODE_ALL_FIELDS_STRING + " FROM mfa_recovery_code " + query
 	var row *wrap.Row
 	if tx == nil {
 		row = dao.db.QueryRow(ctx, querySql)
@@ -1283,8 +1341,8 @@ func (dao *OauthAccountDao) QueryOne(ctx context.Context, tx *wrap.Tx, query str
 	return dao.scanRow(row)
 }
 
-func (dao *OauthAccountDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*OauthAccount, err error) {
-	querySql := "SELECT " + OAUTH_ACCOUNT_ALL_FIELDS_STRING + " FROM oauth_account " + query
+func (dao *MfaRecoveryCodeDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*MfaRecoveryCode, err error) {
+	querySql := "SELECT " + MFA_RECOVERY_CODE_ALL_FIELDS_STRING + " FROM mfa_recovery_code " + query
 	var rows *wrap.Rows
 	if tx == nil {
 		rows, err = dao.db.Query(ctx, querySql)
@@ -1299,8 +1357,8 @@ func (dao *OauthAccountDao) QueryList(ctx context.Context, tx *wrap.Tx, query st
 	return dao.scanRows(rows)
 }
 
-func (dao *OauthAccountDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
-	querySql := "SELECT COUNT(1) FROM oauth_account " + query
+func (dao *MfaRecoveryCodeDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
+	querySql := "SELECT COUNT(1) FROM mfa_recovery_code " + query
 	var row *wrap.Row
 	if tx == nil {
 		row = dao.db.QueryRow(ctx, querySql)
@@ -1320,8 +1378,8 @@ func (dao *OauthAccountDao) QueryCount(ctx context.Context, tx *wrap.Tx, query s
 	return count, nil
 }
 
-func (dao *OauthAccountDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
-	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM oauth_account " + query
+func (dao *MfaRecoveryCodeDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
+	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM mfa_recovery_code " + query
 	if tx == nil {
 		return dao.db.Query(ctx, querySql)
 	} else {
@@ -1329,44 +1387,1591 @@ func (dao *OauthAccountDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, group
 	}
 }
 
-func (dao *OauthAccountDao) GetQuery() *OauthAccountQuery {
-	return NewOauthAccountQuery(dao)
+func (dao *MfaRecoveryCodeDao) GetQuery() *MfaRecoveryCodeQuery {
+	return NewMfaRecoveryCodeQuery(dao)
 }
 
-const OAUTH_STATE_TABLE_NAME = "oauth_state"
+const OAUTH_ACCOUNT_TABLE_NAME = "oauth_account"
 
-type OAUTH_STATE_FIELD string
+type OAUTH_ACCOUNT_FIELD string
 
-const OAUTH_STATE_FIELD_ID = OAUTH_STATE_FIELD("id")
-const OAUTH_STATE_FIELD_OAUTH_STATE = OAUTH_STATE_FIELD("oauth_state")
-const OAUTH_STATE_FIELD_IS_USED = OAUTH_STATE_FIELD("is_used")
-const OAUTH_STATE_FIELD_USER_AGENT = OAUTH_STATE_FIELD("user_agent")
-const OAUTH_STATE_FIELD_CREATE_TIME = OAUTH_STATE_FIELD("create_time")
-const OAUTH_STATE_FIELD_UPDATE_TIME = OAUTH_STATE_FIELD("update_time")
+const OAUTH_ACCOUNT_FIELD_ID = OAUTH_ACCOUNT_FIELD("id")
+const OAUTH_ACCOUNT_FIELD_USER_ID = OAUTH_ACCOUNT_FIELD("user_id")
+const OAUTH_ACCOUNT_FIELD_OAUTH_PROVIDER = OAUTH_ACCOUNT_FIELD("oauth_provider")
+const OAUTH_ACCOUNT_FIELD_OAUTH_OPEN_ID = OAUTH_ACCOUNT_FIELD("oauth_open_id")
+const OAUTH_ACCOUNT_FIELD_OAUTH_NAME = OAUTH_ACCOUNT_FIELD("oauth_name")
+const OAUTH_ACCOUNT_FIELD_OAUTH_ICON = OAUTH_ACCOUNT_FIELD("oauth_icon")
+const OAUTH_ACCOUNT_FIELD_MIRROR_SOURCE = OAUTH_ACCOUNT_FIELD("mirror_source")
+const OAUTH_ACCOUNT_FIELD_MIRROR_ICON = OAUTH_ACCOUNT_FIELD("mirror_icon")
+const OAUTH_ACCOUNT_FIELD_CREATE_TIME = OAUTH_ACCOUNT_FIELD("create_time")
+const OAUTH_ACCOUNT_FIELD_UPDATE_TIME = OAUTH_ACCOUNT_FIELD("update_time")
 
-const OAUTH_STATE_ALL_FIELDS_STRING = "id,oauth_state,is_used,user_agent,create_time,update_time"
+const OAUTH_ACCOUNT_ALL_FIELDS_STRING = "id,user_id,oauth_provider,oauth_open_id,oauth_name,oauth_icon,mirror_source,mirror_icon,create_time,update_time"
 
-var OAUTH_STATE_ALL_FIELDS = []string{
+var OAUTH_ACCOUNT_ALL_FIELDS = []string{
 	"id",
-	"oauth_state",
-	"is_used",
-	"user_agent",
+	"user_id",
+	"oauth_provider",
+	"oauth_open_id",
+	"oauth_name",
+	"oauth_icon",
+	"mirror_source",
+	"mirror_icon",
 	"create_time",
 	"update_time",
 }
 
-type OauthState struct {
-	Id         uint64 //size=20
-	OauthState string //size=128
-	IsUsed     int32  //size=1
-	UserAgent  string //size=256
-	CreateTime time.Time
-	UpdateTime time.Time
+type OauthAccount struct {
+	Id            uint64 //size=20
+	UserId        string //size=32
+	OauthProvider string //size=32
+	OauthOpenId   string //size=128
+	OauthName     string //size=32
+	OauthIcon     string //size=256
+	MirrorSource  string //size=256
+	MirrorIcon    string //size=256
+	CreateTime    time.Time
+	UpdateTime    mysql.NullTime
 }
 
-type OauthStateQuery struct {
+type OauthAccountQuery struct {
 	BaseQuery
-	dao *OauthStateDao
+	dao *OauthAccountDao
+}
+
+func NewOauthAccountQuery(dao *OauthAccountDao) *OauthAccountQuery {
+	q := &OauthAccountQuery{}
+	q.dao = dao
+
+	return q
+}
+
+func (q *OauthAccountQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*OauthAccount, error) {
+	return q.dao.QueryOne(ctx, tx, q.buildQueryString())
+}
+
+func (q *OauthAccountQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*OauthAccount, err error) {
+	return q.dao.QueryList(ctx, tx, q.buildQueryString())
+}
+
+func (q *OauthAccountQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
+	return q.dao.QueryCount(ctx, tx, q.buildQueryString())
+}
+
+func (q *OauthAccountQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
+	return q.dao.QueryGroupBy(ctx, tx, q.groupByFields, q.buildQueryString())
+}
+
+func (q *OauthAccountQuery) ForUpdate() *OauthAccountQuery {
+	q.forUpdate = true
+	return q
+}
+
+func (q *OauthAccountQuery) ForShare() *OauthAccountQuery {
+	q.forShare = true
+	return q
+}
+
+func (q *OauthAccountQuery) GroupBy(fields ...OAUTH_ACCOUNT_FIELD) *OauthAccountQuery {
+	q.groupByFields = make([]string, len(fields))
+	for i, v := range fields {
+		q.groupByFields[i] = string(v)
+	}
+	return q
+}
+
+func (q *OauthAccountQuery) Limit(startIncluded int64, count int64) *OauthAccountQuery {
+	q.limit = fmt.Sprintf(" limit %d,%d", startIncluded, count)
+	return q
+}
+
+func (q *OauthAccountQuery) OrderBy(fieldName OAUTH_ACCOUNT_FIELD, asc bool) *OauthAccountQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += string(fieldName) + " "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *OauthAccountQuery) OrderByGroupCount(asc bool) *OauthAccountQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += "count(1) "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *OauthAccountQuery) w(format string, a ...interface{}) *OauthAccountQuery {
+	q.where += fmt.Sprintf(format, a...)
+	return q
+}
+
+func (q *OauthAccountQuery) Left() *OauthAccountQuery  { return q.w(" ( ") }
+func (q *OauthAccountQuery) Right() *OauthAccountQuery { return q.w(" ) ") }
+func (q *OauthAccountQuery) And() *OauthAccountQuery   { return q.w(" AND ") }
+func (q *OauthAccountQuery) Or() *OauthAccountQuery    { return q.w(" OR ") }
+func (q *OauthAccountQuery) Not() *OauthAccountQuery   { return q.w(" NOT ") }
+
+func (q *OauthAccountQuery) Id_Equal(v uint64) *OauthAccountQuery {
+	return q.w("id='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) Id_NotEqual(v uint64) *OauthAccountQuery {
+	return q.w("id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) Id_Less(v uint64) *OauthAccountQuery {
+	return q.w("id<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) Id_LessEqual(v uint64) *OauthAccountQuery {
+	return q.w("id<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) Id_Greater(v uint64) *OauthAccountQuery {
+	return q.w("id>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) Id_GreaterEqual(v uint64) *OauthAccountQuery {
+	return q.w("id>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) UserId_Equal(v string) *OauthAccountQuery {
+	return q.w("user_id='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) UserId_NotEqual(v string) *OauthAccountQuery {
+	return q.w("user_id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) UserId_Less(v string) *OauthAccountQuery {
+	return q.w("user_id<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) UserId_LessEqual(v string) *OauthAccountQuery {
+	return q.w("user_id<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) UserId_Greater(v string) *OauthAccountQuery {
+	return q.w("user_id>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) UserId_GreaterEqual(v string) *OauthAccountQuery {
+	return q.w("user_id>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthProvider_Equal(v string) *OauthAccountQuery {
+	return q.w("oauth_provider='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthProvider_NotEqual(v string) *OauthAccountQuery {
+	return q.w("oauth_provider<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthProvider_Less(v string) *OauthAccountQuery {
+	return q.w("oauth_provider<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthProvider_LessEqual(v string) *OauthAccountQuery {
+	return q.w("oauth_provider<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthProvider_Greater(v string) *OauthAccountQuery {
+	return q.w("oauth_provider>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthProvider_GreaterEqual(v string) *OauthAccountQuery {
+	return q.w("oauth_provider>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthOpenId_Equal(v string) *OauthAccountQuery {
+	return q.w("oauth_open_id='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthOpenId_NotEqual(v string) *OauthAccountQuery {
+	return q.w("oauth_open_id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthOpenId_Less(v string) *OauthAccountQuery {
+	return q.w("oauth_open_id<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthOpenId_LessEqual(v string) *OauthAccountQuery {
+	return q.w("oauth_open_id<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthOpenId_Greater(v string) *OauthAccountQuery {
+	return q.w("oauth_open_id>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthOpenId_GreaterEqual(v string) *OauthAccountQuery {
+	return q.w("oauth_open_id>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthName_Equal(v string) *OauthAccountQuery {
+	return q.w("oauth_name='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthName_NotEqual(v string) *OauthAccountQuery {
+	return q.w("oauth_name<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthName_Less(v string) *OauthAccountQuery {
+	return q.w("oauth_name<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthName_LessEqual(v string) *OauthAccountQuery {
+	return q.w("oauth_name<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthName_Greater(v string) *OauthAccountQuery {
+	return q.w("oauth_name>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthName_GreaterEqual(v string) *OauthAccountQuery {
+	return q.w("oauth_name>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthIcon_Equal(v string) *OauthAccountQuery {
+	return q.w("oauth_icon='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthIcon_NotEqual(v string) *OauthAccountQuery {
+	return q.w("oauth_icon<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthIcon_Less(v string) *OauthAccountQuery {
+	return q.w("oauth_icon<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthIcon_LessEqual(v string) *OauthAccountQuery {
+	return q.w("oauth_icon<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthIcon_Greater(v string) *OauthAccountQuery {
+	return q.w("oauth_icon>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) OauthIcon_GreaterEqual(v string) *OauthAccountQuery {
+	return q.w("oauth_icon>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) MirrorSource_Equal(v string) *OauthAccountQuery {
+	return q.w("mirror_source='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) MirrorSource_NotEqual(v string) *OauthAccountQuery {
+	return q.w("mirror_source<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) MirrorSource_Less(v string) *OauthAccountQuery {
+	return q.w("mirror_source<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) MirrorSource_LessEqual(v string) *OauthAccountQuery {
+	return q.w("mirror_source<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) MirrorSource_Greater(v string) *OauthAccountQuery {
+	return q.w("mirror_source>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) MirrorSource_GreaterEqual(v string) *OauthAccountQuery {
+	return q.w("mirror_source>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) MirrorIcon_Equal(v string) *OauthAccountQuery {
+	return q.w("mirror_icon='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) MirrorIcon_NotEqual(v string) *OauthAccountQuery {
+	return q.w("mirror_icon<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) MirrorIcon_Less(v string) *OauthAccountQuery {
+	return q.w("mirror_icon<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) MirrorIcon_LessEqual(v string) *OauthAccountQuery {
+	return q.w("mirror_icon<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) MirrorIcon_Greater(v string) *OauthAccountQuery {
+	return q.w("mirror_icon>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) MirrorIcon_GreaterEqual(v string) *OauthAccountQuery {
+	return q.w("mirror_icon>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) CreateTime_Equal(v time.Time) *OauthAccountQuery {
+	return q.w("create_time='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) CreateTime_NotEqual(v time.Time) *OauthAccountQuery {
+	return q.w("create_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) CreateTime_Less(v time.Time) *OauthAccountQuery {
+	return q.w("create_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) CreateTime_LessEqual(v time.Time) *OauthAccountQuery {
+	return q.w("create_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) CreateTime_Greater(v time.Time) *OauthAccountQuery {
+	return q.w("create_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) CreateTime_GreaterEqual(v time.Time) *OauthAccountQuery {
+	return q.w("create_time>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) UpdateTime_Equal(v time.Time) *OauthAccountQuery {
+	return q.w("update_time='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) UpdateTime_NotEqual(v time.Time) *OauthAccountQuery {
+	return q.w("update_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) UpdateTime_Less(v time.Time) *OauthAccountQuery {
+	return q.w("update_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) UpdateTime_LessEqual(v time.Time) *OauthAccountQuery {
+	return q.w("update_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) UpdateTime_Greater(v time.Time) *OauthAccountQuery {
+	return q.w("update_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) UpdateTime_GreaterEqual(v time.Time) *OauthAccountQuery {
+	return q.w("update_time>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAccountQuery) UpdateTime_IsNull() *OauthAccountQuery { return q.w("update_time IS NULL") }
+func (q *OauthAccountQuery) UpdateTime_NotNull() *OauthAccountQuery {
+	return q.w("update_time IS NOT NULL")
+}
+
+type OauthAccountDao struct {
+	logger     *zap.Logger
+	db         *DB
+	insertStmt *wrap.Stmt
+	updateStmt *wrap.Stmt
+	deleteStmt *wrap.Stmt
+}
+
+func NewOauthAccountDao(db *DB) (t *OauthAccountDao, err error) {
+	t = &OauthAccountDao{}
+	t.logger = log.TypedLogger(t)
+	t.db = db
+	err = t.init()
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (dao *OauthAccountDao) init() (err error) {
+	err = dao.prepareInsertStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareUpdateStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareDeleteStmt()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *OauthAccountDao) prepareInsertStmt() (err error) {
+	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO oauth_account (user_id,oauth_provider,oauth_open_id,oauth_name,oauth_icon,mirror_source,mirror_icon) VALUES (?,?,?,?,?,?,?)")
+	return err
+}
+
+func (dao *OauthAccountDao) prepareUpdateStmt() (err error) {
+	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE oauth_account SET user_id=?,oauth_provider=?,oauth_open_id=?,oauth_name=?,oauth_icon=?,mirror_source=?,mirror_icon=? WHERE id=?")
+	return err
+}
+
+func (dao *OauthAccountDao) prepareDeleteStmt() (err error) {
+	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM oauth_account WHERE id=?")
+	return err
+}
+
+func (dao *OauthAccountDao) Insert(ctx context.Context, tx *wrap.Tx, e *OauthAccount) (id int64, err error) {
+	stmt := dao.insertStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	result, err := stmt.Exec(ctx, e.UserId, e.OauthProvider, e.OauthOpenId, e.OauthName, e.OauthIcon, e.MirrorSource, e.MirrorIcon)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err = result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (dao *OauthAccountDao) Update(ctx context.Context, tx *wrap.Tx, e *OauthAccount) (err error) {
+	stmt := dao.updateStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, e.UserId, e.OauthProvider, e.OauthOpenId, e.OauthName, e.OauthIcon, e.MirrorSource, e.MirrorIcon, e.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *OauthAccountDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
+	stmt := dao.deleteStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *OauthAccountDao) scanRow(row *wrap.Row) (*OauthAccount, error) {
+	e := &OauthAccount{}
+	err := row.Scan(&e.Id, &e.UserId, &e.OauthProvider, &e.OauthOpenId, &e.OauthName, &e.OauthIcon, &e.MirrorSource, &e.MirrorIcon, &e.CreateTime, &e.UpdateTime)
+	if err != nil {
+		if err == wrap.ErrNoRows {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+func (dao *OauthAccountDao) scanRows(rows *wrap.Rows) (list []*OauthAccount, err error) {
+	list = make([]*OauthAccount, 0)
+	for rows.Next() {
+		e := OauthAccount{}
+		err = rows.Scan(&e.Id, &e.UserId, &e.OauthProvider, &e.OauthOpenId, &e.OauthName, &e.OauthIcon, &e.MirrorSource, &e.MirrorIcon, &e.CreateTime, &e.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &e)
+	}
+	if rows.Err() != nil {
+		err = rows.Err()
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (dao *OauthAccountDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*OauthAccount, error) {
+	querySql := "SELECT " + OAUTH_ACCOUNT_ALL_FIELDS_STRING + " FROM oauth_account " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	return dao.scanRow(row)
+}
+
+func (dao *OauthAccountDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*OauthAccount, err error) {
+	querySql := "SELECT " + OAUTH_ACCOUNT_ALL_FIELDS_STRING + " FROM oauth_account " + query
+	var rows *wrap.Rows
+	if tx == nil {
+		rows, err = dao.db.Query(ctx, querySql)
+	} else {
+		rows, err = tx.Query(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return nil, err
+	}
+
+	return dao.scanRows(rows)
+}
+
+func (dao *OauthAccountDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
+	querySql := "SELECT COUNT(1) FROM oauth_account " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return 0, err
+	}
+
+	err = row.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (dao *OauthAccountDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
+	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM oauth_account " + query
+	if tx == nil {
+		return dao.db.Query(ctx, querySql)
+	} else {
+		return tx.Query(ctx, querySql)
+	}
+}
+
+func (dao *OauthAccountDao) GetQuery() *OauthAccountQuery {
+	return NewOauthAccountQuery(dao)
+}
+
+const OAUTH_AUTHORIZATION_CODE_TABLE_NAME = "oauth_authorization_code"
+
+type OAUTH_AUTHORIZATION_CODE_FIELD string
+
+const OAUTH_AUTHORIZATION_CODE_FIELD_ID = OAUTH_AUTHORIZATION_CODE_FIELD("id")
+const OAUTH_AUTHORIZATION_CODE_FIELD_CODE = OAUTH_AUTHORIZATION_CODE_FIELD("code")
+const OAUTH_AUTHORIZATION_CODE_FIELD_CLIENT_ID = OAUTH_AUTHORIZATION_CODE_FIELD("client_id")
+const OAUTH_AUTHORIZATION_CODE_FIELD_USER_ID = OAUTH_AUTHORIZATION_CODE_FIELD("user_id")
+const OAUTH_AUTHORIZATION_CODE_FIELD_REDIRECT_URI = OAUTH_AUTHORIZATION_CODE_FIELD("redirect_uri")
+const OAUTH_AUTHORIZATION_CODE_FIELD_SCOPE = OAUTH_AUTHORIZATION_CODE_FIELD("scope")
+const OAUTH_AUTHORIZATION_CODE_FIELD_NONCE = OAUTH_AUTHORIZATION_CODE_FIELD("nonce")
+const OAUTH_AUTHORIZATION_CODE_FIELD_CODE_CHALLENGE = OAUTH_AUTHORIZATION_CODE_FIELD("code_challenge")
+const OAUTH_AUTHORIZATION_CODE_FIELD_SESSION_ID = OAUTH_AUTHORIZATION_CODE_FIELD("session_id")
+const OAUTH_AUTHORIZATION_CODE_FIELD_IS_USED = OAUTH_AUTHORIZATION_CODE_FIELD("is_used")
+const OAUTH_AUTHORIZATION_CODE_FIELD_CREATE_TIME = OAUTH_AUTHORIZATION_CODE_FIELD("create_time")
+const OAUTH_AUTHORIZATION_CODE_FIELD_UPDATE_TIME = OAUTH_AUTHORIZATION_CODE_FIELD("update_time")
+
+const OAUTH_AUTHORIZATION_CODE_ALL_FIELDS_STRING = "id,code,client_id,user_id,redirect_uri,scope,nonce,code_challenge,session_id,is_used,create_time,update_time"
+
+var OAUTH_AUTHORIZATION_CODE_ALL_FIELDS = []string{
+	"id",
+	"code",
+	"client_id",
+	"user_id",
+	"redirect_uri",
+	"scope",
+	"nonce",
+	"code_challenge",
+	"session_id",
+	"is_used",
+	"create_time",
+	"update_time",
+}
+
+type OauthAuthorizationCode struct {
+	Id            uint64 //size=20
+	Code          string //size=128
+	ClientId      string //size=64
+	UserId        string //size=32
+	RedirectUri   string //size=1024
+	Scope         string //size=256
+	Nonce         string //size=256
+	CodeChallenge string //size=128
+	SessionId     string //size=32
+	IsUsed        int32  //size=1
+	CreateTime    time.Time
+	UpdateTime    time.Time
+}
+
+type OauthAuthorizationCodeQuery struct {
+	BaseQuery
+	dao *OauthAuthorizationCodeDao
+}
+
+func NewOauthAuthorizationCodeQuery(dao *OauthAuthorizationCodeDao) *OauthAuthorizationCodeQuery {
+	q := &OauthAuthorizationCodeQuery{}
+	q.dao = dao
+
+	return q
+}
+
+func (q *OauthAuthorizationCodeQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*OauthAuthorizationCode, error) {
+	return q.dao.QueryOne(ctx, tx, q.buildQueryString())
+}
+
+func (q *OauthAuthorizationCodeQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*OauthAuthorizationCode, err error) {
+	return q.dao.QueryList(ctx, tx, q.buildQueryString())
+}
+
+func (q *OauthAuthorizationCodeQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
+	return q.dao.QueryCount(ctx, tx, q.buildQueryString())
+}
+
+func (q *OauthAuthorizationCodeQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
+	return q.dao.QueryGroupBy(ctx, tx, q.groupByFields, q.buildQueryString())
+}
+
+func (q *OauthAuthorizationCodeQuery) ForUpdate() *OauthAuthorizationCodeQuery {
+	q.forUpdate = true
+	return q
+}
+
+func (q *OauthAuthorizationCodeQuery) ForShare() *OauthAuthorizationCodeQuery {
+	q.forShare = true
+	return q
+}
+
+func (q *OauthAuthorizationCodeQuery) GroupBy(fields ...OAUTH_AUTHORIZATION_CODE_FIELD) *OauthAuthorizationCodeQuery {
+	q.groupByFields = make([]string, len(fields))
+	for i, v := range fields {
+		q.groupByFields[i] = string(v)
+	}
+	return q
+}
+
+func (q *OauthAuthorizationCodeQuery) Limit(startIncluded int64, count int64) *OauthAuthorizationCodeQuery {
+	q.limit = fmt.Sprintf(" limit %d,%d", startIncluded, count)
+	return q
+}
+
+func (q *OauthAuthorizationCodeQuery) OrderBy(fieldName OAUTH_AUTHORIZATION_CODE_FIELD, asc bool) *OauthAuthorizationCodeQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += string(fieldName) + " "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *OauthAuthorizationCodeQuery) OrderByGroupCount(asc bool) *OauthAuthorizationCodeQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += "count(1) "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *OauthAuthorizationCodeQuery) w(format string, a ...interface{}) *OauthAuthorizationCodeQuery {
+	q.where += fmt.Sprintf(format, a...)
+	return q
+}
+
+func (q *OauthAuthorizationCodeQuery) Left() *OauthAuthorizationCodeQuery  { return q.w(" ( ") }
+func (q *OauthAuthorizationCodeQuery) Right() *OauthAuthorizationCodeQuery { return q.w(" ) ") }
+func (q *OauthAuthorizationCodeQuery) And() *OauthAuthorizationCodeQuery   { return q.w(" AND ") }
+func (q *OauthAuthorizationCodeQuery) Or() *OauthAuthorizationCodeQuery    { return q.w(" OR ") }
+func (q *OauthAuthorizationCodeQuery) Not() *OauthAuthorizationCodeQuery   { return q.w(" NOT ") }
+
+func (q *OauthAuthorizationCodeQuery) Id_Equal(v uint64) *OauthAuthorizationCodeQuery {
+	return q.w("id='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Id_NotEqual(v uint64) *OauthAuthorizationCodeQuery {
+	return q.w("id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Id_Less(v uint64) *OauthAuthorizationCodeQuery {
+	return q.w("id<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Id_LessEqual(v uint64) *OauthAuthorizationCodeQuery {
+	return q.w("id<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Id_Greater(v uint64) *OauthAuthorizationCodeQuery {
+	return q.w("id>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Id_GreaterEqual(v uint64) *OauthAuthorizationCodeQuery {
+	return q.w("id>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Code_Equal(v string) *OauthAuthorizationCodeQuery {
+	return q.w("code='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Code_NotEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("code<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Code_Less(v string) *OauthAuthorizationCodeQuery {
+	return q.w("code<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Code_LessEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("code<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Code_Greater(v string) *OauthAuthorizationCodeQuery {
+	return q.w("code>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Code_GreaterEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("code>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) ClientId_Equal(v string) *OauthAuthorizationCodeQuery {
+	return q.w("client_id='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) ClientId_NotEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("client_id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) ClientId_Less(v string) *OauthAuthorizationCodeQuery {
+	return q.w("client_id<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) ClientId_LessEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("client_id<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) ClientId_Greater(v string) *OauthAuthorizationCodeQuery {
+	return q.w("client_id>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) ClientId_GreaterEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("client_id>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) UserId_Equal(v string) *OauthAuthorizationCodeQuery {
+	return q.w("user_id='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) UserId_NotEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("user_id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) UserId_Less(v string) *OauthAuthorizationCodeQuery {
+	return q.w("user_id<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) UserId_LessEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("user_id<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) UserId_Greater(v string) *OauthAuthorizationCodeQuery {
+	return q.w("user_id>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) UserId_GreaterEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("user_id>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) RedirectUri_Equal(v string) *OauthAuthorizationCodeQuery {
+	return q.w("redirect_uri='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) RedirectUri_NotEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("redirect_uri<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) RedirectUri_Less(v string) *OauthAuthorizationCodeQuery {
+	return q.w("redirect_uri<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) RedirectUri_LessEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("redirect_uri<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) RedirectUri_Greater(v string) *OauthAuthorizationCodeQuery {
+	return q.w("redirect_uri>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) RedirectUri_GreaterEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("redirect_uri>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Scope_Equal(v string) *OauthAuthorizationCodeQuery {
+	return q.w("scope='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Scope_NotEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("scope<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Scope_Less(v string) *OauthAuthorizationCodeQuery {
+	return q.w("scope<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Scope_LessEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("scope<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Scope_Greater(v string) *OauthAuthorizationCodeQuery {
+	return q.w("scope>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Scope_GreaterEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("scope>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Nonce_Equal(v string) *OauthAuthorizationCodeQuery {
+	return q.w("nonce='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Nonce_NotEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("nonce<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Nonce_Less(v string) *OauthAuthorizationCodeQuery {
+	return q.w("nonce<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Nonce_LessEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("nonce<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Nonce_Greater(v string) *OauthAuthorizationCodeQuery {
+	return q.w("nonce>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) Nonce_GreaterEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("nonce>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) CodeChallenge_Equal(v string) *OauthAuthorizationCodeQuery {
+	return q.w("code_challenge='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) CodeChallenge_NotEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("code_challenge<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) CodeChallenge_Less(v string) *OauthAuthorizationCodeQuery {
+	return q.w("code_challenge<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) CodeChallenge_LessEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("code_challenge<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) CodeChallenge_Greater(v string) *OauthAuthorizationCodeQuery {
+	return q.w("code_challenge>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) CodeChallenge_GreaterEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("code_challenge>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) SessionId_Equal(v string) *OauthAuthorizationCodeQuery {
+	return q.w("session_id='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) SessionId_NotEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("session_id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) SessionId_Less(v string) *OauthAuthorizationCodeQuery {
+	return q.w("session_id<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) SessionId_LessEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("session_id<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) SessionId_Greater(v string) *OauthAuthorizationCodeQuery {
+	return q.w("session_id>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) SessionId_GreaterEqual(v string) *OauthAuthorizationCodeQuery {
+	return q.w("session_id>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) IsUsed_Equal(v int32) *OauthAuthorizationCodeQuery {
+	return q.w("is_used='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) IsUsed_NotEqual(v int32) *OauthAuthorizationCodeQuery {
+	return q.w("is_used<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) IsUsed_Less(v int32) *OauthAuthorizationCodeQuery {
+	return q.w("is_used<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) IsUsed_LessEqual(v int32) *OauthAuthorizationCodeQuery {
+	return q.w("is_used<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) IsUsed_Greater(v int32) *OauthAuthorizationCodeQuery {
+	return q.w("is_used>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) IsUsed_GreaterEqual(v int32) *OauthAuthorizationCodeQuery {
+	return q.w("is_used>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) CreateTime_Equal(v time.Time) *OauthAuthorizationCodeQuery {
+	return q.w("create_time='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) CreateTime_NotEqual(v time.Time) *OauthAuthorizationCodeQuery {
+	return q.w("create_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) CreateTime_Less(v time.Time) *OauthAuthorizationCodeQuery {
+	return q.w("create_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) CreateTime_LessEqual(v time.Time) *OauthAuthorizationCodeQuery {
+	return q.w("create_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) CreateTime_Greater(v time.Time) *OauthAuthorizationCodeQuery {
+	return q.w("create_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) CreateTime_GreaterEqual(v time.Time) *OauthAuthorizationCodeQuery {
+	return q.w("create_time>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) UpdateTime_Equal(v time.Time) *OauthAuthorizationCodeQuery {
+	return q.w("update_time='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) UpdateTime_NotEqual(v time.Time) *OauthAuthorizationCodeQuery {
+	return q.w("update_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) UpdateTime_Less(v time.Time) *OauthAuthorizationCodeQuery {
+	return q.w("update_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) UpdateTime_LessEqual(v time.Time) *OauthAuthorizationCodeQuery {
+	return q.w("update_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) UpdateTime_Greater(v time.Time) *OauthAuthorizationCodeQuery {
+	return q.w("update_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthAuthorizationCodeQuery) UpdateTime_GreaterEqual(v time.Time) *OauthAuthorizationCodeQuery {
+	return q.w("update_time>='" + fmt.Sprint(v) + "'")
+}
+
+type OauthAuthorizationCodeDao struct {
+	logger     *zap.Logger
+	db         *DB
+	insertStmt *wrap.Stmt
+	updateStmt *wrap.Stmt
+	deleteStmt *wrap.Stmt
+}
+
+func NewOauthAuthorizationCodeDao(db *DB) (t *OauthAuthorizationCodeDao, err error) {
+	t = &OauthAuthorizationCodeDao{}
+	t.logger = log.TypedLogger(t)
+	t.db = db
+	err = t.init()
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (dao *OauthAuthorizationCodeDao) init() (err error) {
+	err = dao.prepareInsertStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareUpdateStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareDeleteStmt()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *OauthAuthorizationCodeDao) prepareInsertStmt() (err error) {
+	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO oauth_authorization_code (code,client_id,user_id,redirect_uri,scope,nonce,code_challenge,session_id,is_used) VALUES (?,?,?,?,?,?,?,?,?)")
+	return err
+}
+
+func (dao *OauthAuthorizationCodeDao) prepareUpdateStmt() (err error) {
+	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE oauth_authorization_code SET code=?,client_id=?,user_id=?,redirect_uri=?,scope=?,nonce=?,code_challenge=?,session_id=?,is_used=? WHERE id=?")
+	return err
+}
+
+func (dao *OauthAuthorizationCodeDao) prepareDeleteStmt() (err error) {
+	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM oauth_authorization_code WHERE id=?")
+	return err
+}
+
+func (dao *OauthAuthorizationCodeDao) Insert(ctx context.Context, tx *wrap.Tx, e *OauthAuthorizationCode) (id int64, err error) {
+	stmt := dao.insertStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	result, err := stmt.Exec(ctx, e.Code, e.ClientId, e.UserId, e.RedirectUri, e.Scope, e.Nonce, e.CodeChallenge, e.SessionId, e.IsUsed)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err = result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (dao *OauthAuthorizationCodeDao) Update(ctx context.Context, tx *wrap.Tx, e *OauthAuthorizationCode) (err error) {
+	stmt := dao.updateStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, e.Code, e.ClientId, e.UserId, e.RedirectUri, e.Scope, e.Nonce, e.CodeChallenge, e.SessionId, e.IsUsed, e.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *OauthAuthorizationCodeDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
+	stmt := dao.deleteStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *OauthAuthorizationCodeDao) scanRow(row *wrap.Row) (*OauthAuthorizationCode, error) {
+	e := &OauthAuthorizationCode{}
+	err := row.Scan(&e.Id, &e.Code, &e.ClientId, &e.UserId, &e.RedirectUri, &e.Scope, &e.Nonce, &e.CodeChallenge, &e.SessionId, &e.IsUsed, &e.CreateTime, &e.UpdateTime)
+	if err != nil {
+		if err == wrap.ErrNoRows {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+func (dao *OauthAuthorizationCodeDao) scanRows(rows *wrap.Rows) (list []*OauthAuthorizationCode, err error) {
+	list = make([]*OauthAuthorizationCode, 0)
+	for rows.Next() {
+		e := OauthAuthorizationCode{}
+		err = rows.Scan(&e.Id, &e.Code, &e.ClientId, &e.UserId, &e.RedirectUri, &e.Scope, &e.Nonce, &e.CodeChallenge, &e.SessionId, &e.IsUsed, &e.CreateTime, &e.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &e)
+	}
+	if rows.Err() != nil {
+		err = rows.Err()
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (dao *OauthAuthorizationCodeDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*OauthAuthorizationCode, error) {
+	querySql := "SELECT " + OAUTH_AUTHORIZATION_CODE_ALL_FIELDS_STRING + " FROM oauth_authorization_code " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	return dao.scanRow(row)
+}
+
+func (dao *OauthAuthorizationCodeDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*OauthAuthorizationCode, err error) {
+	querySql := "SELECT " + OAUTH_AUTHORIZATION_CODE_ALL_FIELDS_STRING + " FROM oauth_authorization_code " + query
+	var rows *wrap.Rows
+	if tx == nil {
+		rows, err = dao.db.Query(ctx, querySql)
+	} else {
+		rows, err = tx.Query(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return nil, err
+	}
+
+	return dao.scanRows(rows)
+}
+
+func (dao *OauthAuthorizationCodeDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
+	querySql := "SELECT COUNT(1) FROM oauth_authorization_code " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return 0, err
+	}
+
+	err = row.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (dao *OauthAuthorizationCodeDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
+	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM oauth_authorization_code " + query
+	if tx == nil {
+		return dao.db.Query(ctx, querySql)
+	} else {
+		return tx.Query(ctx, querySql)
+	}
+}
+
+func (dao *OauthAuthorizationCodeDao) GetQuery() *OauthAuthorizationCodeQuery {
+	return NewOauthAuthorizationCodeQuery(dao)
+}
+
+const OAUTH_CLIENT_TABLE_NAME = "oauth_client"
+
+type OAUTH_CLIENT_FIELD string
+
+const OAUTH_CLIENT_FIELD_ID = OAUTH_CLIENT_FIELD("id")
+const OAUTH_CLIENT_FIELD_CLIENT_ID = OAUTH_CLIENT_FIELD("client_id")
+const OAUTH_CLIENT_FIELD_CLIENT_SECRET = OAUTH_CLIENT_FIELD("client_secret")
+const OAUTH_CLIENT_FIELD_CLIENT_NAME = OAUTH_CLIENT_FIELD("client_name")
+const OAUTH_CLIENT_FIELD_REDIRECT_URIS = OAUTH_CLIENT_FIELD("redirect_uris")
+const OAUTH_CLIENT_FIELD_GRANT_TYPES = OAUTH_CLIENT_FIELD("grant_types")
+const OAUTH_CLIENT_FIELD_SCOPE = OAUTH_CLIENT_FIELD("scope")
+const OAUTH_CLIENT_FIELD_CREATE_TIME = OAUTH_CLIENT_FIELD("create_time")
+const OAUTH_CLIENT_FIELD_UPDATE_TIME = OAUTH_CLIENT_FIELD("update_time")
+
+const OAUTH_CLIENT_ALL_FIELDS_STRING = "id,client_id,client_secret,client_name,redirect_uris,grant_types,scope,create_time,update_time"
+
+var OAUTH_CLIENT_ALL_FIELDS = []string{
+	"id",
+	"client_id",
+	"client_secret",
+	"client_name",
+	"redirect_uris",
+	"grant_types",
+	"scope",
+	"create_time",
+	"update_time",
+}
+
+type OauthClient struct {
+	Id           uint64 //size=20
+	ClientId     string //size=64
+	ClientSecret string //size=128
+	ClientName   string //size=128
+	RedirectUris string //size=2048
+	GrantTypes   string //size=256
+	Scope        string //size=256
+	CreateTime   time.Time
+	UpdateTime   time.Time
+}
+
+type OauthClientQuery struct {
+	BaseQuery
+	dao *OauthClientDao
+}
+
+func NewOauthClientQuery(dao *OauthClientDao) *OauthClientQuery {
+	q := &OauthClientQuery{}
+	q.dao = dao
+
+	return q
+}
+
+func (q *OauthClientQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*OauthClient, error) {
+	return q.dao.QueryOne(ctx, tx, q.buildQueryString())
+}
+
+func (q *OauthClientQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*OauthClient, err error) {
+	return q.dao.QueryList(ctx, tx, q.buildQueryString())
+}
+
+func (q *OauthClientQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
+	return q.dao.QueryCount(ctx, tx, q.buildQueryString())
+}
+
+func (q *OauthClientQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
+	return q.dao.QueryGroupBy(ctx, tx, q.groupByFields, q.buildQueryString())
+}
+
+func (q *OauthClientQuery) ForUpdate() *OauthClientQuery {
+	q.forUpdate = true
+	return q
+}
+
+func (q *OauthClientQuery) ForShare() *OauthClientQuery {
+	q.forShare = true
+	return q
+}
+
+func (q *OauthClientQuery) GroupBy(fields ...OAUTH_CLIENT_FIELD) *OauthClientQuery {
+	q.groupByFields = make([]string, len(fields))
+	for i, v := range fields {
+		q.groupByFields[i] = string(v)
+	}
+	return q
+}
+
+func (q *OauthClientQuery) Limit(startIncluded int64, count int64) *OauthClientQuery {
+	q.limit = fmt.Sprintf(" limit %d,%d", startIncluded, count)
+	return q
+}
+
+func (q *OauthClientQuery) OrderBy(fieldName OAUTH_CLIENT_FIELD, asc bool) *OauthClientQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += string(fieldName) + " "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *OauthClientQuery) OrderByGroupCount(asc bool) *OauthClientQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += "count(1) "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *OauthClientQuery) w(format string, a ...interface{}) *OauthClientQuery {
+	q.where += fmt.Sprintf(format, a...)
+	return q
+}
+
+func (q *OauthClientQuery) Left() *OauthClientQuery  { return q.w(" ( ") }
+func (q *OauthClientQuery) Right() *OauthClientQuery { return q.w(" ) ") }
+func (q *OauthClientQuery) And() *OauthClientQuery   { return q.w(" AND ") }
+func (q *OauthClientQuery) Or() *OauthClientQuery    { return q.w(" OR ") }
+func (q *OauthClientQuery) Not() *OauthClientQuery   { return q.w(" NOT ") }
+
+func (q *OauthClientQuery) Id_Equal(v uint64) *OauthClientQuery {
+	return q.w("id='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) Id_NotEqual(v uint64) *OauthClientQuery {
+	return q.w("id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) Id_Less(v uint64) *OauthClientQuery { return q.w("id<'" + fmt.Sprint(v) + "'") }
+func (q *OauthClientQuery) Id_LessEqual(v uint64) *OauthClientQuery {
+	return q.w("id<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) Id_Greater(v uint64) *OauthClientQuery {
+	return q.w("id>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) Id_GreaterEqual(v uint64) *OauthClientQuery {
+	return q.w("id>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) ClientId_Equal(v string) *OauthClientQuery {
+	return q.w("client_id='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) ClientId_NotEqual(v string) *OauthClientQuery {
+	return q.w("client_id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) ClientId_Less(v string) *OauthClientQuery {
+	return q.w("client_id<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) ClientId_LessEqual(v string) *OauthClientQuery {
+	return q.w("client_id<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) ClientId_Greater(v string) *OauthClientQuery {
+	return q.w("client_id>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) ClientId_GreaterEqual(v string) *OauthClientQuery {
+	return q.w("client_id>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) ClientSecret_Equal(v string) *OauthClientQuery {
+	return q.w("client_secret='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) ClientSecret_NotEqual(v string) *OauthClientQuery {
+	return q.w("client_secret<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) ClientSecret_Less(v string) *OauthClientQuery {
+	return q.w("client_secret<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) ClientSecret_LessEqual(v string) *OauthClientQuery {
+	return q.w("client_secret<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) ClientSecret_Greater(v string) *OauthClientQuery {
+	return q.w("client_secret>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) ClientSecret_GreaterEqual(v string) *OauthClientQuery {
+	return q.w("client_secret>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) ClientName_Equal(v string) *OauthClientQuery {
+	return q.w("client_name='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) ClientName_NotEqual(v string) *OauthClientQuery {
+	return q.w("client_name<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) ClientName_Less(v string) *OauthClientQuery {
+	return q.w("client_name<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) ClientName_LessEqual(v string) *OauthClientQuery {
+	return q.w("client_name<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) ClientName_Greater(v string) *OauthClientQuery {
+	return q.w("client_name>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) ClientName_GreaterEqual(v string) *OauthClientQuery {
+	return q.w("client_name>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) RedirectUris_Equal(v string) *OauthClientQuery {
+	return q.w("redirect_uris='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) RedirectUris_NotEqual(v string) *OauthClientQuery {
+	return q.w("redirect_uris<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) RedirectUris_Less(v string) *OauthClientQuery {
+	return q.w("redirect_uris<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) RedirectUris_LessEqual(v string) *OauthClientQuery {
+	return q.w("redirect_uris<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) RedirectUris_Greater(v string) *OauthClientQuery {
+	return q.w("redirect_uris>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) RedirectUris_GreaterEqual(v string) *OauthClientQuery {
+	return q.w("redirect_uris>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) GrantTypes_Equal(v string) *OauthClientQuery {
+	return q.w("grant_types='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) GrantTypes_NotEqual(v string) *OauthClientQuery {
+	return q.w("grant_types<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) GrantTypes_Less(v string) *OauthClientQuery {
+	return q.w("grant_types<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) GrantTypes_LessEqual(v string) *OauthClientQuery {
+	return q.w("grant_types<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) GrantTypes_Greater(v string) *OauthClientQuery {
+	return q.w("grant_types>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) GrantTypes_GreaterEqual(v string) *OauthClientQuery {
+	return q.w("grant_types>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) Scope_Equal(v string) *OauthClientQuery {
+	return q.w("scope='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) Scope_NotEqual(v string) *OauthClientQuery {
+	return q.w("scope<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) Scope_Less(v string) *OauthClientQuery {
+	return q.w("scope<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) Scope_LessEqual(v string) *OauthClientQuery {
+	return q.w("scope<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) Scope_Greater(v string) *OauthClientQuery {
+	return q.w("scope>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) Scope_GreaterEqual(v string) *OauthClientQuery {
+	return q.w("scope>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) CreateTime_Equal(v time.Time) *OauthClientQuery {
+	return q.w("create_time='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) CreateTime_NotEqual(v time.Time) *OauthClientQuery {
+	return q.w("create_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) CreateTime_Less(v time.Time) *OauthClientQuery {
+	return q.w("create_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) CreateTime_LessEqual(v time.Time) *OauthClientQuery {
+	return q.w("create_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) CreateTime_Greater(v time.Time) *OauthClientQuery {
+	return q.w("create_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) CreateTime_GreaterEqual(v time.Time) *OauthClientQuery {
+	return q.w("create_time>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) UpdateTime_Equal(v time.Time) *OauthClientQuery {
+	return q.w("update_time='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) UpdateTime_NotEqual(v time.Time) *OauthClientQuery {
+	return q.w("update_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) UpdateTime_Less(v time.Time) *OauthClientQuery {
+	return q.w("update_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) UpdateTime_LessEqual(v time.Time) *OauthClientQuery {
+	return q.w("update_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) UpdateTime_Greater(v time.Time) *OauthClientQuery {
+	return q.w("update_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthClientQuery) UpdateTime_GreaterEqual(v time.Time) *OauthClientQuery {
+	return q.w("update_time>='" + fmt.Sprint(v) + "'")
+}
+
+type OauthClientDao struct {
+	logger     *zap.Logger
+	db         *DB
+	insertStmt *wrap.Stmt
+	updateStmt *wrap.Stmt
+	deleteStmt *wrap.Stmt
+}
+
+func NewOauthClientDao(db *DB) (t *OauthClientDao, err error) {
+	t = &OauthClientDao{}
+	t.logger = log.TypedLogger(t)
+	t.db = db
+	err = t.init()
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (dao *OauthClientDao) init() (err error) {
+	err = dao.prepareInsertStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareUpdateStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareDeleteStmt()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *OauthClientDao) prepareInsertStmt() (err error) {
+	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO oauth_client (client_id,client_secret,client_name,redirect_uris,grant_types,scope) VALUES (?,?,?,?,?,?)")
+	return err
+}
+
+func (dao *OauthClientDao) prepareUpdateStmt() (err error) {
+	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE oauth_client SET client_id=?,client_secret=?,client_name=?,redirect_uris=?,grant_types=?,scope=? WHERE id=?")
+	return err
+}
+
+func (dao *OauthClientDao) prepareDeleteStmt() (err error) {
+	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM oauth_client WHERE id=?")
+	return err
+}
+
+func (dao *OauthClientDao) Insert(ctx context.Context, tx *wrap.Tx, e *OauthClient) (id int64, err error) {
+	stmt := dao.insertStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	result, err := stmt.Exec(ctx, e.ClientId, e.ClientSecret, e.ClientName, e.RedirectUris, e.GrantTypes, e.Scope)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err = result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (dao *OauthClientDao) Update(ctx context.Context, tx *wrap.Tx, e *OauthClient) (err error) {
+	stmt := dao.updateStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, e.ClientId, e.ClientSecret, e.ClientName, e.RedirectUris, e.GrantTypes, e.Scope, e.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *OauthClientDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
+	stmt := dao.deleteStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *OauthClientDao) scanRow(row *wrap.Row) (*OauthClient, error) {
+	e := &OauthClient{}
+	err := row.Scan(&e.Id, &e.ClientId, &e.ClientSecret, &e.ClientName, &e.RedirectUris, &e.GrantTypes, &e.Scope, &e.CreateTime, &e.UpdateTime)
+	if err != nil {
+		if err == wrap.ErrNoRows {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+func (dao *OauthClientDao) scanRows(rows *wrap.Rows) (list []*OauthClient, err error) {
+	list = make([]*OauthClient, 0)
+	for rows.Next() {
+		e := OauthClient{}
+		err = rows.Scan(&e.Id, &e.ClientId, &e.ClientSecret, &e.ClientName, &e.RedirectUris, &e.GrantTypes, &e.Scope, &e.CreateTime, &e.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &e)
+	}
+	if rows.Err() != nil {
+		err = rows.Err()
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (dao *OauthClientDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*OauthClient, error) {
+	querySql := "SELECT " + OAUTH_CLIENT_ALL_FIELDS_STRING + " FROM oauth_client " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	return dao.scanRow(row)
+}
+
+func (dao *OauthClientDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*OauthClient, err error) {
+	querySql := "SELECT " + OAUTH_CLIENT_ALL_FIELDS_STRING + " FROM oauth_client " + query
+	var rows *wrap.Rows
+	if tx == nil {
+		rows, err = dao.db.Query(ctx, querySql)
+	} else {
+		rows, err = tx.Query(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return nil, err
+	}
+
+	return dao.scanRows(rows)
+}
+
+func (dao *OauthClientDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
+	querySql := "SELECT COUNT(1) FROM oauth_client " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return 0, err
+	}
+
+	err = row.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (dao *OauthClientDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
+	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM oauth_client " + query
+	if tx == nil {
+		return dao.db.Query(ctx, querySql)
+	} else {
+		return tx.Query(ctx, querySql)
+	}
+}
+
+func (dao *OauthClientDao) GetQuery() *OauthClientQuery {
+	return NewOauthClientQuery(dao)
+}
+
+const OAUTH_STATE_TABLE_NAME = "oauth_state"
+
+type OAUTH_STATE_FIELD string
+
+const OAUTH_STATE_FIELD_ID = OAUTH_STATE_FIELD("id")
+const OAUTH_STATE_FIELD_OAUTH_STATE = OAUTH_STATE_FIELD("oauth_state")
+const OAUTH_STATE_FIELD_IS_USED = OAUTH_STATE_FIELD("is_used")
+const OAUTH_STATE_FIELD_USER_AGENT = OAUTH_STATE_FIELD("user_agent")
+const OAUTH_STATE_FIELD_CREATE_TIME = OAUTH_STATE_FIELD("create_time")
+const OAUTH_STATE_FIELD_UPDATE_TIME = OAUTH_STATE_FIELD("update_time")
+
+const OAUTH_STATE_ALL_FIELDS_STRING = "id,oauth_state,is_used,user_agent,create_time,update_time"
+
+var OAUTH_STATE_ALL_FIELDS = []string{
+	"id",
+	"oauth_state",
+	"is_used",
+	"user_agent",
+	"create_time",
+	"update_time",
+}
+
+type OauthState struct {
+	Id         uint64 //size=20
+	OauthState string //size=128
+	IsUsed     int32  //size=1
+	UserAgent  string //size=256
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+type OauthStateQuery struct {
+	BaseQuery
+	dao *OauthStateDao
 }
 
 func NewOauthStateQuery(dao *OauthStateDao) *OauthStateQuery {
@@ -1376,33 +2981,3261 @@ func NewOauthStateQuery(dao *OauthStateDao) *OauthStateQuery {
 	return q
 }
 
-func (q *OauthStateQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*OauthState, error) {
+func (q *OauthStateQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*OauthState, error) {
+	return q.dao.QueryOne(ctx, tx, q.buildQueryString())
+}
+
+func (q *OauthStateQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*OauthState, err error) {
+	return q.dao.QueryList(ctx, tx, q.buildQueryString())
+}
+
+func (q *OauthStateQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
+	return q.dao.QueryCount(ctx, tx, q.buildQueryString())
+}
+
+func (q *OauthStateQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
+	return q.dao.QueryGroupBy(ctx, tx, q.groupByFields, q.buildQueryString())
+}
+
+func (q *OauthStateQuery) ForUpdate() *OauthStateQuery {
+	q.forUpdate = true
+	return q
+}
+
+func (q *OauthStateQuery) ForShare() *OauthStateQuery {
+	q.forShare = true
+	return q
+}
+
+func (q *OauthStateQuery) GroupBy(fields ...OAUTH_STATE_FIELD) *OauthStateQuery {
+	q.groupByFields = make([]string, len(fields))
+	for i, v := range fields {
+		q.groupByFields[i] = string(v)
+	}
+	return q
+}
+
+func (q *OauthStateQuery) Limit(startIncluded int64, count int64) *OauthStateQuery {
+	q.limit = fmt.Sprintf(" limit %d,%d", startIncluded, count)
+	return q
+}
+
+func (q *OauthStateQuery) OrderBy(fieldName OAUTH_STATE_FIELD, asc bool) *OauthStateQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += string(fieldName) + " "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *OauthStateQuery) OrderByGroupCount(asc bool) *OauthStateQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += "count(1) "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *OauthStateQuery) w(format string, a ...interface{}) *OauthStateQuery {
+	q.where += fmt.Sprintf(format, a...)
+	return q
+}
+
+func (q *OauthStateQuery) Left() *OauthStateQuery  { return q.w(" ( ") }
+func (q *OauthStateQuery) Right() *OauthStateQuery { return q.w(" ) ") }
+func (q *OauthStateQuery) And() *OauthStateQuery   { return q.w(" AND ") }
+func (q *OauthStateQuery) Or() *OauthStateQuery    { return q.w(" OR ") }
+func (q *OauthStateQuery) Not() *OauthStateQuery   { return q.w(" NOT ") }
+
+func (q *OauthStateQuery) Id_Equal(v uint64) *OauthStateQuery { return q.w("id='" + fmt.Sprint(v) + "'") }
+func (q *OauthStateQuery) Id_NotEqual(v uint64) *OauthStateQuery {
+	return q.w("id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) Id_Less(v uint64) *OauthStateQuery { return q.w("id<'" + fmt.Sprint(v) + "'") }
+func (q *OauthStateQuery) Id_LessEqual(v uint64) *OauthStateQuery {
+	return q.w("id<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) Id_Greater(v uint64) *OauthStateQuery {
+	return q.w("id>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) Id_GreaterEqual(v uint64) *OauthStateQuery {
+	return q.w("id>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) OauthState_Equal(v string) *OauthStateQuery {
+	return q.w("oauth_state='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) OauthState_NotEqual(v string) *OauthStateQuery {
+	return q.w("oauth_state<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) OauthState_Less(v string) *OauthStateQuery {
+	return q.w("oauth_state<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) OauthState_LessEqual(v string) *OauthStateQuery {
+	return q.w("oauth_state<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) OauthState_Greater(v string) *OauthStateQuery {
+	return q.w("oauth_state>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) OauthState_GreaterEqual(v string) *OauthStateQuery {
+	return q.w("oauth_state>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) IsUsed_Equal(v int32) *OauthStateQuery {
+	return q.w("is_used='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) IsUsed_NotEqual(v int32) *OauthStateQuery {
+	return q.w("is_used<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) IsUsed_Less(v int32) *OauthStateQuery {
+	return q.w("is_used<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) IsUsed_LessEqual(v int32) *OauthStateQuery {
+	return q.w("is_used<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) IsUsed_Greater(v int32) *OauthStateQuery {
+	return q.w("is_used>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) IsUsed_GreaterEqual(v int32) *OauthStateQuery {
+	return q.w("is_used>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) UserAgent_Equal(v string) *OauthStateQuery {
+	return q.w("user_agent='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) UserAgent_NotEqual(v string) *OauthStateQuery {
+	return q.w("user_agent<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) UserAgent_Less(v string) *OauthStateQuery {
+	return q.w("user_agent<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) UserAgent_LessEqual(v string) *OauthStateQuery {
+	return q.w("user_agent<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) UserAgent_Greater(v string) *OauthStateQuery {
+	return q.w("user_agent>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) UserAgent_GreaterEqual(v string) *OauthStateQuery {
+	return q.w("user_agent>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) CreateTime_Equal(v time.Time) *OauthStateQuery {
+	return q.w("create_time='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) CreateTime_NotEqual(v time.Time) *OauthStateQuery {
+	return q.w("create_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) CreateTime_Less(v time.Time) *OauthStateQuery {
+	return q.w("create_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) CreateTime_LessEqual(v time.Time) *OauthStateQuery {
+	return q.w("create_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) CreateTime_Greater(v time.Time) *OauthStateQuery {
+	return q.w("create_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) CreateTime_GreaterEqual(v time.Time) *OauthStateQuery {
+	return q.w("create_time>='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) UpdateTime_Equal(v time.Time) *OauthStateQuery {
+	return q.w("update_time='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) UpdateTime_NotEqual(v time.Time) *OauthStateQuery {
+	return q.w("update_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) UpdateTime_Less(v time.Time) *OauthStateQuery {
+	return q.w("update_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) UpdateTime_LessEqual(v time.Time) *OauthStateQuery {
+	return q.w("update_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) UpdateTime_Greater(v time.Time) *OauthStateQuery {
+	return q.w("update_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *OauthStateQuery) UpdateTime_GreaterEqual(v time.Time) *OauthStateQuery {
+	return q.w("update_time>='" + fmt.Sprint(v) + "'")
+}
+
+type OauthStateDao struct {
+	logger     *zap.Logger
+	db         *DB
+	insertStmt *wrap.Stmt
+	updateStmt *wrap.Stmt
+	deleteStmt *wrap.Stmt
+}
+
+func NewOauthStateDao(db *DB) (t *OauthStateDao, err error) {
+	t = &OauthStateDao{}
+	t.logger = log.TypedLogger(t)
+	t.db = db
+	err = t.init()
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (dao *OauthStateDao) init() (err error) {
+	err = dao.prepareInsertStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareUpdateStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareDeleteStmt()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *OauthStateDao) prepareInsertStmt() (err error) {
+	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO oauth_state (oauth_state,is_used,user_agent) VALUES (?,?,?)")
+	return err
+}
+
+func (dao *OauthStateDao) prepareUpdateStmt() (err error) {
+	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE oauth_state SET oauth_state=?,is_used=?,user_agent=? WHERE id=?")
+	return err
+}
+
+func (dao *OauthStateDao) prepareDeleteStmt() (err error) {
+	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM oauth_state WHERE id=?")
+	return err
+}
+
+func (dao *OauthStateDao) Insert(ctx context.Context, tx *wrap.Tx, e *OauthState) (id int64, err error) {
+	stmt := dao.insertStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	result, err := stmt.Exec(ctx, e.OauthState, e.IsUsed, e.UserAgent)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err = result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (dao *OauthStateDao) Update(ctx context.Context, tx *wrap.Tx, e *OauthState) (err error) {
+	stmt := dao.updateStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, e.OauthState, e.IsUsed, e.UserAgent, e.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *OauthStateDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
+	stmt := dao.deleteStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *OauthStateDao) scanRow(row *wrap.Row) (*OauthState, error) {
+	e := &OauthState{}
+	err := row.Scan(&e.Id, &e.OauthState, &e.IsUsed, &e.UserAgent, &e.CreateTime, &e.UpdateTime)
+	if err != nil {
+		if err == wrap.ErrNoRows {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+func (dao *OauthStateDao) scanRows(rows *wrap.Rows) (list []*OauthState, err error) {
+	list = make([]*OauthState, 0)
+	for rows.Next() {
+		e := OauthState{}
+		err = rows.Scan(&e.Id, &e.OauthState, &e.IsUsed, &e.UserAgent, &e.CreateTime, &e.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &e)
+	}
+	if rows.Err() != nil {
+		err = rows.Err()
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (dao *OauthStateDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*OauthState, error) {
+	querySql := "SELECT " + OAUTH_STATE_ALL_FIELDS_STRING + " FROM oauth_state " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	return dao.scanRow(row)
+}
+
+func (dao *OauthStateDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*OauthState, err error) {
+	querySql := "SELECT " + OAUTH_STATE_ALL_FIELDS_STRING + " FROM oauth_state " + query
+	var rows *wrap.Rows
+	if tx == nil {
+		rows, err = dao.db.Query(ctx, querySql)
+	} else {
+		rows, err = tx.Query(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return nil, err
+	}
+
+	return dao.scanRows(rows)
+}
+
+func (dao *OauthStateDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
+	querySql := "SELECT COUNT(1) FROM oauth_state " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return 0, err
+	}
+
+	err = row.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (dao *OauthStateDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
+	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM oauth_state " + query
+	if tx == nil {
+		return dao.db.Query(ctx, querySql)
+	} else {
+		return tx.Query(ctx, querySql)
+	}
+}
+
+func (dao *OauthStateDao) GetQuery() *OauthStateQuery {
+	return NewOauthStateQuery(dao)
+}
+
+const PASSWORD_ACCOUNT_TABLE_NAME = "password_account"
+
+type PASSWORD_ACCOUNT_FIELD string
+
+const PASSWORD_ACCOUNT_FIELD_ID = PASSWORD_ACCOUNT_FIELD("id")
+const PASSWORD_ACCOUNT_FIELD_USER_ID = PASSWORD_ACCOUNT_FIELD("user_id")
+const PASSWORD_ACCOUNT_FIELD_PASSWORD_HASH = PASSWORD_ACCOUNT_FIELD("password_hash")
+const PASSWORD_ACCOUNT_FIELD_FAILED_COUNT = PASSWORD_ACCOUNT_FIELD("failed_count")
+const PASSWORD_ACCOUNT_FIELD_CREATE_TIME = PASSWORD_ACCOUNT_FIELD("create_time")
+const PASSWORD_ACCOUNT_FIELD_UPDATE_TIME = PASSWORD_ACCOUNT_FIELD("update_time")
+
+const PASSWORD_ACCOUNT_ALL_FIELDS_STRING = "id,user_id,password_hash,failed_count,create_time,update_time"
+
+var PASSWORD_ACCOUNT_ALL_FIELDS = []string{
+	"id",
+	"user_id",
+	"password_hash",
+	"failed_count",
+	"create_time",
+	"update_time",
+}
+
+type PasswordAccount struct {
+	Id           uint64 //size=20
+	UserId       string //size=32
+	PasswordHash string //size=256
+	FailedCount  int32  //size=11
+	CreateTime   time.Time
+	UpdateTime   time.Time
+}
+
+type PasswordAccountQuery struct {
+	BaseQuery
+	dao *PasswordAccountDao
+}
+
+func NewPasswordAccountQuery(dao *PasswordAccountDao) *PasswordAccountQuery {
+	q := &PasswordAccountQuery{}
+	q.dao = dao
+
+	return q
+}
+
+func (q *PasswordAccountQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*PasswordAccount, error) {
+	return q.dao.QueryOne(ctx, tx, q.buildQueryString())
+}
+
+func (q *PasswordAccountQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*PasswordAccount, err error) {
+	return q.dao.QueryList(ctx, tx, q.buildQueryString())
+}
+
+func (q *PasswordAccountQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
+	return q.dao.QueryCount(ctx, tx, q.buildQueryString())
+}
+
+func (q *PasswordAccountQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
+	return q.dao.QueryGroupBy(ctx, tx, q.groupByFields, q.buildQueryString())
+}
+
+func (q *PasswordAccountQuery) ForUpdate() *PasswordAccountQuery {
+	q.forUpdate = true
+	return q
+}
+
+func (q *PasswordAccountQuery) ForShare() *PasswordAccountQuery {
+	q.forShare = true
+	return q
+}
+
+func (q *PasswordAccountQuery) GroupBy(fields ...PASSWORD_ACCOUNT_FIELD) *PasswordAccountQuery {
+	q.groupByFields = make([]string, len(fields))
+	for i, v := range fields {
+		q.groupByFields[i] = string(v)
+	}
+	return q
+}
+
+func (q *PasswordAccountQuery) Limit(startIncluded int64, count int64) *PasswordAccountQuery {
+	q.limit = fmt.Sprintf(" limit %d,%d", startIncluded, count)
+	return q
+}
+
+func (q *PasswordAccountQuery) OrderBy(fieldName PASSWORD_ACCOUNT_FIELD, asc bool) *PasswordAccountQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += string(fieldName) + " "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *PasswordAccountQuery) OrderByGroupCount(asc bool) *PasswordAccountQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += "count(1) "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *PasswordAccountQuery) w(format string, a ...interface{}) *PasswordAccountQuery {
+	q.where += fmt.Sprintf(format, a...)
+	return q
+}
+
+func (q *PasswordAccountQuery) Left() *PasswordAccountQuery  { return q.w(" ( ") }
+func (q *PasswordAccountQuery) Right() *PasswordAccountQuery { return q.w(" ) ") }
+func (q *PasswordAccountQuery) And() *PasswordAccountQuery   { return q.w(" AND ") }
+func (q *PasswordAccountQuery) Or() *PasswordAccountQuery    { return q.w(" OR ") }
+func (q *PasswordAccountQuery) Not() *PasswordAccountQuery   { return q.w(" NOT ") }
+
+func (q *PasswordAccountQuery) Id_Equal(v uint64) *PasswordAccountQuery {
+	return q.w("id='" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) Id_NotEqual(v uint64) *PasswordAccountQuery {
+	return q.w("id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) Id_Less(v uint64) *PasswordAccountQuery {
+	return q.w("id<'" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) Id_LessEqual(v uint64) *PasswordAccountQuery {
+	return q.w("id<='" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) Id_Greater(v uint64) *PasswordAccountQuery {
+	return q.w("id>'" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) Id_GreaterEqual(v uint64) *PasswordAccountQuery {
+	return q.w("id>='" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) UserId_Equal(v string) *PasswordAccountQuery {
+	return q.w("user_id='" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) UserId_NotEqual(v string) *PasswordAccountQuery {
+	return q.w("user_id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) UserId_Less(v string) *PasswordAccountQuery {
+	return q.w("user_id<'" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) UserId_LessEqual(v string) *PasswordAccountQuery {
+	return q.w("user_id<='" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) UserId_Greater(v string) *PasswordAccountQuery {
+	return q.w("user_id>'" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) UserId_GreaterEqual(v string) *PasswordAccountQuery {
+	return q.w("user_id>='" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) PasswordHash_Equal(v string) *PasswordAccountQuery {
+	return q.w("password_hash='" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) PasswordHash_NotEqual(v string) *PasswordAccountQuery {
+	return q.w("password_hash<>'" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) PasswordHash_Less(v string) *PasswordAccountQuery {
+	return q.w("password_hash<'" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) PasswordHash_LessEqual(v string) *PasswordAccountQuery {
+	return q.w("password_hash<='" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) PasswordHash_Greater(v string) *PasswordAccountQuery {
+	return q.w("password_hash>'" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) PasswordHash_GreaterEqual(v string) *PasswordAccountQuery {
+	return q.w("password_hash>='" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) FailedCount_Equal(v int32) *PasswordAccountQuery {
+	return q.w("failed_count='" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) FailedCount_NotEqual(v int32) *PasswordAccountQuery {
+	return q.w("failed_count<>'" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) FailedCount_Less(v int32) *PasswordAccountQuery {
+	return q.w("failed_count<'" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) FailedCount_LessEqual(v int32) *PasswordAccountQuery {
+	return q.w("failed_count<='" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) FailedCount_Greater(v int32) *PasswordAccountQuery {
+	return q.w("failed_count>'" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) FailedCount_GreaterEqual(v int32) *PasswordAccountQuery {
+	return q.w("failed_count>='" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) CreateTime_Equal(v time.Time) *PasswordAccountQuery {
+	return q.w("create_time='" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) CreateTime_NotEqual(v time.Time) *PasswordAccountQuery {
+	return q.w("create_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) CreateTime_Less(v time.Time) *PasswordAccountQuery {
+	return q.w("create_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) CreateTime_LessEqual(v time.Time) *PasswordAccountQuery {
+	return q.w("create_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) CreateTime_Greater(v time.Time) *PasswordAccountQuery {
+	return q.w("create_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) CreateTime_GreaterEqual(v time.Time) *PasswordAccountQuery {
+	return q.w("create_time>='" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) UpdateTime_Equal(v time.Time) *PasswordAccountQuery {
+	return q.w("update_time='" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) UpdateTime_NotEqual(v time.Time) *PasswordAccountQuery {
+	return q.w("update_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) UpdateTime_Less(v time.Time) *PasswordAccountQuery {
+	return q.w("update_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) UpdateTime_LessEqual(v time.Time) *PasswordAccountQuery {
+	return q.w("update_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) UpdateTime_Greater(v time.Time) *PasswordAccountQuery {
+	return q.w("update_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *PasswordAccountQuery) UpdateTime_GreaterEqual(v time.Time) *PasswordAccountQuery {
+	return q.w("update_time>='" + fmt.Sprint(v) + "'")
+}
+
+type PasswordAccountDao struct {
+	logger     *zap.Logger
+	db         *DB
+	insertStmt *wrap.Stmt
+	updateStmt *wrap.Stmt
+	deleteStmt *wrap.Stmt
+}
+
+func NewPasswordAccountDao(db *DB) (t *PasswordAccountDao, err error) {
+	t = &PasswordAccountDao{}
+	t.logger = log.TypedLogger(t)
+	t.db = db
+	err = t.init()
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (dao *PasswordAccountDao) init() (err error) {
+	err = dao.prepareInsertStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareUpdateStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareDeleteStmt()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *PasswordAccountDao) prepareInsertStmt() (err error) {
+	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO password_account (user_id,password_hash,failed_count) VALUES (?,?,?)")
+	return err
+}
+
+func (dao *PasswordAccountDao) prepareUpdateStmt() (err error) {
+	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE password_account SET user_id=?,password_hash=?,failed_count=? WHERE id=?")
+	return err
+}
+
+func (dao *PasswordAccountDao) prepareDeleteStmt() (err error) {
+	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM password_account WHERE id=?")
+	return err
+}
+
+func (dao *PasswordAccountDao) Insert(ctx context.Context, tx *wrap.Tx, e *PasswordAccount) (id int64, err error) {
+	stmt := dao.insertStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	result, err := stmt.Exec(ctx, e.UserId, e.PasswordHash, e.FailedCount)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err = result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (dao *PasswordAccountDao) Update(ctx context.Context, tx *wrap.Tx, e *PasswordAccount) (err error) {
+	stmt := dao.updateStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, e.UserId, e.PasswordHash, e.FailedCount, e.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *PasswordAccountDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
+	stmt := dao.deleteStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *PasswordAccountDao) scanRow(row *wrap.Row) (*PasswordAccount, error) {
+	e := &PasswordAccount{}
+	err := row.Scan(&e.Id, &e.UserId, &e.PasswordHash, &e.FailedCount, &e.CreateTime, &e.UpdateTime)
+	if err != nil {
+		if err == wrap.ErrNoRows {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+func (dao *PasswordAccountDao) scanRows(rows *wrap.Rows) (list []*PasswordAccount, err error) {
+	list = make([]*PasswordAccount, 0)
+	for rows.Next() {
+		e := PasswordAccount{}
+		err = rows.Scan(&e.Id, &e.UserId, &e.PasswordHash, &e.FailedCount, &e.CreateTime, &e.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &e)
+	}
+	if rows.Err() != nil {
+		err = rows.Err()
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (dao *PasswordAccountDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*PasswordAccount, error) {
+	querySql := "SELECT " + PASSWORD_ACCOUNT_ALL_FIELDS_STRING + " FROM password_account " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	return dao.scanRow(row)
+}
+
+func (dao *PasswordAccountDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*PasswordAccount, err error) {
+	querySql := "SELECT " + PASSWORD_ACCOUNT_ALL_FIELDS_STRING + " FROM password_account " + query
+	var rows *wrap.Rows
+	if tx == nil {
+		rows, err = dao.db.Query(ctx, querySql)
+	} else {
+		rows, err = tx.Query(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return nil, err
+	}
+
+	return dao.scanRows(rows)
+}
+
+func (dao *PasswordAccountDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
+	querySql := "SELECT COUNT(1) FROM password_account " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return 0, err
+	}
+
+	err = row.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (dao *PasswordAccountDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
+	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM password_account " + query
+	if tx == nil {
+		return dao.db.Query(ctx, querySql)
+	} else {
+		return tx.Query(ctx, querySql)
+	}
+}
+
+func (dao *PasswordAccountDao) GetQuery() *PasswordAccountQuery {
+	return NewPasswordAccountQuery(dao)
+}
+
+const PHONE_ACCOUNT_TABLE_NAME = "phone_account"
+
+type PHONE_ACCOUNT_FIELD string
+
+const PHONE_ACCOUNT_FIELD_ID = PHONE_ACCOUNT_FIELD("id")
+const PHONE_ACCOUNT_FIELD_USER_ID = PHONE_ACCOUNT_FIELD("user_id")
+const PHONE_ACCOUNT_FIELD_PHONE_NUMBER = PHONE_ACCOUNT_FIELD("phone_number")
+const PHONE_ACCOUNT_FIELD_CREATE_TIME = PHONE_ACCOUNT_FIELD("create_time")
+const PHONE_ACCOUNT_FIELD_UPDATE_TIME = PHONE_ACCOUNT_FIELD("update_time")
+
+const PHONE_ACCOUNT_ALL_FIELDS_STRING = "id,user_id,phone_number,create_time,update_time"
+
+var PHONE_ACCOUNT_ALL_FIELDS = []string{
+	"id",
+	"user_id",
+	"phone_number",
+	"create_time",
+	"update_time",
+}
+
+type PhoneAccount struct {
+	Id          uint64 //size=20
+	UserId      string //size=32
+	PhoneNumber string //size=32
+	CreateTime  time.Time
+	UpdateTime  time.Time
+}
+
+type PhoneAccountQuery struct {
+	BaseQuery
+	dao *PhoneAccountDao
+}
+
+func NewPhoneAccountQuery(dao *PhoneAccountDao) *PhoneAccountQuery {
+	q := &PhoneAccountQuery{}
+	q.dao = dao
+
+	return q
+}
+
+func (q *PhoneAccountQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*PhoneAccount, error) {
+	return q.dao.QueryOne(ctx, tx, q.buildQueryString())
+}
+
+func (q *PhoneAccountQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*PhoneAccount, err error) {
+	return q.dao.QueryList(ctx, tx, q.buildQueryString())
+}
+
+func (q *PhoneAccountQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
+	return q.dao.QueryCount(ctx, tx, q.buildQueryString())
+}
+
+func (q *PhoneAccountQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
+	return q.dao.QueryGroupBy(ctx, tx, q.groupByFields, q.buildQueryString())
+}
+
+func (q *PhoneAccountQuery) ForUpdate() *PhoneAccountQuery {
+	q.forUpdate = true
+	return q
+}
+
+func (q *PhoneAccountQuery) ForShare() *PhoneAccountQuery {
+	q.forShare = true
+	return q
+}
+
+func (q *PhoneAccountQuery) GroupBy(fields ...PHONE_ACCOUNT_FIELD) *PhoneAccountQuery {
+	q.groupByFields = make([]string, len(fields))
+	for i, v := range fields {
+		q.groupByFields[i] = string(v)
+	}
+	return q
+}
+
+func (q *PhoneAccountQuery) Limit(startIncluded int64, count int64) *PhoneAccountQuery {
+	q.limit = fmt.Sprintf(" limit %d,%d", startIncluded, count)
+	return q
+}
+
+func (q *PhoneAccountQuery) OrderBy(fieldName PHONE_ACCOUNT_FIELD, asc bool) *PhoneAccountQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += string(fieldName) + " "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *PhoneAccountQuery) OrderByGroupCount(asc bool) *PhoneAccountQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += "count(1) "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *PhoneAccountQuery) w(format string, a ...interface{}) *PhoneAccountQuery {
+	q.where += fmt.Sprintf(format, a...)
+	return q
+}
+
+func (q *PhoneAccountQuery) Left() *PhoneAccountQuery  { return q.w(" ( ") }
+func (q *PhoneAccountQuery) Right() *PhoneAccountQuery { return q.w(" ) ") }
+func (q *PhoneAccountQuery) And() *PhoneAccountQuery   { return q.w(" AND ") }
+func (q *PhoneAccountQuery) Or() *PhoneAccountQuery    { return q.w(" OR ") }
+func (q *PhoneAccountQuery) Not() *PhoneAccountQuery   { return q.w(" NOT ") }
+
+func (q *PhoneAccountQuery) Id_Equal(v uint64) *PhoneAccountQuery {
+	return q.w("id='" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) Id_NotEqual(v uint64) *PhoneAccountQuery {
+	return q.w("id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) Id_Less(v uint64) *PhoneAccountQuery {
+	return q.w("id<'" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) Id_LessEqual(v uint64) *PhoneAccountQuery {
+	return q.w("id<='" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) Id_Greater(v uint64) *PhoneAccountQuery {
+	return q.w("id>'" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) Id_GreaterEqual(v uint64) *PhoneAccountQuery {
+	return q.w("id>='" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) UserId_Equal(v string) *PhoneAccountQuery {
+	return q.w("user_id='" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) UserId_NotEqual(v string) *PhoneAccountQuery {
+	return q.w("user_id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) UserId_Less(v string) *PhoneAccountQuery {
+	return q.w("user_id<'" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) UserId_LessEqual(v string) *PhoneAccountQuery {
+	return q.w("user_id<='" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) UserId_Greater(v string) *PhoneAccountQuery {
+	return q.w("user_id>'" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) UserId_GreaterEqual(v string) *PhoneAccountQuery {
+	return q.w("user_id>='" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) PhoneNumber_Equal(v string) *PhoneAccountQuery {
+	return q.w("phone_number='" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) PhoneNumber_NotEqual(v string) *PhoneAccountQuery {
+	return q.w("phone_number<>'" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) PhoneNumber_Less(v string) *PhoneAccountQuery {
+	return q.w("phone_number<'" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) PhoneNumber_LessEqual(v string) *PhoneAccountQuery {
+	return q.w("phone_number<='" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) PhoneNumber_Greater(v string) *PhoneAccountQuery {
+	return q.w("phone_number>'" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) PhoneNumber_GreaterEqual(v string) *PhoneAccountQuery {
+	return q.w("phone_number>='" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) CreateTime_Equal(v time.Time) *PhoneAccountQuery {
+	return q.w("create_time='" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) CreateTime_NotEqual(v time.Time) *PhoneAccountQuery {
+	return q.w("create_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) CreateTime_Less(v time.Time) *PhoneAccountQuery {
+	return q.w("create_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) CreateTime_LessEqual(v time.Time) *PhoneAccountQuery {
+	return q.w("create_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) CreateTime_Greater(v time.Time) *PhoneAccountQuery {
+	return q.w("create_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) CreateTime_GreaterEqual(v time.Time) *PhoneAccountQuery {
+	return q.w("create_time>='" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) UpdateTime_Equal(v time.Time) *PhoneAccountQuery {
+	return q.w("update_time='" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) UpdateTime_NotEqual(v time.Time) *PhoneAccountQuery {
+	return q.w("update_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) UpdateTime_Less(v time.Time) *PhoneAccountQuery {
+	return q.w("update_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) UpdateTime_LessEqual(v time.Time) *PhoneAccountQuery {
+	return q.w("update_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) UpdateTime_Greater(v time.Time) *PhoneAccountQuery {
+	return q.w("update_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *PhoneAccountQuery) UpdateTime_GreaterEqual(v time.Time) *PhoneAccountQuery {
+	return q.w("update_time>='" + fmt.Sprint(v) + "'")
+}
+
+type PhoneAccountDao struct {
+	logger     *zap.Logger
+	db         *DB
+	insertStmt *wrap.Stmt
+	updateStmt *wrap.Stmt
+	deleteStmt *wrap.Stmt
+}
+
+func NewPhoneAccountDao(db *DB) (t *PhoneAccountDao, err error) {
+	t = &PhoneAccountDao{}
+	t.logger = log.TypedLogger(t)
+	t.db = db
+	err = t.init()
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (dao *PhoneAccountDao) init() (err error) {
+	err = dao.prepareInsertStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareUpdateStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareDeleteStmt()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *PhoneAccountDao) prepareInsertStmt() (err error) {
+	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO phone_account (user_id,phone_number) VALUES (?,?)")
+	return err
+}
+
+func (dao *PhoneAccountDao) prepareUpdateStmt() (err error) {
+	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE phone_account SET user_id=?,phone_number=? WHERE id=?")
+	return err
+}
+
+func (dao *PhoneAccountDao) prepareDeleteStmt() (err error) {
+	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM phone_account WHERE id=?")
+	return err
+}
+
+func (dao *PhoneAccountDao) Insert(ctx context.Context, tx *wrap.Tx, e *PhoneAccount) (id int64, err error) {
+	stmt := dao.insertStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	result, err := stmt.Exec(ctx, e.UserId, e.PhoneNumber)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err = result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (dao *PhoneAccountDao) Update(ctx context.Context, tx *wrap.Tx, e *PhoneAccount) (err error) {
+	stmt := dao.updateStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, e.UserId, e.PhoneNumber, e.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *PhoneAccountDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
+	stmt := dao.deleteStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *PhoneAccountDao) scanRow(row *wrap.Row) (*PhoneAccount, error) {
+	e := &PhoneAccount{}
+	err := row.Scan(&e.Id, &e.UserId, &e.PhoneNumber, &e.CreateTime, &e.UpdateTime)
+	if err != nil {
+		if err == wrap.ErrNoRows {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+func (dao *PhoneAccountDao) scanRows(rows *wrap.Rows) (list []*PhoneAccount, err error) {
+	list = make([]*PhoneAccount, 0)
+	for rows.Next() {
+		e := PhoneAccount{}
+		err = rows.Scan(&e.Id, &e.UserId, &e.PhoneNumber, &e.CreateTime, &e.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &e)
+	}
+	if rows.Err() != nil {
+		err = rows.Err()
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (dao *PhoneAccountDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*PhoneAccount, error) {
+	querySql := "SELECT " + PHONE_ACCOUNT_ALL_FIELDS_STRING + " FROM phone_account " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	return dao.scanRow(row)
+}
+
+func (dao *PhoneAccountDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*PhoneAccount, err error) {
+	querySql := "SELECT " + PHONE_ACCOUNT_ALL_FIELDS_STRING + " FROM phone_account " + query
+	var rows *wrap.Rows
+	if tx == nil {
+		rows, err = dao.db.Query(ctx, querySql)
+	} else {
+		rows, err = tx.Query(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return nil, err
+	}
+
+	return dao.scanRows(rows)
+}
+
+func (dao *PhoneAccountDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
+	querySql := "SELECT COUNT(1) FROM phone_account " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return 0, err
+	}
+
+	err = row.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (dao *PhoneAccountDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
+	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM phone_account " + query
+	if tx == nil {
+		return dao.db.Query(ctx, querySql)
+	} else {
+		return tx.Query(ctx, querySql)
+	}
+}
+
+func (dao *PhoneAccountDao) GetQuery() *PhoneAccountQuery {
+	return NewPhoneAccountQuery(dao)
+}
+
+const REFRESH_TOKEN_TABLE_NAME = "refresh_token"
+
+type REFRESH_TOKEN_FIELD string
+
+const REFRESH_TOKEN_FIELD_ID = REFRESH_TOKEN_FIELD("id")
+const REFRESH_TOKEN_FIELD_USER_ID = REFRESH_TOKEN_FIELD("user_id")
+const REFRESH_TOKEN_FIELD_REFRESH_TOKEN = REFRESH_TOKEN_FIELD("refresh_token")
+const REFRESH_TOKEN_FIELD_SESSION_ID = REFRESH_TOKEN_FIELD("session_id")
+const REFRESH_TOKEN_FIELD_USER_AGENT = REFRESH_TOKEN_FIELD("user_agent")
+const REFRESH_TOKEN_FIELD_CLIENT_IP = REFRESH_TOKEN_FIELD("client_ip")
+const REFRESH_TOKEN_FIELD_LOGIN_TIME = REFRESH_TOKEN_FIELD("login_time")
+const REFRESH_TOKEN_FIELD_CLIENT_ID = REFRESH_TOKEN_FIELD("client_id")
+const REFRESH_TOKEN_FIELD_SCOPE = REFRESH_TOKEN_FIELD("scope")
+const REFRESH_TOKEN_FIELD_IS_LOGOUT = REFRESH_TOKEN_FIELD("is_logout")
+const REFRESH_TOKEN_FIELD_LOGOUT_TIME = REFRESH_TOKEN_FIELD("logout_time")
+const REFRESH_TOKEN_FIELD_CREATE_TIME = REFRESH_TOKEN_FIELD("create_time")
+const REFRESH_TOKEN_FIELD_UPDATE_TIME = REFRESH_TOKEN_FIELD("update_time")
+
+const REFRESH_TOKEN_ALL_FIELDS_STRING = "id,user_id,refresh_token,session_id,user_agent,client_ip,login_time,client_id,scope,is_logout,logout_time,create_time,update_time"
+
+var REFRESH_TOKEN_ALL_FIELDS = []string{
+	"id",
+	"user_id",
+	"refresh_token",
+	"session_id",
+	"user_agent",
+	"client_ip",
+	"login_time",
+	"client_id",
+	"scope",
+	"is_logout",
+	"logout_time",
+	"create_time",
+	"update_time",
+}
+
+type RefreshToken struct {
+	Id           uint64 //size=20
+	UserId       string //size=32
+	RefreshToken string //size=128
+	SessionId    string //size=32
+	UserAgent    string //size=256
+	ClientIp     string //size=64
+	LoginTime    time.Time
+	ClientId     string //size=64
+	Scope        string //size=256
+	IsLogout     int32  //size=1
+	LogoutTime   time.Time
+	CreateTime   time.Time
+	UpdateTime   time.Time
+}
+
+type RefreshTokenQuery struct {
+	BaseQuery
+	dao *RefreshTokenDao
+}
+
+func NewRefreshTokenQuery(dao *RefreshTokenDao) *RefreshTokenQuery {
+	q := &RefreshTokenQuery{}
+	q.dao = dao
+
+	return q
+}
+
+func (q *RefreshTokenQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*RefreshToken, error) {
+	return q.dao.QueryOne(ctx, tx, q.buildQueryString())
+}
+
+func (q *RefreshTokenQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*RefreshToken, err error) {
+	return q.dao.QueryList(ctx, tx, q.buildQueryString())
+}
+
+func (q *RefreshTokenQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
+	return q.dao.QueryCount(ctx, tx, q.buildQueryString())
+}
+
+func (q *RefreshTokenQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
+	return q.dao.QueryGroupBy(ctx, tx, q.groupByFields, q.buildQueryString())
+}
+
+func (q *RefreshTokenQuery) ForUpdate() *RefreshTokenQuery {
+	q.forUpdate = true
+	return q
+}
+
+func (q *RefreshTokenQuery) ForShare() *RefreshTokenQuery {
+	q.forShare = true
+	return q
+}
+
+func (q *RefreshTokenQuery) GroupBy(fields ...REFRESH_TOKEN_FIELD) *RefreshTokenQuery {
+	q.groupByFields = make([]string, len(fields))
+	for i, v := range fields {
+		q.groupByFields[i] = string(v)
+	}
+	return q
+}
+
+func (q *RefreshTokenQuery) Limit(startIncluded int64, count int64) *RefreshTokenQuery {
+	q.limit = fmt.Sprintf(" limit %d,%d", startIncluded, count)
+	return q
+}
+
+func (q *RefreshTokenQuery) OrderBy(fieldName REFRESH_TOKEN_FIELD, asc bool) *RefreshTokenQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += string(fieldName) + " "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *RefreshTokenQuery) OrderByGroupCount(asc bool) *RefreshTokenQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += "count(1) "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *RefreshTokenQuery) w(format string, a ...interface{}) *RefreshTokenQuery {
+	q.where += fmt.Sprintf(format, a...)
+	return q
+}
+
+func (q *RefreshTokenQuery) Left() *RefreshTokenQuery  { return q.w(" ( ") }
+func (q *RefreshTokenQuery) Right() *RefreshTokenQuery { return q.w(" ) ") }
+func (q *RefreshTokenQuery) And() *RefreshTokenQuery   { return q.w(" AND ") }
+func (q *RefreshTokenQuery) Or() *RefreshTokenQuery    { return q.w(" OR ") }
+func (q *RefreshTokenQuery) Not() *RefreshTokenQuery   { return q.w(" NOT ") }
+
+func (q *RefreshTokenQuery) Id_Equal(v uint64) *RefreshTokenQuery {
+	return q.w("id='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) Id_NotEqual(v uint64) *RefreshTokenQuery {
+	return q.w("id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) Id_Less(v uint64) *RefreshTokenQuery {
+	return q.w("id<'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) Id_LessEqual(v uint64) *RefreshTokenQuery {
+	return q.w("id<='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) Id_Greater(v uint64) *RefreshTokenQuery {
+	return q.w("id>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) Id_GreaterEqual(v uint64) *RefreshTokenQuery {
+	return q.w("id>='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) UserId_Equal(v string) *RefreshTokenQuery {
+	return q.w("user_id='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) UserId_NotEqual(v string) *RefreshTokenQuery {
+	return q.w("user_id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) UserId_Less(v string) *RefreshTokenQuery {
+	return q.w("user_id<'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) UserId_LessEqual(v string) *RefreshTokenQuery {
+	return q.w("user_id<='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) UserId_Greater(v string) *RefreshTokenQuery {
+	return q.w("user_id>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) UserId_GreaterEqual(v string) *RefreshTokenQuery {
+	return q.w("user_id>='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) RefreshToken_Equal(v string) *RefreshTokenQuery {
+	return q.w("refresh_token='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) RefreshToken_NotEqual(v string) *RefreshTokenQuery {
+	return q.w("refresh_token<>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) RefreshToken_Less(v string) *RefreshTokenQuery {
+	return q.w("refresh_token<'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) RefreshToken_LessEqual(v string) *RefreshTokenQuery {
+	return q.w("refresh_token<='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) RefreshToken_Greater(v string) *RefreshTokenQuery {
+	return q.w("refresh_token>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) RefreshToken_GreaterEqual(v string) *RefreshTokenQuery {
+	return q.w("refresh_token>='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) SessionId_Equal(v string) *RefreshTokenQuery {
+	return q.w("session_id='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) SessionId_NotEqual(v string) *RefreshTokenQuery {
+	return q.w("session_id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) SessionId_Less(v string) *RefreshTokenQuery {
+	return q.w("session_id<'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) SessionId_LessEqual(v string) *RefreshTokenQuery {
+	return q.w("session_id<='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) SessionId_Greater(v string) *RefreshTokenQuery {
+	return q.w("session_id>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) SessionId_GreaterEqual(v string) *RefreshTokenQuery {
+	return q.w("session_id>='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) UserAgent_Equal(v string) *RefreshTokenQuery {
+	return q.w("user_agent='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) UserAgent_NotEqual(v string) *RefreshTokenQuery {
+	return q.w("user_agent<>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) UserAgent_Less(v string) *RefreshTokenQuery {
+	return q.w("user_agent<'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) UserAgent_LessEqual(v string) *RefreshTokenQuery {
+	return q.w("user_agent<='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) UserAgent_Greater(v string) *RefreshTokenQuery {
+	return q.w("user_agent>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) UserAgent_GreaterEqual(v string) *RefreshTokenQuery {
+	return q.w("user_agent>='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) ClientIp_Equal(v string) *RefreshTokenQuery {
+	return q.w("client_ip='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) ClientIp_NotEqual(v string) *RefreshTokenQuery {
+	return q.w("client_ip<>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) ClientIp_Less(v string) *RefreshTokenQuery {
+	return q.w("client_ip<'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) ClientIp_LessEqual(v string) *RefreshTokenQuery {
+	return q.w("client_ip<='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) ClientIp_Greater(v string) *RefreshTokenQuery {
+	return q.w("client_ip>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) ClientIp_GreaterEqual(v string) *RefreshTokenQuery {
+	return q.w("client_ip>='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) LoginTime_Equal(v time.Time) *RefreshTokenQuery {
+	return q.w("login_time='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) LoginTime_NotEqual(v time.Time) *RefreshTokenQuery {
+	return q.w("login_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) LoginTime_Less(v time.Time) *RefreshTokenQuery {
+	return q.w("login_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) LoginTime_LessEqual(v time.Time) *RefreshTokenQuery {
+	return q.w("login_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) LoginTime_Greater(v time.Time) *RefreshTokenQuery {
+	return q.w("login_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) LoginTime_GreaterEqual(v time.Time) *RefreshTokenQuery {
+	return q.w("login_time>='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) ClientId_Equal(v string) *RefreshTokenQuery {
+	return q.w("client_id='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) ClientId_NotEqual(v string) *RefreshTokenQuery {
+	return q.w("client_id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) ClientId_Less(v string) *RefreshTokenQuery {
+	return q.w("client_id<'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) ClientId_LessEqual(v string) *RefreshTokenQuery {
+	return q.w("client_id<='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) ClientId_Greater(v string) *RefreshTokenQuery {
+	return q.w("client_id>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) ClientId_GreaterEqual(v string) *RefreshTokenQuery {
+	return q.w("client_id>='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) Scope_Equal(v string) *RefreshTokenQuery {
+	return q.w("scope='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) Scope_NotEqual(v string) *RefreshTokenQuery {
+	return q.w("scope<>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) Scope_Less(v string) *RefreshTokenQuery {
+	return q.w("scope<'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) Scope_LessEqual(v string) *RefreshTokenQuery {
+	return q.w("scope<='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) Scope_Greater(v string) *RefreshTokenQuery {
+	return q.w("scope>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) Scope_GreaterEqual(v string) *RefreshTokenQuery {
+	return q.w("scope>='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) IsLogout_Equal(v int32) *RefreshTokenQuery {
+	return q.w("is_logout='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) IsLogout_NotEqual(v int32) *RefreshTokenQuery {
+	return q.w("is_logout<>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) IsLogout_Less(v int32) *RefreshTokenQuery {
+	return q.w("is_logout<'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) IsLogout_LessEqual(v int32) *RefreshTokenQuery {
+	return q.w("is_logout<='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) IsLogout_Greater(v int32) *RefreshTokenQuery {
+	return q.w("is_logout>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) IsLogout_GreaterEqual(v int32) *RefreshTokenQuery {
+	return q.w("is_logout>='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) LogoutTime_Equal(v time.Time) *RefreshTokenQuery {
+	return q.w("logout_time='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) LogoutTime_NotEqual(v time.Time) *RefreshTokenQuery {
+	return q.w("logout_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) LogoutTime_Less(v time.Time) *RefreshTokenQuery {
+	return q.w("logout_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) LogoutTime_LessEqual(v time.Time) *RefreshTokenQuery {
+	return q.w("logout_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) LogoutTime_Greater(v time.Time) *RefreshTokenQuery {
+	return q.w("logout_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) LogoutTime_GreaterEqual(v time.Time) *RefreshTokenQuery {
+	return q.w("logout_time>='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) CreateTime_Equal(v time.Time) *RefreshTokenQuery {
+	return q.w("create_time='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) CreateTime_NotEqual(v time.Time) *RefreshTokenQuery {
+	return q.w("create_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) CreateTime_Less(v time.Time) *RefreshTokenQuery {
+	return q.w("create_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) CreateTime_LessEqual(v time.Time) *RefreshTokenQuery {
+	return q.w("create_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) CreateTime_Greater(v time.Time) *RefreshTokenQuery {
+	return q.w("create_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) CreateTime_GreaterEqual(v time.Time) *RefreshTokenQuery {
+	return q.w("create_time>='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) UpdateTime_Equal(v time.Time) *RefreshTokenQuery {
+	return q.w("update_time='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) UpdateTime_NotEqual(v time.Time) *RefreshTokenQuery {
+	return q.w("update_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) UpdateTime_Less(v time.Time) *RefreshTokenQuery {
+	return q.w("update_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) UpdateTime_LessEqual(v time.Time) *RefreshTokenQuery {
+	return q.w("update_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) UpdateTime_Greater(v time.Time) *RefreshTokenQuery {
+	return q.w("update_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *RefreshTokenQuery) UpdateTime_GreaterEqual(v time.Time) *RefreshTokenQuery {
+	return q.w("update_time>='" + fmt.Sprint(v) + "'")
+}
+
+type RefreshTokenDao struct {
+	logger     *zap.Logger
+	db         *DB
+	insertStmt *wrap.Stmt
+	updateStmt *wrap.Stmt
+	deleteStmt *wrap.Stmt
+}
+
+func NewRefreshTokenDao(db *DB) (t *RefreshTokenDao, err error) {
+	t = &RefreshTokenDao{}
+	t.logger = log.TypedLogger(t)
+	t.db = db
+	err = t.init()
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (dao *RefreshTokenDao) init() (err error) {
+	err = dao.prepareInsertStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareUpdateStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareDeleteStmt()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *RefreshTokenDao) prepareInsertStmt() (err error) {
+	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO refresh_token (user_id,refresh_token,session_id,user_agent,client_ip,login_time,client_id,scope,is_logout,logout_time) VALUES (?,?,?,?,?,?,?,?,?,?)")
+	return err
+}
+
+func (dao *RefreshTokenDao) prepareUpdateStmt() (err error) {
+	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE refresh_token SET user_id=?,refresh_token=?,session_id=?,user_agent=?,client_ip=?,login_time=?,client_id=?,scope=?,is_logout=?,logout_time=? WHERE id=?")
+	return err
+}
+
+func (dao *RefreshTokenDao) prepareDeleteStmt() (err error) {
+	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM refresh_token WHERE id=?")
+	return err
+}
+
+func (dao *RefreshTokenDao) Insert(ctx context.Context, tx *wrap.Tx, e *RefreshToken) (id int64, err error) {
+	stmt := dao.insertStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	result, err := stmt.Exec(ctx, e.UserId, e.RefreshToken, e.SessionId, e.UserAgent, e.ClientIp, e.LoginTime, e.ClientId, e.Scope, e.IsLogout, e.LogoutTime)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err = result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (dao *RefreshTokenDao) Update(ctx context.Context, tx *wrap.Tx, e *RefreshToken) (err error) {
+	stmt := dao.updateStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, e.UserId, e.RefreshToken, e.SessionId, e.UserAgent, e.ClientIp, e.LoginTime, e.ClientId, e.Scope, e.IsLogout, e.LogoutTime, e.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *RefreshTokenDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
+	stmt := dao.deleteStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *RefreshTokenDao) scanRow(row *wrap.Row) (*RefreshToken, error) {
+	e := &RefreshToken{}
+	err := row.Scan(&e.Id, &e.UserId, &e.RefreshToken, &e.SessionId, &e.UserAgent, &e.ClientIp, &e.LoginTime, &e.ClientId, &e.Scope, &e.IsLogout, &e.LogoutTime, &e.CreateTime, &e.UpdateTime)
+	if err != nil {
+		if err == wrap.ErrNoRows {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+func (dao *RefreshTokenDao) scanRows(rows *wrap.Rows) (list []*RefreshToken, err error) {
+	list = make([]*RefreshToken, 0)
+	for rows.Next() {
+		e := RefreshToken{}
+		err = rows.Scan(&e.Id, &e.UserId, &e.RefreshToken, &e.SessionId, &e.UserAgent, &e.ClientIp, &e.LoginTime, &e.ClientId, &e.Scope, &e.IsLogout, &e.LogoutTime, &e.CreateTime, &e.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &e)
+	}
+	if rows.Err() != nil {
+		err = rows.Err()
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (dao *RefreshTokenDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*RefreshToken, error) {
+	querySql := "SELECT " + REFRESH_TOKEN_ALL_FIELDS_STRING + " FROM refresh_token " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	return dao.scanRow(row)
+}
+
+func (dao *RefreshTokenDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*RefreshToken, err error) {
+	querySql := "SELECT " + REFRESH_TOKEN_ALL_FIELDS_STRING + " FROM refresh_token " + query
+	var rows *wrap.Rows
+	if tx == nil {
+		rows, err = dao.db.Query(ctx, querySql)
+	} else {
+		rows, err = tx.Query(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return nil, err
+	}
+
+	return dao.scanRows(rows)
+}
+
+func (dao *RefreshTokenDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
+	querySql := "SELECT COUNT(1) FROM refresh_token " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return 0, err
+	}
+
+	err = row.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (dao *RefreshTokenDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
+	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM refresh_token " + query
+	if tx == nil {
+		return dao.db.Query(ctx, querySql)
+	} else {
+		return tx.Query(ctx, querySql)
+	}
+}
+
+func (dao *RefreshTokenDao) GetQuery() *RefreshTokenQuery {
+	return NewRefreshTokenQuery(dao)
+}
+
+const TOTP_ACCOUNT_TABLE_NAME = "totp_account"
+
+type TOTP_ACCOUNT_FIELD string
+
+const TOTP_ACCOUNT_FIELD_ID = TOTP_ACCOUNT_FIELD("id")
+const TOTP_ACCOUNT_FIELD_USER_ID = TOTP_ACCOUNT_FIELD("user_id")
+const TOTP_ACCOUNT_FIELD_TOTP_SECRET = TOTP_ACCOUNT_FIELD("totp_secret")
+const TOTP_ACCOUNT_FIELD_IS_ENABLED = TOTP_ACCOUNT_FIELD("is_enabled")
+const TOTP_ACCOUNT_FIELD_LAST_USED_STEP = TOTP_ACCOUNT_FIELD("last_used_step")
+const TOTP_ACCOUNT_FIELD_FAILED_COUNT = TOTP_ACCOUNT_FIELD("failed_count")
+const TOTP_ACCOUNT_FIELD_CREATE_TIME = TOTP_ACCOUNT_FIELD("create_time")
+const TOTP_ACCOUNT_FIELD_UPDATE_TIME = TOTP_ACCOUNT_FIELD("update_time")
+
+const TOTP_ACCOUNT_ALL_FIELDS_STRING = "id,user_id,totp_secret,is_enabled,last_used_step,failed_count,create_time,update_time"
+
+var TOTP_ACCOUNT_ALL_FIELDS = []string{
+	"id",
+	"user_id",
+	"totp_secret",
+	"is_enabled",
+	"last_used_step",
+	"failed_count",
+	"create_time",
+	"update_time",
+}
+
+type TotpAccount struct {
+	Id           uint64 //size=20
+	UserId       string //size=32
+	TotpSecret   string //size=256
+	IsEnabled    int32  //size=1
+	LastUsedStep int64  //size=20
+	FailedCount  int32  //size=11
+	CreateTime   time.Time
+	UpdateTime   time.Time
+}
+
+type TotpAccountQuery struct {
+	BaseQuery
+	dao *TotpAccountDao
+}
+
+func NewTotpAccountQuery(dao *TotpAccountDao) *TotpAccountQuery {
+	q := &TotpAccountQuery{}
+	q.dao = dao
+
+	return q
+}
+
+func (q *TotpAccountQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*TotpAccount, error) {
+	return q.dao.QueryOne(ctx, tx, q.buildQueryString())
+}
+
+func (q *TotpAccountQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*TotpAccount, err error) {
+	return q.dao.QueryList(ctx, tx, q.buildQueryString())
+}
+
+func (q *TotpAccountQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
+	return q.dao.QueryCount(ctx, tx, q.buildQueryString())
+}
+
+func (q *TotpAccountQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
+	return q.dao.QueryGroupBy(ctx, tx, q.groupByFields, q.buildQueryString())
+}
+
+func (q *TotpAccountQuery) ForUpdate() *TotpAccountQuery {
+	q.forUpdate = true
+	return q
+}
+
+func (q *TotpAccountQuery) ForShare() *TotpAccountQuery {
+	q.forShare = true
+	return q
+}
+
+func (q *TotpAccountQuery) GroupBy(fields ...TOTP_ACCOUNT_FIELD) *TotpAccountQuery {
+	q.groupByFields = make([]string, len(fields))
+	for i, v := range fields {
+		q.groupByFields[i] = string(v)
+	}
+	return q
+}
+
+func (q *TotpAccountQuery) Limit(startIncluded int64, count int64) *TotpAccountQuery {
+	q.limit = fmt.Sprintf(" limit %d,%d", startIncluded, count)
+	return q
+}
+
+func (q *TotpAccountQuery) OrderBy(fieldName TOTP_ACCOUNT_FIELD, asc bool) *TotpAccountQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += string(fieldName) + " "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *TotpAccountQuery) OrderByGroupCount(asc bool) *TotpAccountQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += "count(1) "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *TotpAccountQuery) w(format string, a ...interface{}) *TotpAccountQuery {
+	q.where += fmt.Sprintf(format, a...)
+	return q
+}
+
+func (q *TotpAccountQuery) Left() *TotpAccountQuery  { return q.w(" ( ") }
+func (q *TotpAccountQuery) Right() *TotpAccountQuery { return q.w(" ) ") }
+func (q *TotpAccountQuery) And() *TotpAccountQuery   { return q.w(" AND ") }
+func (q *TotpAccountQuery) Or() *TotpAccountQuery    { return q.w(" OR ") }
+func (q *TotpAccountQuery) Not() *TotpAccountQuery   { return q.w(" NOT ") }
+
+func (q *TotpAccountQuery) Id_Equal(v uint64) *TotpAccountQuery {
+	return q.w("id='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) Id_NotEqual(v uint64) *TotpAccountQuery {
+	return q.w("id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) Id_Less(v uint64) *TotpAccountQuery { return q.w("id<'" + fmt.Sprint(v) + "'") }
+func (q *TotpAccountQuery) Id_LessEqual(v uint64) *TotpAccountQuery {
+	return q.w("id<='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) Id_Greater(v uint64) *TotpAccountQuery {
+	return q.w("id>'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) Id_GreaterEqual(v uint64) *TotpAccountQuery {
+	return q.w("id>='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) UserId_Equal(v string) *TotpAccountQuery {
+	return q.w("user_id='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) UserId_NotEqual(v string) *TotpAccountQuery {
+	return q.w("user_id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) UserId_Less(v string) *TotpAccountQuery {
+	return q.w("user_id<'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) UserId_LessEqual(v string) *TotpAccountQuery {
+	return q.w("user_id<='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) UserId_Greater(v string) *TotpAccountQuery {
+	return q.w("user_id>'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) UserId_GreaterEqual(v string) *TotpAccountQuery {
+	return q.w("user_id>='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) TotpSecret_Equal(v string) *TotpAccountQuery {
+	return q.w("totp_secret='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) TotpSecret_NotEqual(v string) *TotpAccountQuery {
+	return q.w("totp_secret<>'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) TotpSecret_Less(v string) *TotpAccountQuery {
+	return q.w("totp_secret<'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) TotpSecret_LessEqual(v string) *TotpAccountQuery {
+	return q.w("totp_secret<='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) TotpSecret_Greater(v string) *TotpAccountQuery {
+	return q.w("totp_secret>'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) TotpSecret_GreaterEqual(v string) *TotpAccountQuery {
+	return q.w("totp_secret>='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) IsEnabled_Equal(v int32) *TotpAccountQuery {
+	return q.w("is_enabled='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) IsEnabled_NotEqual(v int32) *TotpAccountQuery {
+	return q.w("is_enabled<>'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) IsEnabled_Less(v int32) *TotpAccountQuery {
+	return q.w("is_enabled<'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) IsEnabled_LessEqual(v int32) *TotpAccountQuery {
+	return q.w("is_enabled<='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) IsEnabled_Greater(v int32) *TotpAccountQuery {
+	return q.w("is_enabled>'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) IsEnabled_GreaterEqual(v int32) *TotpAccountQuery {
+	return q.w("is_enabled>='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) LastUsedStep_Equal(v int64) *TotpAccountQuery {
+	return q.w("last_used_step='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) LastUsedStep_NotEqual(v int64) *TotpAccountQuery {
+	return q.w("last_used_step<>'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) LastUsedStep_Less(v int64) *TotpAccountQuery {
+	return q.w("last_used_step<'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) LastUsedStep_LessEqual(v int64) *TotpAccountQuery {
+	return q.w("last_used_step<='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) LastUsedStep_Greater(v int64) *TotpAccountQuery {
+	return q.w("last_used_step>'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) LastUsedStep_GreaterEqual(v int64) *TotpAccountQuery {
+	return q.w("last_used_step>='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) FailedCount_Equal(v int32) *TotpAccountQuery {
+	return q.w("failed_count='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) FailedCount_NotEqual(v int32) *TotpAccountQuery {
+	return q.w("failed_count<>'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) FailedCount_Less(v int32) *TotpAccountQuery {
+	return q.w("failed_count<'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) FailedCount_LessEqual(v int32) *TotpAccountQuery {
+	return q.w("failed_count<='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) FailedCount_Greater(v int32) *TotpAccountQuery {
+	return q.w("failed_count>'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) FailedCount_GreaterEqual(v int32) *TotpAccountQuery {
+	return q.w("failed_count>='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) CreateTime_Equal(v time.Time) *TotpAccountQuery {
+	return q.w("create_time='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) CreateTime_NotEqual(v time.Time) *TotpAccountQuery {
+	return q.w("create_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) CreateTime_Less(v time.Time) *TotpAccountQuery {
+	return q.w("create_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) CreateTime_LessEqual(v time.Time) *TotpAccountQuery {
+	return q.w("create_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) CreateTime_Greater(v time.Time) *TotpAccountQuery {
+	return q.w("create_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) CreateTime_GreaterEqual(v time.Time) *TotpAccountQuery {
+	return q.w("create_time>='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) UpdateTime_Equal(v time.Time) *TotpAccountQuery {
+	return q.w("update_time='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) UpdateTime_NotEqual(v time.Time) *TotpAccountQuery {
+	return q.w("update_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) UpdateTime_Less(v time.Time) *TotpAccountQuery {
+	return q.w("update_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) UpdateTime_LessEqual(v time.Time) *TotpAccountQuery {
+	return q.w("update_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) UpdateTime_Greater(v time.Time) *TotpAccountQuery {
+	return q.w("update_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *TotpAccountQuery) UpdateTime_GreaterEqual(v time.Time) *TotpAccountQuery {
+	return q.w("update_time>='" + fmt.Sprint(v) + "'")
+}
+
+type TotpAccountDao struct {
+	logger     *zap.Logger
+	db         *DB
+	insertStmt *wrap.Stmt
+	updateStmt *wrap.Stmt
+	deleteStmt *wrap.Stmt
+}
+
+func NewTotpAccountDao(db *DB) (t *TotpAccountDao, err error) {
+	t = &TotpAccountDao{}
+	t.logger = log.TypedLogger(t)
+	t.db = db
+	err = t.init()
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (dao *TotpAccountDao) init() (err error) {
+	err = dao.prepareInsertStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareUpdateStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareDeleteStmt()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *TotpAccountDao) prepareInsertStmt() (err error) {
+	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO totp_account (user_id,totp_secret,is_enabled,last_used_step,failed_count) VALUES (?,?,?,?,?)")
+	return err
+}
+
+func (dao *TotpAccountDao) prepareUpdateStmt() (err error) {
+	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE totp_account SET user_id=?,totp_secret=?,is_enabled=?,last_used_step=?,failed_count=? WHERE id=?")
+	return err
+}
+
+func (dao *TotpAccountDao) prepareDeleteStmt() (err error) {
+	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM totp_account WHERE id=?")
+	return err
+}
+
+func (dao *TotpAccountDao) Insert(ctx context.Context, tx *wrap.Tx, e *TotpAccount) (id int64, err error) {
+	stmt := dao.insertStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	result, err := stmt.Exec(ctx, e.UserId, e.TotpSecret, e.IsEnabled, e.LastUsedStep, e.FailedCount)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err = result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (dao *TotpAccountDao) Update(ctx context.Context, tx *wrap.Tx, e *TotpAccount) (err error) {
+	stmt := dao.updateStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, e.UserId, e.TotpSecret, e.IsEnabled, e.LastUsedStep, e.FailedCount, e.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *TotpAccountDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
+	stmt := dao.deleteStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *TotpAccountDao) scanRow(row *wrap.Row) (*TotpAccount, error) {
+	e := &TotpAccount{}
+	err := row.Scan(&e.Id, &e.UserId, &e.TotpSecret, &e.IsEnabled, &e.LastUsedStep, &e.FailedCount, &e.CreateTime, &e.UpdateTime)
+	if err != nil {
+		if err == wrap.ErrNoRows {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+func (dao *TotpAccountDao) scanRows(rows *wrap.Rows) (list []*TotpAccount, err error) {
+	list = make([]*TotpAccount, 0)
+	for rows.Next() {
+		e := TotpAccount{}
+		err = rows.Scan(&e.Id, &e.UserId, &e.TotpSecret, &e.IsEnabled, &e.LastUsedStep, &e.FailedCount, &e.CreateTime, &e.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &e)
+	}
+	if rows.Err() != nil {
+		err = rows.Err()
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (dao *TotpAccountDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*TotpAccount, error) {
+	querySql := "SELECT " + TOTP_ACCOUNT_ALL_FIELDS_STRING + " FROM totp_account " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	return dao.scanRow(row)
+}
+
+func (dao *TotpAccountDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*TotpAccount, err error) {
+	querySql := "SELECT " + TOTP_ACCOUNT_ALL_FIELDS_STRING + " FROM totp_account " + query
+	var rows *wrap.Rows
+	if tx == nil {
+		rows, err = dao.db.Query(ctx, querySql)
+	} else {
+		rows, err = tx.Query(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return nil, err
+	}
+
+	return dao.scanRows(rows)
+}
+
+func (dao *TotpAccountDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
+	querySql := "SELECT COUNT(1) FROM totp_account " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return 0, err
+	}
+
+	err = row.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (dao *TotpAccountDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
+	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM totp_account " + query
+	if tx == nil {
+		return dao.db.Query(ctx, querySql)
+	} else {
+		return tx.Query(ctx, querySql)
+	}
+}
+
+func (dao *TotpAccountDao) GetQuery() *TotpAccountQuery {
+	return NewTotpAccountQuery(dao)
+}
+
+const USER_TABLE_NAME = "user"
+
+type USER_FIELD string
+
+const USER_FIELD_ID = USER_FIELD("id")
+const USER_FIELD_USER_ID = USER_FIELD("user_id")
+const USER_FIELD_USER_NAME = USER_FIELD("user_name")
+const USER_FIELD_USER_ICON = USER_FIELD("user_icon")
+const USER_FIELD_BIO = USER_FIELD("bio")
+const USER_FIELD_GENDER = USER_FIELD("gender")
+const USER_FIELD_BIRTHDAY = USER_FIELD("birthday")
+const USER_FIELD_LOCALE = USER_FIELD("locale")
+const USER_FIELD_TIMEZONE = USER_FIELD("timezone")
+const USER_FIELD_CREATE_TIME = USER_FIELD("create_time")
+const USER_FIELD_UPDATE_TIME = USER_FIELD("update_time")
+
+const USER_ALL_FIELDS_STRING = "id,user_id,user_name,user_icon,bio,gender,birthday,locale,timezone,create_time,update_time"
+
+var USER_ALL_FIELDS = []string{
+	"id",
+	"user_id",
+	"user_name",
+	"user_icon",
+	"bio",
+	"gender",
+	"birthday",
+	"locale",
+	"timezone",
+	"create_time",
+	"update_time",
+}
+
+type User struct {
+	Id         uint64 //size=20
+	UserId     string //size=32
+	UserName   string //size=32
+	UserIcon   string //size=256
+	Bio        string //size=256
+	Gender     string //size=16
+	Birthday   string //size=10
+	Locale     string //size=32
+	Timezone   string //size=64
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+type UserQuery struct {
+	BaseQuery
+	dao *UserDao
+}
+
+func NewUserQuery(dao *UserDao) *UserQuery {
+	q := &UserQuery{}
+	q.dao = dao
+
+	return q
+}
+
+func (q *UserQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*User, error) {
+	return q.dao.QueryOne(ctx, tx, q.buildQueryString())
+}
+
+func (q *UserQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*User, err error) {
+	return q.dao.QueryList(ctx, tx, q.buildQueryString())
+}
+
+func (q *UserQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
+	return q.dao.QueryCount(ctx, tx, q.buildQueryString())
+}
+
+func (q *UserQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
+	return q.dao.QueryGroupBy(ctx, tx, q.groupByFields, q.buildQueryString())
+}
+
+func (q *UserQuery) ForUpdate() *UserQuery {
+	q.forUpdate = true
+	return q
+}
+
+func (q *UserQuery) ForShare() *UserQuery {
+	q.forShare = true
+	return q
+}
+
+func (q *UserQuery) GroupBy(fields ...USER_FIELD) *UserQuery {
+	q.groupByFields = make([]string, len(fields))
+	for i, v := range fields {
+		q.groupByFields[i] = string(v)
+	}
+	return q
+}
+
+func (q *UserQuery) Limit(startIncluded int64, count int64) *UserQuery {
+	q.limit = fmt.Sprintf(" limit %d,%d", startIncluded, count)
+	return q
+}
+
+func (q *UserQuery) OrderBy(fieldName USER_FIELD, asc bool) *UserQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += string(fieldName) + " "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *UserQuery) OrderByGroupCount(asc bool) *UserQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += "count(1) "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *UserQuery) w(format string, a ...interface{}) *UserQuery {
+	q.where += fmt.Sprintf(format, a...)
+	return q
+}
+
+func (q *UserQuery) Left() *UserQuery  { return q.w(" ( ") }
+func (q *UserQuery) Right() *UserQuery { return q.w(" ) ") }
+func (q *UserQuery) And() *UserQuery   { return q.w(" AND ") }
+func (q *UserQuery) Or() *UserQuery    { return q.w(" OR ") }
+func (q *UserQuery) Not() *UserQuery   { return q.w(" NOT ") }
+
+func (q *UserQuery) Id_Equal(v uint64) *UserQuery        { return q.w("id='" + fmt.Sprint(v) + "'") }
+func (q *UserQuery) Id_NotEqual(v uint64) *UserQuery     { return q.w("id<>'" + fmt.Sprint(v) + "'") }
+func (q *UserQuery) Id_Less(v uint64) *UserQuery         { return q.w("id<'" + fmt.Sprint(v) + "'") }
+func (q *UserQuery) Id_LessEqual(v uint64) *UserQuery    { return q.w("id<='" + fmt.Sprint(v) + "'") }
+func (q *UserQuery) Id_Greater(v uint64) *UserQuery      { return q.w("id>'" + fmt.Sprint(v) + "'") }
+func (q *UserQuery) Id_GreaterEqual(v uint64) *UserQuery { return q.w("id>='" + fmt.Sprint(v) + "'") }
+func (q *UserQuery) UserId_Equal(v string) *UserQuery    { return q.w("user_id='" + fmt.Sprint(v) + "'") }
+func (q *UserQuery) UserId_NotEqual(v string) *UserQuery { return q.w("user_id<>'" + fmt.Sprint(v) + "'") }
+func (q *UserQuery) UserId_Less(v string) *UserQuery     { return q.w("user_id<'" + fmt.Sprint(v) + "'") }
+func (q *UserQuery) UserId_LessEqual(v string) *UserQuery {
+	return q.w("user_id<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) UserId_Greater(v string) *UserQuery { return q.w("user_id>'" + fmt.Sprint(v) + "'") }
+func (q *UserQuery) UserId_GreaterEqual(v string) *UserQuery {
+	return q.w("user_id>='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) UserName_Equal(v string) *UserQuery { return q.w("user_name='" + fmt.Sprint(v) + "'") }
+func (q *UserQuery) UserName_NotEqual(v string) *UserQuery {
+	return q.w("user_name<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) UserName_Less(v string) *UserQuery { return q.w("user_name<'" + fmt.Sprint(v) + "'") }
+func (q *UserQuery) UserName_LessEqual(v string) *UserQuery {
+	return q.w("user_name<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) UserName_Greater(v string) *UserQuery {
+	return q.w("user_name>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) UserName_GreaterEqual(v string) *UserQuery {
+	return q.w("user_name>='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) UserIcon_Equal(v string) *UserQuery { return q.w("user_icon='" + fmt.Sprint(v) + "'") }
+func (q *UserQuery) UserIcon_NotEqual(v string) *UserQuery {
+	return q.w("user_icon<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) UserIcon_Less(v string) *UserQuery { return q.w("user_icon<'" + fmt.Sprint(v) + "'") }
+func (q *UserQuery) UserIcon_LessEqual(v string) *UserQuery {
+	return q.w("user_icon<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) UserIcon_Greater(v string) *UserQuery {
+	return q.w("user_icon>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) UserIcon_GreaterEqual(v string) *UserQuery {
+	return q.w("user_icon>='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Bio_Equal(v string) *UserQuery {
+	return q.w("bio='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Bio_NotEqual(v string) *UserQuery {
+	return q.w("bio<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Bio_Less(v string) *UserQuery {
+	return q.w("bio<'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Bio_LessEqual(v string) *UserQuery {
+	return q.w("bio<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Bio_Greater(v string) *UserQuery {
+	return q.w("bio>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Bio_GreaterEqual(v string) *UserQuery {
+	return q.w("bio>='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Gender_Equal(v string) *UserQuery {
+	return q.w("gender='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Gender_NotEqual(v string) *UserQuery {
+	return q.w("gender<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Gender_Less(v string) *UserQuery {
+	return q.w("gender<'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Gender_LessEqual(v string) *UserQuery {
+	return q.w("gender<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Gender_Greater(v string) *UserQuery {
+	return q.w("gender>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Gender_GreaterEqual(v string) *UserQuery {
+	return q.w("gender>='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Birthday_Equal(v string) *UserQuery {
+	return q.w("birthday='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Birthday_NotEqual(v string) *UserQuery {
+	return q.w("birthday<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Birthday_Less(v string) *UserQuery {
+	return q.w("birthday<'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Birthday_LessEqual(v string) *UserQuery {
+	return q.w("birthday<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Birthday_Greater(v string) *UserQuery {
+	return q.w("birthday>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Birthday_GreaterEqual(v string) *UserQuery {
+	return q.w("birthday>='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Locale_Equal(v string) *UserQuery {
+	return q.w("locale='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Locale_NotEqual(v string) *UserQuery {
+	return q.w("locale<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Locale_Less(v string) *UserQuery {
+	return q.w("locale<'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Locale_LessEqual(v string) *UserQuery {
+	return q.w("locale<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Locale_Greater(v string) *UserQuery {
+	return q.w("locale>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Locale_GreaterEqual(v string) *UserQuery {
+	return q.w("locale>='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Timezone_Equal(v string) *UserQuery {
+	return q.w("timezone='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Timezone_NotEqual(v string) *UserQuery {
+	return q.w("timezone<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Timezone_Less(v string) *UserQuery {
+	return q.w("timezone<'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Timezone_LessEqual(v string) *UserQuery {
+	return q.w("timezone<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Timezone_Greater(v string) *UserQuery {
+	return q.w("timezone>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) Timezone_GreaterEqual(v string) *UserQuery {
+	return q.w("timezone>='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) CreateTime_Equal(v time.Time) *UserQuery {
+	return q.w("create_time='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) CreateTime_NotEqual(v time.Time) *UserQuery {
+	return q.w("create_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) CreateTime_Less(v time.Time) *UserQuery {
+	return q.w("create_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) CreateTime_LessEqual(v time.Time) *UserQuery {
+	return q.w("create_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) CreateTime_Greater(v time.Time) *UserQuery {
+	return q.w("create_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) CreateTime_GreaterEqual(v time.Time) *UserQuery {
+	return q.w("create_time>='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) UpdateTime_Equal(v time.Time) *UserQuery {
+	return q.w("update_time='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) UpdateTime_NotEqual(v time.Time) *UserQuery {
+	return q.w("update_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) UpdateTime_Less(v time.Time) *UserQuery {
+	return q.w("update_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) UpdateTime_LessEqual(v time.Time) *UserQuery {
+	return q.w("update_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) UpdateTime_Greater(v time.Time) *UserQuery {
+	return q.w("update_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserQuery) UpdateTime_GreaterEqual(v time.Time) *UserQuery {
+	return q.w("update_time>='" + fmt.Sprint(v) + "'")
+}
+
+type UserDao struct {
+	logger     *zap.Logger
+	db         *DB
+	insertStmt *wrap.Stmt
+	updateStmt *wrap.Stmt
+	deleteStmt *wrap.Stmt
+}
+
+func NewUserDao(db *DB) (t *UserDao, err error) {
+	t = &UserDao{}
+	t.logger = log.TypedLogger(t)
+	t.db = db
+	err = t.init()
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (dao *UserDao) init() (err error) {
+	err = dao.prepareInsertStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareUpdateStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareDeleteStmt()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *UserDao) prepareInsertStmt() (err error) {
+	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO user (user_id,user_name,user_icon,bio,gender,birthday,locale,timezone) VALUES (?,?,?,?,?,?,?,?)")
+	return err
+}
+
+func (dao *UserDao) prepareUpdateStmt() (err error) {
+	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE user SET user_id=?,user_name=?,user_icon=?,bio=?,gender=?,birthday=?,locale=?,timezone=? WHERE id=?")
+	return err
+}
+
+func (dao *UserDao) prepareDeleteStmt() (err error) {
+	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM user WHERE id=?")
+	return err
+}
+
+func (dao *UserDao) Insert(ctx context.Context, tx *wrap.Tx, e *User) (id int64, err error) {
+	stmt := dao.insertStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	result, err := stmt.Exec(ctx, e.UserId, e.UserName, e.UserIcon, e.Bio, e.Gender, e.Birthday, e.Locale, e.Timezone)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err = result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (dao *UserDao) Update(ctx context.Context, tx *wrap.Tx, e *User) (err error) {
+	stmt := dao.updateStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, e.UserId, e.UserName, e.UserIcon, e.Bio, e.Gender, e.Birthday, e.Locale, e.Timezone, e.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *UserDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
+	stmt := dao.deleteStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *UserDao) scanRow(row *wrap.Row) (*User, error) {
+	e := &User{}
+	err := row.Scan(&e.Id, &e.UserId, &e.UserName, &e.UserIcon, &e.Bio, &e.Gender, &e.Birthday, &e.Locale, &e.Timezone, &e.CreateTime, &e.UpdateTime)
+	if err != nil {
+		if err == wrap.ErrNoRows {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+func (dao *UserDao) scanRows(rows *wrap.Rows) (list []*User, err error) {
+	list = make([]*User, 0)
+	for rows.Next() {
+		e := User{}
+		err = rows.Scan(&e.Id, &e.UserId, &e.UserName, &e.UserIcon, &e.Bio, &e.Gender, &e.Birthday, &e.Locale, &e.Timezone, &e.CreateTime, &e.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &e)
+	}
+	if rows.Err() != nil {
+		err = rows.Err()
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (dao *UserDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*User, error) {
+	querySql := "SELECT " + USER_ALL_FIELDS_STRING + " FROM user " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	return dao.scanRow(row)
+}
+
+func (dao *UserDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*User, err error) {
+	querySql := "SELECT " + USER_ALL_FIELDS_STRING + " FROM user " + query
+	var rows *wrap.Rows
+	if tx == nil {
+		rows, err = dao.db.Query(ctx, querySql)
+	} else {
+		rows, err = tx.Query(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return nil, err
+	}
+
+	return dao.scanRows(rows)
+}
+
+func (dao *UserDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
+	querySql := "SELECT COUNT(1) FROM user " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return 0, err
+	}
+
+	err = row.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (dao *UserDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
+	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM user " + query
+	if tx == nil {
+		return dao.db.Query(ctx, querySql)
+	} else {
+		return tx.Query(ctx, querySql)
+	}
+}
+
+func (dao *UserDao) GetQuery() *UserQuery {
+	return NewUserQuery(dao)
+}
+
+const USER_ATTRIBUTE_TABLE_NAME = "user_attribute"
+
+type USER_ATTRIBUTE_FIELD string
+
+const USER_ATTRIBUTE_FIELD_ID = USER_ATTRIBUTE_FIELD("id")
+const USER_ATTRIBUTE_FIELD_USER_ID = USER_ATTRIBUTE_FIELD("user_id")
+const USER_ATTRIBUTE_FIELD_NAMESPACE = USER_ATTRIBUTE_FIELD("namespace")
+const USER_ATTRIBUTE_FIELD_ATTR_KEY = USER_ATTRIBUTE_FIELD("attr_key")
+const USER_ATTRIBUTE_FIELD_ATTR_VALUE = USER_ATTRIBUTE_FIELD("attr_value")
+const USER_ATTRIBUTE_FIELD_CREATE_TIME = USER_ATTRIBUTE_FIELD("create_time")
+const USER_ATTRIBUTE_FIELD_UPDATE_TIME = USER_ATTRIBUTE_FIELD("update_time")
+
+const USER_ATTRIBUTE_ALL_FIELDS_STRING = "id,user_id,namespace,attr_key,attr_value,create_time,update_time"
+
+var USER_ATTRIBUTE_ALL_FIELDS = []string{
+	"id",
+	"user_id",
+	"namespace",
+	"attr_key",
+	"attr_value",
+	"create_time",
+	"update_time",
+}
+
+type UserAttribute struct {
+	Id         uint64 //size=20
+	UserId     string //size=32
+	Namespace  string //size=32
+	AttrKey    string //size=64
+	AttrValue  string //size=1024
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+type UserAttributeQuery struct {
+	BaseQuery
+	dao *UserAttributeDao
+}
+
+func NewUserAttributeQuery(dao *UserAttributeDao) *UserAttributeQuery {
+	q := &UserAttributeQuery{}
+	q.dao = dao
+
+	return q
+}
+
+func (q *UserAttributeQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*UserAttribute, error) {
+	return q.dao.QueryOne(ctx, tx, q.buildQueryString())
+}
+
+func (q *UserAttributeQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*UserAttribute, err error) {
+	return q.dao.QueryList(ctx, tx, q.buildQueryString())
+}
+
+func (q *UserAttributeQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
+	return q.dao.QueryCount(ctx, tx, q.buildQueryString())
+}
+
+func (q *UserAttributeQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
+	return q.dao.QueryGroupBy(ctx, tx, q.groupByFields, q.buildQueryString())
+}
+
+func (q *UserAttributeQuery) ForUpdate() *UserAttributeQuery {
+	q.forUpdate = true
+	return q
+}
+
+func (q *UserAttributeQuery) ForShare() *UserAttributeQuery {
+	q.forShare = true
+	return q
+}
+
+func (q *UserAttributeQuery) GroupBy(fields ...USER_ATTRIBUTE_FIELD) *UserAttributeQuery {
+	q.groupByFields = make([]string, len(fields))
+	for i, v := range fields {
+		q.groupByFields[i] = string(v)
+	}
+	return q
+}
+
+func (q *UserAttributeQuery) Limit(startIncluded int64, count int64) *UserAttributeQuery {
+	q.limit = fmt.Sprintf(" limit %d,%d", startIncluded, count)
+	return q
+}
+
+func (q *UserAttributeQuery) OrderBy(fieldName USER_ATTRIBUTE_FIELD, asc bool) *UserAttributeQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += string(fieldName) + " "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *UserAttributeQuery) OrderByGroupCount(asc bool) *UserAttributeQuery {
+	if q.order != "" {
+		q.order += ","
+	}
+	q.order += "count(1) "
+	if asc {
+		q.order += "asc"
+	} else {
+		q.order += "desc"
+	}
+
+	return q
+}
+
+func (q *UserAttributeQuery) w(format string, a ...interface{}) *UserAttributeQuery {
+	q.where += fmt.Sprintf(format, a...)
+	return q
+}
+
+func (q *UserAttributeQuery) Left() *UserAttributeQuery  { return q.w(" ( ") }
+func (q *UserAttributeQuery) Right() *UserAttributeQuery { return q.w(" ) ") }
+func (q *UserAttributeQuery) And() *UserAttributeQuery   { return q.w(" AND ") }
+func (q *UserAttributeQuery) Or() *UserAttributeQuery    { return q.w(" OR ") }
+func (q *UserAttributeQuery) Not() *UserAttributeQuery   { return q.w(" NOT ") }
+
+func (q *UserAttributeQuery) Id_Equal(v uint64) *UserAttributeQuery {
+	return q.w("id='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) Id_NotEqual(v uint64) *UserAttributeQuery {
+	return q.w("id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) Id_Less(v uint64) *UserAttributeQuery {
+	return q.w("id<'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) Id_LessEqual(v uint64) *UserAttributeQuery {
+	return q.w("id<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) Id_Greater(v uint64) *UserAttributeQuery {
+	return q.w("id>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) Id_GreaterEqual(v uint64) *UserAttributeQuery {
+	return q.w("id>='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) UserId_Equal(v string) *UserAttributeQuery {
+	return q.w("user_id='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) UserId_NotEqual(v string) *UserAttributeQuery {
+	return q.w("user_id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) UserId_Less(v string) *UserAttributeQuery {
+	return q.w("user_id<'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) UserId_LessEqual(v string) *UserAttributeQuery {
+	return q.w("user_id<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) UserId_Greater(v string) *UserAttributeQuery {
+	return q.w("user_id>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) UserId_GreaterEqual(v string) *UserAttributeQuery {
+	return q.w("user_id>='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) Namespace_Equal(v string) *UserAttributeQuery {
+	return q.w("namespace='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) Namespace_NotEqual(v string) *UserAttributeQuery {
+	return q.w("namespace<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) Namespace_Less(v string) *UserAttributeQuery {
+	return q.w("namespace<'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) Namespace_LessEqual(v string) *UserAttributeQuery {
+	return q.w("namespace<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) Namespace_Greater(v string) *UserAttributeQuery {
+	return q.w("namespace>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) Namespace_GreaterEqual(v string) *UserAttributeQuery {
+	return q.w("namespace>='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) AttrKey_Equal(v string) *UserAttributeQuery {
+	return q.w("attr_key='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) AttrKey_NotEqual(v string) *UserAttributeQuery {
+	return q.w("attr_key<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) AttrKey_Less(v string) *UserAttributeQuery {
+	return q.w("attr_key<'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) AttrKey_LessEqual(v string) *UserAttributeQuery {
+	return q.w("attr_key<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) AttrKey_Greater(v string) *UserAttributeQuery {
+	return q.w("attr_key>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) AttrKey_GreaterEqual(v string) *UserAttributeQuery {
+	return q.w("attr_key>='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) AttrValue_Equal(v string) *UserAttributeQuery {
+	return q.w("attr_value='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) AttrValue_NotEqual(v string) *UserAttributeQuery {
+	return q.w("attr_value<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) AttrValue_Less(v string) *UserAttributeQuery {
+	return q.w("attr_value<'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) AttrValue_LessEqual(v string) *UserAttributeQuery {
+	return q.w("attr_value<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) AttrValue_Greater(v string) *UserAttributeQuery {
+	return q.w("attr_value>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) AttrValue_GreaterEqual(v string) *UserAttributeQuery {
+	return q.w("attr_value>='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) CreateTime_Equal(v time.Time) *UserAttributeQuery {
+	return q.w("create_time='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) CreateTime_NotEqual(v time.Time) *UserAttributeQuery {
+	return q.w("create_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) CreateTime_Less(v time.Time) *UserAttributeQuery {
+	return q.w("create_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) CreateTime_LessEqual(v time.Time) *UserAttributeQuery {
+	return q.w("create_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) CreateTime_Greater(v time.Time) *UserAttributeQuery {
+	return q.w("create_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) CreateTime_GreaterEqual(v time.Time) *UserAttributeQuery {
+	return q.w("create_time>='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) UpdateTime_Equal(v time.Time) *UserAttributeQuery {
+	return q.w("update_time='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) UpdateTime_NotEqual(v time.Time) *UserAttributeQuery {
+	return q.w("update_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) UpdateTime_Less(v time.Time) *UserAttributeQuery {
+	return q.w("update_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) UpdateTime_LessEqual(v time.Time) *UserAttributeQuery {
+	return q.w("update_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) UpdateTime_Greater(v time.Time) *UserAttributeQuery {
+	return q.w("update_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserAttributeQuery) UpdateTime_GreaterEqual(v time.Time) *UserAttributeQuery {
+	return q.w("update_time>='" + fmt.Sprint(v) + "'")
+}
+
+type UserAttributeDao struct {
+	logger     *zap.Logger
+	db         *DB
+	insertStmt *wrap.Stmt
+	updateStmt *wrap.Stmt
+	deleteStmt *wrap.Stmt
+}
+
+func NewUserAttributeDao(db *DB) (t *UserAttributeDao, err error) {
+	t = &UserAttributeDao{}
+	t.logger = log.TypedLogger(t)
+	t.db = db
+	err = t.init()
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (dao *UserAttributeDao) init() (err error) {
+	err = dao.prepareInsertStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareUpdateStmt()
+	if err != nil {
+		return err
+	}
+
+	err = dao.prepareDeleteStmt()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *UserAttributeDao) prepareInsertStmt() (err error) {
+	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO user_attribute (user_id,namespace,attr_key,attr_value) VALUES (?,?,?,?)")
+	return err
+}
+
+func (dao *UserAttributeDao) prepareUpdateStmt() (err error) {
+	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE user_attribute SET user_id=?,namespace=?,attr_key=?,attr_value=? WHERE id=?")
+	return err
+}
+
+func (dao *UserAttributeDao) prepareDeleteStmt() (err error) {
+	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM user_attribute WHERE id=?")
+	return err
+}
+
+func (dao *UserAttributeDao) Insert(ctx context.Context, tx *wrap.Tx, e *UserAttribute) (id int64, err error) {
+	stmt := dao.insertStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	result, err := stmt.Exec(ctx, e.UserId, e.Namespace, e.AttrKey, e.AttrValue)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err = result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (dao *UserAttributeDao) Update(ctx context.Context, tx *wrap.Tx, e *UserAttribute) (err error) {
+	stmt := dao.updateStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, e.UserId, e.Namespace, e.AttrKey, e.AttrValue, e.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *UserAttributeDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
+	stmt := dao.deleteStmt
+	if tx != nil {
+		stmt = tx.Stmt(ctx, stmt)
+	}
+
+	_, err = stmt.Exec(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dao *UserAttributeDao) scanRow(row *wrap.Row) (*UserAttribute, error) {
+	e := &UserAttribute{}
+	err := row.Scan(&e.Id, &e.UserId, &e.Namespace, &e.AttrKey, &e.AttrValue, &e.CreateTime, &e.UpdateTime)
+	if err != nil {
+		if err == wrap.ErrNoRows {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+func (dao *UserAttributeDao) scanRows(rows *wrap.Rows) (list []*UserAttribute, err error) {
+	list = make([]*UserAttribute, 0)
+	for rows.Next() {
+		e := UserAttribute{}
+		err = rows.Scan(&e.Id, &e.UserId, &e.Namespace, &e.AttrKey, &e.AttrValue, &e.CreateTime, &e.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &e)
+	}
+	if rows.Err() != nil {
+		err = rows.Err()
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (dao *UserAttributeDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*UserAttribute, error) {
+	querySql := "SELECT " + USER_ATTRIBUTE_ALL_FIELDS_STRING + " FROM user_attribute " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	return dao.scanRow(row)
+}
+
+func (dao *UserAttributeDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*UserAttribute, err error) {
+	querySql := "SELECT " + USER_ATTRIBUTE_ALL_FIELDS_STRING + " FROM user_attribute " + query
+	var rows *wrap.Rows
+	if tx == nil {
+		rows, err = dao.db.Query(ctx, querySql)
+	} else {
+		rows, err = tx.Query(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return nil, err
+	}
+
+	return dao.scanRows(rows)
+}
+
+func (dao *UserAttributeDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
+	querySql := "SELECT COUNT(1) FROM user_attribute " + query
+	var row *wrap.Row
+	if tx == nil {
+		row = dao.db.QueryRow(ctx, querySql)
+	} else {
+		row = tx.QueryRow(ctx, querySql)
+	}
+	if err != nil {
+		dao.logger.Error("sqlDriver", zap.Error(err))
+		return 0, err
+	}
+
+	err = row.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (dao *UserAttributeDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
+	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM user_attribute " + query
+	if tx == nil {
+		return dao.db.Query(ctx, querySql)
+	} else {
+		return tx.Query(ctx, querySql)
+	}
+}
+
+func (dao *UserAttributeDao) GetQuery() *UserAttributeQuery {
+	return NewUserAttributeQuery(dao)
+}
+
+const USER_NAME_HISTORY_TABLE_NAME = "user_name_history"
+
+type USER_NAME_HISTORY_FIELD string
+
+const USER_NAME_HISTORY_FIELD_ID = USER_NAME_HISTORY_FIELD("id")
+const USER_NAME_HISTORY_FIELD_USER_ID = USER_NAME_HISTORY_FIELD("user_id")
+const USER_NAME_HISTORY_FIELD_USER_NAME = USER_NAME_HISTORY_FIELD("user_name")
+const USER_NAME_HISTORY_FIELD_RESERVED_UNTIL = USER_NAME_HISTORY_FIELD("reserved_until")
+const USER_NAME_HISTORY_FIELD_CREATE_TIME = USER_NAME_HISTORY_FIELD("create_time")
+const USER_NAME_HISTORY_FIELD_UPDATE_TIME = USER_NAME_HISTORY_FIELD("update_time")
+
+const USER_NAME_HISTORY_ALL_FIELDS_STRING = "id,user_id,user_name,reserved_until,create_time,update_time"
+
+var USER_NAME_HISTORY_ALL_FIELDS = []string{
+	"id",
+	"user_id",
+	"user_name",
+	"reserved_until",
+	"create_time",
+	"update_time",
+}
+
+type UserNameHistory struct {
+	Id            uint64 //size=20
+	UserId        string //size=32
+	UserName      string //size=32
+	ReservedUntil time.Time
+	CreateTime    time.Time
+	UpdateTime    time.Time
+}
+
+type UserNameHistoryQuery struct {
+	BaseQuery
+	dao *UserNameHistoryDao
+}
+
+func NewUserNameHistoryQuery(dao *UserNameHistoryDao) *UserNameHistoryQuery {
+	q := &UserNameHistoryQuery{}
+	q.dao = dao
+
+	return q
+}
+
+func (q *UserNameHistoryQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*UserNameHistory, error) {
 	return q.dao.QueryOne(ctx, tx, q.buildQueryString())
 }
 
-func (q *OauthStateQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*OauthState, err error) {
+func (q *UserNameHistoryQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*UserNameHistory, err error) {
 	return q.dao.QueryList(ctx, tx, q.buildQueryString())
 }
 
-func (q *OauthStateQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
+func (q *UserNameHistoryQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
 	return q.dao.QueryCount(ctx, tx, q.buildQueryString())
 }
 
-func (q *OauthStateQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
+func (q *UserNameHistoryQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
 	return q.dao.QueryGroupBy(ctx, tx, q.groupByFields, q.buildQueryString())
 }
 
-func (q *OauthStateQuery) ForUpdate() *OauthStateQuery {
+func (q *UserNameHistoryQuery) ForUpdate() *UserNameHistoryQuery {
 	q.forUpdate = true
 	return q
 }
 
-func (q *OauthStateQuery) ForShare() *OauthStateQuery {
+func (q *UserNameHistoryQuery) ForShare() *UserNameHistoryQuery {
 	q.forShare = true
 	return q
 }
 
-func (q *OauthStateQuery) GroupBy(fields ...OAUTH_STATE_FIELD) *OauthStateQuery {
+func (q *UserNameHistoryQuery) GroupBy(fields ...USER_NAME_HISTORY_FIELD) *UserNameHistoryQuery {
 	q.groupByFields = make([]string, len(fields))
 	for i, v := range fields {
 		q.groupByFields[i] = string(v)
@@ -1410,12 +6243,12 @@ func (q *OauthStateQuery) GroupBy(fields ...OAUTH_STATE_FIELD) *OauthStateQuery
 	return q
 }
 
-func (q *OauthStateQuery) Limit(startIncluded int64, count int64) *OauthStateQuery {
+func (q *UserNameHistoryQuery) Limit(startIncluded int64, count int64) *UserNameHistoryQuery {
 	q.limit = fmt.Sprintf(" limit %d,%d", startIncluded, count)
 	return q
 }
 
-func (q *OauthStateQuery) OrderBy(fieldName OAUTH_STATE_FIELD, asc bool) *OauthStateQuery {
+func (q *UserNameHistoryQuery) OrderBy(fieldName USER_NAME_HISTORY_FIELD, asc bool) *UserNameHistoryQuery {
 	if q.order != "" {
 		q.order += ","
 	}
@@ -1429,7 +6262,7 @@ func (q *OauthStateQuery) OrderBy(fieldName OAUTH_STATE_FIELD, asc bool) *OauthS
 	return q
 }
 
-func (q *OauthStateQuery) OrderByGroupCount(asc bool) *OauthStateQuery {
+func (q *UserNameHistoryQuery) OrderByGroupCount(asc bool) *UserNameHistoryQuery {
 	if q.order != "" {
 		q.order += ","
 	}
@@ -1443,123 +6276,127 @@ func (q *OauthStateQuery) OrderByGroupCount(asc bool) *OauthStateQuery {
 	return q
 }
 
-func (q *OauthStateQuery) w(format string, a ...interface{}) *OauthStateQuery {
+func (q *UserNameHistoryQuery) w(format string, a ...interface{}) *UserNameHistoryQuery {
 	q.where += fmt.Sprintf(format, a...)
 	return q
 }
 
-func (q *OauthStateQuery) Left() *OauthStateQuery  { return q.w(" ( ") }
-func (q *OauthStateQuery) Right() *OauthStateQuery { return q.w(" ) ") }
-func (q *OauthStateQuery) And() *OauthStateQuery   { return q.w(" AND ") }
-func (q *OauthStateQuery) Or() *OauthStateQuery    { return q.w(" OR ") }
-func (q *OauthStateQuery) Not() *OauthStateQuery   { return q.w(" NOT ") }
+func (q *UserNameHistoryQuery) Left() *UserNameHistoryQuery  { return q.w(" ( ") }
+func (q *UserNameHistoryQuery) Right() *UserNameHistoryQuery { return q.w(" ) ") }
+func (q *UserNameHistoryQuery) And() *UserNameHistoryQuery   { return q.w(" AND ") }
+func (q *UserNameHistoryQuery) Or() *UserNameHistoryQuery    { return q.w(" OR ") }
+func (q *UserNameHistoryQuery) Not() *UserNameHistoryQuery   { return q.w(" NOT ") }
 
-func (q *OauthStateQuery) Id_Equal(v uint64) *OauthStateQuery { return q.w("id='" + fmt.Sprint(v) + "'") }
-func (q *OauthStateQuery) Id_NotEqual(v uint64) *OauthStateQuery {
+func (q *UserNameHistoryQuery) Id_Equal(v uint64) *UserNameHistoryQuery {
+	return q.w("id='" + fmt.Sprint(v) + "'")
+}
+func (q *UserNameHistoryQuery) Id_NotEqual(v uint64) *UserNameHistoryQuery {
 	return q.w("id<>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) Id_Less(v uint64) *OauthStateQuery { return q.w("id<'" + fmt.Sprint(v) + "'") }
-func (q *OauthStateQuery) Id_LessEqual(v uint64) *OauthStateQuery {
+func (q *UserNameHistoryQuery) Id_Less(v uint64) *UserNameHistoryQuery {
+	return q.w("id<'" + fmt.Sprint(v) + "'")
+}
+func (q *UserNameHistoryQuery) Id_LessEqual(v uint64) *UserNameHistoryQuery {
 	return q.w("id<='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) Id_Greater(v uint64) *OauthStateQuery {
+func (q *UserNameHistoryQuery) Id_Greater(v uint64) *UserNameHistoryQuery {
 	return q.w("id>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) Id_GreaterEqual(v uint64) *OauthStateQuery {
+func (q *UserNameHistoryQuery) Id_GreaterEqual(v uint64) *UserNameHistoryQuery {
 	return q.w("id>='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) OauthState_Equal(v string) *OauthStateQuery {
-	return q.w("oauth_state='" + fmt.Sprint(v) + "'")
+func (q *UserNameHistoryQuery) UserId_Equal(v string) *UserNameHistoryQuery {
+	return q.w("user_id='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) OauthState_NotEqual(v string) *OauthStateQuery {
-	return q.w("oauth_state<>'" + fmt.Sprint(v) + "'")
+func (q *UserNameHistoryQuery) UserId_NotEqual(v string) *UserNameHistoryQuery {
+	return q.w("user_id<>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) OauthState_Less(v string) *OauthStateQuery {
-	return q.w("oauth_state<'" + fmt.Sprint(v) + "'")
+func (q *UserNameHistoryQuery) UserId_Less(v string) *UserNameHistoryQuery {
+	return q.w("user_id<'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) OauthState_LessEqual(v string) *OauthStateQuery {
-	return q.w("oauth_state<='" + fmt.Sprint(v) + "'")
+func (q *UserNameHistoryQuery) UserId_LessEqual(v string) *UserNameHistoryQuery {
+	return q.w("user_id<='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) OauthState_Greater(v string) *OauthStateQuery {
-	return q.w("oauth_state>'" + fmt.Sprint(v) + "'")
+func (q *UserNameHistoryQuery) UserId_Greater(v string) *UserNameHistoryQuery {
+	return q.w("user_id>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) OauthState_GreaterEqual(v string) *OauthStateQuery {
-	return q.w("oauth_state>='" + fmt.Sprint(v) + "'")
+func (q *UserNameHistoryQuery) UserId_GreaterEqual(v string) *UserNameHistoryQuery {
+	return q.w("user_id>='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) IsUsed_Equal(v int32) *OauthStateQuery {
-	return q.w("is_used='" + fmt.Sprint(v) + "'")
+func (q *UserNameHistoryQuery) UserName_Equal(v string) *UserNameHistoryQuery {
+	return q.w("user_name='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) IsUsed_NotEqual(v int32) *OauthStateQuery {
-	return q.w("is_used<>'" + fmt.Sprint(v) + "'")
+func (q *UserNameHistoryQuery) UserName_NotEqual(v string) *UserNameHistoryQuery {
+	return q.w("user_name<>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) IsUsed_Less(v int32) *OauthStateQuery {
-	return q.w("is_used<'" + fmt.Sprint(v) + "'")
+func (q *UserNameHistoryQuery) UserName_Less(v string) *UserNameHistoryQuery {
+	return q.w("user_name<'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) IsUsed_LessEqual(v int32) *OauthStateQuery {
-	return q.w("is_used<='" + fmt.Sprint(v) + "'")
+func (q *UserNameHistoryQuery) UserName_LessEqual(v string) *UserNameHistoryQuery {
+	return q.w("user_name<='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) IsUsed_Greater(v int32) *OauthStateQuery {
-	return q.w("is_used>'" + fmt.Sprint(v) + "'")
+func (q *UserNameHistoryQuery) UserName_Greater(v string) *UserNameHistoryQuery {
+	return q.w("user_name>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) IsUsed_GreaterEqual(v int32) *OauthStateQuery {
-	return q.w("is_used>='" + fmt.Sprint(v) + "'")
+func (q *UserNameHistoryQuery) UserName_GreaterEqual(v string) *UserNameHistoryQuery {
+	return q.w("user_name>='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) UserAgent_Equal(v string) *OauthStateQuery {
-	return q.w("user_agent='" + fmt.Sprint(v) + "'")
+func (q *UserNameHistoryQuery) ReservedUntil_Equal(v time.Time) *UserNameHistoryQuery {
+	return q.w("reserved_until='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) UserAgent_NotEqual(v string) *OauthStateQuery {
-	return q.w("user_agent<>'" + fmt.Sprint(v) + "'")
+func (q *UserNameHistoryQuery) ReservedUntil_NotEqual(v time.Time) *UserNameHistoryQuery {
+	return q.w("reserved_until<>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) UserAgent_Less(v string) *OauthStateQuery {
-	return q.w("user_agent<'" + fmt.Sprint(v) + "'")
+func (q *UserNameHistoryQuery) ReservedUntil_Less(v time.Time) *UserNameHistoryQuery {
+	return q.w("reserved_until<'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) UserAgent_LessEqual(v string) *OauthStateQuery {
-	return q.w("user_agent<='" + fmt.Sprint(v) + "'")
+func (q *UserNameHistoryQuery) ReservedUntil_LessEqual(v time.Time) *UserNameHistoryQuery {
+	return q.w("reserved_until<='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) UserAgent_Greater(v string) *OauthStateQuery {
-	return q.w("user_agent>'" + fmt.Sprint(v) + "'")
+func (q *UserNameHistoryQuery) ReservedUntil_Greater(v time.Time) *UserNameHistoryQuery {
+	return q.w("reserved_until>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) UserAgent_GreaterEqual(v string) *OauthStateQuery {
-	return q.w("user_agent>='" + fmt.Sprint(v) + "'")
+func (q *UserNameHistoryQuery) ReservedUntil_GreaterEqual(v time.Time) *UserNameHistoryQuery {
+	return q.w("reserved_until>='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) CreateTime_Equal(v time.Time) *OauthStateQuery {
+func (q *UserNameHistoryQuery) CreateTime_Equal(v time.Time) *UserNameHistoryQuery {
 	return q.w("create_time='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) CreateTime_NotEqual(v time.Time) *OauthStateQuery {
+func (q *UserNameHistoryQuery) CreateTime_NotEqual(v time.Time) *UserNameHistoryQuery {
 	return q.w("create_time<>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) CreateTime_Less(v time.Time) *OauthStateQuery {
+func (q *UserNameHistoryQuery) CreateTime_Less(v time.Time) *UserNameHistoryQuery {
 	return q.w("create_time<'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) CreateTime_LessEqual(v time.Time) *OauthStateQuery {
+func (q *UserNameHistoryQuery) CreateTime_LessEqual(v time.Time) *UserNameHistoryQuery {
 	return q.w("create_time<='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) CreateTime_Greater(v time.Time) *OauthStateQuery {
+func (q *UserNameHistoryQuery) CreateTime_Greater(v time.Time) *UserNameHistoryQuery {
 	return q.w("create_time>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) CreateTime_GreaterEqual(v time.Time) *OauthStateQuery {
+func (q *UserNameHistoryQuery) CreateTime_GreaterEqual(v time.Time) *UserNameHistoryQuery {
 	return q.w("create_time>='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) UpdateTime_Equal(v time.Time) *OauthStateQuery {
+func (q *UserNameHistoryQuery) UpdateTime_Equal(v time.Time) *UserNameHistoryQuery {
 	return q.w("update_time='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) UpdateTime_NotEqual(v time.Time) *OauthStateQuery {
+func (q *UserNameHistoryQuery) UpdateTime_NotEqual(v time.Time) *UserNameHistoryQuery {
 	return q.w("update_time<>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) UpdateTime_Less(v time.Time) *OauthStateQuery {
+func (q *UserNameHistoryQuery) UpdateTime_Less(v time.Time) *UserNameHistoryQuery {
 	return q.w("update_time<'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) UpdateTime_LessEqual(v time.Time) *OauthStateQuery {
+func (q *UserNameHistoryQuery) UpdateTime_LessEqual(v time.Time) *UserNameHistoryQuery {
 	return q.w("update_time<='" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) UpdateTime_Greater(v time.Time) *OauthStateQuery {
+func (q *UserNameHistoryQuery) UpdateTime_Greater(v time.Time) *UserNameHistoryQuery {
 	return q.w("update_time>'" + fmt.Sprint(v) + "'")
 }
-func (q *OauthStateQuery) UpdateTime_GreaterEqual(v time.Time) *OauthStateQuery {
+func (q *UserNameHistoryQuery) UpdateTime_GreaterEqual(v time.Time) *UserNameHistoryQuery {
 	return q.w("update_time>='" + fmt.Sprint(v) + "'")
 }
 
-type OauthStateDao struct {
+type UserNameHistoryDao struct {
 	logger     *zap.Logger
 	db         *DB
 	insertStmt *wrap.Stmt
@@ -1567,8 +6404,8 @@ type OauthStateDao struct {
 	deleteStmt *wrap.Stmt
 }
 
-func NewOauthStateDao(db *DB) (t *OauthStateDao, err error) {
-	t = &OauthStateDao{}
+func NewUserNameHistoryDao(db *DB) (t *UserNameHistoryDao, err error) {
+	t = &UserNameHistoryDao{}
 	t.logger = log.TypedLogger(t)
 	t.db = db
 	err = t.init()
@@ -1579,7 +6416,7 @@ func NewOauthStateDao(db *DB) (t *OauthStateDao, err error) {
 	return t, nil
 }
 
-func (dao *OauthStateDao) init() (err error) {
+func (dao *UserNameHistoryDao) init() (err error) {
 	err = dao.prepareInsertStmt()
 	if err != nil {
 		return err
@@ -1598,28 +6435,28 @@ func (dao *OauthStateDao) init() (err error) {
 	return nil
 }
 
-func (dao *OauthStateDao) prepareInsertStmt() (err error) {
-	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO oauth_state (oauth_state,is_used,user_agent) VALUES (?,?,?)")
+func (dao *UserNameHistoryDao) prepareInsertStmt() (err error) {
+	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO user_name_history (user_id,user_name,reserved_until) VALUES (?,?,?)")
 	return err
 }
 
-func (dao *OauthStateDao) prepareUpdateStmt() (err error) {
-	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE oauth_state SET oauth_state=?,is_used=?,user_agent=? WHERE id=?")
+func (dao *UserNameHistoryDao) prepareUpdateStmt() (err error) {
+	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE user_name_history SET user_id=?,user_name=?,reserved_until=? WHERE id=?")
 	return err
 }
 
-func (dao *OauthStateDao) prepareDeleteStmt() (err error) {
-	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM oauth_state WHERE id=?")
+func (dao *UserNameHistoryDao) prepareDeleteStmt() (err error) {
+	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM user_name_history WHERE id=?")
 	return err
 }
 
-func (dao *OauthStateDao) Insert(ctx context.Context, tx *wrap.Tx, e *OauthState) (id int64, err error) {
+func (dao *UserNameHistoryDao) Insert(ctx context.Context, tx *wrap.Tx, e *UserNameHistory) (id int64, err error) {
 	stmt := dao.insertStmt
 	if tx != nil {
 		stmt = tx.Stmt(ctx, stmt)
 	}
 
-	result, err := stmt.Exec(ctx, e.OauthState, e.IsUsed, e.UserAgent)
+	result, err := stmt.Exec(ctx, e.UserId, e.UserName, e.ReservedUntil)
 	if err != nil {
 		return 0, err
 	}
@@ -1632,13 +6469,13 @@ func (dao *OauthStateDao) Insert(ctx context.Context, tx *wrap.Tx, e *OauthState
 	return id, nil
 }
 
-func (dao *OauthStateDao) Update(ctx context.Context, tx *wrap.Tx, e *OauthState) (err error) {
+func (dao *UserNameHistoryDao) Update(ctx context.Context, tx *wrap.Tx, e *UserNameHistory) (err error) {
 	stmt := dao.updateStmt
 	if tx != nil {
 		stmt = tx.Stmt(ctx, stmt)
 	}
 
-	_, err = stmt.Exec(ctx, e.OauthState, e.IsUsed, e.UserAgent, e.Id)
+	_, err = stmt.Exec(ctx, e.UserId, e.UserName, e.ReservedUntil, e.Id)
 	if err != nil {
 		return err
 	}
@@ -1646,7 +6483,7 @@ func (dao *OauthStateDao) Update(ctx context.Context, tx *wrap.Tx, e *OauthState
 	return nil
 }
 
-func (dao *OauthStateDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
+func (dao *UserNameHistoryDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
 	stmt := dao.deleteStmt
 	if tx != nil {
 		stmt = tx.Stmt(ctx, stmt)
@@ -1660,9 +6497,9 @@ func (dao *OauthStateDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (e
 	return nil
 }
 
-func (dao *OauthStateDao) scanRow(row *wrap.Row) (*OauthState, error) {
-	e := &OauthState{}
-	err := row.Scan(&e.Id, &e.OauthState, &e.IsUsed, &e.UserAgent, &e.CreateTime, &e.UpdateTime)
+func (dao *UserNameHistoryDao) scanRow(row *wrap.Row) (*UserNameHistory, error) {
+	e := &UserNameHistory{}
+	err := row.Scan(&e.Id, &e.UserId, &e.UserName, &e.ReservedUntil, &e.CreateTime, &e.UpdateTime)
 	if err != nil {
 		if err == wrap.ErrNoRows {
 			return nil, nil
@@ -1674,11 +6511,11 @@ func (dao *OauthStateDao) scanRow(row *wrap.Row) (*OauthState, error) {
 	return e, nil
 }
 
-func (dao *OauthStateDao) scanRows(rows *wrap.Rows) (list []*OauthState, err error) {
-	list = make([]*OauthState, 0)
+func (dao *UserNameHistoryDao) scanRows(rows *wrap.Rows) (list []*UserNameHistory, err error) {
+	list = make([]*UserNameHistory, 0)
 	for rows.Next() {
-		e := OauthState{}
-		err = rows.Scan(&e.Id, &e.OauthState, &e.IsUsed, &e.UserAgent, &e.CreateTime, &e.UpdateTime)
+		e := UserNameHistory{}
+		err = rows.Scan(&e.Id, &e.UserId, &e.UserName, &e.ReservedUntil, &e.CreateTime, &e.UpdateTime)
 		if err != nil {
 			return nil, err
 		}
@@ -1692,8 +6529,8 @@ func (dao *OauthStateDao) scanRows(rows *wrap.Rows) (list []*OauthState, err err
 	return list, nil
 }
 
-func (dao *OauthStateDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*OauthState, error) {
-	querySql := "SELECT " + OAUTH_STATE_ALL_FIELDS_STRING + " FROM oauth_state " + query
+func (dao *UserNameHistoryDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*UserNameHistory, error) {
+	querySql := "SELECT " + USER_NAME_HISTORY_ALL_FIELDS_STRING + " FROM user_name_history " + query
 	var row *wrap.Row
 	if tx == nil {
 		row = dao.db.QueryRow(ctx, querySql)
@@ -1703,8 +6540,8 @@ func (dao *OauthStateDao) QueryOne(ctx context.Context, tx *wrap.Tx, query strin
 	return dao.scanRow(row)
 }
 
-func (dao *OauthStateDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*OauthState, err error) {
-	querySql := "SELECT " + OAUTH_STATE_ALL_FIELDS_STRING + " FROM oauth_state " + query
+func (dao *UserNameHistoryDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*UserNameHistory, err error) {
+	querySql := "SELECT " + USER_NAME_HISTORY_ALL_FIELDS_STRING + " FROM user_name_history " + query
 	var rows *wrap.Rows
 	if tx == nil {
 		rows, err = dao.db.Query(ctx, querySql)
@@ -1719,8 +6556,8 @@ func (dao *OauthStateDao) QueryList(ctx context.Context, tx *wrap.Tx, query stri
 	return dao.scanRows(rows)
 }
 
-func (dao *OauthStateDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
-	querySql := "SELECT COUNT(1) FROM oauth_state " + query
+func (dao *UserNameHistoryDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
+	querySql := "SELECT COUNT(1) FROM user_name_history " + query
 	var row *wrap.Row
 	if tx == nil {
 		row = dao.db.QueryRow(ctx, querySql)
@@ -1740,8 +6577,8 @@ func (dao *OauthStateDao) QueryCount(ctx context.Context, tx *wrap.Tx, query str
 	return count, nil
 }
 
-func (dao *OauthStateDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
-	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM oauth_state " + query
+func (dao *UserNameHistoryDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
+	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM user_name_history " + query
 	if tx == nil {
 		return dao.db.Query(ctx, querySql)
 	} else {
@@ -1749,77 +6586,86 @@ func (dao *OauthStateDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupBy
 	}
 }
 
-func (dao *OauthStateDao) GetQuery() *OauthStateQuery {
-	return NewOauthStateQuery(dao)
+func (dao *UserNameHistoryDao) GetQuery() *UserNameHistoryQuery {
+	return NewUserNameHistoryQuery(dao)
 }
 
-const PHONE_ACCOUNT_TABLE_NAME = "phone_account"
+const USER_OPERATION_TABLE_NAME = "user_operation"
 
-type PHONE_ACCOUNT_FIELD string
+type USER_OPERATION_FIELD string
 
-const PHONE_ACCOUNT_FIELD_ID = PHONE_ACCOUNT_FIELD("id")
-const PHONE_ACCOUNT_FIELD_USER_ID = PHONE_ACCOUNT_FIELD("user_id")
-const PHONE_ACCOUNT_FIELD_PHONE_NUMBER = PHONE_ACCOUNT_FIELD("phone_number")
-const PHONE_ACCOUNT_FIELD_CREATE_TIME = PHONE_ACCOUNT_FIELD("create_time")
-const PHONE_ACCOUNT_FIELD_UPDATE_TIME = PHONE_ACCOUNT_FIELD("update_time")
+const USER_OPERATION_FIELD_ID = USER_OPERATION_FIELD("id")
+const USER_OPERATION_FIELD_USER_ID = USER_OPERATION_FIELD("user_id")
+const USER_OPERATION_FIELD_OPERATIONTYPE = USER_OPERATION_FIELD("operationType")
+const USER_OPERATION_FIELD_USER_AGENT = USER_OPERATION_FIELD("user_agent")
+const USER_OPERATION_FIELD_PHONE_NUMBER = USER_OPERATION_FIELD("phone_number")
+const USER_OPERATION_FIELD_ACTOR_ID = USER_OPERATION_FIELD("actor_id")
+const USER_OPERATION_FIELD_REASON = USER_OPERATION_FIELD("reason")
+const USER_OPERATION_FIELD_CREATE_TIME = USER_OPERATION_FIELD("create_time")
 
-const PHONE_ACCOUNT_ALL_FIELDS_STRING = "id,user_id,phone_number,create_time,update_time"
+const USER_OPERATION_ALL_FIELDS_STRING = "id,user_id,operationType,user_agent,phone_number,actor_id,reason,create_time"
 
-var PHONE_ACCOUNT_ALL_FIELDS = []string{
+var USER_OPERATION_ALL_FIELDS = []string{
 	"id",
 	"user_id",
+	"operationType",
+	"user_agent",
 	"phone_number",
+	"actor_id",
+	"reason",
 	"create_time",
-	"update_time",
 }
 
-type PhoneAccount struct {
-	Id          uint64 //size=20
-	UserId      string //size=32
-	PhoneNumber string //size=32
-	CreateTime  time.Time
-	UpdateTime  time.Time
+type UserOperation struct {
+	Id            uint64 //size=20
+	UserId        string //size=32
+	OperationType string //size=32
+	UserAgent     string //size=256
+	PhoneNumber   string //size=32
+	ActorId       string //size=32
+	Reason        string //size=256
+	CreateTime    time.Time
 }
 
-type PhoneAccountQuery struct {
+type UserOperationQuery struct {
 	BaseQuery
-	dao *PhoneAccountDao
+	dao *UserOperationDao
 }
 
-func NewPhoneAccountQuery(dao *PhoneAccountDao) *PhoneAccountQuery {
-	q := &PhoneAccountQuery{}
+func NewUserOperationQuery(dao *UserOperationDao) *UserOperationQuery {
+	q := &UserOperationQuery{}
 	q.dao = dao
 
 	return q
 }
 
-func (q *PhoneAccountQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*PhoneAccount, error) {
+func (q *UserOperationQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*UserOperation, error) {
 	return q.dao.QueryOne(ctx, tx, q.buildQueryString())
 }
 
-func (q *PhoneAccountQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*PhoneAccount, err error) {
+func (q *UserOperationQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*UserOperation, err error) {
 	return q.dao.QueryList(ctx, tx, q.buildQueryString())
 }
 
-func (q *PhoneAccountQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
+func (q *UserOperationQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
 	return q.dao.QueryCount(ctx, tx, q.buildQueryString())
 }
 
-func (q *PhoneAccountQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
+func (q *UserOperationQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
 	return q.dao.QueryGroupBy(ctx, tx, q.groupByFields, q.buildQueryString())
 }
 
-func (q *PhoneAccountQuery) ForUpdate() *PhoneAccountQuery {
+func (q *UserOperationQuery) ForUpdate() *UserOperationQuery {
 	q.forUpdate = true
 	return q
 }
 
-func (q *PhoneAccountQuery) ForShare() *PhoneAccountQuery {
+func (q *UserOperationQuery) ForShare() *UserOperationQuery {
 	q.forShare = true
 	return q
 }
 
-func (q *PhoneAccountQuery) GroupBy(fields ...PHONE_ACCOUNT_FIELD) *PhoneAccountQuery {
+func (q *UserOperationQuery) GroupBy(fields ...USER_OPERATION_FIELD) *UserOperationQuery {
 	q.groupByFields = make([]string, len(fields))
 	for i, v := range fields {
 		q.groupByFields[i] = string(v)
@@ -1827,12 +6673,12 @@ func (q *PhoneAccountQuery) GroupBy(fields ...PHONE_ACCOUNT_FIELD) *PhoneAccount
 	return q
 }
 
-func (q *PhoneAccountQuery) Limit(startIncluded int64, count int64) *PhoneAccountQuery {
+func (q *UserOperationQuery) Limit(startIncluded int64, count int64) *UserOperationQuery {
 	q.limit = fmt.Sprintf(" limit %d,%d", startIncluded, count)
 	return q
 }
 
-func (q *PhoneAccountQuery) OrderBy(fieldName PHONE_ACCOUNT_FIELD, asc bool) *PhoneAccountQuery {
+func (q *UserOperationQuery) OrderBy(fieldName USER_OPERATION_FIELD, asc bool) *UserOperationQuery {
 	if q.order != "" {
 		q.order += ","
 	}
@@ -1846,7 +6692,7 @@ func (q *PhoneAccountQuery) OrderBy(fieldName PHONE_ACCOUNT_FIELD, asc bool) *Ph
 	return q
 }
 
-func (q *PhoneAccountQuery) OrderByGroupCount(asc bool) *PhoneAccountQuery {
+func (q *UserOperationQuery) OrderByGroupCount(asc bool) *UserOperationQuery {
 	if q.order != "" {
 		q.order += ","
 	}
@@ -1860,109 +6706,163 @@ func (q *PhoneAccountQuery) OrderByGroupCount(asc bool) *PhoneAccountQuery {
 	return q
 }
 
-func (q *PhoneAccountQuery) w(format string, a ...interface{}) *PhoneAccountQuery {
+func (q *UserOperationQuery) w(format string, a ...interface{}) *UserOperationQuery {
 	q.where += fmt.Sprintf(format, a...)
 	return q
 }
 
-func (q *PhoneAccountQuery) Left() *PhoneAccountQuery  { return q.w(" ( ") }
-func (q *PhoneAccountQuery) Right() *PhoneAccountQuery { return q.w(" ) ") }
-func (q *PhoneAccountQuery) And() *PhoneAccountQuery   { return q.w(" AND ") }
-func (q *PhoneAccountQuery) Or() *PhoneAccountQuery    { return q.w(" OR ") }
-func (q *PhoneAccountQuery) Not() *PhoneAccountQuery   { return q.w(" NOT ") }
+func (q *UserOperationQuery) Left() *UserOperationQuery  { return q.w(" ( ") }
+func (q *UserOperationQuery) Right() *UserOperationQuery { return q.w(" ) ") }
+func (q *UserOperationQuery) And() *UserOperationQuery   { return q.w(" AND ") }
+func (q *UserOperationQuery) Or() *UserOperationQuery    { return q.w(" OR ") }
+func (q *UserOperationQuery) Not() *UserOperationQuery   { return q.w(" NOT ") }
 
-func (q *PhoneAccountQuery) Id_Equal(v uint64) *PhoneAccountQuery {
+func (q *UserOperationQuery) Id_Equal(v uint64) *UserOperationQuery {
 	return q.w("id='" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) Id_NotEqual(v uint64) *PhoneAccountQuery {
+func (q *UserOperationQuery) Id_NotEqual(v uint64) *UserOperationQuery {
 	return q.w("id<>'" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) Id_Less(v uint64) *PhoneAccountQuery {
+func (q *UserOperationQuery) Id_Less(v uint64) *UserOperationQuery {
 	return q.w("id<'" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) Id_LessEqual(v uint64) *PhoneAccountQuery {
+func (q *UserOperationQuery) Id_LessEqual(v uint64) *UserOperationQuery {
 	return q.w("id<='" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) Id_Greater(v uint64) *PhoneAccountQuery {
+func (q *UserOperationQuery) Id_Greater(v uint64) *UserOperationQuery {
 	return q.w("id>'" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) Id_GreaterEqual(v uint64) *PhoneAccountQuery {
+func (q *UserOperationQuery) Id_GreaterEqual(v uint64) *UserOperationQuery {
 	return q.w("id>='" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) UserId_Equal(v string) *PhoneAccountQuery {
+func (q *UserOperationQuery) UserId_Equal(v string) *UserOperationQuery {
 	return q.w("user_id='" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) UserId_NotEqual(v string) *PhoneAccountQuery {
+func (q *UserOperationQuery) UserId_NotEqual(v string) *UserOperationQuery {
 	return q.w("user_id<>'" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) UserId_Less(v string) *PhoneAccountQuery {
+func (q *UserOperationQuery) UserId_Less(v string) *UserOperationQuery {
 	return q.w("user_id<'" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) UserId_LessEqual(v string) *PhoneAccountQuery {
+func (q *UserOperationQuery) UserId_LessEqual(v string) *UserOperationQuery {
 	return q.w("user_id<='" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) UserId_Greater(v string) *PhoneAccountQuery {
+func (q *UserOperationQuery) UserId_Greater(v string) *UserOperationQuery {
 	return q.w("user_id>'" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) UserId_GreaterEqual(v string) *PhoneAccountQuery {
+func (q *UserOperationQuery) UserId_GreaterEqual(v string) *UserOperationQuery {
 	return q.w("user_id>='" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) PhoneNumber_Equal(v string) *PhoneAccountQuery {
+func (q *UserOperationQuery) OperationType_Equal(v string) *UserOperationQuery {
+	return q.w("operationType='" + fmt.Sprint(v) + "'")
+}
+func (q *UserOperationQuery) OperationType_NotEqual(v string) *UserOperationQuery {
+	return q.w("operationType<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserOperationQuery) OperationType_Less(v string) *UserOperationQuery {
+	return q.w("operationType<'" + fmt.Sprint(v) + "'")
+}
+func (q *UserOperationQuery) OperationType_LessEqual(v string) *UserOperationQuery {
+	return q.w("operationType<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserOperationQuery) OperationType_Greater(v string) *UserOperationQuery {
+	return q.w("operationType>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserOperationQuery) OperationType_GreaterEqual(v string) *UserOperationQuery {
+	return q.w("operationType>='" + fmt.Sprint(v) + "'")
+}
+func (q *UserOperationQuery) UserAgent_Equal(v string) *UserOperationQuery {
+	return q.w("user_agent='" + fmt.Sprint(v) + "'")
+}
+func (q *UserOperationQuery) UserAgent_NotEqual(v string) *UserOperationQuery {
+	return q.w("user_agent<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserOperationQuery) UserAgent_Less(v string) *UserOperationQuery {
+	return q.w("user_agent<'" + fmt.Sprint(v) + "'")
+}
+func (q *UserOperationQuery) UserAgent_LessEqual(v string) *UserOperationQuery {
+	return q.w("user_agent<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserOperationQuery) UserAgent_Greater(v string) *UserOperationQuery {
+	return q.w("user_agent>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserOperationQuery) UserAgent_GreaterEqual(v string) *UserOperationQuery {
+	return q.w("user_agent>='" + fmt.Sprint(v) + "'")
+}
+func (q *UserOperationQuery) PhoneNumber_Equal(v string) *UserOperationQuery {
 	return q.w("phone_number='" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) PhoneNumber_NotEqual(v string) *PhoneAccountQuery {
+func (q *UserOperationQuery) PhoneNumber_NotEqual(v string) *UserOperationQuery {
 	return q.w("phone_number<>'" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) PhoneNumber_Less(v string) *PhoneAccountQuery {
+func (q *UserOperationQuery) PhoneNumber_Less(v string) *UserOperationQuery {
 	return q.w("phone_number<'" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) PhoneNumber_LessEqual(v string) *PhoneAccountQuery {
+func (q *UserOperationQuery) PhoneNumber_LessEqual(v string) *UserOperationQuery {
 	return q.w("phone_number<='" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) PhoneNumber_Greater(v string) *PhoneAccountQuery {
+func (q *UserOperationQuery) PhoneNumber_Greater(v string) *UserOperationQuery {
 	return q.w("phone_number>'" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) PhoneNumber_GreaterEqual(v string) *PhoneAccountQuery {
+func (q *UserOperationQuery) PhoneNumber_GreaterEqual(v string) *UserOperationQuery {
 	return q.w("phone_number>='" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) CreateTime_Equal(v time.Time) *PhoneAccountQuery {
-	return q.w("create_time='" + fmt.Sprint(v) + "'")
+func (q *UserOperationQuery) ActorId_Equal(v string) *UserOperationQuery {
+	return q.w("actor_id='" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) CreateTime_NotEqual(v time.Time) *PhoneAccountQuery {
-	return q.w("create_time<>'" + fmt.Sprint(v) + "'")
+func (q *UserOperationQuery) ActorId_NotEqual(v string) *UserOperationQuery {
+	return q.w("actor_id<>'" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) CreateTime_Less(v time.Time) *PhoneAccountQuery {
-	return q.w("create_time<'" + fmt.Sprint(v) + "'")
+func (q *UserOperationQuery) ActorId_Less(v string) *UserOperationQuery {
+	return q.w("actor_id<'" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) CreateTime_LessEqual(v time.Time) *PhoneAccountQuery {
-	return q.w("create_time<='" + fmt.Sprint(v) + "'")
+func (q *UserOperationQuery) ActorId_LessEqual(v string) *UserOperationQuery {
+	return q.w("actor_id<='" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) CreateTime_Greater(v time.Time) *PhoneAccountQuery {
-	return q.w("create_time>'" + fmt.Sprint(v) + "'")
+func (q *UserOperationQuery) ActorId_Greater(v string) *UserOperationQuery {
+	return q.w("actor_id>'" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) CreateTime_GreaterEqual(v time.Time) *PhoneAccountQuery {
-	return q.w("create_time>='" + fmt.Sprint(v) + "'")
+func (q *UserOperationQuery) ActorId_GreaterEqual(v string) *UserOperationQuery {
+	return q.w("actor_id>='" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) UpdateTime_Equal(v time.Time) *PhoneAccountQuery {
-	return q.w("update_time='" + fmt.Sprint(v) + "'")
+func (q *UserOperationQuery) Reason_Equal(v string) *UserOperationQuery {
+	return q.w("reason='" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) UpdateTime_NotEqual(v time.Time) *PhoneAccountQuery {
-	return q.w("update_time<>'" + fmt.Sprint(v) + "'")
+func (q *UserOperationQuery) Reason_NotEqual(v string) *UserOperationQuery {
+	return q.w("reason<>'" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) UpdateTime_Less(v time.Time) *PhoneAccountQuery {
-	return q.w("update_time<'" + fmt.Sprint(v) + "'")
+func (q *UserOperationQuery) Reason_Less(v string) *UserOperationQuery {
+	return q.w("reason<'" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) UpdateTime_LessEqual(v time.Time) *PhoneAccountQuery {
-	return q.w("update_time<='" + fmt.Sprint(v) + "'")
+func (q *UserOperationQuery) Reason_LessEqual(v string) *UserOperationQuery {
+	return q.w("reason<='" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) UpdateTime_Greater(v time.Time) *PhoneAccountQuery {
-	return q.w("update_time>'" + fmt.Sprint(v) + "'")
+func (q *UserOperationQuery) Reason_Greater(v string) *UserOperationQuery {
+	return q.w("reason>'" + fmt.Sprint(v) + "'")
 }
-func (q *PhoneAccountQuery) UpdateTime_GreaterEqual(v time.Time) *PhoneAccountQuery {
-	return q.w("update_time>='" + fmt.Sprint(v) + "'")
+func (q *UserOperationQuery) Reason_GreaterEqual(v string) *UserOperationQuery {
+	return q.w("reason>='" + fmt.Sprint(v) + "'")
+}
+func (q *UserOperationQuery) CreateTime_Equal(v time.Time) *UserOperationQuery {
+	return q.w("create_time='" + fmt.Sprint(v) + "'")
+}
+func (q *UserOperationQuery) CreateTime_NotEqual(v time.Time) *UserOperationQuery {
+	return q.w("create_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserOperationQuery) CreateTime_Less(v time.Time) *UserOperationQuery {
+	return q.w("create_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *UserOperationQuery) CreateTime_LessEqual(v time.Time) *UserOperationQuery {
+	return q.w("create_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *UserOperationQuery) CreateTime_Greater(v time.Time) *UserOperationQuery {
+	return q.w("create_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *UserOperationQuery) CreateTime_GreaterEqual(v time.Time) *UserOperationQuery {
+	return q.w("create_time>='" + fmt.Sprint(v) + "'")
 }
 
-type PhoneAccountDao struct {
+type UserOperationDao struct {
 	logger     *zap.Logger
 	db         *DB
 	insertStmt *wrap.Stmt
@@ -1970,8 +6870,8 @@ type PhoneAccountDao struct {
 	deleteStmt *wrap.Stmt
 }
 
-func NewPhoneAccountDao(db *DB) (t *PhoneAccountDao, err error) {
-	t = &PhoneAccountDao{}
+func NewUserOperationDao(db *DB) (t *UserOperationDao, err error) {
+	t = &UserOperationDao{}
 	t.logger = log.TypedLogger(t)
 	t.db = db
 	err = t.init()
@@ -1982,7 +6882,7 @@ func NewPhoneAccountDao(db *DB) (t *PhoneAccountDao, err error) {
 	return t, nil
 }
 
-func (dao *PhoneAccountDao) init() (err error) {
+func (dao *UserOperationDao) init() (err error) {
 	err = dao.prepareInsertStmt()
 	if err != nil {
 		return err
@@ -2001,28 +6901,28 @@ func (dao *PhoneAccountDao) init() (err error) {
 	return nil
 }
 
-func (dao *PhoneAccountDao) prepareInsertStmt() (err error) {
-	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO phone_account (user_id,phone_number) VALUES (?,?)")
+func (dao *UserOperationDao) prepareInsertStmt() (err error) {
+	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO user_operation (user_id,operationType,user_agent,phone_number,actor_id,reason) VALUES (?,?,?,?,?,?)")
 	return err
 }
 
-func (dao *PhoneAccountDao) prepareUpdateStmt() (err error) {
-	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE phone_account SET user_id=?,phone_number=? WHERE id=?")
+func (dao *UserOperationDao) prepareUpdateStmt() (err error) {
+	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE user_operation SET user_id=?,operationType=?,user_agent=?,phone_number=?,actor_id=?,reason=? WHERE id=?")
 	return err
 }
 
-func (dao *PhoneAccountDao) prepareDeleteStmt() (err error) {
-	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM phone_account WHERE id=?")
+func (dao *UserOperationDao) prepareDeleteStmt() (err error) {
+	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM user_operation WHERE id=?")
 	return err
 }
 
-func (dao *PhoneAccountDao) Insert(ctx context.Context, tx *wrap.Tx, e *PhoneAccount) (id int64, err error) {
+func (dao *UserOperationDao) Insert(ctx context.Context, tx *wrap.Tx, e *UserOperation) (id int64, err error) {
 	stmt := dao.insertStmt
 	if tx != nil {
 		stmt = tx.Stmt(ctx, stmt)
 	}
 
-	result, err := stmt.Exec(ctx, e.UserId, e.PhoneNumber)
+	result, err := stmt.Exec(ctx, e.UserId, e.OperationType, e.UserAgent, e.PhoneNumber, e.ActorId, e.Reason)
 	if err != nil {
 		return 0, err
 	}
@@ -2035,13 +6935,13 @@ func (dao *PhoneAccountDao) Insert(ctx context.Context, tx *wrap.Tx, e *PhoneAcc
 	return id, nil
 }
 
-func (dao *PhoneAccountDao) Update(ctx context.Context, tx *wrap.Tx, e *PhoneAccount) (err error) {
+func (dao *UserOperationDao) Update(ctx context.Context, tx *wrap.Tx, e *UserOperation) (err error) {
 	stmt := dao.updateStmt
 	if tx != nil {
 		stmt = tx.Stmt(ctx, stmt)
 	}
 
-	_, err = stmt.Exec(ctx, e.UserId, e.PhoneNumber, e.Id)
+	_, err = stmt.Exec(ctx, e.UserId, e.OperationType, e.UserAgent, e.PhoneNumber, e.ActorId, e.Reason, e.Id)
 	if err != nil {
 		return err
 	}
@@ -2049,7 +6949,7 @@ func (dao *PhoneAccountDao) Update(ctx context.Context, tx *wrap.Tx, e *PhoneAcc
 	return nil
 }
 
-func (dao *PhoneAccountDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
+func (dao *UserOperationDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
 	stmt := dao.deleteStmt
 	if tx != nil {
 		stmt = tx.Stmt(ctx, stmt)
@@ -2063,9 +6963,9 @@ func (dao *PhoneAccountDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64)
 	return nil
 }
 
-func (dao *PhoneAccountDao) scanRow(row *wrap.Row) (*PhoneAccount, error) {
-	e := &PhoneAccount{}
-	err := row.Scan(&e.Id, &e.UserId, &e.PhoneNumber, &e.CreateTime, &e.UpdateTime)
+func (dao *UserOperationDao) scanRow(row *wrap.Row) (*UserOperation, error) {
+	e := &UserOperation{}
+	err := row.Scan(&e.Id, &e.UserId, &e.OperationType, &e.UserAgent, &e.PhoneNumber, &e.ActorId, &e.Reason, &e.CreateTime)
 	if err != nil {
 		if err == wrap.ErrNoRows {
 			return nil, nil
@@ -2077,11 +6977,11 @@ func (dao *PhoneAccountDao) scanRow(row *wrap.Row) (*PhoneAccount, error) {
 	return e, nil
 }
 
-func (dao *PhoneAccountDao) scanRows(rows *wrap.Rows) (list []*PhoneAccount, err error) {
-	list = make([]*PhoneAccount, 0)
+func (dao *UserOperationDao) scanRows(rows *wrap.Rows) (list []*UserOperation, err error) {
+	list = make([]*UserOperation, 0)
 	for rows.Next() {
-		e := PhoneAccount{}
-		err = rows.Scan(&e.Id, &e.UserId, &e.PhoneNumber, &e.CreateTime, &e.UpdateTime)
+		e := UserOperation{}
+		err = rows.Scan(&e.Id, &e.UserId, &e.OperationType, &e.UserAgent, &e.PhoneNumber, &e.ActorId, &e.Reason, &e.CreateTime)
 		if err != nil {
 			return nil, err
 		}
@@ -2095,8 +6995,8 @@ func (dao *PhoneAccountDao) scanRows(rows *wrap.Rows) (list []*PhoneAccount, err
 	return list, nil
 }
 
-func (dao *PhoneAccountDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*PhoneAccount, error) {
-	querySql := "SELECT " + PHONE_ACCOUNT_ALL_FIELDS_STRING + " FROM phone_account " + query
+func (dao *UserOperationDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*UserOperation, error) {
+	querySql := "SELECT " + USER_OPERATION_ALL_FIELDS_STRING + " FROM user_operation " + query
 	var row *wrap.Row
 	if tx == nil {
 		row = dao.db.QueryRow(ctx, querySql)
@@ -2106,8 +7006,8 @@ func (dao *PhoneAccountDao) QueryOne(ctx context.Context, tx *wrap.Tx, query str
 	return dao.scanRow(row)
 }
 
-func (dao *PhoneAccountDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*PhoneAccount, err error) {
-	querySql := "SELECT " + PHONE_ACCOUNT_ALL_FIELDS_STRING + " FROM phone_account " + query
+func (dao *UserOperationDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*UserOperation, err error) {
+	querySql := "SELECT " + USER_OPERATION_ALL_FIELDS_STRING + " FROM user_operation " + query
 	var rows *wrap.Rows
 	if tx == nil {
 		rows, err = dao.db.Query(ctx, querySql)
@@ -2122,8 +7022,8 @@ func (dao *PhoneAccountDao) QueryList(ctx context.Context, tx *wrap.Tx, query st
 	return dao.scanRows(rows)
 }
 
-func (dao *PhoneAccountDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
-	querySql := "SELECT COUNT(1) FROM phone_account " + query
+func (dao *UserOperationDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
+	querySql := "SELECT COUNT(1) FROM user_operation " + query
 	var row *wrap.Row
 	if tx == nil {
 		row = dao.db.QueryRow(ctx, querySql)
@@ -2143,8 +7043,8 @@ func (dao *PhoneAccountDao) QueryCount(ctx context.Context, tx *wrap.Tx, query s
 	return count, nil
 }
 
-func (dao *PhoneAccountDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
-	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM phone_account " + query
+func (dao *UserOperationDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
+	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM user_operation " + query
 	if tx == nil {
 		return dao.db.Query(ctx, querySql)
 	} else {
@@ -2152,83 +7052,77 @@ func (dao *PhoneAccountDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, group
 	}
 }
 
-func (dao *PhoneAccountDao) GetQuery() *PhoneAccountQuery {
-	return NewPhoneAccountQuery(dao)
+func (dao *UserOperationDao) GetQuery() *UserOperationQuery {
+	return NewUserOperationQuery(dao)
 }
 
-const REFRESH_TOKEN_TABLE_NAME = "refresh_token"
+const USER_ROLE_TABLE_NAME = "user_role"
 
-type REFRESH_TOKEN_FIELD string
+type USER_ROLE_FIELD string
 
-const REFRESH_TOKEN_FIELD_ID = REFRESH_TOKEN_FIELD("id")
-const REFRESH_TOKEN_FIELD_USER_ID = REFRESH_TOKEN_FIELD("user_id")
-const REFRESH_TOKEN_FIELD_REFRESH_TOKEN = REFRESH_TOKEN_FIELD("refresh_token")
-const REFRESH_TOKEN_FIELD_IS_LOGOUT = REFRESH_TOKEN_FIELD("is_logout")
-const REFRESH_TOKEN_FIELD_LOGOUT_TIME = REFRESH_TOKEN_FIELD("logout_time")
-const REFRESH_TOKEN_FIELD_CREATE_TIME = REFRESH_TOKEN_FIELD("create_time")
-const REFRESH_TOKEN_FIELD_UPDATE_TIME = REFRESH_TOKEN_FIELD("update_time")
+const USER_ROLE_FIELD_ID = USER_ROLE_FIELD("id")
+const USER_ROLE_FIELD_USER_ID = USER_ROLE_FIELD("user_id")
+const USER_ROLE_FIELD_ROLE = USER_ROLE_FIELD("role")
+const USER_ROLE_FIELD_CREATE_TIME = USER_ROLE_FIELD("create_time")
+const USER_ROLE_FIELD_UPDATE_TIME = USER_ROLE_FIELD("update_time")
 
-const REFRESH_TOKEN_ALL_FIELDS_STRING = "id,user_id,refresh_token,is_logout,logout_time,create_time,update_time"
+const USER_ROLE_ALL_FIELDS_STRING = "id,user_id,role,create_time,update_time"
 
-var REFRESH_TOKEN_ALL_FIELDS = []string{
+var USER_ROLE_ALL_FIELDS = []string{
 	"id",
 	"user_id",
-	"refresh_token",
-	"is_logout",
-	"logout_time",
+	"role",
 	"create_time",
 	"update_time",
 }
 
-type RefreshToken struct {
-	Id           uint64 //size=20
-	UserId       string //size=32
-	RefreshToken string //size=128
-	IsLogout     int32  //size=1
-	LogoutTime   time.Time
-	CreateTime   time.Time
-	UpdateTime   time.Time
+type UserRole struct {
+	Id         uint64 //size=20
+	UserId     string //size=32
+	Role       string //size=32
+	CreateTime time.Time
+	UpdateTime time.Time
 }
 
-type RefreshTokenQuery struct {
+type UserRoleQuery struct {
 	BaseQuery
-	dao *RefreshTokenDao
+	dao *UserRoleDao
 }
 
-func NewRefreshTokenQuery(dao *RefreshTokenDao) *RefreshTokenQuery {
-	q := &RefreshTokenQuery{}
+func NewUserRoleQuery(dao *UserRoleDao) *UserRoleQuery {
+	q := &UserRoleQuery{}
 	q.dao = dao
 
 	return q
 }
 
-func (q *RefreshTokenQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*RefreshToken, error) {
+func (q *UserRoleQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*UserRole, error) {
 	return q.dao.QueryOne(ctx, tx, q.buildQueryString())
 }
 
-func (q *RefreshTokenQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*RefreshToken, err error) {
+func (q *UserRoleQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*UserRole, err error) {
 	return q.dao.QueryList(ctx, tx, q.buildQueryString())
 }
 
-func (q *RefreshTokenQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
+func (q *UserRoleQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
 	return q.dao.QueryCount(ctx, tx, q.buildQueryString())
 }
 
-func (q *RefreshTokenQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
+func (q *UserRoleQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
 	return q.dao.QueryGroupBy(ctx, tx, q.groupByFields, q.buildQueryString())
 }
 
-func (q *RefreshTokenQuery) ForUpdate() *RefreshTokenQuery {
+func (q *UserRoleQuery) ForUpdate() *UserRoleQuery {
 	q.forUpdate = true
 	return q
 }
 
-func (q *RefreshTokenQuery) ForShare() *RefreshTokenQuery {
+func (q *UserRoleQuery) ForShare() *UserRoleQuery {
 	q.forShare = true
 	return q
 }
 
-func (q *RefreshTokenQuery) GroupBy(fields ...REFRESH_TOKEN_FIELD) *RefreshTokenQuery {
+func (q *UserRoleQuery) GroupBy(fields ...USER_ROLE_FIELD) *UserRoleQuery {
 	q.groupByFields = make([]string, len(fields))
 	for i, v := range fields {
 		q.groupByFields[i] = string(v)
@@ -2236,12 +7130,12 @@ func (q *RefreshTokenQuery) GroupBy(fields ...REFRESH_TOKEN_FIELD) *RefreshToken
 	return q
 }
 
-func (q *RefreshTokenQuery) Limit(startIncluded int64, count int64) *RefreshTokenQuery {
+func (q *UserRoleQuery) Limit(startIncluded int64, count int64) *UserRoleQuery {
 	q.limit = fmt.Sprintf(" limit %d,%d", startIncluded, count)
 	return q
 }
 
-func (q *RefreshTokenQuery) OrderBy(fieldName REFRESH_TOKEN_FIELD, asc bool) *RefreshTokenQuery {
+func (q *UserRoleQuery) OrderBy(fieldName USER_ROLE_FIELD, asc bool) *UserRoleQuery {
 	if q.order != "" {
 		q.order += ","
 	}
@@ -2255,7 +7149,7 @@ func (q *RefreshTokenQuery) OrderBy(fieldName REFRESH_TOKEN_FIELD, asc bool) *Re
 	return q
 }
 
-func (q *RefreshTokenQuery) OrderByGroupCount(asc bool) *RefreshTokenQuery {
+func (q *UserRoleQuery) OrderByGroupCount(asc bool) *UserRoleQuery {
 	if q.order != "" {
 		q.order += ","
 	}
@@ -2269,145 +7163,109 @@ func (q *RefreshTokenQuery) OrderByGroupCount(asc bool) *RefreshTokenQuery {
 	return q
 }
 
-func (q *RefreshTokenQuery) w(format string, a ...interface{}) *RefreshTokenQuery {
+func (q *UserRoleQuery) w(format string, a ...interface{}) *UserRoleQuery {
 	q.where += fmt.Sprintf(format, a...)
 	return q
 }
 
-func (q *RefreshTokenQuery) Left() *RefreshTokenQuery  { return q.w(" ( ") }
-func (q *RefreshTokenQuery) Right() *RefreshTokenQuery { return q.w(" ) ") }
-func (q *RefreshTokenQuery) And() *RefreshTokenQuery   { return q.w(" AND ") }
-func (q *RefreshTokenQuery) Or() *RefreshTokenQuery    { return q.w(" OR ") }
-func (q *RefreshTokenQuery) Not() *RefreshTokenQuery   { return q.w(" NOT ") }
+func (q *UserRoleQuery) Left() *UserRoleQuery  { return q.w(" ( ") }
+func (q *UserRoleQuery) Right() *UserRoleQuery { return q.w(" ) ") }
+func (q *UserRoleQuery) And() *UserRoleQuery   { return q.w(" AND ") }
+func (q *UserRoleQuery) Or() *UserRoleQuery    { return q.w(" OR ") }
+func (q *UserRoleQuery) Not() *UserRoleQuery   { return q.w(" NOT ") }
 
-func (q *RefreshTokenQuery) Id_Equal(v uint64) *RefreshTokenQuery {
+func (q *UserRoleQuery) Id_Equal(v uint64) *UserRoleQuery {
 	return q.w("id='" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) Id_NotEqual(v uint64) *RefreshTokenQuery {
+func (q *UserRoleQuery) Id_NotEqual(v uint64) *UserRoleQuery {
 	return q.w("id<>'" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) Id_Less(v uint64) *RefreshTokenQuery {
+func (q *UserRoleQuery) Id_Less(v uint64) *UserRoleQuery {
 	return q.w("id<'" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) Id_LessEqual(v uint64) *RefreshTokenQuery {
+func (q *UserRoleQuery) Id_LessEqual(v uint64) *UserRoleQuery {
 	return q.w("id<='" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) Id_Greater(v uint64) *RefreshTokenQuery {
+func (q *UserRoleQuery) Id_Greater(v uint64) *UserRoleQuery {
 	return q.w("id>'" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) Id_GreaterEqual(v uint64) *RefreshTokenQuery {
+func (q *UserRoleQuery) Id_GreaterEqual(v uint64) *UserRoleQuery {
 	return q.w("id>='" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) UserId_Equal(v string) *RefreshTokenQuery {
+func (q *UserRoleQuery) UserId_Equal(v string) *UserRoleQuery {
 	return q.w("user_id='" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) UserId_NotEqual(v string) *RefreshTokenQuery {
+func (q *UserRoleQuery) UserId_NotEqual(v string) *UserRoleQuery {
 	return q.w("user_id<>'" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) UserId_Less(v string) *RefreshTokenQuery {
+func (q *UserRoleQuery) UserId_Less(v string) *UserRoleQuery {
 	return q.w("user_id<'" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) UserId_LessEqual(v string) *RefreshTokenQuery {
+func (q *UserRoleQuery) UserId_LessEqual(v string) *UserRoleQuery {
 	return q.w("user_id<='" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) UserId_Greater(v string) *RefreshTokenQuery {
+func (q *UserRoleQuery) UserId_Greater(v string) *UserRoleQuery {
 	return q.w("user_id>'" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) UserId_GreaterEqual(v string) *RefreshTokenQuery {
+func (q *UserRoleQuery) UserId_GreaterEqual(v string) *UserRoleQuery {
 	return q.w("user_id>='" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) RefreshToken_Equal(v string) *RefreshTokenQuery {
-	return q.w("refresh_token='" + fmt.Sprint(v) + "'")
-}
-func (q *RefreshTokenQuery) RefreshToken_NotEqual(v string) *RefreshTokenQuery {
-	return q.w("refresh_token<>'" + fmt.Sprint(v) + "'")
-}
-func (q *RefreshTokenQuery) RefreshToken_Less(v string) *RefreshTokenQuery {
-	return q.w("refresh_token<'" + fmt.Sprint(v) + "'")
-}
-func (q *RefreshTokenQuery) RefreshToken_LessEqual(v string) *RefreshTokenQuery {
-	return q.w("refresh_token<='" + fmt.Sprint(v) + "'")
-}
-func (q *RefreshTokenQuery) RefreshToken_Greater(v string) *RefreshTokenQuery {
-	return q.w("refresh_token>'" + fmt.Sprint(v) + "'")
-}
-func (q *RefreshTokenQuery) RefreshToken_GreaterEqual(v string) *RefreshTokenQuery {
-	return q.w("refresh_token>='" + fmt.Sprint(v) + "'")
-}
-func (q *RefreshTokenQuery) IsLogout_Equal(v int32) *RefreshTokenQuery {
-	return q.w("is_logout='" + fmt.Sprint(v) + "'")
-}
-func (q *RefreshTokenQuery) IsLogout_NotEqual(v int32) *RefreshTokenQuery {
-	return q.w("is_logout<>'" + fmt.Sprint(v) + "'")
-}
-func (q *RefreshTokenQuery) IsLogout_Less(v int32) *RefreshTokenQuery {
-	return q.w("is_logout<'" + fmt.Sprint(v) + "'")
-}
-func (q *RefreshTokenQuery) IsLogout_LessEqual(v int32) *RefreshTokenQuery {
-	return q.w("is_logout<='" + fmt.Sprint(v) + "'")
-}
-func (q *RefreshTokenQuery) IsLogout_Greater(v int32) *RefreshTokenQuery {
-	return q.w("is_logout>'" + fmt.Sprint(v) + "'")
-}
-func (q *RefreshTokenQuery) IsLogout_GreaterEqual(v int32) *RefreshTokenQuery {
-	return q.w("is_logout>='" + fmt.Sprint(v) + "'")
-}
-func (q *RefreshTokenQuery) LogoutTime_Equal(v time.Time) *RefreshTokenQuery {
-	return q.w("logout_time='" + fmt.Sprint(v) + "'")
+func (q *UserRoleQuery) Role_Equal(v string) *UserRoleQuery {
+	return q.w("role='" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) LogoutTime_NotEqual(v time.Time) *RefreshTokenQuery {
-	return q.w("logout_time<>'" + fmt.Sprint(v) + "'")
+func (q *UserRoleQuery) Role_NotEqual(v string) *UserRoleQuery {
+	return q.w("role<>'" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) LogoutTime_Less(v time.Time) *RefreshTokenQuery {
-	return q.w("logout_time<'" + fmt.Sprint(v) + "'")
+func (q *UserRoleQuery) Role_Less(v string) *UserRoleQuery {
+	return q.w("role<'" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) LogoutTime_LessEqual(v time.Time) *RefreshTokenQuery {
-	return q.w("logout_time<='" + fmt.Sprint(v) + "'")
+func (q *UserRoleQuery) Role_LessEqual(v string) *UserRoleQuery {
+	return q.w("role<='" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) LogoutTime_Greater(v time.Time) *RefreshTokenQuery {
-	return q.w("logout_time>'" + fmt.Sprint(v) + "'")
+func (q *UserRoleQuery) Role_Greater(v string) *UserRoleQuery {
+	return q.w("role>'" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) LogoutTime_GreaterEqual(v time.Time) *RefreshTokenQuery {
-	return q.w("logout_time>='" + fmt.Sprint(v) + "'")
+func (q *UserRoleQuery) Role_GreaterEqual(v string) *UserRoleQuery {
+	return q.w("role>='" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) CreateTime_Equal(v time.Time) *RefreshTokenQuery {
+func (q *UserRoleQuery) CreateTime_Equal(v time.Time) *UserRoleQuery {
 	return q.w("create_time='" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) CreateTime_NotEqual(v time.Time) *RefreshTokenQuery {
+func (q *UserRoleQuery) CreateTime_NotEqual(v time.Time) *UserRoleQuery {
 	return q.w("create_time<>'" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) CreateTime_Less(v time.Time) *RefreshTokenQuery {
+func (q *UserRoleQuery) CreateTime_Less(v time.Time) *UserRoleQuery {
 	return q.w("create_time<'" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) CreateTime_LessEqual(v time.Time) *RefreshTokenQuery {
+func (q *UserRoleQuery) CreateTime_LessEqual(v time.Time) *UserRoleQuery {
 	return q.w("create_time<='" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) CreateTime_Greater(v time.Time) *RefreshTokenQuery {
+func (q *UserRoleQuery) CreateTime_Greater(v time.Time) *UserRoleQuery {
 	return q.w("create_time>'" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) CreateTime_GreaterEqual(v time.Time) *RefreshTokenQuery {
+func (q *UserRoleQuery) CreateTime_GreaterEqual(v time.Time) *UserRoleQuery {
 	return q.w("create_time>='" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) UpdateTime_Equal(v time.Time) *RefreshTokenQuery {
+func (q *UserRoleQuery) UpdateTime_Equal(v time.Time) *UserRoleQuery {
 	return q.w("update_time='" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) UpdateTime_NotEqual(v time.Time) *RefreshTokenQuery {
+func (q *UserRoleQuery) UpdateTime_NotEqual(v time.Time) *UserRoleQuery {
 	return q.w("update_time<>'" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) UpdateTime_Less(v time.Time) *RefreshTokenQuery {
+func (q *UserRoleQuery) UpdateTime_Less(v time.Time) *UserRoleQuery {
 	return q.w("update_time<'" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) UpdateTime_LessEqual(v time.Time) *RefreshTokenQuery {
+func (q *UserRoleQuery) UpdateTime_LessEqual(v time.Time) *UserRoleQuery {
 	return q.w("update_time<='" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) UpdateTime_Greater(v time.Time) *RefreshTokenQuery {
+func (q *UserRoleQuery) UpdateTime_Greater(v time.Time) *UserRoleQuery {
 	return q.w("update_time>'" + fmt.Sprint(v) + "'")
 }
-func (q *RefreshTokenQuery) UpdateTime_GreaterEqual(v time.Time) *RefreshTokenQuery {
+func (q *UserRoleQuery) UpdateTime_GreaterEqual(v time.Time) *UserRoleQuery {
 	return q.w("update_time>='" + fmt.Sprint(v) + "'")
 }
 
-type RefreshTokenDao struct {
+type UserRoleDao struct {
 	logger     *zap.Logger
 	db         *DB
 	insertStmt *wrap.Stmt
@@ -2415,8 +7273,8 @@ type RefreshTokenDao struct {
 	deleteStmt *wrap.Stmt
 }
 
-func NewRefreshTokenDao(db *DB) (t *RefreshTokenDao, err error) {
-	t = &RefreshTokenDao{}
+func NewUserRoleDao(db *DB) (t *UserRoleDao, err error) {
+	t = &UserRoleDao{}
 	t.logger = log.TypedLogger(t)
 	t.db = db
 	err = t.init()
@@ -2427,7 +7285,7 @@ func NewRefreshTokenDao(db *DB) (t *RefreshTokenDao, err error) {
 	return t, nil
 }
 
-func (dao *RefreshTokenDao) init() (err error) {
+func (dao *UserRoleDao) init() (err error) {
 	err = dao.prepareInsertStmt()
 	if err != nil {
 		return err
@@ -2446,28 +7304,28 @@ func (dao *RefreshTokenDao) init() (err error) {
 	return nil
 }
 
-func (dao *RefreshTokenDao) prepareInsertStmt() (err error) {
-	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO refresh_token (user_id,refresh_token,is_logout,logout_time) VALUES (?,?,?,?)")
+func (dao *UserRoleDao) prepareInsertStmt() (err error) {
+	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO user_role (user_id,role) VALUES (?,?)")
 	return err
 }
 
-func (dao *RefreshTokenDao) prepareUpdateStmt() (err error) {
-	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE refresh_token SET user_id=?,refresh_token=?,is_logout=?,logout_time=? WHERE id=?")
+func (dao *UserRoleDao) prepareUpdateStmt() (err error) {
+	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE user_role SET user_id=?,role=? WHERE id=?")
 	return err
 }
 
-func (dao *RefreshTokenDao) prepareDeleteStmt() (err error) {
-	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM refresh_token WHERE id=?")
+func (dao *UserRoleDao) prepareDeleteStmt() (err error) {
+	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM user_role WHERE id=?")
 	return err
 }
 
-func (dao *RefreshTokenDao) Insert(ctx context.Context, tx *wrap.Tx, e *RefreshToken) (id int64, err error) {
+func (dao *UserRoleDao) Insert(ctx context.Context, tx *wrap.Tx, e *UserRole) (id int64, err error) {
 	stmt := dao.insertStmt
 	if tx != nil {
 		stmt = tx.Stmt(ctx, stmt)
 	}
 
-	result, err := stmt.Exec(ctx, e.UserId, e.RefreshToken, e.IsLogout, e.LogoutTime)
+	result, err := stmt.Exec(ctx, e.UserId, e.Role)
 	if err != nil {
 		return 0, err
 	}
@@ -2480,13 +7338,13 @@ func (dao *RefreshTokenDao) Insert(ctx context.Context, tx *wrap.Tx, e *RefreshT
 	return id, nil
 }
 
-func (dao *RefreshTokenDao) Update(ctx context.Context, tx *wrap.Tx, e *RefreshToken) (err error) {
+func (dao *UserRoleDao) Update(ctx context.Context, tx *wrap.Tx, e *UserRole) (err error) {
 	stmt := dao.updateStmt
 	if tx != nil {
 		stmt = tx.Stmt(ctx, stmt)
 	}
 
-	_, err = stmt.Exec(ctx, e.UserId, e.RefreshToken, e.IsLogout, e.LogoutTime, e.Id)
+	_, err = stmt.Exec(ctx, e.UserId, e.Role, e.Id)
 	if err != nil {
 		return err
 	}
@@ -2494,7 +7352,7 @@ func (dao *RefreshTokenDao) Update(ctx context.Context, tx *wrap.Tx, e *RefreshT
 	return nil
 }
 
-func (dao *RefreshTokenDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
+func (dao *UserRoleDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
 	stmt := dao.deleteStmt
 	if tx != nil {
 		stmt = tx.Stmt(ctx, stmt)
@@ -2508,9 +7366,9 @@ func (dao *RefreshTokenDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64)
 	return nil
 }
 
-func (dao *RefreshTokenDao) scanRow(row *wrap.Row) (*RefreshToken, error) {
-	e := &RefreshToken{}
-	err := row.Scan(&e.Id, &e.UserId, &e.RefreshToken, &e.IsLogout, &e.LogoutTime, &e.CreateTime, &e.UpdateTime)
+func (dao *UserRoleDao) scanRow(row *wrap.Row) (*UserRole, error) {
+	e := &UserRole{}
+	err := row.Scan(&e.Id, &e.UserId, &e.Role, &e.CreateTime, &e.UpdateTime)
 	if err != nil {
 		if err == wrap.ErrNoRows {
 			return nil, nil
@@ -2522,11 +7380,11 @@ func (dao *RefreshTokenDao) scanRow(row *wrap.Row) (*RefreshToken, error) {
 	return e, nil
 }
 
-func (dao *RefreshTokenDao) scanRows(rows *wrap.Rows) (list []*RefreshToken, err error) {
-	list = make([]*RefreshToken, 0)
+func (dao *UserRoleDao) scanRows(rows *wrap.Rows) (list []*UserRole, err error) {
+	list = make([]*UserRole, 0)
 	for rows.Next() {
-		e := RefreshToken{}
-		err = rows.Scan(&e.Id, &e.UserId, &e.RefreshToken, &e.IsLogout, &e.LogoutTime, &e.CreateTime, &e.UpdateTime)
+		e := UserRole{}
+		err = rows.Scan(&e.Id, &e.UserId, &e.Role, &e.CreateTime, &e.UpdateTime)
 		if err != nil {
 			return nil, err
 		}
@@ -2540,8 +7398,8 @@ func (dao *RefreshTokenDao) scanRows(rows *wrap.Rows) (list []*RefreshToken, err
 	return list, nil
 }
 
-func (dao *RefreshTokenDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*RefreshToken, error) {
-	querySql := "SELECT " + REFRESH_TOKEN_ALL_FIELDS_STRING + " FROM refresh_token " + query
+func (dao *UserRoleDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*UserRole, error) {
+	querySql := "SELECT " + USER_ROLE_ALL_FIELDS_STRING + " FROM user_role " + query
 	var row *wrap.Row
 	if tx == nil {
 		row = dao.db.QueryRow(ctx, querySql)
@@ -2551,8 +7409,8 @@ func (dao *RefreshTokenDao) QueryOne(ctx context.Context, tx *wrap.Tx, query str
 	return dao.scanRow(row)
 }
 
-func (dao *RefreshTokenDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*RefreshToken, err error) {
-	querySql := "SELECT " + REFRESH_TOKEN_ALL_FIELDS_STRING + " FROM refresh_token " + query
+func (dao *UserRoleDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*UserRole, err error) {
+	querySql := "SELECT " + USER_ROLE_ALL_FIELDS_STRING + " FROM user_role " + query
 	var rows *wrap.Rows
 	if tx == nil {
 		rows, err = dao.db.Query(ctx, querySql)
@@ -2567,8 +7425,8 @@ func (dao *RefreshTokenDao) QueryList(ctx context.Context, tx *wrap.Tx, query st
 	return dao.scanRows(rows)
 }
 
-func (dao *RefreshTokenDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
-	querySql := "SELECT COUNT(1) FROM refresh_token " + query
+func (dao *UserRoleDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
+	querySql := "SELECT COUNT(1) FROM user_role " + query
 	var row *wrap.Row
 	if tx == nil {
 		row = dao.db.QueryRow(ctx, querySql)
@@ -2588,8 +7446,8 @@ func (dao *RefreshTokenDao) QueryCount(ctx context.Context, tx *wrap.Tx, query s
 	return count, nil
 }
 
-func (dao *RefreshTokenDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
-	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM refresh_token " + query
+func (dao *UserRoleDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
+	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM user_role " + query
 	if tx == nil {
 		return dao.db.Query(ctx, querySql)
 	} else {
@@ -2597,80 +7455,80 @@ func (dao *RefreshTokenDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, group
 	}
 }
 
-func (dao *RefreshTokenDao) GetQuery() *RefreshTokenQuery {
-	return NewRefreshTokenQuery(dao)
+func (dao *UserRoleDao) GetQuery() *UserRoleQuery {
+	return NewUserRoleQuery(dao)
 }
 
-const USER_TABLE_NAME = "user"
+const WEBAUTHN_CREDENTIAL_TABLE_NAME = "webauthn_credential"
 
-type USER_FIELD string
+type WEBAUTHN_CREDENTIAL_FIELD string
 
-const USER_FIELD_ID = USER_FIELD("id")
-const USER_FIELD_USER_ID = USER_FIELD("user_id")
-const USER_FIELD_USER_NAME = USER_FIELD("user_name")
-const USER_FIELD_USER_ICON = USER_FIELD("user_icon")
-const USER_FIELD_CREATE_TIME = USER_FIELD("create_time")
-const USER_FIELD_UPDATE_TIME = USER_FIELD("update_time")
+const WEBAUTHN_CREDENTIAL_FIELD_ID = WEBAUTHN_CREDENTIAL_FIELD("id")
+const WEBAUTHN_CREDENTIAL_FIELD_USER_ID = WEBAUTHN_CREDENTIAL_FIELD("user_id")
+const WEBAUTHN_CREDENTIAL_FIELD_CREDENTIAL_ID = WEBAUTHN_CREDENTIAL_FIELD("credential_id")
+const WEBAUTHN_CREDENTIAL_FIELD_CREDENTIAL_DATA = WEBAUTHN_CREDENTIAL_FIELD("credential_data")
+const WEBAUTHN_CREDENTIAL_FIELD_CREATE_TIME = WEBAUTHN_CREDENTIAL_FIELD("create_time")
+const WEBAUTHN_CREDENTIAL_FIELD_UPDATE_TIME = WEBAUTHN_CREDENTIAL_FIELD("update_time")
 
-const USER_ALL_FIELDS_STRING = "id,user_id,user_name,user_icon,create_time,update_time"
+const WEBAUTHN_CREDENTIAL_ALL_FIELDS_STRING = "id,user_id,credential_id,credential_data,create_time,update_time"
 
-var USER_ALL_FIELDS = []string{
+var WEBAUTHN_CREDENTIAL_ALL_FIELDS = []string{
 	"id",
 	"user_id",
-	"user_name",
-	"user_icon",
+	"credential_id",
+	"credential_data",
 	"create_time",
 	"update_time",
 }
 
-type User struct {
-	Id         uint64 //size=20
-	UserId     string //size=32
-	UserName   string //size=32
-	UserIcon   string //size=256
-	CreateTime time.Time
-	UpdateTime time.Time
+type WebauthnCredential struct {
+	Id             uint64 //size=20
+	UserId         string //size=32
+	CredentialId   string //size=255
+	CredentialData string
+	CreateTime     time.Time
+	UpdateTime     time.Time
 }
 
-type UserQuery struct {
+type WebauthnCredentialQuery struct {
 	BaseQuery
-	dao *UserDao
+	dao *WebauthnCredentialDao
 }
 
-func NewUserQuery(dao *UserDao) *UserQuery {
-	q := &UserQuery{}
+func NewWebauthnCredentialQuery(dao *WebauthnCredentialDao) *WebauthnCredentialQuery {
+	q := &WebauthnCredentialQuery{}
 	q.dao = dao
 
 	return q
 }
 
-func (q *UserQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*User, error) {
+func (q *WebauthnCredentialQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*WebauthnCredential, error) {
 	return q.dao.QueryOne(ctx, tx, q.buildQueryString())
 }
 
-func (q *UserQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*User, err error) {
+func (q *WebauthnCredentialQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*WebauthnCredential, err error) {
 	return q.dao.QueryList(ctx, tx, q.buildQueryString())
 }
 
-func (q *UserQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
+func (q *WebauthnCredentialQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
 	return q.dao.QueryCount(ctx, tx, q.buildQueryString())
 }
 
-func (q *UserQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
+func (q *WebauthnCredentialQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
 	return q.dao.QueryGroupBy(ctx, tx, q.groupByFields, q.buildQueryString())
 }
 
-func (q *UserQuery) ForUpdate() *UserQuery {
+func (q *WebauthnCredentialQuery) ForUpdate() *WebauthnCredentialQuery {
 	q.forUpdate = true
 	return q
 }
 
-func (q *UserQuery) ForShare() *UserQuery {
+func (q *WebauthnCredentialQuery) ForShare() *WebauthnCredentialQuery {
 	q.forShare = true
 	return q
 }
 
-func (q *UserQuery) GroupBy(fields ...USER_FIELD) *UserQuery {
+func (q *WebauthnCredentialQuery) GroupBy(fields ...WEBAUTHN_CREDENTIAL_FIELD) *WebauthnCredentialQuery {
 	q.groupByFields = make([]string, len(fields))
 	for i, v := range fields {
 		q.groupByFields[i] = string(v)
@@ -2678,12 +7536,12 @@ func (q *UserQuery) GroupBy(fields ...USER_FIELD) *UserQuery {
 	return q
 }
 
-func (q *UserQuery) Limit(startIncluded int64, count int64) *UserQuery {
+func (q *WebauthnCredentialQuery) Limit(startIncluded int64, count int64) *WebauthnCredentialQuery {
 	q.limit = fmt.Sprintf(" limit %d,%d", startIncluded, count)
 	return q
 }
 
-func (q *UserQuery) OrderBy(fieldName USER_FIELD, asc bool) *UserQuery {
+func (q *WebauthnCredentialQuery) OrderBy(fieldName WEBAUTHN_CREDENTIAL_FIELD, asc bool) *WebauthnCredentialQuery {
 	if q.order != "" {
 		q.order += ","
 	}
@@ -2697,7 +7555,7 @@ func (q *UserQuery) OrderBy(fieldName USER_FIELD, asc bool) *UserQuery {
 	return q
 }
 
-func (q *UserQuery) OrderByGroupCount(asc bool) *UserQuery {
+func (q *WebauthnCredentialQuery) OrderByGroupCount(asc bool) *WebauthnCredentialQuery {
 	if q.order != "" {
 		q.order += ","
 	}
@@ -2711,99 +7569,127 @@ func (q *UserQuery) OrderByGroupCount(asc bool) *UserQuery {
 	return q
 }
 
-func (q *UserQuery) w(format string, a ...interface{}) *UserQuery {
+func (q *WebauthnCredentialQuery) w(format string, a ...interface{}) *WebauthnCredentialQuery {
 	q.where += fmt.Sprintf(format, a...)
 	return q
 }
 
-func (q *UserQuery) Left() *UserQuery  { return q.w(" ( ") }
-func (q *UserQuery) Right() *UserQuery { return q.w(" ) ") }
-func (q *UserQuery) And() *UserQuery   { return q.w(" AND ") }
-func (q *UserQuery) Or() *UserQuery    { return q.w(" OR ") }
-func (q *UserQuery) Not() *UserQuery   { return q.w(" NOT ") }
+func (q *WebauthnCredentialQuery) Left() *WebauthnCredentialQuery  { return q.w(" ( ") }
+func (q *WebauthnCredentialQuery) Right() *WebauthnCredentialQuery { return q.w(" ) ") }
+func (q *WebauthnCredentialQuery) And() *WebauthnCredentialQuery   { return q.w(" AND ") }
+func (q *WebauthnCredentialQuery) Or() *WebauthnCredentialQuery    { return q.w(" OR ") }
+func (q *WebauthnCredentialQuery) Not() *WebauthnCredentialQuery   { return q.w(" NOT ") }
 
-func (q *UserQuery) Id_Equal(v uint64) *UserQuery        { return q.w("id='" + fmt.Sprint(v) + "'") }
-func (q *UserQuery) Id_NotEqual(v uint64) *UserQuery     { return q.w("id<>'" + fmt.Sprint(v) + "'") }
-func (q *UserQuery) Id_Less(v uint64) *UserQuery         { return q.w("id<'" + fmt.Sprint(v) + "'") }
-func (q *UserQuery) Id_LessEqual(v uint64) *UserQuery    { return q.w("id<='" + fmt.Sprint(v) + "'") }
-func (q *UserQuery) Id_Greater(v uint64) *UserQuery      { return q.w("id>'" + fmt.Sprint(v) + "'") }
-func (q *UserQuery) Id_GreaterEqual(v uint64) *UserQuery { return q.w("id>='" + fmt.Sprint(v) + "'") }
-func (q *UserQuery) UserId_Equal(v string) *UserQuery    { return q.w("user_id='" + fmt.Sprint(v) + "'") }
-func (q *UserQuery) UserId_NotEqual(v string) *UserQuery { return q.w("user_id<>'" + fmt.Sprint(v) + "'") }
-func (q *UserQuery) UserId_Less(v string) *UserQuery     { return q.w("user_id<'" + fmt.Sprint(v) + "'") }
-func (q *UserQuery) UserId_LessEqual(v string) *UserQuery {
+func (q *WebauthnCredentialQuery) Id_Equal(v uint64) *WebauthnCredentialQuery {
+	return q.w("id='" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnCredentialQuery) Id_NotEqual(v uint64) *WebauthnCredentialQuery {
+	return q.w("id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnCredentialQuery) Id_Less(v uint64) *WebauthnCredentialQuery {
+	return q.w("id<'" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnCredentialQuery) Id_LessEqual(v uint64) *WebauthnCredentialQuery {
+	return q.w("id<='" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnCredentialQuery) Id_Greater(v uint64) *WebauthnCredentialQuery {
+	return q.w("id>'" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnCredentialQuery) Id_GreaterEqual(v uint64) *WebauthnCredentialQuery {
+	return q.w("id>='" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnCredentialQuery) UserId_Equal(v string) *WebauthnCredentialQuery {
+	return q.w("user_id='" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnCredentialQuery) UserId_NotEqual(v string) *WebauthnCredentialQuery {
+	return q.w("user_id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnCredentialQuery) UserId_Less(v string) *WebauthnCredentialQuery {
+	return q.w("user_id<'" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnCredentialQuery) UserId_LessEqual(v string) *WebauthnCredentialQuery {
 	return q.w("user_id<='" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) UserId_Greater(v string) *UserQuery { return q.w("user_id>'" + fmt.Sprint(v) + "'") }
-func (q *UserQuery) UserId_GreaterEqual(v string) *UserQuery {
+func (q *WebauthnCredentialQuery) UserId_Greater(v string) *WebauthnCredentialQuery {
+	return q.w("user_id>'" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnCredentialQuery) UserId_GreaterEqual(v string) *WebauthnCredentialQuery {
 	return q.w("user_id>='" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) UserName_Equal(v string) *UserQuery { return q.w("user_name='" + fmt.Sprint(v) + "'") }
-func (q *UserQuery) UserName_NotEqual(v string) *UserQuery {
-	return q.w("user_name<>'" + fmt.Sprint(v) + "'")
+func (q *WebauthnCredentialQuery) CredentialId_Equal(v string) *WebauthnCredentialQuery {
+	return q.w("credential_id='" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnCredentialQuery) CredentialId_NotEqual(v string) *WebauthnCredentialQuery {
+	return q.w("credential_id<>'" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnCredentialQuery) CredentialId_Less(v string) *WebauthnCredentialQuery {
+	return q.w("credential_id<'" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnCredentialQuery) CredentialId_LessEqual(v string) *WebauthnCredentialQuery {
+	return q.w("credential_id<='" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnCredentialQuery) CredentialId_Greater(v string) *WebauthnCredentialQuery {
+	return q.w("credential_id>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) UserName_Less(v string) *UserQuery { return q.w("user_name<'" + fmt.Sprint(v) + "'") }
-func (q *UserQuery) UserName_LessEqual(v string) *UserQuery {
-	return q.w("user_name<='" + fmt.Sprint(v) + "'")
+func (q *WebauthnCredentialQuery) CredentialId_GreaterEqual(v string) *WebauthnCredentialQuery {
+	return q.w("credential_id>='" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) UserName_Greater(v string) *UserQuery {
-	return q.w("user_name>'" + fmt.Sprint(v) + "'")
+func (q *WebauthnCredentialQuery) CredentialData_Equal(v string) *WebauthnCredentialQuery {
+	return q.w("credential_data='" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) UserName_GreaterEqual(v string) *UserQuery {
-	return q.w("user_name>='" + fmt.Sprint(v) + "'")
+func (q *WebauthnCredentialQuery) CredentialData_NotEqual(v string) *WebauthnCredentialQuery {
+	return q.w("credential_data<>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) UserIcon_Equal(v string) *UserQuery { return q.w("user_icon='" + fmt.Sprint(v) + "'") }
-func (q *UserQuery) UserIcon_NotEqual(v string) *UserQuery {
-	return q.w("user_icon<>'" + fmt.Sprint(v) + "'")
+func (q *WebauthnCredentialQuery) CredentialData_Less(v string) *WebauthnCredentialQuery {
+	return q.w("credential_data<'" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) UserIcon_Less(v string) *UserQuery { return q.w("user_icon<'" + fmt.Sprint(v) + "'") }
-func (q *UserQuery) UserIcon_LessEqual(v string) *UserQuery {
-	return q.w("user_icon<='" + fmt.Sprint(v) + "'")
+func (q *WebauthnCredentialQuery) CredentialData_LessEqual(v string) *WebauthnCredentialQuery {
+	return q.w("credential_data<='" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) UserIcon_Greater(v string) *UserQuery {
-	return q.w("user_icon>'" + fmt.Sprint(v) + "'")
+func (q *WebauthnCredentialQuery) CredentialData_Greater(v string) *WebauthnCredentialQuery {
+	return q.w("credential_data>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) UserIcon_GreaterEqual(v string) *UserQuery {
-	return q.w("user_icon>='" + fmt.Sprint(v) + "'")
+func (q *WebauthnCredentialQuery) CredentialData_GreaterEqual(v string) *WebauthnCredentialQuery {
+	return q.w("credential_data>='" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) CreateTime_Equal(v time.Time) *UserQuery {
+func (q *WebauthnCredentialQuery) CreateTime_Equal(v time.Time) *WebauthnCredentialQuery {
 	return q.w("create_time='" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) CreateTime_NotEqual(v time.Time) *UserQuery {
+func (q *WebauthnCredentialQuery) CreateTime_NotEqual(v time.Time) *WebauthnCredentialQuery {
 	return q.w("create_time<>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) CreateTime_Less(v time.Time) *UserQuery {
+func (q *WebauthnCredentialQuery) CreateTime_Less(v time.Time) *WebauthnCredentialQuery {
 	return q.w("create_time<'" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) CreateTime_LessEqual(v time.Time) *UserQuery {
+func (q *WebauthnCredentialQuery) CreateTime_LessEqual(v time.Time) *WebauthnCredentialQuery {
 	return q.w("create_time<='" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) CreateTime_Greater(v time.Time) *UserQuery {
+func (q *WebauthnCredentialQuery) CreateTime_Greater(v time.Time) *WebauthnCredentialQuery {
 	return q.w("create_time>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) CreateTime_GreaterEqual(v time.Time) *UserQuery {
+func (q *WebauthnCredentialQuery) CreateTime_GreaterEqual(v time.Time) *WebauthnCredentialQuery {
 	return q.w("create_time>='" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) UpdateTime_Equal(v time.Time) *UserQuery {
+func (q *WebauthnCredentialQuery) UpdateTime_Equal(v time.Time) *WebauthnCredentialQuery {
 	return q.w("update_time='" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) UpdateTime_NotEqual(v time.Time) *UserQuery {
+func (q *WebauthnCredentialQuery) UpdateTime_NotEqual(v time.Time) *WebauthnCredentialQuery {
 	return q.w("update_time<>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) UpdateTime_Less(v time.Time) *UserQuery {
+func (q *WebauthnCredentialQuery) UpdateTime_Less(v time.Time) *WebauthnCredentialQuery {
 	return q.w("update_time<'" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) UpdateTime_LessEqual(v time.Time) *UserQuery {
+func (q *WebauthnCredentialQuery) UpdateTime_LessEqual(v time.Time) *WebauthnCredentialQuery {
 	return q.w("update_time<='" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) UpdateTime_Greater(v time.Time) *UserQuery {
+func (q *WebauthnCredentialQuery) UpdateTime_Greater(v time.Time) *WebauthnCredentialQuery {
 	return q.w("update_time>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserQuery) UpdateTime_GreaterEqual(v time.Time) *UserQuery {
+func (q *WebauthnCredentialQuery) UpdateTime_GreaterEqual(v time.Time) *WebauthnCredentialQuery {
 	return q.w("update_time>='" + fmt.Sprint(v) + "'")
 }
 
-type UserDao struct {
+type WebauthnCredentialDao struct {
 	logger     *zap.Logger
 	db         *DB
 	insertStmt *wrap.Stmt
@@ -2811,8 +7697,8 @@ type UserDao struct {
 	deleteStmt *wrap.Stmt
 }
 
-func NewUserDao(db *DB) (t *UserDao, err error) {
-	t = &UserDao{}
+func NewWebauthnCredentialDao(db *DB) (t *WebauthnCredentialDao, err error) {
+	t = &WebauthnCredentialDao{}
 	t.logger = log.TypedLogger(t)
 	t.db = db
 	err = t.init()
@@ -2823,7 +7709,7 @@ func NewUserDao(db *DB) (t *UserDao, err error) {
 	return t, nil
 }
 
-func (dao *UserDao) init() (err error) {
+func (dao *WebauthnCredentialDao) init() (err error) {
 	err = dao.prepareInsertStmt()
 	if err != nil {
 		return err
@@ -2842,28 +7728,28 @@ func (dao *UserDao) init() (err error) {
 	return nil
 }
 
-func (dao *UserDao) prepareInsertStmt() (err error) {
-	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO user (user_id,user_name,user_icon) VALUES (?,?,?)")
+func (dao *WebauthnCredentialDao) prepareInsertStmt() (err error) {
+	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO webauthn_credential (user_id,credential_id,credential_data) VALUES (?,?,?)")
 	return err
 }
 
-func (dao *UserDao) prepareUpdateStmt() (err error) {
-	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE user SET user_id=?,user_name=?,user_icon=? WHERE id=?")
+func (dao *WebauthnCredentialDao) prepareUpdateStmt() (err error) {
+	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE webauthn_credential SET user_id=?,credential_id=?,credential_data=? WHERE id=?")
 	return err
 }
 
-func (dao *UserDao) prepareDeleteStmt() (err error) {
-	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM user WHERE id=?")
+func (dao *WebauthnCredentialDao) prepareDeleteStmt() (err error) {
+	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM webauthn_credential WHERE id=?")
 	return err
 }
 
-func (dao *UserDao) Insert(ctx context.Context, tx *wrap.Tx, e *User) (id int64, err error) {
+func (dao *WebauthnCredentialDao) Insert(ctx context.Context, tx *wrap.Tx, e *WebauthnCredential) (id int64, err error) {
 	stmt := dao.insertStmt
 	if tx != nil {
 		stmt = tx.Stmt(ctx, stmt)
 	}
 
-	result, err := stmt.Exec(ctx, e.UserId, e.UserName, e.UserIcon)
+	result, err := stmt.Exec(ctx, e.UserId, e.CredentialId, e.CredentialData)
 	if err != nil {
 		return 0, err
 	}
@@ -2876,13 +7762,13 @@ func (dao *UserDao) Insert(ctx context.Context, tx *wrap.Tx, e *User) (id int64,
 	return id, nil
 }
 
-func (dao *UserDao) Update(ctx context.Context, tx *wrap.Tx, e *User) (err error) {
+func (dao *WebauthnCredentialDao) Update(ctx context.Context, tx *wrap.Tx, e *WebauthnCredential) (err error) {
 	stmt := dao.updateStmt
 	if tx != nil {
 		stmt = tx.Stmt(ctx, stmt)
 	}
 
-	_, err = stmt.Exec(ctx, e.UserId, e.UserName, e.UserIcon, e.Id)
+	_, err = stmt.Exec(ctx, e.UserId, e.CredentialId, e.CredentialData, e.Id)
 	if err != nil {
 		return err
 	}
@@ -2890,7 +7776,7 @@ func (dao *UserDao) Update(ctx context.Context, tx *wrap.Tx, e *User) (err error
 	return nil
 }
 
-func (dao *UserDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
+func (dao *WebauthnCredentialDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
 	stmt := dao.deleteStmt
 	if tx != nil {
 		stmt = tx.Stmt(ctx, stmt)
@@ -2904,9 +7790,9 @@ func (dao *UserDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err err
 	return nil
 }
 
-func (dao *UserDao) scanRow(row *wrap.Row) (*User, error) {
-	e := &User{}
-	err := row.Scan(&e.Id, &e.UserId, &e.UserName, &e.UserIcon, &e.CreateTime, &e.UpdateTime)
+func (dao *WebauthnCredentialDao) scanRow(row *wrap.Row) (*WebauthnCredential, error) {
+	e := &WebauthnCredential{}
+	err := row.Scan(&e.Id, &e.UserId, &e.CredentialId, &e.CredentialData, &e.CreateTime, &e.UpdateTime)
 	if err != nil {
 		if err == wrap.ErrNoRows {
 			return nil, nil
@@ -2918,11 +7804,11 @@ func (dao *UserDao) scanRow(row *wrap.Row) (*User, error) {
 	return e, nil
 }
 
-func (dao *UserDao) scanRows(rows *wrap.Rows) (list []*User, err error) {
-	list = make([]*User, 0)
+func (dao *WebauthnCredentialDao) scanRows(rows *wrap.Rows) (list []*WebauthnCredential, err error) {
+	list = make([]*WebauthnCredential, 0)
 	for rows.Next() {
-		e := User{}
-		err = rows.Scan(&e.Id, &e.UserId, &e.UserName, &e.UserIcon, &e.CreateTime, &e.UpdateTime)
+		e := WebauthnCredential{}
+		err = rows.Scan(&e.Id, &e.UserId, &e.CredentialId, &e.CredentialData, &e.CreateTime, &e.UpdateTime)
 		if err != nil {
 			return nil, err
 		}
@@ -2936,8 +7822,8 @@ func (dao *UserDao) scanRows(rows *wrap.Rows) (list []*User, err error) {
 	return list, nil
 }
 
-func (dao *UserDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*User, error) {
-	querySql := "SELECT " + USER_ALL_FIELDS_STRING + " FROM user " + query
+func (dao *WebauthnCredentialDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*WebauthnCredential, error) {
+	querySql := "SELECT " + WEBAUTHN_CREDENTIAL_ALL_FIELDS_STRING + " FROM webauthn_credential " + query
 	var row *wrap.Row
 	if tx == nil {
 		row = dao.db.QueryRow(ctx, querySql)
@@ -2947,8 +7833,8 @@ func (dao *UserDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*U
 	return dao.scanRow(row)
 }
 
-func (dao *UserDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*User, err error) {
-	querySql := "SELECT " + USER_ALL_FIELDS_STRING + " FROM user " + query
+func (dao *WebauthnCredentialDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*WebauthnCredential, err error) {
+	querySql := "SELECT " + WEBAUTHN_CREDENTIAL_ALL_FIELDS_STRING + " FROM webauthn_credential " + query
 	var rows *wrap.Rows
 	if tx == nil {
 		rows, err = dao.db.Query(ctx, querySql)
@@ -2963,8 +7849,8 @@ func (dao *UserDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (l
 	return dao.scanRows(rows)
 }
 
-func (dao *UserDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
-	querySql := "SELECT COUNT(1) FROM user " + query
+func (dao *WebauthnCredentialDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
+	querySql := "SELECT COUNT(1) FROM webauthn_credential " + query
 	var row *wrap.Row
 	if tx == nil {
 		row = dao.db.QueryRow(ctx, querySql)
@@ -2984,8 +7870,8 @@ func (dao *UserDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (
 	return count, nil
 }
 
-func (dao *UserDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
-	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM user " + query
+func (dao *WebauthnCredentialDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
+	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM webauthn_credential " + query
 	if tx == nil {
 		return dao.db.Query(ctx, querySql)
 	} else {
@@ -2993,80 +7879,83 @@ func (dao *UserDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields
 	}
 }
 
-func (dao *UserDao) GetQuery() *UserQuery {
-	return NewUserQuery(dao)
+func (dao *WebauthnCredentialDao) GetQuery() *WebauthnCredentialQuery {
+	return NewWebauthnCredentialQuery(dao)
 }
 
-const USER_OPERATION_TABLE_NAME = "user_operation"
+const WEBAUTHN_SESSION_TABLE_NAME = "webauthn_session"
 
-type USER_OPERATION_FIELD string
+type WEBAUTHN_SESSION_FIELD string
 
-const USER_OPERATION_FIELD_ID = USER_OPERATION_FIELD("id")
-const USER_OPERATION_FIELD_USER_ID = USER_OPERATION_FIELD("user_id")
-const USER_OPERATION_FIELD_OPERATIONTYPE = USER_OPERATION_FIELD("operationType")
-const USER_OPERATION_FIELD_USER_AGENT = USER_OPERATION_FIELD("user_agent")
-const USER_OPERATION_FIELD_PHONE_NUMBER = USER_OPERATION_FIELD("phone_number")
-const USER_OPERATION_FIELD_CREATE_TIME = USER_OPERATION_FIELD("create_time")
+const WEBAUTHN_SESSION_FIELD_ID = WEBAUTHN_SESSION_FIELD("id")
+const WEBAUTHN_SESSION_FIELD_SESSION_ID = WEBAUTHN_SESSION_FIELD("session_id")
+const WEBAUTHN_SESSION_FIELD_USER_ID = WEBAUTHN_SESSION_FIELD("user_id")
+const WEBAUTHN_SESSION_FIELD_SESSION_DATA = WEBAUTHN_SESSION_FIELD("session_data")
+const WEBAUTHN_SESSION_FIELD_IS_USED = WEBAUTHN_SESSION_FIELD("is_used")
+const WEBAUTHN_SESSION_FIELD_CREATE_TIME = WEBAUTHN_SESSION_FIELD("create_time")
+const WEBAUTHN_SESSION_FIELD_UPDATE_TIME = WEBAUTHN_SESSION_FIELD("update_time")
 
-const USER_OPERATION_ALL_FIELDS_STRING = "id,user_id,operationType,user_agent,phone_number,create_time"
+const WEBAUTHN_SESSION_ALL_FIELDS_STRING = "id,session_id,user_id,session_data,is_used,create_time,update_time"
 
-var USER_OPERATION_ALL_FIELDS = []string{
+var WEBAUTHN_SESSION_ALL_FIELDS = []string{
 	"id",
+	"session_id",
 	"user_id",
-	"operationType",
-	"user_agent",
-	"phone_number",
+	"session_data",
+	"is_used",
 	"create_time",
+	"update_time",
 }
 
-type UserOperation struct {
-	Id            uint64 //size=20
-	UserId        string //size=32
-	OperationType string //size=32
-	UserAgent     string //size=256
-	PhoneNumber   string //size=32
-	CreateTime    time.Time
+type WebauthnSession struct {
+	Id          uint64 //size=20
+	SessionId   string //size=128
+	UserId      string //size=32
+	SessionData string
+	IsUsed      int32 //size=1
+	CreateTime  time.Time
+	UpdateTime  time.Time
 }
 
-type UserOperationQuery struct {
+type WebauthnSessionQuery struct {
 	BaseQuery
-	dao *UserOperationDao
+	dao *WebauthnSessionDao
 }
 
-func NewUserOperationQuery(dao *UserOperationDao) *UserOperationQuery {
-	q := &UserOperationQuery{}
+func NewWebauthnSessionQuery(dao *WebauthnSessionDao) *WebauthnSessionQuery {
+	q := &WebauthnSessionQuery{}
 	q.dao = dao
 
 	return q
 }
 
-func (q *UserOperationQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*UserOperation, error) {
+func (q *WebauthnSessionQuery) QueryOne(ctx context.Context, tx *wrap.Tx) (*WebauthnSession, error) {
 	return q.dao.QueryOne(ctx, tx, q.buildQueryString())
 }
 
-func (q *UserOperationQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*UserOperation, err error) {
+func (q *WebauthnSessionQuery) QueryList(ctx context.Context, tx *wrap.Tx) (list []*WebauthnSession, err error) {
 	return q.dao.QueryList(ctx, tx, q.buildQueryString())
 }
 
-func (q *UserOperationQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
+func (q *WebauthnSessionQuery) QueryCount(ctx context.Context, tx *wrap.Tx) (count int64, err error) {
 	return q.dao.QueryCount(ctx, tx, q.buildQueryString())
 }
 
-func (q *UserOperationQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
+func (q *WebauthnSessionQuery) QueryGroupBy(ctx context.Context, tx *wrap.Tx) (rows *wrap.Rows, err error) {
 	return q.dao.QueryGroupBy(ctx, tx, q.groupByFields, q.buildQueryString())
 }
 
-func (q *UserOperationQuery) ForUpdate() *UserOperationQuery {
+func (q *WebauthnSessionQuery) ForUpdate() *WebauthnSessionQuery {
 	q.forUpdate = true
 	return q
 }
 
-func (q *UserOperationQuery) ForShare() *UserOperationQuery {
+func (q *WebauthnSessionQuery) ForShare() *WebauthnSessionQuery {
 	q.forShare = true
 	return q
 }
 
-func (q *UserOperationQuery) GroupBy(fields ...USER_OPERATION_FIELD) *UserOperationQuery {
+func (q *WebauthnSessionQuery) GroupBy(fields ...WEBAUTHN_SESSION_FIELD) *WebauthnSessionQuery {
 	q.groupByFields = make([]string, len(fields))
 	for i, v := range fields {
 		q.groupByFields[i] = string(v)
@@ -3074,12 +7963,12 @@ func (q *UserOperationQuery) GroupBy(fields ...USER_OPERATION_FIELD) *UserOperat
 	return q
 }
 
-func (q *UserOperationQuery) Limit(startIncluded int64, count int64) *UserOperationQuery {
+func (q *WebauthnSessionQuery) Limit(startIncluded int64, count int64) *WebauthnSessionQuery {
 	q.limit = fmt.Sprintf(" limit %d,%d", startIncluded, count)
 	return q
 }
 
-func (q *UserOperationQuery) OrderBy(fieldName USER_OPERATION_FIELD, asc bool) *UserOperationQuery {
+func (q *WebauthnSessionQuery) OrderBy(fieldName WEBAUTHN_SESSION_FIELD, asc bool) *WebauthnSessionQuery {
 	if q.order != "" {
 		q.order += ","
 	}
@@ -3093,7 +7982,7 @@ func (q *UserOperationQuery) OrderBy(fieldName USER_OPERATION_FIELD, asc bool) *
 	return q
 }
 
-func (q *UserOperationQuery) OrderByGroupCount(asc bool) *UserOperationQuery {
+func (q *WebauthnSessionQuery) OrderByGroupCount(asc bool) *WebauthnSessionQuery {
 	if q.order != "" {
 		q.order += ","
 	}
@@ -3107,127 +7996,145 @@ func (q *UserOperationQuery) OrderByGroupCount(asc bool) *UserOperationQuery {
 	return q
 }
 
-func (q *UserOperationQuery) w(format string, a ...interface{}) *UserOperationQuery {
+func (q *WebauthnSessionQuery) w(format string, a ...interface{}) *WebauthnSessionQuery {
 	q.where += fmt.Sprintf(format, a...)
 	return q
 }
 
-func (q *UserOperationQuery) Left() *UserOperationQuery  { return q.w(" ( ") }
-func (q *UserOperationQuery) Right() *UserOperationQuery { return q.w(" ) ") }
-func (q *UserOperationQuery) And() *UserOperationQuery   { return q.w(" AND ") }
-func (q *UserOperationQuery) Or() *UserOperationQuery    { return q.w(" OR ") }
-func (q *UserOperationQuery) Not() *UserOperationQuery   { return q.w(" NOT ") }
+func (q *WebauthnSessionQuery) Left() *WebauthnSessionQuery  { return q.w(" ( ") }
+func (q *WebauthnSessionQuery) Right() *WebauthnSessionQuery { return q.w(" ) ") }
+func (q *WebauthnSessionQuery) And() *WebauthnSessionQuery   { return q.w(" AND ") }
+func (q *WebauthnSessionQuery) Or() *WebauthnSessionQuery    { return q.w(" OR ") }
+func (q *WebauthnSessionQuery) Not() *WebauthnSessionQuery   { return q.w(" NOT ") }
 
-func (q *UserOperationQuery) Id_Equal(v uint64) *UserOperationQuery {
+func (q *WebauthnSessionQuery) Id_Equal(v uint64) *WebauthnSessionQuery {
 	return q.w("id='" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) Id_NotEqual(v uint64) *UserOperationQuery {
+func (q *WebauthnSessionQuery) Id_NotEqual(v uint64) *WebauthnSessionQuery {
 	return q.w("id<>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) Id_Less(v uint64) *UserOperationQuery {
+func (q *WebauthnSessionQuery) Id_Less(v uint64) *WebauthnSessionQuery {
 	return q.w("id<'" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) Id_LessEqual(v uint64) *UserOperationQuery {
+func (q *WebauthnSessionQuery) Id_LessEqual(v uint64) *WebauthnSessionQuery {
 	return q.w("id<='" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) Id_Greater(v uint64) *UserOperationQuery {
+func (q *WebauthnSessionQuery) Id_Greater(v uint64) *WebauthnSessionQuery {
 	return q.w("id>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) Id_GreaterEqual(v uint64) *UserOperationQuery {
+func (q *WebauthnSessionQuery) Id_GreaterEqual(v uint64) *WebauthnSessionQuery {
 	return q.w("id>='" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) UserId_Equal(v string) *UserOperationQuery {
-	return q.w("user_id='" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) SessionId_Equal(v string) *WebauthnSessionQuery {
+	return q.w("session_id='" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) UserId_NotEqual(v string) *UserOperationQuery {
-	return q.w("user_id<>'" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) SessionId_NotEqual(v string) *WebauthnSessionQuery {
+	return q.w("session_id<>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) UserId_Less(v string) *UserOperationQuery {
-	return q.w("user_id<'" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) SessionId_Less(v string) *WebauthnSessionQuery {
+	return q.w("session_id<'" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) UserId_LessEqual(v string) *UserOperationQuery {
-	return q.w("user_id<='" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) SessionId_LessEqual(v string) *WebauthnSessionQuery {
+	return q.w("session_id<='" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) UserId_Greater(v string) *UserOperationQuery {
-	return q.w("user_id>'" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) SessionId_Greater(v string) *WebauthnSessionQuery {
+	return q.w("session_id>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) UserId_GreaterEqual(v string) *UserOperationQuery {
-	return q.w("user_id>='" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) SessionId_GreaterEqual(v string) *WebauthnSessionQuery {
+	return q.w("session_id>='" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) OperationType_Equal(v string) *UserOperationQuery {
-	return q.w("operationType='" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) UserId_Equal(v string) *WebauthnSessionQuery {
+	return q.w("user_id='" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) OperationType_NotEqual(v string) *UserOperationQuery {
-	return q.w("operationType<>'" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) UserId_NotEqual(v string) *WebauthnSessionQuery {
+	return q.w("user_id<>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) OperationType_Less(v string) *UserOperationQuery {
-	return q.w("operationType<'" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) UserId_Less(v string) *WebauthnSessionQuery {
+	return q.w("user_id<'" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) OperationType_LessEqual(v string) *UserOperationQuery {
-	return q.w("operationType<='" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) UserId_LessEqual(v string) *WebauthnSessionQuery {
+	return q.w("user_id<='" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) OperationType_Greater(v string) *UserOperationQuery {
-	return q.w("operationType>'" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) UserId_Greater(v string) *WebauthnSessionQuery {
+	return q.w("user_id>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) OperationType_GreaterEqual(v string) *UserOperationQuery {
-	return q.w("operationType>='" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) UserId_GreaterEqual(v string) *WebauthnSessionQuery {
+	return q.w("user_id>='" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) UserAgent_Equal(v string) *UserOperationQuery {
-	return q.w("user_agent='" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) SessionData_Equal(v string) *WebauthnSessionQuery {
+	return q.w("session_data='" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) UserAgent_NotEqual(v string) *UserOperationQuery {
-	return q.w("user_agent<>'" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) SessionData_NotEqual(v string) *WebauthnSessionQuery {
+	return q.w("session_data<>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) UserAgent_Less(v string) *UserOperationQuery {
-	return q.w("user_agent<'" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) SessionData_Less(v string) *WebauthnSessionQuery {
+	return q.w("session_data<'" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) UserAgent_LessEqual(v string) *UserOperationQuery {
-	return q.w("user_agent<='" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) SessionData_LessEqual(v string) *WebauthnSessionQuery {
+	return q.w("session_data<='" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) UserAgent_Greater(v string) *UserOperationQuery {
-	return q.w("user_agent>'" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) SessionData_Greater(v string) *WebauthnSessionQuery {
+	return q.w("session_data>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) UserAgent_GreaterEqual(v string) *UserOperationQuery {
-	return q.w("user_agent>='" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) SessionData_GreaterEqual(v string) *WebauthnSessionQuery {
+	return q.w("session_data>='" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) PhoneNumber_Equal(v string) *UserOperationQuery {
-	return q.w("phone_number='" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) IsUsed_Equal(v int32) *WebauthnSessionQuery {
+	return q.w("is_used='" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) PhoneNumber_NotEqual(v string) *UserOperationQuery {
-	return q.w("phone_number<>'" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) IsUsed_NotEqual(v int32) *WebauthnSessionQuery {
+	return q.w("is_used<>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) PhoneNumber_Less(v string) *UserOperationQuery {
-	return q.w("phone_number<'" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) IsUsed_Less(v int32) *WebauthnSessionQuery {
+	return q.w("is_used<'" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) PhoneNumber_LessEqual(v string) *UserOperationQuery {
-	return q.w("phone_number<='" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) IsUsed_LessEqual(v int32) *WebauthnSessionQuery {
+	return q.w("is_used<='" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) PhoneNumber_Greater(v string) *UserOperationQuery {
-	return q.w("phone_number>'" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) IsUsed_Greater(v int32) *WebauthnSessionQuery {
+	return q.w("is_used>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) PhoneNumber_GreaterEqual(v string) *UserOperationQuery {
-	return q.w("phone_number>='" + fmt.Sprint(v) + "'")
+func (q *WebauthnSessionQuery) IsUsed_GreaterEqual(v int32) *WebauthnSessionQuery {
+	return q.w("is_used>='" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) CreateTime_Equal(v time.Time) *UserOperationQuery {
+func (q *WebauthnSessionQuery) CreateTime_Equal(v time.Time) *WebauthnSessionQuery {
 	return q.w("create_time='" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) CreateTime_NotEqual(v time.Time) *UserOperationQuery {
+func (q *WebauthnSessionQuery) CreateTime_NotEqual(v time.Time) *WebauthnSessionQuery {
 	return q.w("create_time<>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) CreateTime_Less(v time.Time) *UserOperationQuery {
+func (q *WebauthnSessionQuery) CreateTime_Less(v time.Time) *WebauthnSessionQuery {
 	return q.w("create_time<'" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) CreateTime_LessEqual(v time.Time) *UserOperationQuery {
+func (q *WebauthnSessionQuery) CreateTime_LessEqual(v time.Time) *WebauthnSessionQuery {
 	return q.w("create_time<='" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) CreateTime_Greater(v time.Time) *UserOperationQuery {
+func (q *WebauthnSessionQuery) CreateTime_Greater(v time.Time) *WebauthnSessionQuery {
 	return q.w("create_time>'" + fmt.Sprint(v) + "'")
 }
-func (q *UserOperationQuery) CreateTime_GreaterEqual(v time.Time) *UserOperationQuery {
+func (q *WebauthnSessionQuery) CreateTime_GreaterEqual(v time.Time) *WebauthnSessionQuery {
 	return q.w("create_time>='" + fmt.Sprint(v) + "'")
 }
+func (q *WebauthnSessionQuery) UpdateTime_Equal(v time.Time) *WebauthnSessionQuery {
+	return q.w("update_time='" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnSessionQuery) UpdateTime_NotEqual(v time.Time) *WebauthnSessionQuery {
+	return q.w("update_time<>'" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnSessionQuery) UpdateTime_Less(v time.Time) *WebauthnSessionQuery {
+	return q.w("update_time<'" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnSessionQuery) UpdateTime_LessEqual(v time.Time) *WebauthnSessionQuery {
+	return q.w("update_time<='" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnSessionQuery) UpdateTime_Greater(v time.Time) *WebauthnSessionQuery {
+	return q.w("update_time>'" + fmt.Sprint(v) + "'")
+}
+func (q *WebauthnSessionQuery) UpdateTime_GreaterEqual(v time.Time) *WebauthnSessionQuery {
+	return q.w("update_time>='" + fmt.Sprint(v) + "'")
+}
 
-type UserOperationDao struct {
+type WebauthnSessionDao struct {
 	logger     *zap.Logger
 	db         *DB
 	insertStmt *wrap.Stmt
@@ -3235,8 +8142,8 @@ type UserOperationDao struct {
 	deleteStmt *wrap.Stmt
 }
 
-func NewUserOperationDao(db *DB) (t *UserOperationDao, err error) {
-	t = &UserOperationDao{}
+func NewWebauthnSessionDao(db *DB) (t *WebauthnSessionDao, err error) {
+	t = &WebauthnSessionDao{}
 	t.logger = log.TypedLogger(t)
 	t.db = db
 	err = t.init()
@@ -3247,7 +8154,7 @@ func NewUserOperationDao(db *DB) (t *UserOperationDao, err error) {
 	return t, nil
 }
 
-func (dao *UserOperationDao) init() (err error) {
+func (dao *WebauthnSessionDao) init() (err error) {
 	err = dao.prepareInsertStmt()
 	if err != nil {
 		return err
@@ -3266,28 +8173,28 @@ func (dao *UserOperationDao) init() (err error) {
 	return nil
 }
 
-func (dao *UserOperationDao) prepareInsertStmt() (err error) {
-	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO user_operation (user_id,operationType,user_agent,phone_number) VALUES (?,?,?,?)")
+func (dao *WebauthnSessionDao) prepareInsertStmt() (err error) {
+	dao.insertStmt, err = dao.db.Prepare(context.Background(), "INSERT INTO webauthn_session (session_id,user_id,session_data,is_used) VALUES (?,?,?,?)")
 	return err
 }
 
-func (dao *UserOperationDao) prepareUpdateStmt() (err error) {
-	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE user_operation SET user_id=?,operationType=?,user_agent=?,phone_number=? WHERE id=?")
+func (dao *WebauthnSessionDao) prepareUpdateStmt() (err error) {
+	dao.updateStmt, err = dao.db.Prepare(context.Background(), "UPDATE webauthn_session SET session_id=?,user_id=?,session_data=?,is_used=? WHERE id=?")
 	return err
 }
 
-func (dao *UserOperationDao) prepareDeleteStmt() (err error) {
-	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM user_operation WHERE id=?")
+func (dao *WebauthnSessionDao) prepareDeleteStmt() (err error) {
+	dao.deleteStmt, err = dao.db.Prepare(context.Background(), "DELETE FROM webauthn_session WHERE id=?")
 	return err
 }
 
-func (dao *UserOperationDao) Insert(ctx context.Context, tx *wrap.Tx, e *UserOperation) (id int64, err error) {
+func (dao *WebauthnSessionDao) Insert(ctx context.Context, tx *wrap.Tx, e *WebauthnSession) (id int64, err error) {
 	stmt := dao.insertStmt
 	if tx != nil {
 		stmt = tx.Stmt(ctx, stmt)
 	}
 
-	result, err := stmt.Exec(ctx, e.UserId, e.OperationType, e.UserAgent, e.PhoneNumber)
+	result, err := stmt.Exec(ctx, e.SessionId, e.UserId, e.SessionData, e.IsUsed)
 	if err != nil {
 		return 0, err
 	}
@@ -3300,13 +8207,13 @@ func (dao *UserOperationDao) Insert(ctx context.Context, tx *wrap.Tx, e *UserOpe
 	return id, nil
 }
 
-func (dao *UserOperationDao) Update(ctx context.Context, tx *wrap.Tx, e *UserOperation) (err error) {
+func (dao *WebauthnSessionDao) Update(ctx context.Context, tx *wrap.Tx, e *WebauthnSession) (err error) {
 	stmt := dao.updateStmt
 	if tx != nil {
 		stmt = tx.Stmt(ctx, stmt)
 	}
 
-	_, err = stmt.Exec(ctx, e.UserId, e.OperationType, e.UserAgent, e.PhoneNumber, e.Id)
+	_, err = stmt.Exec(ctx, e.SessionId, e.UserId, e.SessionData, e.IsUsed, e.Id)
 	if err != nil {
 		return err
 	}
@@ -3314,7 +8221,7 @@ func (dao *UserOperationDao) Update(ctx context.Context, tx *wrap.Tx, e *UserOpe
 	return nil
 }
 
-func (dao *UserOperationDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
+func (dao *WebauthnSessionDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64) (err error) {
 	stmt := dao.deleteStmt
 	if tx != nil {
 		stmt = tx.Stmt(ctx, stmt)
@@ -3328,9 +8235,9 @@ func (dao *UserOperationDao) Delete(ctx context.Context, tx *wrap.Tx, id uint64)
 	return nil
 }
 
-func (dao *UserOperationDao) scanRow(row *wrap.Row) (*UserOperation, error) {
-	e := &UserOperation{}
-	err := row.Scan(&e.Id, &e.UserId, &e.OperationType, &e.UserAgent, &e.PhoneNumber, &e.CreateTime)
+func (dao *WebauthnSessionDao) scanRow(row *wrap.Row) (*WebauthnSession, error) {
+	e := &WebauthnSession{}
+	err := row.Scan(&e.Id, &e.SessionId, &e.UserId, &e.SessionData, &e.IsUsed, &e.CreateTime, &e.UpdateTime)
 	if err != nil {
 		if err == wrap.ErrNoRows {
 			return nil, nil
@@ -3342,11 +8249,11 @@ func (dao *UserOperationDao) scanRow(row *wrap.Row) (*UserOperation, error) {
 	return e, nil
 }
 
-func (dao *UserOperationDao) scanRows(rows *wrap.Rows) (list []*UserOperation, err error) {
-	list = make([]*UserOperation, 0)
+func (dao *WebauthnSessionDao) scanRows(rows *wrap.Rows) (list []*WebauthnSession, err error) {
+	list = make([]*WebauthnSession, 0)
 	for rows.Next() {
-		e := UserOperation{}
-		err = rows.Scan(&e.Id, &e.UserId, &e.OperationType, &e.UserAgent, &e.PhoneNumber, &e.CreateTime)
+		e := WebauthnSession{}
+		err = rows.Scan(&e.Id, &e.SessionId, &e.UserId, &e.SessionData, &e.IsUsed, &e.CreateTime, &e.UpdateTime)
 		if err != nil {
 			return nil, err
 		}
@@ -3360,8 +8267,8 @@ func (dao *UserOperationDao) scanRows(rows *wrap.Rows) (list []*UserOperation, e
 	return list, nil
 }
 
-func (dao *UserOperationDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*UserOperation, error) {
-	querySql := "SELECT " + USER_OPERATION_ALL_FIELDS_STRING + " FROM user_operation " + query
+func (dao *WebauthnSessionDao) QueryOne(ctx context.Context, tx *wrap.Tx, query string) (*WebauthnSession, error) {
+	querySql := "SELECT " + WEBAUTHN_SESSION_ALL_FIELDS_STRING + " FROM webauthn_session " + query
 	var row *wrap.Row
 	if tx == nil {
 		row = dao.db.QueryRow(ctx, querySql)
@@ -3371,8 +8278,8 @@ func (dao *UserOperationDao) QueryOne(ctx context.Context, tx *wrap.Tx, query st
 	return dao.scanRow(row)
 }
 
-func (dao *UserOperationDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*UserOperation, err error) {
-	querySql := "SELECT " + USER_OPERATION_ALL_FIELDS_STRING + " FROM user_operation " + query
+func (dao *WebauthnSessionDao) QueryList(ctx context.Context, tx *wrap.Tx, query string) (list []*WebauthnSession, err error) {
+	querySql := "SELECT " + WEBAUTHN_SESSION_ALL_FIELDS_STRING + " FROM webauthn_session " + query
 	var rows *wrap.Rows
 	if tx == nil {
 		rows, err = dao.db.Query(ctx, querySql)
@@ -3387,8 +8294,8 @@ func (dao *UserOperationDao) QueryList(ctx context.Context, tx *wrap.Tx, query s
 	return dao.scanRows(rows)
 }
 
-func (dao *UserOperationDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
-	querySql := "SELECT COUNT(1) FROM user_operation " + query
+func (dao *WebauthnSessionDao) QueryCount(ctx context.Context, tx *wrap.Tx, query string) (count int64, err error) {
+	querySql := "SELECT COUNT(1) FROM webauthn_session " + query
 	var row *wrap.Row
 	if tx == nil {
 		row = dao.db.QueryRow(ctx, querySql)
@@ -3408,8 +8315,8 @@ func (dao *UserOperationDao) QueryCount(ctx context.Context, tx *wrap.Tx, query
 	return count, nil
 }
 
-func (dao *UserOperationDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
-	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM user_operation " + query
+func (dao *WebauthnSessionDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, groupByFields []string, query string) (rows *wrap.Rows, err error) {
+	querySql := "SELECT " + strings.Join(groupByFields, ",") + ",count(1) FROM webauthn_session " + query
 	if tx == nil {
 		return dao.db.Query(ctx, querySql)
 	} else {
@@ -3417,80 +8324,6 @@ func (dao *UserOperationDao) QueryGroupBy(ctx context.Context, tx *wrap.Tx, grou
 	}
 }
 
-func (dao *UserOperationDao) GetQuery() *UserOperationQuery {
-	return NewUserOperationQuery(dao)
-}
-
-type DB struct {
-	wrap.DB
-	AccessToken   *AccessTokenDao
-	LoginSmsCode  *LoginSmsCodeDao
-	OauthAccount  *OauthAccountDao
-	OauthState    *OauthStateDao
-	PhoneAccount  *PhoneAccountDao
-	RefreshToken  *RefreshTokenDao
-	User          *UserDao
-	UserOperation *UserOperationDao
-}
-
-func NewDB() (d *DB, err error) {
-	d = &DB{}
-
-	connectionString := os.Getenv("DB")
-	if connectionString == "" {
-		return nil, fmt.Errorf("DB env nil")
-	}
-	connectionString += "/neuron-user?parseTime=true"
-	db, err := wrap.Open("mysql", connectionString)
-	if err != nil {
-		return nil, err
-	}
-	d.DB = *db
-
-	err = d.Ping(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
-	d.AccessToken, err = NewAccessTokenDao(d)
-	if err != nil {
-		return nil, err
-	}
-
-	d.LoginSmsCode, err = NewLoginSmsCodeDao(d)
-	if err != nil {
-		return nil, err
-	}
-
-	d.OauthAccount, err = NewOauthAccountDao(d)
-	if err != nil {
-		return nil, err
-	}
-
-	d.OauthState, err = NewOauthStateDao(d)
-	if err != nil {
-		return nil, err
-	}
-
-	d.PhoneAccount, err = NewPhoneAccountDao(d)
-	if err != nil {
-		return nil, err
-	}
-
-	d.RefreshToken, err = NewRefreshTokenDao(d)
-	if err != nil {
-		return nil, err
-	}
-
-	d.User, err = NewUserDao(d)
-	if err != nil {
-		return nil, err
-	}
-
-	d.UserOperation, err = NewUserOperationDao(d)
-	if err != nil {
-		return nil, err
-	}
-
-	return d, nil
+func (dao *WebauthnSessionDao) GetQuery() *WebauthnSessionQuery {
+	return NewWebauthnSessionQuery(dao)
 }
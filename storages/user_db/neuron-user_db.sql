@@ -26,11 +26,14 @@ CREATE TABLE `access_token` (
   `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
   `user_id` varchar(32) NOT NULL,
   `access_token` varchar(1024) NOT NULL,
+  `session_id` varchar(32) NOT NULL DEFAULT '',
+  `is_revoked` tinyint(1) NOT NULL DEFAULT '0',
   `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   PRIMARY KEY (`id`),
   UNIQUE KEY `idx_access_token` (`access_token`),
   KEY `idx_user_id` (`user_id`),
+  KEY `idx_session_id` (`session_id`),
   KEY `idx_update` (`update_time`)
 ) ENGINE=InnoDB AUTO_INCREMENT=2 DEFAULT CHARSET=utf8;
 /*!40101 SET character_set_client = @saved_cs_client */;
@@ -45,15 +48,39 @@ DROP TABLE IF EXISTS `login_sms_code`;
 CREATE TABLE `login_sms_code` (
   `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
   `phone_number` varchar(32) NOT NULL,
-  `sms_code` varchar(8) NOT NULL,
+  `sms_code` varchar(128) NOT NULL,
+  `client_ip` varchar(64) NOT NULL DEFAULT '',
+  `verify_count` int(11) NOT NULL DEFAULT '0',
+  `is_used` tinyint(1) NOT NULL DEFAULT '0',
   `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   PRIMARY KEY (`id`),
   KEY `idx_update` (`update_time`),
-  KEY `idx_phone` (`phone_number`)
+  KEY `idx_phone` (`phone_number`),
+  KEY `idx_client_ip` (`client_ip`)
 ) ENGINE=InnoDB AUTO_INCREMENT=4 DEFAULT CHARSET=utf8;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `mfa_recovery_code`
+--
+
+DROP TABLE IF EXISTS `mfa_recovery_code`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `mfa_recovery_code` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` varchar(32) NOT NULL,
+  `code_hash` varchar(128) NOT NULL,
+  `is_used` tinyint(1) NOT NULL DEFAULT '0',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_user_id` (`user_id`),
+  KEY `idx_update` (`update_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `oauth_account`
 --
@@ -68,15 +95,66 @@ CREATE TABLE `oauth_account` (
   `oauth_open_id` varchar(128) NOT NULL,
   `oauth_name` varchar(32) NOT NULL,
   `oauth_icon` varchar(256) NOT NULL,
+  `mirror_source` varchar(256) NOT NULL DEFAULT '',
+  `mirror_icon` varchar(256) NOT NULL DEFAULT '',
   `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `update_time` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   PRIMARY KEY (`id`),
   UNIQUE KEY `idx_oauth_provider_account` (`oauth_provider`,`oauth_open_id`),
   KEY `idx_user_id` (`user_id`),
   KEY `idx_update` (`update_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `oauth_authorization_code`
+--
+
+DROP TABLE IF EXISTS `oauth_authorization_code`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `oauth_authorization_code` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `code` varchar(128) NOT NULL,
+  `client_id` varchar(64) NOT NULL,
+  `user_id` varchar(32) NOT NULL,
+  `redirect_uri` varchar(1024) NOT NULL,
+  `scope` varchar(256) NOT NULL,
+  `nonce` varchar(256) NOT NULL,
+  `code_challenge` varchar(128) NOT NULL,
+  `session_id` varchar(32) NOT NULL DEFAULT '',
+  `is_used` tinyint(1) NOT NULL DEFAULT '0',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_code` (`code`),
+  KEY `idx_update` (`update_time`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `oauth_client`
+--
+
+DROP TABLE IF EXISTS `oauth_client`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `oauth_client` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `client_id` varchar(64) NOT NULL,
+  `client_secret` varchar(128) NOT NULL,
+  `client_name` varchar(128) NOT NULL,
+  `redirect_uris` varchar(2048) NOT NULL,
+  `grant_types` varchar(256) NOT NULL,
+  `scope` varchar(256) NOT NULL,
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_client_id` (`client_id`),
+  KEY `idx_update` (`update_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `oauth_state`
 --
@@ -97,6 +175,26 @@ CREATE TABLE `oauth_state` (
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `password_account`
+--
+
+DROP TABLE IF EXISTS `password_account`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `password_account` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` varchar(32) NOT NULL,
+  `password_hash` varchar(256) NOT NULL,
+  `failed_count` int(11) NOT NULL DEFAULT '0',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_user_id` (`user_id`),
+  KEY `idx_update` (`update_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `phone_account`
 --
@@ -128,6 +226,12 @@ CREATE TABLE `refresh_token` (
   `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
   `user_id` varchar(32) NOT NULL,
   `refresh_token` varchar(128) NOT NULL,
+  `session_id` varchar(32) NOT NULL DEFAULT '',
+  `user_agent` varchar(256) NOT NULL DEFAULT '',
+  `client_ip` varchar(64) NOT NULL DEFAULT '',
+  `login_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `client_id` varchar(64) NOT NULL DEFAULT '',
+  `scope` varchar(256) NOT NULL DEFAULT '',
   `is_logout` tinyint(1) NOT NULL,
   `logout_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
@@ -135,10 +239,33 @@ CREATE TABLE `refresh_token` (
   PRIMARY KEY (`id`),
   UNIQUE KEY `idx_refresh_token` (`refresh_token`),
   KEY `idx_user_id` (`user_id`),
+  KEY `idx_session_id` (`session_id`),
   KEY `idx_update` (`update_time`)
 ) ENGINE=InnoDB AUTO_INCREMENT=3 DEFAULT CHARSET=utf8;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `totp_account`
+--
+
+DROP TABLE IF EXISTS `totp_account`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `totp_account` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` varchar(32) NOT NULL,
+  `totp_secret` varchar(256) NOT NULL,
+  `is_enabled` tinyint(1) NOT NULL DEFAULT '0',
+  `last_used_step` bigint(20) NOT NULL DEFAULT '0',
+  `failed_count` int(11) NOT NULL DEFAULT '0',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_user_id` (`user_id`),
+  KEY `idx_update` (`update_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `user`
 --
@@ -151,13 +278,60 @@ CREATE TABLE `user` (
   `user_id` varchar(32) NOT NULL,
   `user_name` varchar(32) NOT NULL,
   `user_icon` varchar(256) NOT NULL,
+  `bio` varchar(256) NOT NULL DEFAULT '',
+  `gender` varchar(16) NOT NULL DEFAULT '',
+  `birthday` varchar(10) NOT NULL DEFAULT '',
+  `locale` varchar(32) NOT NULL DEFAULT '',
+  `timezone` varchar(64) NOT NULL DEFAULT '',
   `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   PRIMARY KEY (`id`),
   UNIQUE KEY `idx_user_id` (`user_id`),
   UNIQUE KEY `udx_user_name` (`user_name`),
   KEY `idx_update` (`update_time`)
-) ENGINE=InnoDB AUTO_INCREMENT=2 DEFAULT CHARSET=utf8;
+) ENGINE=InnoDB AUTO_INCREMENT=2 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `user_attribute`
+--
+
+DROP TABLE IF EXISTS `user_attribute`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `user_attribute` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` varchar(32) NOT NULL,
+  `namespace` varchar(32) NOT NULL,
+  `attr_key` varchar(64) NOT NULL,
+  `attr_value` varchar(1024) NOT NULL,
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_user_attribute` (`user_id`,`namespace`,`attr_key`),
+  KEY `idx_update` (`update_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `user_name_history`
+--
+
+DROP TABLE IF EXISTS `user_name_history`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `user_name_history` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` varchar(32) NOT NULL,
+  `user_name` varchar(32) NOT NULL,
+  `reserved_until` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_user_id` (`user_id`),
+  KEY `idx_user_name` (`user_name`),
+  KEY `idx_update` (`update_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
 --
@@ -173,13 +347,76 @@ CREATE TABLE `user_operation` (
   `operationType` varchar(32) NOT NULL,
   `user_agent` varchar(256) NOT NULL,
   `phone_number` varchar(32) NOT NULL,
+  `actor_id` varchar(32) NOT NULL DEFAULT '',
+  `reason` varchar(256) NOT NULL DEFAULT '',
   `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   PRIMARY KEY (`id`),
   KEY `idx_user_id` (`user_id`),
   KEY `idx_create_time` (`create_time`),
-  KEY `idx_phone` (`phone_number`)
+  KEY `idx_phone` (`phone_number`),
+  KEY `idx_actor_id` (`actor_id`)
 ) ENGINE=InnoDB AUTO_INCREMENT=3 DEFAULT CHARSET=utf8;
 /*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `user_role`
+--
+
+DROP TABLE IF EXISTS `user_role`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `user_role` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` varchar(32) NOT NULL,
+  `role` varchar(32) NOT NULL,
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_user_role` (`user_id`,`role`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `webauthn_credential`
+--
+
+DROP TABLE IF EXISTS `webauthn_credential`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `webauthn_credential` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` varchar(32) NOT NULL,
+  `credential_id` varchar(255) NOT NULL,
+  `credential_data` text NOT NULL,
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_credential_id` (`credential_id`),
+  KEY `idx_user_id` (`user_id`),
+  KEY `idx_update` (`update_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `webauthn_session`
+--
+
+DROP TABLE IF EXISTS `webauthn_session`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `webauthn_session` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `session_id` varchar(128) NOT NULL,
+  `user_id` varchar(32) NOT NULL,
+  `session_data` text NOT NULL,
+  `is_used` tinyint(1) NOT NULL DEFAULT '0',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_session_id` (`session_id`),
+  KEY `idx_update` (`update_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
 /*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
 
 /*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
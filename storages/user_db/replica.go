@@ -0,0 +1,79 @@
+package user_db
+
+import (
+	"context"
+	"github.com/NeuronFramework/sql/wrap"
+)
+
+type primaryKey struct{}
+
+// WithPrimary marks ctx so that reads made with it skip the replica, e.g. to
+// read a row the caller has just written.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func isPrimary(ctx context.Context) bool {
+	v, _ := ctx.Value(primaryKey{}).(bool)
+	return v
+}
+
+func (d *DB) connect(ctx context.Context, config *Config) (err error) {
+	d.config = config
+	if config.Driver == DriverSqlite {
+		return d.connectSqlite(ctx, config)
+	}
+
+	db, err := config.Connect(ctx, config.Addr)
+	if err != nil {
+		return err
+	}
+	d.DB = *db
+
+	d.locks, err = config.openLocks()
+	if err != nil {
+		d.DB.Close()
+		return err
+	}
+
+	if config.ReplicaAddr != "" {
+		d.replica, err = config.Connect(ctx, config.ReplicaAddr)
+		if err != nil {
+			d.locks.Close()
+			d.DB.Close()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// The generated DAOs call Query/QueryRow on DB only when they are not given
+// a transaction, so these are the reads that may go to the replica. Locking
+// reads are marked WithPrimary by the storage layer and stay on the primary.
+func (d *DB) reader(ctx context.Context) *wrap.DB {
+	if d.replica == nil || isPrimary(ctx) {
+		return &d.DB
+	}
+
+	return d.replica
+}
+
+func (d *DB) Query(ctx context.Context, query string, args ...interface{}) (*wrap.Rows, error) {
+	return d.reader(ctx).Query(ctx, query, args...)
+}
+
+func (d *DB) QueryRow(ctx context.Context, query string, args ...interface{}) *wrap.Row {
+	return d.reader(ctx).QueryRow(ctx, query, args...)
+}
+
+func (d *DB) Close() error {
+	if d.replica != nil {
+		d.replica.Close()
+	}
+	if d.locks != nil {
+		d.locks.Close()
+	}
+
+	return d.DB.Close()
+}
@@ -0,0 +1,89 @@
+package user_db
+
+import (
+	"context"
+	"fmt"
+	"github.com/NeuronFramework/sql/wrap"
+	"github.com/go-sql-driver/mysql"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+	"net/url"
+)
+
+func (d *DB) Driver() string {
+	return d.config.Driver
+}
+
+// connectSqlite opens config.Addr as a SQLite file and brings its schema up
+// to date. Transactions take the write lock immediately, which stands in for
+// the FOR UPDATE reads SQLite does not support.
+func (d *DB) connectSqlite(ctx context.Context, config *Config) (err error) {
+	params := url.Values{}
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Set("_txlock", "immediate")
+	params.Set("_time_format", "sqlite")
+
+	db, err := wrap.Open(DriverSqlite, "file:"+config.Addr+"?"+params.Encode())
+	if err != nil {
+		return err
+	}
+	d.DB = *db
+
+	err = d.Ping(ctx)
+	if err != nil {
+		return err
+	}
+
+	return d.migrateSqlite(ctx)
+}
+
+// migrateSqlite applies the migrations the database is missing. Each runs
+// in a transaction together with its user_version update, so a failed
+// migration leaves neither half-applied statements nor a version claiming
+// it ran. The version is read again inside the transaction, which holds the
+// write lock, so instances starting together don't apply a migration twice.
+func (d *DB) migrateSqlite(ctx context.Context) (err error) {
+	version := 0
+	err = d.DB.QueryRow(ctx, "PRAGMA user_version").Scan(&version)
+	if err != nil {
+		return err
+	}
+
+	for i := version; i < len(sqliteMigrations); i++ {
+		err = d.DB.TransactionReadCommitted(ctx, false, func(tx *wrap.Tx) error {
+			version := 0
+			err := tx.QueryRow(ctx, "PRAGMA user_version").Scan(&version)
+			if err != nil {
+				return err
+			}
+			if version > i {
+				return nil
+			}
+
+			_, err = tx.Exec(ctx, sqliteMigrations[i])
+			if err != nil {
+				return fmt.Errorf("sqlite migration %d: %v", i+1, err)
+			}
+
+			_, err = tx.Exec(ctx, fmt.Sprintf("PRAGMA user_version = %d", i+1))
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func IsDuplicateEntry(err error) bool {
+	switch e := err.(type) {
+	case *mysql.MySQLError:
+		return e.Number == 1062
+	case *sqlite.Error:
+		return e.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || e.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+	default:
+		return false
+	}
+}
@@ -0,0 +1,358 @@
+package user_db
+
+// sqliteMigrations translate neuron-user_db.sql (and the files in migrations/)
+// for SQLite. Entry i upgrades a database from PRAGMA user_version i to i+1,
+// so new schema changes must be appended, never edited in place.
+var sqliteMigrations = []string{
+	// initial schema
+	`
+CREATE TABLE access_token (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id VARCHAR(32) NOT NULL,
+  access_token VARCHAR(1024) NOT NULL,
+  create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX access_token_idx_access_token ON access_token (access_token);
+CREATE INDEX access_token_idx_user_id ON access_token (user_id);
+CREATE INDEX access_token_idx_update ON access_token (update_time);
+CREATE TRIGGER access_token_update_time AFTER UPDATE ON access_token FOR EACH ROW WHEN NEW.update_time IS OLD.update_time
+BEGIN
+  UPDATE access_token SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TABLE login_sms_code (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  phone_number VARCHAR(32) NOT NULL,
+  sms_code VARCHAR(8) NOT NULL,
+  create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX login_sms_code_idx_update ON login_sms_code (update_time);
+CREATE INDEX login_sms_code_idx_phone ON login_sms_code (phone_number);
+CREATE TRIGGER login_sms_code_update_time AFTER UPDATE ON login_sms_code FOR EACH ROW WHEN NEW.update_time IS OLD.update_time
+BEGIN
+  UPDATE login_sms_code SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TABLE oauth_account (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id VARCHAR(32) NOT NULL,
+  oauth_provider VARCHAR(32) NOT NULL,
+  oauth_open_id VARCHAR(128) NOT NULL,
+  oauth_name VARCHAR(32) NOT NULL,
+  oauth_icon VARCHAR(256) NOT NULL,
+  create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  update_time TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX oauth_account_idx_oauth_provider_account ON oauth_account (oauth_provider,oauth_open_id);
+CREATE INDEX oauth_account_idx_user_id ON oauth_account (user_id);
+CREATE INDEX oauth_account_idx_update ON oauth_account (update_time);
+CREATE TRIGGER oauth_account_update_time AFTER UPDATE ON oauth_account FOR EACH ROW WHEN NEW.update_time IS OLD.update_time
+BEGIN
+  UPDATE oauth_account SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TABLE oauth_state (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  oauth_state VARCHAR(128) NOT NULL,
+  is_used TINYINT(1) NOT NULL,
+  user_agent VARCHAR(256) NOT NULL,
+  create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX oauth_state_idx_state ON oauth_state (oauth_state);
+CREATE INDEX oauth_state_idx_update ON oauth_state (update_time);
+CREATE TRIGGER oauth_state_update_time AFTER UPDATE ON oauth_state FOR EACH ROW WHEN NEW.update_time IS OLD.update_time
+BEGIN
+  UPDATE oauth_state SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TABLE phone_account (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id VARCHAR(32) NOT NULL,
+  phone_number VARCHAR(32) NOT NULL,
+  create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX phone_account_idx_phone ON phone_account (phone_number);
+CREATE UNIQUE INDEX phone_account_idx_user_id ON phone_account (user_id);
+CREATE INDEX phone_account_idx_update ON phone_account (update_time);
+CREATE TRIGGER phone_account_update_time AFTER UPDATE ON phone_account FOR EACH ROW WHEN NEW.update_time IS OLD.update_time
+BEGIN
+  UPDATE phone_account SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TABLE refresh_token (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id VARCHAR(32) NOT NULL,
+  refresh_token VARCHAR(128) NOT NULL,
+  is_logout TINYINT(1) NOT NULL,
+  logout_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX refresh_token_idx_refresh_token ON refresh_token (refresh_token);
+CREATE INDEX refresh_token_idx_user_id ON refresh_token (user_id);
+CREATE INDEX refresh_token_idx_update ON refresh_token (update_time);
+CREATE TRIGGER refresh_token_update_time AFTER UPDATE ON refresh_token FOR EACH ROW WHEN NEW.update_time IS OLD.update_time
+BEGIN
+  UPDATE refresh_token SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TABLE user (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id VARCHAR(32) NOT NULL,
+  user_name VARCHAR(32) NOT NULL COLLATE NOCASE,
+  user_icon VARCHAR(256) NOT NULL,
+  create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX user_idx_user_id ON user (user_id);
+CREATE UNIQUE INDEX user_udx_user_name ON user (user_name);
+CREATE INDEX user_idx_update ON user (update_time);
+CREATE TRIGGER user_update_time AFTER UPDATE ON user FOR EACH ROW WHEN NEW.update_time IS OLD.update_time
+BEGIN
+  UPDATE user SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TABLE user_operation (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id VARCHAR(32) NOT NULL,
+  operationType VARCHAR(32) NOT NULL,
+  user_agent VARCHAR(256) NOT NULL,
+  phone_number VARCHAR(32) NOT NULL,
+  create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX user_operation_idx_user_id ON user_operation (user_id);
+CREATE INDEX user_operation_idx_create_time ON user_operation (create_time);
+CREATE INDEX user_operation_idx_phone ON user_operation (phone_number);
+`,
+	// migrations/0002_login_sms_code_limits.sql
+	`
+ALTER TABLE login_sms_code ADD COLUMN client_ip VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE login_sms_code ADD COLUMN verify_count INT NOT NULL DEFAULT 0;
+ALTER TABLE login_sms_code ADD COLUMN is_used TINYINT(1) NOT NULL DEFAULT 0;
+CREATE INDEX login_sms_code_idx_client_ip ON login_sms_code (client_ip);
+`,
+	// migrations/0004_password_account.sql
+	`
+CREATE TABLE password_account (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id VARCHAR(32) NOT NULL,
+  password_hash VARCHAR(256) NOT NULL,
+  failed_count INT NOT NULL DEFAULT 0,
+  create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX password_account_idx_user_id ON password_account (user_id);
+CREATE INDEX password_account_idx_update ON password_account (update_time);
+CREATE TRIGGER password_account_update_time AFTER UPDATE ON password_account FOR EACH ROW WHEN NEW.update_time IS OLD.update_time
+BEGIN
+  UPDATE password_account SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+`,
+	// migrations/0005_totp.sql
+	`
+CREATE TABLE totp_account (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id VARCHAR(32) NOT NULL,
+  totp_secret VARCHAR(256) NOT NULL,
+  is_enabled TINYINT(1) NOT NULL DEFAULT 0,
+  last_used_step BIGINT NOT NULL DEFAULT 0,
+  failed_count INT NOT NULL DEFAULT 0,
+  create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX totp_account_idx_user_id ON totp_account (user_id);
+CREATE INDEX totp_account_idx_update ON totp_account (update_time);
+CREATE TRIGGER totp_account_update_time AFTER UPDATE ON totp_account FOR EACH ROW WHEN NEW.update_time IS OLD.update_time
+BEGIN
+  UPDATE totp_account SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TABLE mfa_recovery_code (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id VARCHAR(32) NOT NULL,
+  code_hash VARCHAR(128) NOT NULL,
+  is_used TINYINT(1) NOT NULL DEFAULT 0,
+  create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX mfa_recovery_code_idx_user_id ON mfa_recovery_code (user_id);
+CREATE INDEX mfa_recovery_code_idx_update ON mfa_recovery_code (update_time);
+CREATE TRIGGER mfa_recovery_code_update_time AFTER UPDATE ON mfa_recovery_code FOR EACH ROW WHEN NEW.update_time IS OLD.update_time
+BEGIN
+  UPDATE mfa_recovery_code SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+`,
+	// migrations/0006_webauthn.sql
+	`
+CREATE TABLE webauthn_credential (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id VARCHAR(32) NOT NULL,
+  credential_id VARCHAR(255) NOT NULL,
+  credential_data TEXT NOT NULL,
+  create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX webauthn_credential_idx_credential_id ON webauthn_credential (credential_id);
+CREATE INDEX webauthn_credential_idx_user_id ON webauthn_credential (user_id);
+CREATE INDEX webauthn_credential_idx_update ON webauthn_credential (update_time);
+CREATE TRIGGER webauthn_credential_update_time AFTER UPDATE ON webauthn_credential FOR EACH ROW WHEN NEW.update_time IS OLD.update_time
+BEGIN
+  UPDATE webauthn_credential SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TABLE webauthn_session (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  session_id VARCHAR(128) NOT NULL,
+  user_id VARCHAR(32) NOT NULL,
+  session_data TEXT NOT NULL,
+  is_used TINYINT(1) NOT NULL DEFAULT 0,
+  create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX webauthn_session_idx_session_id ON webauthn_session (session_id);
+CREATE INDEX webauthn_session_idx_update ON webauthn_session (update_time);
+CREATE TRIGGER webauthn_session_update_time AFTER UPDATE ON webauthn_session FOR EACH ROW WHEN NEW.update_time IS OLD.update_time
+BEGIN
+  UPDATE webauthn_session SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+`,
+	// migrations/0007_refresh_token_sessions.sql
+	`
+ALTER TABLE refresh_token ADD COLUMN session_id VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE refresh_token ADD COLUMN user_agent VARCHAR(256) NOT NULL DEFAULT '';
+ALTER TABLE refresh_token ADD COLUMN client_ip VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE refresh_token ADD COLUMN login_time TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00';
+-- keep update_time, which the janitor expires sessions by
+DROP TRIGGER refresh_token_update_time;
+UPDATE refresh_token SET session_id = printf('%032x', id), login_time = create_time;
+CREATE TRIGGER refresh_token_update_time AFTER UPDATE ON refresh_token FOR EACH ROW WHEN NEW.update_time IS OLD.update_time
+BEGIN
+  UPDATE refresh_token SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+CREATE INDEX refresh_token_idx_session_id ON refresh_token (session_id);
+`,
+	// migrations/0008_oauth_provider.sql
+	`
+CREATE TABLE oauth_authorization_code (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  code VARCHAR(128) NOT NULL,
+  client_id VARCHAR(64) NOT NULL,
+  user_id VARCHAR(32) NOT NULL,
+  redirect_uri VARCHAR(1024) NOT NULL,
+  scope VARCHAR(256) NOT NULL,
+  nonce VARCHAR(256) NOT NULL,
+  code_challenge VARCHAR(128) NOT NULL,
+  session_id VARCHAR(32) NOT NULL DEFAULT '',
+  is_used TINYINT(1) NOT NULL DEFAULT 0,
+  create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX oauth_authorization_code_idx_code ON oauth_authorization_code (code);
+CREATE INDEX oauth_authorization_code_idx_update ON oauth_authorization_code (update_time);
+CREATE TRIGGER oauth_authorization_code_update_time AFTER UPDATE ON oauth_authorization_code FOR EACH ROW WHEN NEW.update_time IS OLD.update_time
+BEGIN
+  UPDATE oauth_authorization_code SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TABLE oauth_client (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  client_id VARCHAR(64) NOT NULL,
+  client_secret VARCHAR(128) NOT NULL,
+  client_name VARCHAR(128) NOT NULL,
+  redirect_uris VARCHAR(2048) NOT NULL,
+  grant_types VARCHAR(256) NOT NULL,
+  scope VARCHAR(256) NOT NULL,
+  create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX oauth_client_idx_client_id ON oauth_client (client_id);
+CREATE INDEX oauth_client_idx_update ON oauth_client (update_time);
+CREATE TRIGGER oauth_client_update_time AFTER UPDATE ON oauth_client FOR EACH ROW WHEN NEW.update_time IS OLD.update_time
+BEGIN
+  UPDATE oauth_client SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+ALTER TABLE refresh_token ADD COLUMN client_id VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE refresh_token ADD COLUMN scope VARCHAR(256) NOT NULL DEFAULT '';
+`,
+	// migrations/0009_access_token_revocation.sql
+	`
+ALTER TABLE access_token ADD COLUMN session_id VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE access_token ADD COLUMN is_revoked TINYINT(1) NOT NULL DEFAULT 0;
+`,
+	// migrations/0010_user_role.sql
+	`
+CREATE TABLE user_role (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id VARCHAR(32) NOT NULL,
+  role VARCHAR(32) NOT NULL,
+  create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX user_role_idx_user_role ON user_role (user_id, role);
+CREATE TRIGGER user_role_update_time AFTER UPDATE ON user_role FOR EACH ROW WHEN NEW.update_time IS OLD.update_time
+BEGIN
+  UPDATE user_role SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+`,
+	// migrations/0011_impersonation.sql
+	`
+ALTER TABLE user_operation ADD COLUMN actor_id VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE user_operation ADD COLUMN reason VARCHAR(256) NOT NULL DEFAULT '';
+CREATE INDEX user_operation_idx_actor_id ON user_operation (actor_id);
+`,
+	// migrations/0012_user_name_history.sql
+	`
+CREATE TABLE user_name_history (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id VARCHAR(32) NOT NULL,
+  user_name VARCHAR(32) NOT NULL COLLATE NOCASE,
+  reserved_until TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX user_name_history_idx_user_id ON user_name_history (user_id);
+CREATE INDEX user_name_history_idx_user_name ON user_name_history (user_name);
+CREATE INDEX user_name_history_idx_update ON user_name_history (update_time);
+CREATE TRIGGER user_name_history_update_time AFTER UPDATE ON user_name_history FOR EACH ROW WHEN NEW.update_time IS OLD.update_time
+BEGIN
+  UPDATE user_name_history SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+`,
+	// migrations/0013_oauth_icon_mirror.sql
+	`
+ALTER TABLE oauth_account ADD COLUMN mirror_source VARCHAR(256) NOT NULL DEFAULT '';
+ALTER TABLE oauth_account ADD COLUMN mirror_icon VARCHAR(256) NOT NULL DEFAULT '';
+`,
+	// migrations/0014_user_profile.sql
+	`
+ALTER TABLE user ADD COLUMN bio VARCHAR(256) NOT NULL DEFAULT '';
+ALTER TABLE user ADD COLUMN gender VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE user ADD COLUMN birthday VARCHAR(10) NOT NULL DEFAULT '';
+ALTER TABLE user ADD COLUMN locale VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE user ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT '';
+CREATE TABLE user_attribute (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id VARCHAR(32) NOT NULL,
+  namespace VARCHAR(32) NOT NULL,
+  attr_key VARCHAR(64) NOT NULL,
+  attr_value VARCHAR(1024) NOT NULL,
+  create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX user_attribute_idx_user_attribute ON user_attribute (user_id, namespace, attr_key);
+CREATE INDEX user_attribute_idx_update ON user_attribute (update_time);
+CREATE TRIGGER user_attribute_update_time AFTER UPDATE ON user_attribute FOR EACH ROW WHEN NEW.update_time IS OLD.update_time
+BEGIN
+  UPDATE user_attribute SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+`,
+	// migrations/0015_access_token_session_index.sql
+	`
+CREATE INDEX access_token_idx_session_id ON access_token (session_id);
+`,
+}